@@ -28,9 +28,11 @@ import (
 	"net/textproto"
 
 	clusterPkg "github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/validation"
 
 	"github.com/gorilla/mux"
+	pkgerrors "github.com/pkg/errors"
 )
 
 var cpJSONFile string = "json-schemas/metadata.json"
@@ -190,11 +192,36 @@ func (h clusterHandler) createClusterHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Reject the kubeconfigs which can't be used to reach the cluster now,
+	// rather than when the first application is deployed to it
+	p.Warnings, err = clusterPkg.ValidateKubeconfig(content)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var status *clusterPkg.ClusterStatus
+	if r.URL.Query().Get("verify") == "true" {
+		s, err := clusterPkg.VerifyKubeconfig(q.Kubeconfig)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		status = &s
+	}
+
 	ret, err := h.client.CreateCluster(provider, p, q)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if status != nil {
+		ret.Status = status
+	}
+
+	// Probe the new cluster right away, rather than at the next health check,
+	// so that its status and discovered inventory are known
+	go h.client.ProbeCluster(provider, ret.Metadata.Name)
 
 	//	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -205,45 +232,140 @@ func (h clusterHandler) createClusterHandler(w http.ResponseWriter, r *http.Requ
 	}
 }
 
-// Get handles GET operations on a particular Cluster Name
-// Returns a Cluster
-func (h clusterHandler) getClusterHandler(w http.ResponseWriter, r *http.Request) {
+// importClustersHandler handles the bulk import of clusters: the "file"
+// field is a kubeconfig with many contexts or a tarball of kubeconfigs, and
+// the optional "metadata" field is the manifest of the clusters
+func (h clusterHandler) importClustersHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	provider := vars["provider-name"]
-	name := vars["name"]
 
-	label := r.URL.Query().Get("label")
-	if len(label) != 0 {
-		ret, err := h.client.GetClustersWithLabel(provider, label)
+	err := r.ParseMultipartForm(16777216)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Unable to process file", http.StatusUnprocessableEntity)
+		return
+	}
+	defer file.Close()
+	content, err := ioutil.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Unable to read file", http.StatusUnprocessableEntity)
+		return
+	}
+
+	imports, err := clusterPkg.ParseClusterImport([]byte(r.FormValue("metadata")), content)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for i := range imports {
+		if imports[i].Err == nil {
+			imports[i].Err = validateClusterImport(imports[i])
+		}
+	}
+
+	ret, err := h.client.ImportClusters(provider, imports)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The imported Clusters are probed in the background by a bounded
+	// number of workers
+	var names []string
+	for _, res := range ret {
+		if res.Status != clusterPkg.ClusterImportFailed {
+			names = append(names, res.Name)
+		}
+	}
+	go h.client.ProbeClusters(provider, names)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// validateClusterImport validates a cluster of a bulk import, and its labels
+// and kv pairs, like their own POST requests
+func validateClusterImport(imp clusterPkg.ClusterImport) error {
+	if imp.Cluster.Metadata.Name == "" {
+		return pkgerrors.New("Missing cluster name")
+	}
+	err, _ := validation.ValidateJsonSchemaData(cpJSONFile, imp.Cluster)
+	if err != nil {
+		return err
+	}
+	for _, l := range imp.Labels {
+		err, _ := validation.ValidateJsonSchemaData(clJSONFile, l)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			return err
 		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		err = json.NewEncoder(w).Encode(ret)
+	}
+	for _, p := range imp.KvPairs {
+		err, _ := validation.ValidateJsonSchemaData(ckvJSONFile, p)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			return err
 		}
-		return
 	}
+	return nil
+}
 
-	// handle the get all clusters case - return a list of only the json parts
+// Get handles GET operations on a particular Cluster Name
+// Returns a Cluster
+func (h clusterHandler) getClusterHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	provider := vars["provider-name"]
+	name := vars["name"]
+
+	// handle the get all clusters case - return a list of only the json parts,
+	// or of the names of the clusters when they are selected by label or by
+	// label selector
 	if len(name) == 0 {
-		var retList []clusterPkg.Cluster
+		o, err := db.ListOptionsFromQuery(r.URL.Query(), "label", "selector")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-		ret, err := h.client.GetClusters(provider)
+		ret, next, err := h.client.ListClusters(provider, o)
 		if err != nil {
+			if _, ok := pkgerrors.Cause(err).(*db.ListOptionsError); ok {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		for _, cl := range ret {
-			retList = append(retList, clusterPkg.Cluster{Metadata: cl.Metadata})
+		var retList interface{}
+		_, byLabel := o.Filters["label"]
+		_, bySelector := o.Filters["selector"]
+		if byLabel || bySelector {
+			names := []string{}
+			for _, cl := range ret {
+				names = append(names, cl.Metadata.Name)
+			}
+			retList = names
+		} else {
+			var clusters []clusterPkg.Cluster
+			for _, cl := range ret {
+				clusters = append(clusters, clusterPkg.Cluster{Metadata: cl.Metadata})
+			}
+			retList = clusters
 		}
 
 		w.Header().Set("Content-Type", "application/json")
+		if next != "" {
+			w.Header().Set("X-Continue-Token", next)
+		}
 		w.WriteHeader(http.StatusOK)
 		err = json.NewEncoder(w).Encode(retList)
 		if err != nil {
@@ -259,11 +381,20 @@ func (h clusterHandler) getClusterHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// The version is read before the Cluster, so that the ETag is never
+	// newer than the body of the response
+	version, err := h.client.GetClusterVersion(provider, name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	retCluster, err := h.client.GetCluster(provider, name)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set("ETag", db.ETag(version))
 
 	retKubeconfig, err := h.client.GetClusterContent(provider, name)
 	if err != nil {
@@ -342,6 +473,35 @@ func (h clusterHandler) deleteClusterHandler(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// cordonClusterHandler leaves a Cluster out of the new placement decisions
+func (h clusterHandler) cordonClusterHandler(w http.ResponseWriter, r *http.Request) {
+	h.setClusterCordon(w, r, true)
+}
+
+// uncordonClusterHandler returns a cordoned Cluster to the placement
+func (h clusterHandler) uncordonClusterHandler(w http.ResponseWriter, r *http.Request) {
+	h.setClusterCordon(w, r, false)
+}
+
+func (h clusterHandler) setClusterCordon(w http.ResponseWriter, r *http.Request, cordoned bool) {
+	vars := mux.Vars(r)
+	provider := vars["provider-name"]
+	name := vars["cluster-name"]
+
+	version, err := h.client.CordonCluster(provider, name, cordoned, r.Header.Get("If-Match"))
+	if _, ok := pkgerrors.Cause(err).(*db.VersionConflictError); ok {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", db.ETag(version))
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Create handles creation of the ClusterLabel entry in the database
 func (h clusterHandler) createClusterLabelHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -449,6 +609,10 @@ func (h clusterHandler) createClusterKvPairsHandler(w http.ResponseWriter, r *ht
 		http.Error(w, "Missing Key Value pair name in POST request", http.StatusBadRequest)
 		return
 	}
+	if p.Metadata.Name == clusterPkg.DiscoveredKvPairsName {
+		http.Error(w, "Key Value pair name "+clusterPkg.DiscoveredKvPairsName+" is reserved", http.StatusBadRequest)
+		return
+	}
 
 	ret, err := h.client.CreateClusterKvPairs(provider, cluster, p)
 	if err != nil {
@@ -506,6 +670,11 @@ func (h clusterHandler) deleteClusterKvPairsHandler(w http.ResponseWriter, r *ht
 	cluster := vars["cluster-name"]
 	kvpair := vars["kvpair"]
 
+	if kvpair == clusterPkg.DiscoveredKvPairsName {
+		http.Error(w, "Key Value pair "+clusterPkg.DiscoveredKvPairsName+" is maintained by clm", http.StatusBadRequest)
+		return
+	}
+
 	err := h.client.DeleteClusterKvPairs(provider, cluster, kvpair)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
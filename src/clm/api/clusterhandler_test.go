@@ -25,9 +25,11 @@ import (
 	"net/http/httptest"
 	"net/textproto"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	types "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module/types"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
 
@@ -47,7 +49,11 @@ type mockClusterManager struct {
 	ClusterLabelItems    []cluster.ClusterLabel
 	ClusterKvPairsItems  []cluster.ClusterKvPairs
 	ClusterList          []string
+	Next                 string
+	Version              int64
 	Err                  error
+	// Probed receives the names of the Clusters probed by ProbeClusters
+	Probed chan []string
 }
 
 func (m *mockClusterManager) CreateClusterProvider(inp cluster.ClusterProvider) (cluster.ClusterProvider, error) {
@@ -94,6 +100,10 @@ func (m *mockClusterManager) GetCluster(provider, name string) (cluster.Cluster,
 	return m.ClusterItems[0], nil
 }
 
+func (m *mockClusterManager) GetClusterVersion(provider, name string) (int64, error) {
+	return m.Version, m.Err
+}
+
 func (m *mockClusterManager) GetClusterContent(provider, name string) (cluster.ClusterContent, error) {
 	if m.Err != nil {
 		return cluster.ClusterContent{}, m.Err
@@ -110,6 +120,28 @@ func (m *mockClusterManager) GetClusterState(provider, name string) (state.State
 	return m.ClusterStateInfo[0], nil
 }
 
+func (m *mockClusterManager) GetClusterStatus(provider, name string) (cluster.ClusterStatus, error) {
+	if m.Err != nil {
+		return cluster.ClusterStatus{}, m.Err
+	}
+
+	return cluster.ClusterStatus{State: cluster.ClusterStateUnknown}, nil
+}
+
+func (m *mockClusterManager) ProbeClusters(provider string, names []string) {
+	if m.Probed != nil {
+		m.Probed <- names
+	}
+}
+
+func (m *mockClusterManager) ProbeCluster(provider, name string) (cluster.ClusterStatus, error) {
+	if m.Err != nil {
+		return cluster.ClusterStatus{}, m.Err
+	}
+
+	return cluster.ClusterStatus{State: cluster.ClusterStateUnknown}, nil
+}
+
 func (m *mockClusterManager) GetClusters(provider string) ([]cluster.Cluster, error) {
 	if m.Err != nil {
 		return []cluster.Cluster{}, m.Err
@@ -118,6 +150,14 @@ func (m *mockClusterManager) GetClusters(provider string) ([]cluster.Cluster, er
 	return m.ClusterItems, nil
 }
 
+func (m *mockClusterManager) ListClusters(provider string, o db.ListOptions) ([]cluster.Cluster, string, error) {
+	if m.Err != nil {
+		return []cluster.Cluster{}, "", m.Err
+	}
+
+	return m.ClusterItems, m.Next, nil
+}
+
 func (m *mockClusterManager) GetClustersWithLabel(provider, label string) ([]string, error) {
 	if m.Err != nil {
 		return []string{}, m.Err
@@ -126,10 +166,32 @@ func (m *mockClusterManager) GetClustersWithLabel(provider, label string) ([]str
 	return m.ClusterList, nil
 }
 
+func (m *mockClusterManager) GetLabelSelector(provider, label string) (cluster.LabelSelector, error) {
+	if m.Err != nil {
+		return cluster.LabelSelector{}, m.Err
+	}
+
+	return cluster.ParseLabelSelector(label)
+}
+
 func (m *mockClusterManager) DeleteCluster(provider, name string) error {
 	return m.Err
 }
 
+func (m *mockClusterManager) CordonCluster(provider, name string, cordoned bool, ifMatch string) (int64, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	if !db.IfMatch(ifMatch, m.Version) {
+		return 0, &db.VersionConflictError{Version: m.Version}
+	}
+	return m.Version + 1, nil
+}
+
+func (m *mockClusterManager) IsClusterCordoned(provider, name string) (bool, error) {
+	return false, m.Err
+}
+
 func (m *mockClusterManager) CreateClusterLabel(provider, clusterName string, inp cluster.ClusterLabel) (cluster.ClusterLabel, error) {
 	if m.Err != nil {
 		return cluster.ClusterLabel{}, m.Err
@@ -186,6 +248,23 @@ func (m *mockClusterManager) DeleteClusterKvPairs(provider, clusterName, kvpair
 	return m.Err
 }
 
+func (m *mockClusterManager) ImportClusters(provider string, imports []cluster.ClusterImport) ([]cluster.ClusterImportResult, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+
+	results := []cluster.ClusterImportResult{}
+	for _, imp := range imports {
+		r := cluster.ClusterImportResult{Name: imp.Cluster.Metadata.Name, Status: cluster.ClusterImportCreated}
+		if imp.Err != nil {
+			r.Status = cluster.ClusterImportFailed
+			r.Error = imp.Err.Error()
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
 func init()  {
 	cpJSONFile = "../json-schemas/metadata.json"
 	ckvJSONFile = "../json-schemas/cluster-kv.json"
@@ -462,6 +541,25 @@ func TestClusterProviderDeleteHandler(t *testing.T) {
 	}
 }
 
+// testKubeconfig is a kubeconfig with a single cluster
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://127.0.0.1:6443
+  name: cluster1
+contexts:
+- context:
+    cluster: cluster1
+    user: admin
+  name: admin@cluster1
+current-context: admin@cluster1
+users:
+- name: admin
+  user:
+    token: abc
+`
+
 func TestClusterCreateHandler(t *testing.T) {
 	testCases := []struct {
 		label         string
@@ -488,11 +586,7 @@ func TestClusterCreateHandler(t *testing.T) {
   "userData2": "some cluster data def"
  }
 }`,
-			kubeconfig: `test contents
-of a file attached
-to the creation
-of clusterTest
-`,
+			kubeconfig: testKubeconfig,
 			expected: cluster.Cluster{
 				Metadata: types.Metadata{
 					Name:        "clusterTest",
@@ -530,6 +624,34 @@ of clusterTest
 				},
 			},
 		},
+		{
+			label:        "Invalid Kubeconfig",
+			expectedCode: http.StatusBadRequest,
+			metadata: `
+{
+ "metadata": {
+  "name": "clusterTest"
+ }
+}`,
+			kubeconfig: `test contents
+of a file attached
+to the creation
+of clusterTest
+`,
+			clusterClient: &mockClusterManager{},
+		},
+		{
+			label:        "Kubeconfig Without Current Context",
+			expectedCode: http.StatusBadRequest,
+			metadata: `
+{
+ "metadata": {
+  "name": "clusterTest"
+ }
+}`,
+			kubeconfig:    strings.Replace(testKubeconfig, "current-context: admin@cluster1", "", 1),
+			clusterClient: &mockClusterManager{},
+		},
 		{
 			label:        "Missing Cluster Name in Request Body",
 			expectedCode: http.StatusBadRequest,
@@ -585,6 +707,85 @@ of clusterTest
 	}
 }
 
+func TestClusterImportHandler(t *testing.T) {
+	multiContext := strings.Replace(testKubeconfig, "current-context: admin@cluster1", `- context:
+    cluster: cluster1
+    user: admin
+  name: edge2
+current-context: admin@cluster1`, 1)
+
+	testCases := []struct {
+		label        string
+		manifest     string
+		file         string
+		expected     []cluster.ClusterImportResult
+		expectedCode int
+		probed       []string
+	}{
+		{
+			label:        "Import Contexts",
+			file:         multiContext,
+			expectedCode: http.StatusOK,
+			expected: []cluster.ClusterImportResult{
+				{Name: "admin@cluster1", Status: cluster.ClusterImportCreated},
+				{Name: "edge2", Status: cluster.ClusterImportCreated},
+			},
+			probed: []string{"admin@cluster1", "edge2"},
+		},
+		{
+			label:        "Import With Manifest",
+			manifest:     `{"clusters": [{"metadata": {"name": "edge1"}, "context": "edge2", "labels": ["edge"]}, {"metadata": {}}]}`,
+			file:         multiContext,
+			expectedCode: http.StatusOK,
+			expected: []cluster.ClusterImportResult{
+				{Name: "edge1", Status: cluster.ClusterImportCreated},
+				{Name: "", Status: cluster.ClusterImportFailed, Error: "Missing cluster name"},
+			},
+			probed: []string{"edge1"},
+		},
+		{
+			label:        "Invalid File",
+			file:         "test contents",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			body := new(bytes.Buffer)
+			multiwr := multipart.NewWriter(body)
+			if testCase.manifest != "" {
+				multiwr.WriteField("metadata", testCase.manifest)
+			}
+			pw, _ := multiwr.CreateFormFile("file", "kubeconfig")
+			pw.Write([]byte(testCase.file))
+			multiwr.Close()
+
+			request := httptest.NewRequest("POST", "/v2/cluster-providers/clusterProvider1/import", body)
+			request.Header.Set("Content-Type", multiwr.FormDataContentType())
+			client := &mockClusterManager{Probed: make(chan []string, 1)}
+			resp := executeRequest(request, NewRouter(client))
+
+			if resp.StatusCode != testCase.expectedCode {
+				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
+			}
+			if resp.StatusCode == http.StatusOK {
+				got := []cluster.ClusterImportResult{}
+				json.NewDecoder(resp.Body).Decode(&got)
+				if reflect.DeepEqual(testCase.expected, got) == false {
+					t.Errorf("importClustersHandler returned unexpected body: got %v;"+
+						" expected %v", got, testCase.expected)
+				}
+				// The imported Clusters are probed together
+				probed := <-client.Probed
+				if reflect.DeepEqual(testCase.probed, probed) == false {
+					t.Errorf("importClustersHandler probed %v, expected %v", probed, testCase.probed)
+				}
+			}
+		})
+	}
+}
+
 func TestClusterGetAllHandler(t *testing.T) {
 
 	testCases := []struct {
@@ -672,6 +873,88 @@ func TestClusterGetAllHandler(t *testing.T) {
 	}
 }
 
+func TestClusterListHandler(t *testing.T) {
+	clusters := []cluster.Cluster{
+		{Metadata: types.Metadata{Name: "testCluster1"}},
+		{Metadata: types.Metadata{Name: "testCluster2"}},
+	}
+
+	testCases := []struct {
+		label         string
+		query         string
+		expected      interface{}
+		expectedCode  int
+		expectedNext  string
+		clusterClient *mockClusterManager
+	}{
+		{
+			label:         "List Clusters With Next Page",
+			query:         "?limit=2",
+			expected:      clusters,
+			expectedCode:  http.StatusOK,
+			expectedNext:  "Mg",
+			clusterClient: &mockClusterManager{ClusterItems: clusters, Next: "Mg"},
+		},
+		{
+			label:         "List Clusters With Label",
+			query:         "?label=edge&limit=2",
+			expected:      []string{"testCluster1", "testCluster2"},
+			expectedCode:  http.StatusOK,
+			clusterClient: &mockClusterManager{ClusterItems: clusters},
+		},
+		{
+			label:         "List Clusters With Selector",
+			query:         "?selector=region%3Deu%2Ctier%21%3Dedge",
+			expected:      []string{"testCluster1", "testCluster2"},
+			expectedCode:  http.StatusOK,
+			clusterClient: &mockClusterManager{ClusterItems: clusters},
+		},
+		{
+			label:        "Invalid Selector",
+			query:        "?selector=region%3D%3D%3Deu",
+			expectedCode: http.StatusBadRequest,
+			clusterClient: &mockClusterManager{
+				Err: &db.ListOptionsError{Reason: "Invalid label selector"},
+			},
+		},
+		{
+			label:         "Invalid Limit",
+			query:         "?limit=many",
+			expectedCode:  http.StatusBadRequest,
+			clusterClient: &mockClusterManager{},
+		},
+		{
+			label:        "Invalid Continue Token",
+			query:        "?continue=bad",
+			expectedCode: http.StatusBadRequest,
+			clusterClient: &mockClusterManager{
+				Err: &db.ListOptionsError{Reason: "Invalid continue token"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/v2/cluster-providers/clusterProvder1/clusters"+testCase.query, nil)
+			resp := executeRequest(request, NewRouter(testCase.clusterClient))
+
+			if resp.StatusCode != testCase.expectedCode {
+				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
+			}
+			if next := resp.Header.Get("X-Continue-Token"); next != testCase.expectedNext {
+				t.Errorf("Expected continue token %q; Got: %q", testCase.expectedNext, next)
+			}
+			if resp.StatusCode == http.StatusOK {
+				got := reflect.New(reflect.TypeOf(testCase.expected))
+				json.NewDecoder(resp.Body).Decode(got.Interface())
+				if !reflect.DeepEqual(testCase.expected, got.Elem().Interface()) {
+					t.Errorf("listHandler returned unexpected body: got %v; expected %v", got.Elem(), testCase.expected)
+				}
+			}
+		})
+	}
+}
+
 func TestClusterGetHandler(t *testing.T) {
 
 	testCases := []struct {
@@ -696,6 +979,7 @@ func TestClusterGetHandler(t *testing.T) {
 			},
 			name: "testCluster",
 			clusterClient: &mockClusterManager{
+				Version: 7,
 				//Items that will be returned by the mocked Client
 				ClusterItems: []cluster.Cluster{
 					{
@@ -748,6 +1032,9 @@ func TestClusterGetHandler(t *testing.T) {
 					t.Errorf("listHandler returned unexpected body: got %v;"+
 						" expected %v", got, testCase.expected)
 				}
+				if etag := resp.Header.Get("ETag"); etag != `"7"` {
+					t.Errorf("getHandler returned unexpected ETag %s", etag)
+				}
 			}
 		})
 	}
@@ -870,6 +1157,73 @@ func TestClusterDeleteHandler(t *testing.T) {
 	}
 }
 
+func TestClusterCordonHandler(t *testing.T) {
+
+	testCases := []struct {
+		label         string
+		action        string
+		ifMatch       string
+		expectedCode  int
+		clusterClient *mockClusterManager
+	}{
+		{
+			label:         "Cordon Cluster",
+			action:        "cordon",
+			expectedCode:  http.StatusNoContent,
+			clusterClient: &mockClusterManager{},
+		},
+		{
+			label:         "Uncordon Cluster",
+			action:        "uncordon",
+			expectedCode:  http.StatusNoContent,
+			clusterClient: &mockClusterManager{},
+		},
+		{
+			label:         "Cordon Cluster at the current version",
+			action:        "cordon",
+			ifMatch:       `"4"`,
+			expectedCode:  http.StatusNoContent,
+			clusterClient: &mockClusterManager{Version: 4},
+		},
+		{
+			label:         "Cordon Cluster at a stale version",
+			action:        "cordon",
+			ifMatch:       `"3"`,
+			expectedCode:  http.StatusPreconditionFailed,
+			clusterClient: &mockClusterManager{Version: 4},
+		},
+		{
+			label:        "Cordon Non-Existing Cluster",
+			action:       "cordon",
+			expectedCode: http.StatusInternalServerError,
+			clusterClient: &mockClusterManager{
+				Err: pkgerrors.New("Cluster does not exist"),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/v2/cluster-providers/clusterProvider1/clusters/testCluster/"+testCase.action, nil)
+			if testCase.ifMatch != "" {
+				request.Header.Set("If-Match", testCase.ifMatch)
+			}
+			resp := executeRequest(request, NewRouter(testCase.clusterClient))
+
+			//Check returned code
+			if resp.StatusCode != testCase.expectedCode {
+				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
+			}
+			if resp.StatusCode == http.StatusNoContent {
+				expected := db.ETag(testCase.clusterClient.Version + 1)
+				if etag := resp.Header.Get("ETag"); etag != expected {
+					t.Errorf("Expected ETag %s; Got: %s", expected, etag)
+				}
+			}
+		})
+	}
+}
+
 func TestClusterLabelCreateHandler(t *testing.T) {
 	testCases := []struct {
 		label         string
@@ -901,6 +1255,34 @@ func TestClusterLabelCreateHandler(t *testing.T) {
 				},
 			},
 		},
+		{
+			label:        "Create Cluster Label With Value",
+			expectedCode: http.StatusCreated,
+			reader: bytes.NewBuffer([]byte(`{
+					"label-name": "region",
+					"value": "eu-west"
+				}`)),
+			expected: cluster.ClusterLabel{
+				LabelName: "region",
+				Value:     "eu-west",
+			},
+			clusterClient: &mockClusterManager{
+				ClusterLabelItems: []cluster.ClusterLabel{
+					{
+						LabelName: "region",
+						Value:     "eu-west",
+					},
+				},
+			},
+		},
+		{
+			label:        "Invalid Cluster Label Key",
+			expectedCode: http.StatusBadRequest,
+			reader: bytes.NewBuffer([]byte(`{
+					"label-name": "region=eu"
+				}`)),
+			clusterClient: &mockClusterManager{},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/openapi"
+)
+
+const (
+	specProviderPath = "/v2/cluster-providers/{provider-name}"
+	specClusterPath  = specProviderPath + "/clusters/{cluster-name}"
+)
+
+// openAPISpec documents the routes of NewRouter. The operations of the
+// collections and of their items are generated from the resources, the
+// other routes have an operation. Every route must have one, see
+// TestOpenAPISpec.
+func openAPISpec() openapi.Spec {
+	return openapi.Spec{
+		Title:   "EMCO Cluster Manager",
+		Version: "v2",
+		Resources: map[string]openapi.Resource{
+			"cluster-providers": {Name: "cluster provider", Schema: cpJSONFile, Model: cluster.ClusterProvider{}},
+			"clusters":          {Name: "cluster", Schema: cpJSONFile, Files: []string{"file"}, Model: cluster.Cluster{}},
+			"labels":            {Name: "cluster label", Schema: clJSONFile, Model: cluster.ClusterLabel{}},
+			"kv-pairs":          {Name: "cluster key value pair", Schema: ckvJSONFile, Model: cluster.ClusterKvPairs{}},
+		},
+		Operations: map[string]openapi.Operation{
+			"GET /v2/openapi.json": {Summary: "Get the OpenAPI specification of the service", Response: map[string]interface{}{}},
+
+			"GET " + specProviderPath + "/clusters":        {Summary: "List the clusters, or the names of the clusters with a label or selected by a label selector", Response: []cluster.Cluster{}, Queries: []string{"limit", "continue", "sort", "label", "selector"}},
+			"GET " + specProviderPath + "/clusters/{name}": {Summary: "Get a cluster, with its kubeconfig for multipart/form-data", Response: cluster.Cluster{}},
+			"POST " + specProviderPath + "/import":         {Summary: "Create or update clusters from a multi-context kubeconfig or a tarball of kubeconfigs", Request: cluster.ClusterImportManifest{}, Files: []string{"file"}, Response: []cluster.ClusterImportResult{}},
+
+			"POST " + specClusterPath + "/cordon":   {Summary: "Cordon a cluster, leaving it out of the new placement decisions", Status: http.StatusNoContent},
+			"POST " + specClusterPath + "/uncordon": {Summary: "Uncordon a cluster", Status: http.StatusNoContent},
+		},
+	}
+}
@@ -15,6 +15,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"math/rand"
 	"net/http"
@@ -24,13 +25,24 @@ import (
 
 	"github.com/gorilla/handlers"
 	"github.com/onap/multicloud-k8s/src/clm/api"
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/audit"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/auth"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/contextdb"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/metrics"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
 )
 
 func main() {
+	reencrypt := flag.Bool("reencrypt-secrets", false, "Re-encrypt the secrets stored in the database with the primary key, and exit")
+	rotate := flag.Bool("rotate-secret-key", false, "Create a new primary key before re-encrypting the secrets, and exit")
+	flag.Parse()
+
+	conf := config.GetConfiguration()
+	secret.Configure(secret.Config{KeyProvider: conf.SecretKeyProvider, KeyFile: conf.SecretKeyFile})
+
 	rand.Seed(time.Now().UnixNano())
 
 	err := db.InitializeDatabaseConnection("mco")
@@ -45,9 +57,24 @@ func main() {
 		log.Println(err)
 		log.Fatalln("Exiting...")
 	}
+	metrics.InstrumentDatabases()
+
+	if *reencrypt || *rotate {
+		n, err := secret.Migrate(*rotate, cluster.NewClusterClient().ReencryptSecrets)
+		if err != nil {
+			log.Fatalln("Error re-encrypting secrets:", err)
+		}
+		log.Printf("Re-encrypted %d secrets", n)
+		return
+	}
+
+	// Probe the clusters periodically so that their readiness is known
+	cluster.NewClusterClient().StartHealthChecks()
 
 	httpRouter := api.NewRouter(nil)
-	loggedRouter := handlers.LoggingHandler(os.Stdout, httpRouter)
+	httpRouter.Use(metrics.Middleware("clm"))
+	httpRouter.Use(audit.Middleware("clm"))
+	loggedRouter := handlers.LoggingHandler(os.Stdout, metrics.Handler(httpRouter))
 	log.Println("Starting Cluster Manager")
 
 	httpServer := &http.Server{
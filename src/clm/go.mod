@@ -1,10 +1,15 @@
 module github.com/onap/multicloud-k8s/src/clm
 
 require (
-	github.com/gorilla/handlers v1.3.0
+	github.com/gorilla/handlers v1.4.0
 	github.com/gorilla/mux v1.7.3
 	github.com/onap/multicloud-k8s/src/orchestrator v0.0.0-20251205113806-246f4e4b1e9f
 	github.com/pkg/errors v0.9.1
+	k8s.io/api v0.18.2
+	k8s.io/apiextensions-apiserver v0.18.2
+	k8s.io/apimachinery v0.18.2
+	k8s.io/client-go v12.0.0+incompatible
+	sigs.k8s.io/yaml v1.2.0
 )
 
 replace (
@@ -21,6 +21,7 @@ import (
 
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
 	mtypes "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module/types"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
 
@@ -32,6 +33,8 @@ type clientDbInfo struct {
 	tagMeta    string // attribute key name for the json data of a client document
 	tagContent string // attribute key name for the file data of a client document
 	tagState   string // attribute key name for StateInfo object in the cluster
+	tagStatus  string // attribute key name for the ClusterStatus of the cluster
+	tagCordon  string // attribute key name for the ClusterCordon of the cluster
 }
 
 // ClusterProvider contains the parameters needed for ClusterProviders
@@ -41,14 +44,22 @@ type ClusterProvider struct {
 
 type Cluster struct {
 	Metadata mtypes.Metadata `json:"metadata"`
+	Status   *ClusterStatus  `json:"status,omitempty"`
+	// Warnings about the kubeconfig found at the registration
+	Warnings []string `json:"warnings,omitempty"`
+	// Cordoned is set when the cluster is left out of the placement
+	Cordoned bool `json:"cordoned,omitempty"`
 }
 
 type ClusterContent struct {
 	Kubeconfig string `json:"kubeconfig"`
 }
 
+// ClusterLabel is a key value label of a cluster. LabelName is the key. The
+// labels created before the labels had values have the empty value.
 type ClusterLabel struct {
 	LabelName string `json:"label-name"`
+	Value     string `json:"value"`
 }
 
 type ClusterKvPairs struct {
@@ -103,11 +114,19 @@ type ClusterManager interface {
 	DeleteClusterProvider(name string) error
 	CreateCluster(provider string, pr Cluster, qr ClusterContent) (Cluster, error)
 	GetCluster(provider, name string) (Cluster, error)
+	GetClusterVersion(provider, name string) (int64, error)
 	GetClusterContent(provider, name string) (ClusterContent, error)
 	GetClusterState(provider, name string) (state.StateInfo, error)
+	GetClusterStatus(provider, name string) (ClusterStatus, error)
+	ProbeCluster(provider, name string) (ClusterStatus, error)
+	ProbeClusters(provider string, names []string)
 	GetClusters(provider string) ([]Cluster, error)
+	ListClusters(provider string, o db.ListOptions) ([]Cluster, string, error)
 	GetClustersWithLabel(provider, label string) ([]string, error)
+	GetLabelSelector(provider, label string) (LabelSelector, error)
 	DeleteCluster(provider, name string) error
+	CordonCluster(provider, name string, cordoned bool, ifMatch string) (int64, error)
+	IsClusterCordoned(provider, name string) (bool, error)
 	CreateClusterLabel(provider, cluster string, pr ClusterLabel) (ClusterLabel, error)
 	GetClusterLabel(provider, cluster, label string) (ClusterLabel, error)
 	GetClusterLabels(provider, cluster string) ([]ClusterLabel, error)
@@ -116,6 +135,7 @@ type ClusterManager interface {
 	GetClusterKvPairs(provider, cluster, kvpair string) (ClusterKvPairs, error)
 	GetAllClusterKvPairs(provider, cluster string) ([]ClusterKvPairs, error)
 	DeleteClusterKvPairs(provider, cluster, kvpair string) error
+	ImportClusters(provider string, imports []ClusterImport) ([]ClusterImportResult, error)
 }
 
 // ClusterClient implements the Manager
@@ -133,6 +153,8 @@ func NewClusterClient() *ClusterClient {
 			tagMeta:    "clustermetadata",
 			tagContent: "clustercontent",
 			tagState:   "stateInfo",
+			tagStatus:  "clusterstatus",
+			tagCordon:  "clustercordon",
 		},
 	}
 }
@@ -248,10 +270,20 @@ func (v *ClusterClient) CreateCluster(provider string, p Cluster, q ClusterConte
 		return Cluster{}, pkgerrors.New("Cluster already exists")
 	}
 
+	// The status is recorded by the health probes, and the cordon is
+	// changed through its own requests
+	p.Status = nil
+	p.Cordoned = false
+
 	err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
 	if err != nil {
 		return Cluster{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
+	// The kubeconfig is encrypted at rest
+	q.Kubeconfig, err = secret.Encrypt(q.Kubeconfig)
+	if err != nil {
+		return Cluster{}, pkgerrors.Wrap(err, "Encrypting kubeconfig")
+	}
 	err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagContent, q)
 	if err != nil {
 		return Cluster{}, pkgerrors.Wrap(err, "Creating DB Entry")
@@ -294,12 +326,35 @@ func (v *ClusterClient) GetCluster(provider, name string) (Cluster, error) {
 		if err != nil {
 			return Cluster{}, pkgerrors.Wrap(err, "Unmarshalling Value")
 		}
+		s, err := v.GetClusterStatus(provider, name)
+		if err != nil {
+			return Cluster{}, err
+		}
+		cl.Status = &s
+		cl.Cordoned, err = v.IsClusterCordoned(provider, name)
+		if err != nil {
+			return Cluster{}, err
+		}
 		return cl, nil
 	}
 
 	return Cluster{}, pkgerrors.New("Error getting Cluster")
 }
 
+// GetClusterVersion returns the version of the Cluster for corresponding
+// provider and name
+func (v *ClusterClient) GetClusterVersion(provider, name string) (int64, error) {
+	key := ClusterKey{
+		ClusterProviderName: provider,
+		ClusterName:         name,
+	}
+	version, err := db.DBconn.GetVersion(v.db.storeName, key)
+	if err != nil {
+		return 0, pkgerrors.Wrap(err, "Get Cluster version")
+	}
+	return version, nil
+}
+
 // GetClusterContent returns the ClusterContent for corresponding provider and name
 func (v *ClusterClient) GetClusterContent(provider, name string) (ClusterContent, error) {
 	//Construct key and tag to select the entry
@@ -320,6 +375,10 @@ func (v *ClusterClient) GetClusterContent(provider, name string) (ClusterContent
 		if err != nil {
 			return ClusterContent{}, pkgerrors.Wrap(err, "Unmarshalling Value")
 		}
+		cc.Kubeconfig, err = secret.Decrypt(cc.Kubeconfig)
+		if err != nil {
+			return ClusterContent{}, pkgerrors.Wrap(err, "Decrypting kubeconfig")
+		}
 		return cc, nil
 	}
 
@@ -353,16 +412,83 @@ func (v *ClusterClient) GetClusterState(provider, name string) (state.StateInfo,
 
 // GetClusters returns all the Clusters for corresponding provider
 func (v *ClusterClient) GetClusters(provider string) ([]Cluster, error) {
+	resp, _, err := v.ListClusters(provider, db.ListOptions{})
+	return resp, err
+}
+
+// ListClusters returns a page of the Clusters for corresponding provider, and
+// the continue token of the next page. The "label" filter selects the Clusters
+// with any of the given labels, and the "selector" filter the Clusters all
+// the given label selectors select.
+func (v *ClusterClient) ListClusters(provider string, o db.ListOptions) ([]Cluster, string, error) {
 	//Construct key and tag to select the entry
 	key := ClusterKey{
 		ClusterProviderName: provider,
 		ClusterName:         "",
 	}
 
-	values, err := db.DBconn.Find(v.db.storeName, key, v.db.tagMeta)
+	// The labels are stored apart from the Clusters, so the label filter
+	// is resolved to a filter of the names of the labeled Clusters
+	if labels, ok := o.Filters["label"]; ok {
+		var names []string
+		for _, label := range labels {
+			l, err := v.GetClustersWithLabel(provider, label)
+			if err != nil {
+				return []Cluster{}, "", err
+			}
+			names = append(names, l...)
+		}
+		if len(names) == 0 {
+			return []Cluster{}, "", nil
+		}
+		filters := map[string][]string{}
+		for name, values := range o.Filters {
+			filters[name] = values
+		}
+		delete(filters, "label")
+		filters["name"] = names
+		o.Filters = filters
+	}
+	if selectors, ok := o.Filters["selector"]; ok {
+		var names []string
+		for i, selector := range selectors {
+			if _, err := ParseLabelSelector(selector); err != nil {
+				return []Cluster{}, "", &db.ListOptionsError{Reason: err.Error()}
+			}
+			l, err := v.GetClustersWithLabel(provider, selector)
+			if err != nil {
+				return []Cluster{}, "", err
+			}
+			if i == 0 {
+				names = l
+			} else {
+				names = intersect(names, l)
+			}
+		}
+		if prev, ok := o.Filters["name"]; ok {
+			names = intersect(prev, names)
+		}
+		if len(names) == 0 {
+			return []Cluster{}, "", nil
+		}
+		filters := map[string][]string{}
+		for name, values := range o.Filters {
+			filters[name] = values
+		}
+		delete(filters, "selector")
+		filters["name"] = names
+		o.Filters = filters
+	}
+
+	fo, err := o.FindOptions(map[string]string{"name": "cluster"})
+	if err != nil {
+		return []Cluster{}, "", err
+	}
+	values, err := db.DBconn.FindWithOptions(v.db.storeName, key, v.db.tagMeta, fo)
 	if err != nil {
-		return []Cluster{}, pkgerrors.Wrap(err, "Get Clusters")
+		return []Cluster{}, "", pkgerrors.Wrap(err, "Get Clusters")
 	}
+	values, next := o.Page(values)
 
 	var resp []Cluster
 
@@ -370,37 +496,99 @@ func (v *ClusterClient) GetClusters(provider string) ([]Cluster, error) {
 		cp := Cluster{}
 		err = db.DBconn.Unmarshal(value, &cp)
 		if err != nil {
-			return []Cluster{}, pkgerrors.Wrap(err, "Unmarshalling Value")
+			return []Cluster{}, "", pkgerrors.Wrap(err, "Unmarshalling Value")
 		}
 		resp = append(resp, cp)
 	}
 
-	return resp, nil
+	return resp, next, nil
+}
+
+// intersect returns the names which are in both a and b
+func intersect(a, b []string) []string {
+	in := map[string]bool{}
+	for _, n := range b {
+		in[n] = true
+	}
+	var resp []string
+	for _, n := range a {
+		if in[n] {
+			resp = append(resp, n)
+		}
+	}
+	return resp
 }
 
-// GetClustersWithLabel returns all the Clusters with Labels for provider
+// GetClustersWithLabel returns the names of the Clusters of the provider
+// which the label selector selects, see LabelSelector. A label name selects
+// the Clusters with the label.
 // Support Query like /cluster-providers/{Provider}/clusters?label={label}
 func (v *ClusterClient) GetClustersWithLabel(provider, label string) ([]string, error) {
-	//Construct key and tag to select the entry
-	key := LabelKey{
-		ClusterProviderName: provider,
-		ClusterLabelName:    label,
+	selector, err := v.GetLabelSelector(provider, label)
+	if err != nil {
+		return []string{}, err
 	}
 
-	values, err := db.DBconn.Find(v.db.storeName, key, "cluster")
-	if err != nil {
-		return []string{}, pkgerrors.Wrap(err, "Get Clusters by label")
+	// The Clusters with a label key the selector requires are found with
+	// the key, the other selectors are checked against all the Clusters
+	var names []string
+	if k, ok := selector.requiredKey(); ok {
+		key := LabelKey{
+			ClusterProviderName: provider,
+			ClusterLabelName:    k,
+		}
+		values, err := db.DBconn.Find(v.db.storeName, key, "cluster")
+		if err != nil {
+			return []string{}, pkgerrors.Wrap(err, "Get Clusters by label")
+		}
+		for _, value := range values {
+			names = append(names, string(value))
+		}
+	} else {
+		clusters, err := v.GetClusters(provider)
+		if err != nil {
+			return []string{}, pkgerrors.Wrap(err, "Get Clusters by label")
+		}
+		for _, c := range clusters {
+			names = append(names, c.Metadata.Name)
+		}
 	}
-	var resp []string
 
-	for _, value := range values {
-		cp := string(value)
-		resp = append(resp, cp)
+	var resp []string
+	for _, name := range names {
+		labels, err := v.GetClusterLabels(provider, name)
+		if err != nil {
+			return []string{}, err
+		}
+		if selector.Matches(LabelSet(labels)) {
+			resp = append(resp, name)
+		}
 	}
 
 	return resp, nil
 }
 
+// GetLabelSelector returns the LabelSelector of label. The label names were
+// not checked as a whole before the labels had values, so a label name
+// like zone.a1 or dc/east1 which the Clusters of the provider have selects
+// them by the exact name instead.
+func (v *ClusterClient) GetLabelSelector(provider, label string) (LabelSelector, error) {
+	if label != "" && !labelKeyPattern.MatchString(label) {
+		key := LabelKey{
+			ClusterProviderName: provider,
+			ClusterLabelName:    label,
+		}
+		values, err := db.DBconn.Find(v.db.storeName, key, "cluster")
+		if err != nil {
+			return LabelSelector{}, pkgerrors.Wrap(err, "Get Clusters by label")
+		}
+		if len(values) > 0 {
+			return exactLabelSelector(label), nil
+		}
+	}
+	return ParseLabelSelector(label)
+}
+
 // DeleteCluster the  Cluster from database
 func (v *ClusterClient) DeleteCluster(provider, name string) error {
 	//Construct key and tag to select the entry
@@ -444,6 +632,16 @@ func (v *ClusterClient) DeleteCluster(provider, name string) error {
 		}
 	}
 
+	// The discovered KV pairs are maintained by clm, remove them first
+	err = db.DBconn.Remove(v.db.storeName, ClusterKvPairsKey{
+		ClusterProviderName: provider,
+		ClusterName:         name,
+		ClusterKvPairsName:  DiscoveredKvPairsName,
+	})
+	if err != nil {
+		return pkgerrors.Wrap(err, "Delete discovered ClusterKvPairs Entry;")
+	}
+
 	err = db.DBconn.Remove(v.db.storeName, key)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Delete Cluster Entry;")
@@ -561,6 +759,10 @@ func (v *ClusterClient) CreateClusterKvPairs(provider string, cluster string, p
 		ClusterKvPairsName:  p.Metadata.Name,
 	}
 
+	if p.Metadata.Name == DiscoveredKvPairsName {
+		return ClusterKvPairs{}, pkgerrors.New("Cluster KV Pair name " + DiscoveredKvPairsName + " is reserved")
+	}
+
 	//Verify Cluster already exists
 	_, err := v.GetCluster(provider, cluster)
 	if err != nil {
@@ -652,3 +854,52 @@ func (v *ClusterClient) DeleteClusterKvPairs(provider, cluster, kvpair string) e
 
 	return nil
 }
+
+// ReencryptSecrets re-encrypts the kubeconfigs of all the Clusters with the
+// primary key, and returns the number of re-encrypted kubeconfigs
+func (v *ClusterClient) ReencryptSecrets() (int, error) {
+	providers, err := v.GetClusterProviders()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, p := range providers {
+		clusters, err := v.GetClusters(p.Metadata.Name)
+		if err != nil {
+			return n, err
+		}
+		for _, c := range clusters {
+			key := ClusterKey{
+				ClusterProviderName: p.Metadata.Name,
+				ClusterName:         c.Metadata.Name,
+			}
+			value, err := db.DBconn.Find(v.db.storeName, key, v.db.tagContent)
+			if err != nil {
+				return n, pkgerrors.Wrap(err, "Get Cluster Content")
+			}
+			if len(value) == 0 || value[0] == nil {
+				continue
+			}
+			cc := ClusterContent{}
+			err = db.DBconn.Unmarshal(value[0], &cc)
+			if err != nil {
+				return n, pkgerrors.Wrap(err, "Unmarshalling Value")
+			}
+			var changed bool
+			cc.Kubeconfig, changed, err = secret.Reencrypt(cc.Kubeconfig)
+			if err != nil {
+				return n, pkgerrors.Wrapf(err, "Re-encrypting kubeconfig of Cluster %s", c.Metadata.Name)
+			}
+			if !changed {
+				continue
+			}
+			err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagContent, cc)
+			if err != nil {
+				return n, pkgerrors.Wrap(err, "Updating Cluster Content")
+			}
+			n++
+		}
+	}
+	return n, nil
+}
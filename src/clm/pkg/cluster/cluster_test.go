@@ -0,0 +1,98 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
+)
+
+func TestGetClusterContentEncrypted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	k, err := secret.NewLocalKMS(filepath.Join(dir, "keys.json"))
+	if err != nil {
+		t.Fatalf("NewLocalKMS returned an error: %s", err)
+	}
+	secret.SetKeyProvider(k)
+	defer secret.SetKeyProvider(nil)
+
+	encrypted, err := secret.Encrypt("a3ViZWNvbmZpZw==")
+	if err != nil {
+		t.Fatalf("Encrypt returned an error: %s", err)
+	}
+	key := fmt.Sprintf("%v", ClusterKey{ClusterProviderName: "p1", ClusterName: "c1"})
+	db.DBconn = &db.MockDB{
+		Items: map[string]map[string][]byte{
+			key: {
+				"clustercontent": []byte(fmt.Sprintf(`{"kubeconfig":%q}`, encrypted)),
+			},
+		},
+	}
+
+	cc, err := NewClusterClient().GetClusterContent("p1", "c1")
+	if err != nil {
+		t.Fatalf("GetClusterContent returned an error: %s", err)
+	}
+	if cc.Kubeconfig != "a3ViZWNvbmZpZw==" {
+		t.Errorf("Expected the decrypted kubeconfig, got %s", cc.Kubeconfig)
+	}
+}
+
+func TestClusterCordon(t *testing.T) {
+	key := fmt.Sprintf("%v", ClusterKey{ClusterProviderName: "p1", ClusterName: "c1"})
+	db.DBconn = &db.MockDB{
+		Items: map[string]map[string][]byte{
+			key: {
+				"clustermetadata": []byte(`{"metadata":{"name":"c1"}}`),
+				"clustercordon":   []byte(`{"cordoned":true}`),
+			},
+		},
+		Versions: map[string]int64{key: 3},
+	}
+
+	c := NewClusterClient()
+	cl, err := c.GetCluster("p1", "c1")
+	if err != nil {
+		t.Fatalf("GetCluster returned an error: %s", err)
+	}
+	if !cl.Cordoned {
+		t.Errorf("Expected the cluster to be cordoned")
+	}
+	if _, err := c.CordonCluster("p1", "c1", false, ""); err != nil {
+		t.Errorf("CordonCluster returned an error: %s", err)
+	}
+	if _, err := c.CordonCluster("p1", "c1", false, `"2"`); err == nil {
+		t.Errorf("Expected an error cordoning a cluster at a stale version")
+	}
+	if _, err := c.CordonCluster("p1", "c2", true, ""); err == nil {
+		t.Errorf("Expected an error cordoning a missing cluster")
+	}
+	cordoned, err := c.IsClusterCordoned("p1", "c2")
+	if err != nil || cordoned {
+		t.Errorf("Expected a cluster without a cordon record to be uncordoned, got %v %v", cordoned, err)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// ClusterCordon records whether a Cluster is cordoned. A cordoned Cluster
+// is left out of the new placement decisions, and keeps the resources which
+// are already deployed to it.
+type ClusterCordon struct {
+	Cordoned bool `json:"cordoned"`
+}
+
+// CordonCluster cordons or uncordons a Cluster at the version the If-Match
+// header matches, and returns the version the Cluster was stored at
+func (v *ClusterClient) CordonCluster(provider, name string, cordoned bool, ifMatch string) (int64, error) {
+	key := ClusterKey{
+		ClusterProviderName: provider,
+		ClusterName:         name,
+	}
+
+	_, err := v.GetCluster(provider, name)
+	if err != nil {
+		return 0, pkgerrors.New("Cluster does not exist")
+	}
+
+	// The cordon is only written while the Cluster exists, so that a Cluster
+	// deleted meanwhile is not recreated by its cordon record
+	version, err := v.updateExistingCluster(key, v.db.tagCordon, ClusterCordon{Cordoned: cordoned}, ifMatch)
+	if err != nil {
+		return 0, pkgerrors.Wrap(err, "Updating Cluster Cordon")
+	}
+	return version, nil
+}
+
+// IsClusterCordoned tells whether a Cluster is cordoned
+func (v *ClusterClient) IsClusterCordoned(provider, name string) (bool, error) {
+	key := ClusterKey{
+		ClusterProviderName: provider,
+		ClusterName:         name,
+	}
+
+	value, err := db.DBconn.Find(v.db.storeName, key, v.db.tagCordon)
+	if err != nil {
+		return false, pkgerrors.Wrap(err, "Get Cluster Cordon")
+	}
+
+	// A Cluster which has never been cordoned has no cordon record
+	if len(value) == 0 || value[0] == nil {
+		return false, nil
+	}
+
+	c := ClusterCordon{}
+	err = db.DBconn.Unmarshal(value[0], &c)
+	if err != nil {
+		return false, pkgerrors.Wrap(err, "Unmarshalling Cluster Cordon")
+	}
+	return c.Cordoned, nil
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	mtypes "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module/types"
+	pkgerrors "github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DiscoveredKvPairsName is the name of the ClusterKvPairs holding the
+// inventory clm discovers from the kubeconfig of a cluster. It is reserved:
+// the ClusterKvPairs is kept up to date by the health probes and can't be
+// created or deleted through the API.
+const DiscoveredKvPairsName = "discovered"
+
+// Keys of the discovered ClusterKvPairs
+const (
+	DiscoveredK8sVersion        = "k8sVersion"
+	DiscoveredNodeCount         = "nodeCount"
+	DiscoveredAllocatableCPU    = "allocatableCpu"
+	DiscoveredAllocatableMemory = "allocatableMemory"
+	DiscoveredNodeLabels        = "nodeLabels"
+	DiscoveredAPIGroups         = "apiGroups"
+	DiscoveredCRDs              = "crds"
+	DiscoveredStorageClasses    = "storageClasses"
+)
+
+// summarizedLabelExcluded are the node labels which are unique to each node,
+// and are left out of the node labels summary
+var summarizedLabelExcluded = map[string]bool{
+	"kubernetes.io/hostname": true,
+}
+
+// discoverInventory collects the inventory of a cluster, as the kv list of
+// the discovered ClusterKvPairs. The node labels are summarized as the
+// number of nodes with each "key=value" label. Each section of the inventory
+// is discovered independently: the sections which fail keep the values of
+// the stored kv list, and are reported by the error along with the kv list.
+func discoverInventory(cs clientsets, stored []map[string]interface{}) ([]map[string]interface{}, error) {
+	var kv []map[string]interface{}
+	var failed []string
+	sections := []struct {
+		name     string
+		keys     []string
+		discover func(clientsets) ([]map[string]interface{}, error)
+	}{
+		{"server version", []string{DiscoveredK8sVersion}, discoverVersion},
+		{"nodes", []string{DiscoveredNodeCount, DiscoveredAllocatableCPU, DiscoveredAllocatableMemory, DiscoveredNodeLabels}, discoverNodes},
+		{"API groups", []string{DiscoveredAPIGroups}, discoverAPIGroups},
+		{"CRDs", []string{DiscoveredCRDs}, discoverCRDs},
+		{"storage classes", []string{DiscoveredStorageClasses}, discoverStorageClasses},
+	}
+	for _, section := range sections {
+		skv, err := section.discover(cs)
+		if err != nil {
+			failed = append(failed, section.name+": "+err.Error())
+			kv = append(kv, storedKv(stored, section.keys)...)
+			continue
+		}
+		kv = append(kv, skv...)
+	}
+	if len(failed) > 0 {
+		return kv, pkgerrors.Errorf("Discovering %s", strings.Join(failed, "; "))
+	}
+	return kv, nil
+}
+
+// storedKv returns the entries of the stored kv list with one of the keys
+func storedKv(stored []map[string]interface{}, keys []string) []map[string]interface{} {
+	var kv []map[string]interface{}
+	for _, m := range stored {
+		for _, k := range keys {
+			if v, ok := m[k]; ok {
+				kv = append(kv, map[string]interface{}{k: v})
+			}
+		}
+	}
+	return kv
+}
+
+func discoverVersion(cs clientsets) ([]map[string]interface{}, error) {
+	v, err := cs.kube.Discovery().ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	return []map[string]interface{}{{DiscoveredK8sVersion: v.GitVersion}}, nil
+}
+
+func discoverNodes(cs clientsets) ([]map[string]interface{}, error) {
+	nodes, err := cs.kube.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	cpu := resource.Quantity{}
+	memory := resource.Quantity{}
+	labels := map[string]int{}
+	for _, n := range nodes.Items {
+		if q, ok := n.Status.Allocatable[v1.ResourceCPU]; ok {
+			cpu.Add(q)
+		}
+		if q, ok := n.Status.Allocatable[v1.ResourceMemory]; ok {
+			memory.Add(q)
+		}
+		for k, l := range n.Labels {
+			if summarizedLabelExcluded[k] {
+				continue
+			}
+			labels[k+"="+l]++
+		}
+	}
+	return []map[string]interface{}{
+		{DiscoveredNodeCount: len(nodes.Items)},
+		{DiscoveredAllocatableCPU: cpu.String()},
+		{DiscoveredAllocatableMemory: memory.String()},
+		{DiscoveredNodeLabels: labels},
+	}, nil
+}
+
+func discoverAPIGroups(cs clientsets) ([]map[string]interface{}, error) {
+	groups, err := cs.kube.Discovery().ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+	apiGroups := []string{}
+	for _, g := range groups.Groups {
+		for _, gv := range g.Versions {
+			apiGroups = append(apiGroups, gv.GroupVersion)
+		}
+	}
+	sort.Strings(apiGroups)
+	return []map[string]interface{}{{DiscoveredAPIGroups: apiGroups}}, nil
+}
+
+func discoverCRDs(cs clientsets) ([]map[string]interface{}, error) {
+	crdList, err := cs.apiext.ApiextensionsV1().CustomResourceDefinitions().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	crds := []string{}
+	for _, c := range crdList.Items {
+		crds = append(crds, c.Name)
+	}
+	sort.Strings(crds)
+	return []map[string]interface{}{{DiscoveredCRDs: crds}}, nil
+}
+
+func discoverStorageClasses(cs clientsets) ([]map[string]interface{}, error) {
+	scList, err := cs.kube.StorageV1().StorageClasses().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	storageClasses := []string{}
+	for _, sc := range scList.Items {
+		storageClasses = append(storageClasses, sc.Name)
+	}
+	sort.Strings(storageClasses)
+	return []map[string]interface{}{{DiscoveredStorageClasses: storageClasses}}, nil
+}
+
+// setDiscoveredKvPairs records the discovered inventory of a Cluster
+func (v *ClusterClient) setDiscoveredKvPairs(provider, cluster string, kv []map[string]interface{}) error {
+	key := ClusterKvPairsKey{
+		ClusterProviderName: provider,
+		ClusterName:         cluster,
+		ClusterKvPairsName:  DiscoveredKvPairsName,
+	}
+	p := ClusterKvPairs{
+		Metadata: mtypes.Metadata{
+			Name:        DiscoveredKvPairsName,
+			Description: "Inventory discovered by clm from the cluster",
+		},
+		Spec: ClusterKvSpec{Kv: kv},
+	}
+
+	err := db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Updating discovered Cluster KV Pairs")
+	}
+	return nil
+}
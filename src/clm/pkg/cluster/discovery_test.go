@@ -0,0 +1,129 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestDiscoverInventory(t *testing.T) {
+	n1 := node("n1", v1.ConditionTrue)
+	n1.Labels = map[string]string{"kubernetes.io/hostname": "n1", "gpu": "true"}
+	n1.Status.Allocatable = v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+	n2 := node("n2", v1.ConditionTrue)
+	n2.Labels = map[string]string{"kubernetes.io/hostname": "n2", "gpu": "true"}
+	n2.Status.Allocatable = v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2500m"),
+		v1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+	sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard"}}
+	crd := &apiextv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "networkchainings.k8s.plugin.opnfv.org"}}
+
+	kube := fake.NewSimpleClientset(n1, n2, sc)
+	kube.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1"},
+		{GroupVersion: "apps/v1"},
+	}
+	cs := clientsets{kube: kube, apiext: apiextfake.NewSimpleClientset(crd)}
+
+	kv, err := discoverInventory(cs, nil)
+	if err != nil {
+		t.Fatalf("discoverInventory returned an error: %s", err)
+	}
+	got := map[string]interface{}{}
+	for _, m := range kv {
+		for k, v := range m {
+			got[k] = v
+		}
+	}
+
+	expected := map[string]interface{}{
+		DiscoveredNodeCount:         2,
+		DiscoveredAllocatableCPU:    "6500m",
+		DiscoveredAllocatableMemory: "16Gi",
+		DiscoveredNodeLabels:        map[string]int{"gpu=true": 2},
+		DiscoveredCRDs:              []string{"networkchainings.k8s.plugin.opnfv.org"},
+		DiscoveredStorageClasses:    []string{"standard"},
+	}
+	for k, e := range expected {
+		if !reflect.DeepEqual(got[k], e) {
+			t.Errorf("Expected %s to be %v, got %v", k, e, got[k])
+		}
+	}
+	groups := strings.Join(got[DiscoveredAPIGroups].([]string), ",")
+	if !strings.Contains(groups, "apps/v1") {
+		t.Errorf("Expected the API groups to include apps/v1, got %s", groups)
+	}
+}
+
+func TestDiscoverInventoryPartial(t *testing.T) {
+	kube := fake.NewSimpleClientset(node("n1", v1.ConditionTrue))
+	apiext := apiextfake.NewSimpleClientset()
+	apiext.PrependReactor("list", "customresourcedefinitions", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, pkgerrors.New("forbidden")
+	})
+
+	stored := []map[string]interface{}{
+		{DiscoveredNodeCount: 3},
+		{DiscoveredCRDs: []string{"networkchainings.k8s.plugin.opnfv.org"}},
+	}
+	kv, err := discoverInventory(clientsets{kube: kube, apiext: apiext}, stored)
+	if err == nil || !strings.Contains(err.Error(), "CRDs") {
+		t.Errorf("Expected the CRDs to be reported as failed, got %v", err)
+	}
+	got := map[string]interface{}{}
+	for _, m := range kv {
+		for k, v := range m {
+			got[k] = v
+		}
+	}
+	if !reflect.DeepEqual(got[DiscoveredCRDs], stored[1][DiscoveredCRDs]) {
+		t.Errorf("Expected the CRDs to keep their stored value, got %v", got[DiscoveredCRDs])
+	}
+	if got[DiscoveredNodeCount] != 1 {
+		t.Errorf("Expected the other sections to be discovered, got %v", got)
+	}
+}
+
+func TestDiscoveredKvPairsReserved(t *testing.T) {
+	db.DBconn = &db.MockDB{}
+	p := ClusterKvPairs{}
+	p.Metadata.Name = DiscoveredKvPairsName
+	_, err := NewClusterClient().CreateClusterKvPairs("p1", "c1", p)
+	if err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("Expected the discovered name to be reserved, got %v", err)
+	}
+}
@@ -0,0 +1,360 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"encoding/base64"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	pkgerrors "github.com/pkg/errors"
+	apiextclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Readiness states reported for a cluster
+const (
+	ClusterStateUnknown  = "Unknown"
+	ClusterStateReady    = "Ready"
+	ClusterStateNotReady = "NotReady"
+)
+
+// clusterProbeTimeout bounds the requests of a single probe of a cluster
+const clusterProbeTimeout = 10 * time.Second
+
+// ClusterStatus is the result of the most recent health probe of a cluster.
+// A cluster is Ready when its API server answers and at least one of its
+// nodes is ready, and Unknown until it has been probed.
+type ClusterStatus struct {
+	State         string    `json:"state"`
+	LastProbeTime time.Time `json:"lastProbeTime,omitempty"`
+	K8sVersion    string    `json:"k8sVersion,omitempty"`
+	NodesReady    int       `json:"nodesReady"`
+	NodesTotal    int       `json:"nodesTotal"`
+	Message       string    `json:"message,omitempty"`
+}
+
+// clientsets are the clients of a cluster used by the health probes and
+// the inventory discovery
+type clientsets struct {
+	kube   kubernetes.Interface
+	apiext apiextclientset.Interface
+}
+
+// newClientsets returns the clientsets for the base64 encoded kubeconfig of
+// a cluster. It is a variable so that unit tests can replace it.
+var newClientsets = func(kubeconfig string) (clientsets, error) {
+	kc, err := base64.StdEncoding.DecodeString(kubeconfig)
+	if err != nil {
+		return clientsets{}, pkgerrors.Wrap(err, "Decoding kubeconfig")
+	}
+	rc, err := clientcmd.RESTConfigFromKubeConfig(kc)
+	if err != nil {
+		return clientsets{}, pkgerrors.Wrap(err, "Parsing kubeconfig")
+	}
+	rc.Timeout = clusterProbeTimeout
+	kube, err := kubernetes.NewForConfig(rc)
+	if err != nil {
+		return clientsets{}, err
+	}
+	apiext, err := apiextclientset.NewForConfig(rc)
+	if err != nil {
+		return clientsets{}, err
+	}
+	return clientsets{kube: kube, apiext: apiext}, nil
+}
+
+// probeClientset checks the reachability of the API server, the version
+// and the readiness of the nodes of a cluster
+func probeClientset(cs kubernetes.Interface) ClusterStatus {
+	s := ClusterStatus{
+		State:         ClusterStateNotReady,
+		LastProbeTime: time.Now(),
+	}
+
+	v, err := cs.Discovery().ServerVersion()
+	if err != nil {
+		s.Message = err.Error()
+		return s
+	}
+	s.K8sVersion = v.GitVersion
+
+	nodes, err := cs.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		s.Message = err.Error()
+		return s
+	}
+	s.NodesTotal = len(nodes.Items)
+	for _, n := range nodes.Items {
+		for _, c := range n.Status.Conditions {
+			if c.Type == "Ready" && c.Status == "True" {
+				s.NodesReady++
+				break
+			}
+		}
+	}
+
+	if s.NodesReady == 0 {
+		s.Message = "No node of the cluster is ready"
+		return s
+	}
+	s.State = ClusterStateReady
+	return s
+}
+
+// GetClusterStatus returns the last recorded status of a Cluster
+func (v *ClusterClient) GetClusterStatus(provider, name string) (ClusterStatus, error) {
+	//Construct key and tag to select the entry
+	key := ClusterKey{
+		ClusterProviderName: provider,
+		ClusterName:         name,
+	}
+
+	value, err := db.DBconn.Find(v.db.storeName, key, v.db.tagStatus)
+	if err != nil {
+		return ClusterStatus{}, pkgerrors.Wrap(err, "Get Cluster Status")
+	}
+
+	// A Cluster which has not been probed yet has no status
+	if len(value) == 0 || value[0] == nil {
+		return ClusterStatus{State: ClusterStateUnknown}, nil
+	}
+
+	s := ClusterStatus{}
+	err = db.DBconn.Unmarshal(value[0], &s)
+	if err != nil {
+		return ClusterStatus{}, pkgerrors.Wrap(err, "Unmarshalling Cluster Status")
+	}
+	return s, nil
+}
+
+// ProbeCluster runs a health probe against a Cluster with its stored
+// kubeconfig and records the result. The discovered inventory of a Cluster
+// which answers is refreshed as well, with the sections which could be
+// discovered. The other sections keep the values discovered before.
+func (v *ClusterClient) ProbeCluster(provider, name string) (ClusterStatus, error) {
+	content, err := v.GetClusterContent(provider, name)
+	if err != nil {
+		return ClusterStatus{}, err
+	}
+
+	var kv []map[string]interface{}
+	var s ClusterStatus
+	cs, err := newClientsets(content.Kubeconfig)
+	if err != nil {
+		s = ClusterStatus{
+			State:         ClusterStateNotReady,
+			LastProbeTime: time.Now(),
+			Message:       err.Error(),
+		}
+	} else {
+		s = probeClientset(cs.kube)
+		if s.K8sVersion != "" {
+			var stored []map[string]interface{}
+			if p, err := v.GetClusterKvPairs(provider, name, DiscoveredKvPairsName); err == nil {
+				stored = p.Spec.Kv
+			}
+			kv, err = discoverInventory(cs, stored)
+			if err != nil {
+				log.Warn("Cluster inventory discovery failed", log.Fields{
+					"ClusterProvider": provider,
+					"Cluster":         name,
+					"Error":           err,
+				})
+			}
+		}
+	}
+	if s.State != ClusterStateReady {
+		log.Warn("Cluster health probe failed", log.Fields{
+			"ClusterProvider": provider,
+			"Cluster":         name,
+			"State":           s.State,
+			"Message":         s.Message,
+		})
+	}
+
+	key := ClusterKey{
+		ClusterProviderName: provider,
+		ClusterName:         name,
+	}
+	// The discovered KV pairs are written before the status, which is only
+	// written if the Cluster still exists, so that the KV pairs written for
+	// a Cluster deleted while it was probed can be removed
+	if kv != nil {
+		err = v.setDiscoveredKvPairs(provider, name, kv)
+		if err != nil {
+			return ClusterStatus{}, err
+		}
+	}
+	_, err = v.updateExistingCluster(key, v.db.tagStatus, s, "")
+	if err != nil {
+		if kv != nil {
+			db.DBconn.Remove(v.db.storeName, ClusterKvPairsKey{
+				ClusterProviderName: provider,
+				ClusterName:         name,
+				ClusterKvPairsName:  DiscoveredKvPairsName,
+			})
+		}
+		return ClusterStatus{}, err
+	}
+	return s, nil
+}
+
+// updateExistingCluster updates the tag of a Cluster, unlike Insert only if
+// the Cluster exists. With an If-Match header the tag is only updated at the
+// version the header matches. It returns the version the Cluster was stored at.
+func (v *ClusterClient) updateExistingCluster(key ClusterKey, tag string, data interface{}, ifMatch string) (int64, error) {
+	for i := 0; i < 3; i++ {
+		version, err := db.DBconn.GetVersion(v.db.storeName, key)
+		if err != nil {
+			return 0, pkgerrors.Wrap(err, "Get Cluster version")
+		}
+		if !db.IfMatch(ifMatch, version) {
+			return 0, pkgerrors.Wrap(&db.VersionConflictError{Version: version},
+				"The Cluster does not match If-Match "+ifMatch)
+		}
+		version, err = db.DBconn.InsertIfVersion(v.db.storeName, key, nil, tag, data, version)
+		if _, ok := err.(*db.VersionConflictError); !ok {
+			if err != nil {
+				return 0, pkgerrors.Wrap(err, "Updating Cluster")
+			}
+			return version, nil
+		}
+		// The Cluster was either updated or deleted meanwhile
+		_, err = v.GetCluster(key.ClusterProviderName, key.ClusterName)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return 0, pkgerrors.New("Updating Cluster: the Cluster keeps changing")
+}
+
+// getHealthCheckInterval returns the configured cluster probe period
+func getHealthCheckInterval() time.Duration {
+	i := config.GetConfiguration().ClusterHealthInterval
+	d, err := time.ParseDuration(i)
+	if err != nil || d <= 0 {
+		log.Warn("Invalid cluster health check interval, using default", log.Fields{
+			"Interval": i,
+		})
+		return 60 * time.Second
+	}
+	return d
+}
+
+// getHealthCheckWorkers returns the configured number of the Clusters
+// probed at a time
+func getHealthCheckWorkers() int {
+	w := config.GetConfiguration().ClusterHealthWorkers
+	n, err := strconv.Atoi(w)
+	if err != nil || n <= 0 {
+		log.Warn("Invalid cluster health check workers, using default", log.Fields{
+			"Workers": w,
+		})
+		return 10
+	}
+	return n
+}
+
+// probe is a Cluster to probe
+type probe struct {
+	provider, name string
+}
+
+// runProbes probes the Clusters received from probes with a pool of workers,
+// so that unreachable Clusters do not delay the others, until probes is closed
+func (v *ClusterClient) runProbes(workers int, probes <-chan probe) {
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range probes {
+				_, err := v.ProbeCluster(p.provider, p.name)
+				if err != nil {
+					log.Error("Error probing cluster", log.Fields{
+						"ClusterProvider": p.provider,
+						"Cluster":         p.name,
+						"Error":           err,
+					})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// probeClusters probes every Cluster of every ClusterProvider
+func (v *ClusterClient) probeClusters(workers int) {
+	providers, err := v.GetClusterProviders()
+	if err != nil {
+		log.Error("Error getting cluster providers for health check", log.Fields{
+			"Error": err,
+		})
+		return
+	}
+
+	probes := make(chan probe)
+	go func() {
+		defer close(probes)
+		for _, p := range providers {
+			clusters, err := v.GetClusters(p.Metadata.Name)
+			if err != nil {
+				log.Error("Error getting clusters for health check", log.Fields{
+					"ClusterProvider": p.Metadata.Name,
+					"Error":           err,
+				})
+				continue
+			}
+			for _, c := range clusters {
+				probes <- probe{provider: p.Metadata.Name, name: c.Metadata.Name}
+			}
+		}
+	}()
+	v.runProbes(workers, probes)
+}
+
+// ProbeClusters probes the named Clusters of the provider with as many
+// workers as the periodic health checks, and returns once they are probed
+func (v *ClusterClient) ProbeClusters(provider string, names []string) {
+	probes := make(chan probe)
+	go func() {
+		defer close(probes)
+		for _, name := range names {
+			probes <- probe{provider: provider, name: name}
+		}
+	}()
+	v.runProbes(getHealthCheckWorkers(), probes)
+}
+
+// StartHealthChecks periodically probes every Cluster
+func (v *ClusterClient) StartHealthChecks() {
+	interval := getHealthCheckInterval()
+	workers := getHealthCheckWorkers()
+	go func() {
+		for {
+			v.probeClusters(workers)
+			time.Sleep(interval)
+		}
+	}()
+}
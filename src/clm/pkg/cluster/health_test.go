@@ -0,0 +1,193 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	pkgerrors "github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apiextfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func node(name string, ready v1.ConditionStatus) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+func TestProbeClientset(t *testing.T) {
+	testCases := []struct {
+		label         string
+		nodes         []*v1.Node
+		expectedState string
+		expectedReady int
+		expectedTotal int
+	}{
+		{
+			label:         "Cluster with a ready node",
+			nodes:         []*v1.Node{node("n1", v1.ConditionTrue), node("n2", v1.ConditionFalse)},
+			expectedState: ClusterStateReady,
+			expectedReady: 1,
+			expectedTotal: 2,
+		},
+		{
+			label:         "Cluster without ready nodes",
+			nodes:         []*v1.Node{node("n1", v1.ConditionFalse)},
+			expectedState: ClusterStateNotReady,
+			expectedReady: 0,
+			expectedTotal: 1,
+		},
+		{
+			label:         "Cluster without nodes",
+			expectedState: ClusterStateNotReady,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			cs := fake.NewSimpleClientset()
+			for _, n := range testCase.nodes {
+				cs.Tracker().Add(n)
+			}
+			cs.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.18.2"}
+
+			s := probeClientset(cs)
+			if s.State != testCase.expectedState {
+				t.Errorf("Expected state %s, got %s", testCase.expectedState, s.State)
+			}
+			if s.NodesReady != testCase.expectedReady || s.NodesTotal != testCase.expectedTotal {
+				t.Errorf("Expected %d/%d nodes ready, got %d/%d", testCase.expectedReady, testCase.expectedTotal, s.NodesReady, s.NodesTotal)
+			}
+			if s.K8sVersion != "v1.18.2" {
+				t.Errorf("Expected version v1.18.2, got %s", s.K8sVersion)
+			}
+			if s.LastProbeTime.IsZero() {
+				t.Errorf("Expected the probe time to be set")
+			}
+		})
+	}
+}
+
+func TestNewClientsets(t *testing.T) {
+	_, err := newClientsets("not base64")
+	if err == nil {
+		t.Errorf("Expected an error for a kubeconfig which is not base64 encoded")
+	}
+
+	_, err = newClientsets(base64.StdEncoding.EncodeToString([]byte("not a kubeconfig")))
+	if err == nil {
+		t.Errorf("Expected an error for an invalid kubeconfig")
+	}
+}
+
+func TestProbeCluster(t *testing.T) {
+	key := fmt.Sprintf("%v", ClusterKey{ClusterProviderName: "p1", ClusterName: "c1"})
+	db.DBconn = &db.MockDB{
+		Items: map[string]map[string][]byte{
+			key: {
+				"clustermetadata": []byte(`{"metadata":{"name":"c1"}}`),
+				"clustercontent":  []byte(`{"kubeconfig":"a3ViZWNvbmZpZw=="}`),
+			},
+		},
+	}
+
+	saved := newClientsets
+	defer func() { newClientsets = saved }()
+
+	newClientsets = func(kubeconfig string) (clientsets, error) {
+		return clientsets{}, pkgerrors.New("Parsing kubeconfig")
+	}
+	s, err := NewClusterClient().ProbeCluster("p1", "c1")
+	if err != nil {
+		t.Fatalf("ProbeCluster returned an error: %s", err)
+	}
+	if s.State != ClusterStateNotReady || s.Message == "" {
+		t.Errorf("Expected an invalid kubeconfig to be NotReady with a message, got %v", s)
+	}
+
+	newClientsets = func(kubeconfig string) (clientsets, error) {
+		return clientsets{
+			kube:   fake.NewSimpleClientset(node("n1", v1.ConditionTrue)),
+			apiext: apiextfake.NewSimpleClientset(),
+		}, nil
+	}
+	s, err = NewClusterClient().ProbeCluster("p1", "c1")
+	if err != nil {
+		t.Fatalf("ProbeCluster returned an error: %s", err)
+	}
+	if s.State != ClusterStateReady || s.NodesReady != 1 || s.NodesTotal != 1 {
+		t.Errorf("Expected the cluster to be Ready, got %v", s)
+	}
+}
+
+func TestGetClusterStatus(t *testing.T) {
+	// The mock database keys its items with the string of the key
+	key := fmt.Sprintf("%v", ClusterKey{ClusterProviderName: "p1", ClusterName: "c1"})
+
+	t.Run("Cluster not probed yet", func(t *testing.T) {
+		db.DBconn = &db.MockDB{
+			Items: map[string]map[string][]byte{
+				key: {
+					"clustermetadata": []byte(`{"metadata":{"name":"c1"}}`),
+				},
+			},
+		}
+		cl, err := NewClusterClient().GetCluster("p1", "c1")
+		if err != nil {
+			t.Fatalf("GetCluster returned an error: %s", err)
+		}
+		if cl.Status == nil || cl.Status.State != ClusterStateUnknown {
+			t.Errorf("Expected the status to be Unknown, got %v", cl.Status)
+		}
+	})
+
+	t.Run("Cluster probed", func(t *testing.T) {
+		db.DBconn = &db.MockDB{
+			Items: map[string]map[string][]byte{
+				key: {
+					"clustermetadata": []byte(`{"metadata":{"name":"c1"}}`),
+					"clusterstatus":   []byte(`{"state":"NotReady","nodesReady":0,"nodesTotal":3}`),
+				},
+			},
+		}
+		cl, err := NewClusterClient().GetCluster("p1", "c1")
+		if err != nil {
+			t.Fatalf("GetCluster returned an error: %s", err)
+		}
+		if cl.Status == nil || cl.Status.State != ClusterStateNotReady || cl.Status.NodesTotal != 3 {
+			t.Errorf("Expected the recorded status, got %v", cl.Status)
+		}
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		db.DBconn = &db.MockDB{Err: pkgerrors.New("unavailable")}
+		if _, err := NewClusterClient().GetClusterStatus("p1", "c1"); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
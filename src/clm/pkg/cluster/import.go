@@ -0,0 +1,407 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
+	mtypes "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module/types"
+	pkgerrors "github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// importManifestFile is the manifest of a tarball import, when it is not
+// sent with the tarball
+const importManifestFile = "manifest.json"
+
+// maxImportFileSize bounds the size of the files extracted from a tarball,
+// maxImportTotalSize the size of all of them and maxImportEntries the number
+// of the entries of the tarball
+const (
+	maxImportFileSize  = 16 << 20
+	maxImportTotalSize = 64 << 20
+	maxImportEntries   = 1024
+)
+
+// Results of the import of a cluster
+const (
+	ClusterImportCreated = "created"
+	ClusterImportUpdated = "updated"
+	ClusterImportFailed  = "failed"
+)
+
+// ClusterImportManifest describes the clusters of a bulk import
+type ClusterImportManifest struct {
+	Clusters []ClusterImportEntry `json:"clusters"`
+}
+
+// ClusterImportEntry describes a cluster of a bulk import. Its kubeconfig is
+// the Kubeconfig file of the tarball, or the uploaded kubeconfig. When
+// Context is set, or the kubeconfig was uploaded, the cluster is registered
+// with the context of that kubeconfig only, which defaults to the name of
+// the cluster. The Labels are "key" or "key=value".
+type ClusterImportEntry struct {
+	Metadata   mtypes.Metadata  `json:"metadata"`
+	Kubeconfig string           `json:"kubeconfig,omitempty"`
+	Context    string           `json:"context,omitempty"`
+	Labels     []string         `json:"labels,omitempty"`
+	KvPairs    []ClusterKvPairs `json:"kv-pairs,omitempty"`
+}
+
+// ClusterImport is a cluster to import, with its kubeconfig, labels and kv
+// pairs. Err is the reason the cluster can't be imported.
+type ClusterImport struct {
+	Cluster Cluster
+	Content ClusterContent
+	Labels  []ClusterLabel
+	KvPairs []ClusterKvPairs
+	Err     error
+}
+
+// ClusterImportResult reports the import of a cluster
+type ClusterImportResult struct {
+	Name     string   `json:"name"`
+	Status   string   `json:"status"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ParseClusterImport returns the clusters of a bulk import. The file is
+// either a kubeconfig, or a tarball of kubeconfigs (optionally gzipped).
+// Without a manifest, every context of a kubeconfig is imported as the
+// cluster of the same name, and a tarball must hold a manifest.json.
+func ParseClusterImport(manifest, file []byte) ([]ClusterImport, error) {
+	files, err := readTarball(file)
+	if err != nil {
+		return nil, err
+	}
+
+	m := ClusterImportManifest{}
+	switch {
+	case len(manifest) > 0:
+		err = json.Unmarshal(manifest, &m)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Invalid manifest")
+		}
+	case files != nil:
+		content, ok := files[importManifestFile]
+		if !ok {
+			return nil, pkgerrors.New("Missing " + importManifestFile + " in the tarball")
+		}
+		err = json.Unmarshal(content, &m)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Invalid "+importManifestFile)
+		}
+	default:
+		kc, err := clientcmd.Load(file)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Invalid kubeconfig")
+		}
+		names := []string{}
+		for name := range kc.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			e := ClusterImportEntry{}
+			e.Metadata.Name = name
+			m.Clusters = append(m.Clusters, e)
+		}
+	}
+	if len(m.Clusters) == 0 {
+		return nil, pkgerrors.New("No cluster to import")
+	}
+
+	var imports []ClusterImport
+	for _, e := range m.Clusters {
+		imp := ClusterImport{
+			Cluster: Cluster{Metadata: e.Metadata},
+			KvPairs: e.KvPairs,
+		}
+		for _, l := range e.Labels {
+			kv := strings.SplitN(l, "=", 2)
+			label := ClusterLabel{LabelName: kv[0]}
+			if len(kv) == 2 {
+				label.Value = kv[1]
+			}
+			imp.Labels = append(imp.Labels, label)
+		}
+		content, err := entryKubeconfig(e, file, files)
+		if err != nil {
+			imp.Err = err
+		}
+		imp.Content.Kubeconfig = base64.StdEncoding.EncodeToString(content)
+		imports = append(imports, imp)
+	}
+	return imports, nil
+}
+
+// readTarball returns the regular files of a tarball, or nil when the file
+// is not a tarball
+func readTarball(file []byte) (map[string][]byte, error) {
+	var r io.Reader = bytes.NewReader(file)
+	gzipped := len(file) > 2 && file[0] == 0x1f && file[1] == 0x8b
+	if gzipped {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Invalid gzip file")
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	files := map[string][]byte{}
+	var entries, total int64
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Not a tarball, unless it was gzipped
+			if !gzipped && len(files) == 0 {
+				return nil, nil
+			}
+			return nil, pkgerrors.Wrap(err, "Invalid tarball")
+		}
+		entries++
+		if entries > maxImportEntries {
+			return nil, pkgerrors.Errorf("The tarball has more than %d entries", maxImportEntries)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		if h.Size > maxImportFileSize {
+			return nil, pkgerrors.Errorf("File %s of the tarball is too large", h.Name)
+		}
+		if total+h.Size > maxImportTotalSize {
+			return nil, pkgerrors.New("The files of the tarball are too large")
+		}
+		content, err := ioutil.ReadAll(io.LimitReader(tr, maxImportFileSize))
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Invalid tarball")
+		}
+		total += int64(len(content))
+		files[path.Clean(h.Name)] = content
+	}
+	if !gzipped && len(files) == 0 {
+		return nil, nil
+	}
+	return files, nil
+}
+
+// entryKubeconfig returns the kubeconfig of a cluster of an import
+func entryKubeconfig(e ClusterImportEntry, file []byte, files map[string][]byte) ([]byte, error) {
+	content := file
+	context := e.Context
+	if files != nil {
+		if e.Kubeconfig == "" {
+			return nil, pkgerrors.New("Missing kubeconfig file in the manifest")
+		}
+		c, ok := files[path.Clean(e.Kubeconfig)]
+		if !ok {
+			return nil, pkgerrors.Errorf("Kubeconfig file %s is not in the tarball", e.Kubeconfig)
+		}
+		content = c
+	} else if context == "" {
+		context = e.Metadata.Name
+	}
+	if context == "" {
+		return content, nil
+	}
+
+	return contextKubeconfig(content, context)
+}
+
+// contextKubeconfig returns the kubeconfig with only the context, and its
+// cluster and user, of a kubeconfig
+func contextKubeconfig(content []byte, context string) ([]byte, error) {
+	kc := clientcmdv1.Config{}
+	err := yaml.Unmarshal(content, &kc)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Invalid kubeconfig")
+	}
+
+	out := clientcmdv1.Config{
+		APIVersion:     "v1",
+		Kind:           "Config",
+		CurrentContext: context,
+		Preferences:    kc.Preferences,
+	}
+	var ctx *clientcmdv1.Context
+	for _, c := range kc.Contexts {
+		if c.Name == context {
+			out.Contexts = append(out.Contexts, c)
+			ctx = &c.Context
+			break
+		}
+	}
+	if ctx == nil {
+		return nil, pkgerrors.Errorf("Context %s is not in the kubeconfig", context)
+	}
+	for _, c := range kc.Clusters {
+		if c.Name == ctx.Cluster {
+			out.Clusters = append(out.Clusters, c)
+		}
+	}
+	for _, a := range kc.AuthInfos {
+		if a.Name == ctx.AuthInfo {
+			out.AuthInfos = append(out.AuthInfos, a)
+		}
+	}
+
+	content, err = yaml.Marshal(out)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Writing kubeconfig")
+	}
+	return content, nil
+}
+
+// ImportClusters creates or updates the clusters of a bulk import, with
+// their labels and kv pairs. The clusters are imported independently, and
+// the result of each is reported. Existing labels and kv pairs which are not
+// in the import are kept.
+func (v *ClusterClient) ImportClusters(provider string, imports []ClusterImport) ([]ClusterImportResult, error) {
+	_, err := v.GetClusterProvider(provider)
+	if err != nil {
+		return nil, pkgerrors.New("ClusterProvider does not exist")
+	}
+
+	results := []ClusterImportResult{}
+	seen := map[string]bool{}
+	for _, imp := range imports {
+		name := imp.Cluster.Metadata.Name
+		r := ClusterImportResult{Name: name}
+		if seen[name] {
+			imp.Err = pkgerrors.New("Duplicate cluster in the import")
+		}
+		seen[name] = true
+
+		if imp.Err == nil {
+			r.Status, r.Warnings, imp.Err = v.importCluster(provider, imp)
+		}
+		if imp.Err != nil {
+			r.Status = ClusterImportFailed
+			r.Error = imp.Err.Error()
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// importCluster creates or updates a cluster of an import, and returns
+// whether it was created or updated, and the warnings about its kubeconfig
+func (v *ClusterClient) importCluster(provider string, imp ClusterImport) (string, []string, error) {
+	content, err := base64.StdEncoding.DecodeString(imp.Content.Kubeconfig)
+	if err != nil {
+		return "", nil, pkgerrors.Wrap(err, "Decoding kubeconfig")
+	}
+	warnings, err := ValidateKubeconfig(content)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, p := range imp.KvPairs {
+		if p.Metadata.Name == DiscoveredKvPairsName {
+			return "", nil, pkgerrors.New("Cluster KV Pair name " + DiscoveredKvPairsName + " is reserved")
+		}
+	}
+
+	c := imp.Cluster
+	c.Warnings = warnings
+	status := ClusterImportCreated
+	_, err = v.GetCluster(provider, c.Metadata.Name)
+	if err == nil {
+		status = ClusterImportUpdated
+		err = v.updateCluster(provider, c, imp.Content)
+	} else {
+		_, err = v.CreateCluster(provider, c, imp.Content)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, l := range imp.Labels {
+		cur, err := v.GetClusterLabel(provider, c.Metadata.Name, l.LabelName)
+		if err != nil {
+			_, err = v.CreateClusterLabel(provider, c.Metadata.Name, l)
+		} else if cur.Value != l.Value {
+			key := ClusterLabelKey{
+				ClusterProviderName: provider,
+				ClusterName:         c.Metadata.Name,
+				ClusterLabelName:    l.LabelName,
+			}
+			err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagMeta, l)
+		}
+		if err != nil {
+			return "", nil, pkgerrors.Wrapf(err, "Adding label %s", l.LabelName)
+		}
+	}
+	for _, p := range imp.KvPairs {
+		key := ClusterKvPairsKey{
+			ClusterProviderName: provider,
+			ClusterName:         c.Metadata.Name,
+			ClusterKvPairsName:  p.Metadata.Name,
+		}
+		err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
+		if err != nil {
+			return "", nil, pkgerrors.Wrapf(err, "Adding KV pairs %s", p.Metadata.Name)
+		}
+	}
+	return status, warnings, nil
+}
+
+// updateCluster replaces the metadata and the kubeconfig of an existing
+// Cluster
+func (v *ClusterClient) updateCluster(provider string, p Cluster, q ClusterContent) error {
+	key := ClusterKey{
+		ClusterProviderName: provider,
+		ClusterName:         p.Metadata.Name,
+	}
+
+	// The status is recorded by the health probes, and the cordon is
+	// changed through its own requests
+	p.Status = nil
+	p.Cordoned = false
+
+	err := db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Updating DB Entry")
+	}
+	q.Kubeconfig, err = secret.Encrypt(q.Kubeconfig)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Encrypting kubeconfig")
+	}
+	err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagContent, q)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Updating DB Entry")
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const multiContextKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://10.0.0.1:6443
+  name: edge1
+- cluster:
+    server: https://10.0.0.2:6443
+  name: edge2
+contexts:
+- context:
+    cluster: edge1
+    user: admin1
+  name: edge1
+- context:
+    cluster: edge2
+    user: admin2
+  name: edge2
+current-context: edge1
+users:
+- name: admin1
+  user:
+    token: abc
+- name: admin2
+  user:
+    token: def
+`
+
+func tarball(t *testing.T, files map[string]string) []byte {
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestParseClusterImportKubeconfig(t *testing.T) {
+	imports, err := ParseClusterImport(nil, []byte(multiContextKubeconfig))
+	if err != nil {
+		t.Fatalf("ParseClusterImport returned an error: %s", err)
+	}
+	if len(imports) != 2 {
+		t.Fatalf("Expected a cluster per context, got %d", len(imports))
+	}
+	for i, name := range []string{"edge1", "edge2"} {
+		imp := imports[i]
+		if imp.Cluster.Metadata.Name != name || imp.Err != nil {
+			t.Fatalf("Expected the cluster %s, got %s %v", name, imp.Cluster.Metadata.Name, imp.Err)
+		}
+		content, _ := base64.StdEncoding.DecodeString(imp.Content.Kubeconfig)
+		kc, err := clientcmd.Load(content)
+		if err != nil {
+			t.Fatalf("Invalid kubeconfig of %s: %s", name, err)
+		}
+		if kc.CurrentContext != name || len(kc.Clusters) != 1 || kc.Clusters[name] == nil || len(kc.AuthInfos) != 1 {
+			t.Errorf("Expected the kubeconfig of %s to have its context only, got %v", name, kc)
+		}
+	}
+
+	manifest := `{"clusters": [
+		{"metadata": {"name": "e1"}, "context": "edge1", "labels": ["edge"]},
+		{"metadata": {"name": "e3"}, "context": "edge3"}
+	]}`
+	imports, err = ParseClusterImport([]byte(manifest), []byte(multiContextKubeconfig))
+	if err != nil {
+		t.Fatalf("ParseClusterImport returned an error: %s", err)
+	}
+	if len(imports) != 2 || imports[0].Err != nil || len(imports[0].Labels) != 1 || imports[0].Labels[0].LabelName != "edge" {
+		t.Fatalf("Unexpected import of e1: %v", imports)
+	}
+	if imports[1].Err == nil || !strings.Contains(imports[1].Err.Error(), "edge3") {
+		t.Errorf("Expected an error for the missing context, got %v", imports[1].Err)
+	}
+
+	if _, err := ParseClusterImport(nil, []byte("not a kubeconfig")); err == nil {
+		t.Errorf("Expected an error for an invalid kubeconfig")
+	}
+}
+
+func TestParseClusterImportTarball(t *testing.T) {
+	manifest := `{"clusters": [
+		{"metadata": {"name": "edge1"}, "kubeconfig": "kubeconfigs/edge1.yaml",
+		 "kv-pairs": [{"metadata": {"name": "site"}, "spec": {"kv": [{"region": "west"}]}}]},
+		{"metadata": {"name": "edge2"}, "kubeconfig": "./kubeconfigs/edge2.yaml", "context": "edge2"},
+		{"metadata": {"name": "edge4"}, "kubeconfig": "kubeconfigs/edge4.yaml"}
+	]}`
+	single := strings.Replace(multiContextKubeconfig, "current-context: edge1", "current-context: edge2", 1)
+	file := tarball(t, map[string]string{
+		"manifest.json":          manifest,
+		"kubeconfigs/edge1.yaml": fmt.Sprintf(kubeconfigTemplate, "", "admin@cluster1", "abc"),
+		"kubeconfigs/edge2.yaml": single,
+	})
+
+	imports, err := ParseClusterImport(nil, file)
+	if err != nil {
+		t.Fatalf("ParseClusterImport returned an error: %s", err)
+	}
+	if len(imports) != 3 {
+		t.Fatalf("Expected the clusters of the manifest, got %d", len(imports))
+	}
+	if imports[0].Err != nil || len(imports[0].KvPairs) != 1 || imports[0].KvPairs[0].Metadata.Name != "site" {
+		t.Errorf("Unexpected import of edge1: %v", imports[0])
+	}
+	content, _ := base64.StdEncoding.DecodeString(imports[1].Content.Kubeconfig)
+	if _, err := ValidateKubeconfig(content); imports[1].Err != nil || err != nil {
+		t.Errorf("Expected the context edge2 to be extracted, got %v %v", imports[1].Err, err)
+	}
+	if imports[2].Err == nil {
+		t.Errorf("Expected an error for the missing kubeconfig file")
+	}
+
+	if _, err := ParseClusterImport(nil, tarball(t, map[string]string{"a.yaml": single})); err == nil {
+		t.Errorf("Expected an error for a tarball without manifest")
+	}
+}
+
+func TestReadTarballLimits(t *testing.T) {
+	many := map[string]string{}
+	for i := 0; i <= maxImportEntries; i++ {
+		many[fmt.Sprintf("f%d", i)] = "x"
+	}
+	if _, err := readTarball(tarball(t, many)); err == nil || !strings.Contains(err.Error(), "entries") {
+		t.Errorf("Expected an error for too many entries, got %v", err)
+	}
+
+	large := map[string]string{}
+	content := strings.Repeat("x", maxImportFileSize)
+	for i := 0; i*maxImportFileSize <= maxImportTotalSize; i++ {
+		large[fmt.Sprintf("f%d", i)] = content
+	}
+	if _, err := readTarball(tarball(t, large)); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("Expected an error for too large files, got %v", err)
+	}
+}
+
+func TestImportClusters(t *testing.T) {
+	kubeconfig := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(kubeconfigTemplate, "", "admin@cluster1", "abc")))
+	db.DBconn = &db.MockDB{
+		Items: map[string]map[string][]byte{
+			fmt.Sprintf("%v", ClusterProviderKey{ClusterProviderName: "p1"}): {
+				"clustermetadata": []byte(`{"metadata":{"name":"p1"}}`),
+			},
+			fmt.Sprintf("%v", ClusterKey{ClusterProviderName: "p1", ClusterName: "c2"}): {
+				"clustermetadata": []byte(`{"metadata":{"name":"c2"}}`),
+			},
+		},
+	}
+
+	imports := []ClusterImport{
+		{Cluster: Cluster{}, Content: ClusterContent{Kubeconfig: kubeconfig}},
+		{Cluster: Cluster{}, Content: ClusterContent{Kubeconfig: kubeconfig}, Labels: []ClusterLabel{{LabelName: "edge"}}},
+		{Cluster: Cluster{}, Content: ClusterContent{Kubeconfig: base64.StdEncoding.EncodeToString([]byte("invalid"))}},
+		{Cluster: Cluster{}, Content: ClusterContent{Kubeconfig: kubeconfig}},
+	}
+	for i, name := range []string{"c1", "c2", "c3", "c1"} {
+		imports[i].Cluster.Metadata.Name = name
+	}
+
+	results, err := NewClusterClient().ImportClusters("p1", imports)
+	if err != nil {
+		t.Fatalf("ImportClusters returned an error: %s", err)
+	}
+	expected := []string{ClusterImportCreated, ClusterImportUpdated, ClusterImportFailed, ClusterImportFailed}
+	for i, r := range results {
+		if r.Status != expected[i] {
+			t.Errorf("Expected %s to be %s, got %v", r.Name, expected[i], r)
+		}
+	}
+	if !strings.Contains(results[3].Error, "Duplicate") {
+		t.Errorf("Expected the duplicate cluster to fail, got %v", results[3])
+	}
+
+	if _, err := NewClusterClient().ImportClusters("p2", imports); err == nil {
+		t.Errorf("Expected an error for a missing cluster provider")
+	}
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// credentialExpiryWarning is how long before their expiry the credentials
+// of a kubeconfig are warned about
+const credentialExpiryWarning = 30 * 24 * time.Hour
+
+// ValidateKubeconfig parses the kubeconfig of a cluster being registered.
+// The kubeconfig must have a current context, and a single cluster. It must
+// not run commands or read files, see checkKubeconfigReferences. It returns
+// the warnings about the credentials of the current context which
+// have expired or will expire soon.
+func ValidateKubeconfig(content []byte) ([]string, error) {
+	kc, err := clientcmd.Load(content)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Invalid kubeconfig")
+	}
+	if kc.CurrentContext == "" {
+		return nil, pkgerrors.New("Invalid kubeconfig: no current-context")
+	}
+	ctx, ok := kc.Contexts[kc.CurrentContext]
+	if !ok {
+		return nil, pkgerrors.Errorf("Invalid kubeconfig: current-context %s is not defined", kc.CurrentContext)
+	}
+	if len(kc.Clusters) != 1 {
+		return nil, pkgerrors.Errorf("Invalid kubeconfig: expected a single cluster, found %d", len(kc.Clusters))
+	}
+	if _, ok := kc.Clusters[ctx.Cluster]; !ok {
+		return nil, pkgerrors.Errorf("Invalid kubeconfig: cluster %s of the current-context is not defined", ctx.Cluster)
+	}
+	err = checkKubeconfigReferences(kc)
+	if err != nil {
+		return nil, err
+	}
+
+	authInfo, ok := kc.AuthInfos[ctx.AuthInfo]
+	if !ok {
+		return nil, nil
+	}
+	return credentialWarnings(authInfo, time.Now()), nil
+}
+
+// checkKubeconfigReferences rejects the kubeconfigs which make clm run a
+// command, like exec credential plugins and auth providers, or read its own
+// files, like the file paths of certificates, keys and tokens. The
+// credentials must be embedded in the kubeconfig.
+func checkKubeconfigReferences(kc *clientcmdapi.Config) error {
+	for name, c := range kc.Clusters {
+		if c.CertificateAuthority != "" {
+			return pkgerrors.Errorf("Invalid kubeconfig: cluster %s refers to the file %s, use certificate-authority-data", name, c.CertificateAuthority)
+		}
+	}
+	for name, u := range kc.AuthInfos {
+		switch {
+		case u.Exec != nil:
+			return pkgerrors.Errorf("Invalid kubeconfig: user %s runs the command %s, exec credential plugins are not supported", name, u.Exec.Command)
+		case u.AuthProvider != nil:
+			return pkgerrors.Errorf("Invalid kubeconfig: user %s uses the auth provider %s, auth providers are not supported", name, u.AuthProvider.Name)
+		case u.ClientCertificate != "":
+			return pkgerrors.Errorf("Invalid kubeconfig: user %s refers to the file %s, use client-certificate-data", name, u.ClientCertificate)
+		case u.ClientKey != "":
+			return pkgerrors.Errorf("Invalid kubeconfig: user %s refers to the file %s, use client-key-data", name, u.ClientKey)
+		case u.TokenFile != "":
+			return pkgerrors.Errorf("Invalid kubeconfig: user %s refers to the file %s, use token", name, u.TokenFile)
+		}
+	}
+	return nil
+}
+
+// credentialWarnings returns the warnings about the client certificate and
+// the token of a user which expire within credentialExpiryWarning of now
+func credentialWarnings(authInfo *clientcmdapi.AuthInfo, now time.Time) []string {
+	var warnings []string
+	warn := func(credential string, expiry time.Time) {
+		switch {
+		case expiry.Before(now):
+			warnings = append(warnings, fmt.Sprintf("The %s expired on %s", credential, expiry.UTC().Format(time.RFC3339)))
+		case expiry.Before(now.Add(credentialExpiryWarning)):
+			warnings = append(warnings, fmt.Sprintf("The %s expires on %s", credential, expiry.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	if len(authInfo.ClientCertificateData) > 0 {
+		if notAfter, ok := certificateExpiry(authInfo.ClientCertificateData); ok {
+			warn("client certificate", notAfter)
+		}
+	}
+	if authInfo.Token != "" {
+		if exp, ok := tokenExpiry(authInfo.Token); ok {
+			warn("token", exp)
+		}
+	}
+	return warnings
+}
+
+// certificateExpiry returns the NotAfter time of the first certificate of
+// PEM encoded data
+func certificateExpiry(data []byte) (time.Time, bool) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return cert.NotAfter, true
+}
+
+// tokenExpiry returns the exp claim of a JWT bearer token. The tokens which
+// are not JWTs, or don't expire, have no expiry.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
+
+// VerifyKubeconfig connects to the cluster of a base64 encoded kubeconfig,
+// and returns its status. It fails when the API server can't be reached.
+func VerifyKubeconfig(kubeconfig string) (ClusterStatus, error) {
+	cs, err := newClientsets(kubeconfig)
+	if err != nil {
+		return ClusterStatus{}, err
+	}
+	s := probeClientset(cs.kube)
+	if s.K8sVersion == "" {
+		return s, pkgerrors.Errorf("Unable to connect to the cluster: %s", s.Message)
+	}
+	return s, nil
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apiextfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const kubeconfigTemplate = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://127.0.0.1:6443
+  name: cluster1
+%s
+contexts:
+- context:
+    cluster: cluster1
+    user: admin
+  name: admin@cluster1
+current-context: %s
+users:
+- name: admin
+  user:
+    token: %s
+`
+
+func TestValidateKubeconfig(t *testing.T) {
+	secondCluster := `- cluster:
+    server: https://127.0.0.2:6443
+  name: cluster2`
+	testCases := []struct {
+		label      string
+		kubeconfig string
+		err        string
+	}{
+		{
+			label:      "Valid Kubeconfig",
+			kubeconfig: fmt.Sprintf(kubeconfigTemplate, "", "admin@cluster1", "abc"),
+		},
+		{
+			label:      "Not A Kubeconfig",
+			kubeconfig: "test contents\nof a file",
+			err:        "Invalid kubeconfig",
+		},
+		{
+			label:      "No Current Context",
+			kubeconfig: fmt.Sprintf(kubeconfigTemplate, "", `""`, "abc"),
+			err:        "no current-context",
+		},
+		{
+			label:      "Undefined Current Context",
+			kubeconfig: fmt.Sprintf(kubeconfigTemplate, "", "other", "abc"),
+			err:        "is not defined",
+		},
+		{
+			label:      "Multiple Clusters",
+			kubeconfig: fmt.Sprintf(kubeconfigTemplate, secondCluster, "admin@cluster1", "abc"),
+			err:        "expected a single cluster, found 2",
+		},
+		{
+			label:      "Exec Credential Plugin",
+			kubeconfig: fmt.Sprintf(kubeconfigTemplate, "", "admin@cluster1", "abc\n    exec:\n      apiVersion: client.authentication.k8s.io/v1beta1\n      command: /bin/sh"),
+			err:        "exec credential plugins are not supported",
+		},
+		{
+			label:      "Auth Provider",
+			kubeconfig: fmt.Sprintf(kubeconfigTemplate, "", "admin@cluster1", "abc\n    auth-provider:\n      name: gcp"),
+			err:        "auth providers are not supported",
+		},
+		{
+			label:      "Client Key File",
+			kubeconfig: fmt.Sprintf(kubeconfigTemplate, "", "admin@cluster1", "abc\n    client-key: /etc/kubernetes/admin.key"),
+			err:        "refers to the file /etc/kubernetes/admin.key",
+		},
+		{
+			label:      "Token File",
+			kubeconfig: fmt.Sprintf(kubeconfigTemplate, "", "admin@cluster1", "abc\n    tokenFile: /var/run/secrets/token"),
+			err:        "refers to the file /var/run/secrets/token",
+		},
+		{
+			label: "Certificate Authority File",
+			kubeconfig: strings.Replace(fmt.Sprintf(kubeconfigTemplate, "", "admin@cluster1", "abc"),
+				"server: https://127.0.0.1:6443", "server: https://127.0.0.1:6443\n    certificate-authority: /etc/kubernetes/ca.crt", 1),
+			err: "refers to the file /etc/kubernetes/ca.crt",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			warnings, err := ValidateKubeconfig([]byte(testCase.kubeconfig))
+			if testCase.err == "" {
+				if err != nil || len(warnings) != 0 {
+					t.Fatalf("Expected a valid kubeconfig without warnings, got %v %v", warnings, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.err) {
+				t.Fatalf("Expected the error %q, got %v", testCase.err, err)
+			}
+		})
+	}
+}
+
+func TestCredentialWarnings(t *testing.T) {
+	now := time.Now()
+	jwt := func(exp time.Time) string {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+		return "eyJhbGciOiJSUzI1NiJ9." + payload + ".c2lnbmF0dXJl"
+	}
+	cert := func(notAfter time.Time) []byte {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "admin"},
+			NotBefore:    now.Add(-time.Hour),
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	warnings := credentialWarnings(&clientcmdapi.AuthInfo{
+		ClientCertificateData: cert(now.Add(365 * 24 * time.Hour)),
+		Token:                 jwt(now.Add(365 * 24 * time.Hour)),
+	}, now)
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warning for long lived credentials, got %v", warnings)
+	}
+
+	warnings = credentialWarnings(&clientcmdapi.AuthInfo{
+		ClientCertificateData: cert(now.Add(24 * time.Hour)),
+		Token:                 jwt(now.Add(-time.Hour)),
+	}, now)
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "client certificate expires") ||
+		!strings.Contains(warnings[1], "token expired") {
+		t.Errorf("Expected warnings for the certificate and the token, got %v", warnings)
+	}
+
+	warnings = credentialWarnings(&clientcmdapi.AuthInfo{Token: "abc"}, now)
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warning for an opaque token, got %v", warnings)
+	}
+}
+
+func TestVerifyKubeconfig(t *testing.T) {
+	saved := newClientsets
+	defer func() { newClientsets = saved }()
+
+	newClientsets = func(kubeconfig string) (clientsets, error) {
+		return clientsets{}, pkgerrors.New("Parsing kubeconfig")
+	}
+	if _, err := VerifyKubeconfig("a3ViZWNvbmZpZw=="); err == nil {
+		t.Errorf("Expected an error for an invalid kubeconfig")
+	}
+
+	newClientsets = func(kubeconfig string) (clientsets, error) {
+		return clientsets{
+			kube:   fake.NewSimpleClientset(node("n1", v1.ConditionTrue)),
+			apiext: apiextfake.NewSimpleClientset(),
+		}, nil
+	}
+	s, err := VerifyKubeconfig("a3ViZWNvbmZpZw==")
+	if err != nil || s.K8sVersion == "" {
+		t.Errorf("Expected the server version, got %v %v", s, err)
+	}
+}
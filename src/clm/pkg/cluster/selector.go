@@ -0,0 +1,145 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"regexp"
+	"strings"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// labelKeyPattern and labelValuePattern are the keys and the values a label
+// can have, see json-schemas/cluster-label.json
+var labelKeyPattern = regexp.MustCompile(`^[-_0-9a-zA-Z]+$`)
+var labelValuePattern = regexp.MustCompile(`^[-_.0-9a-zA-Z]*$`)
+
+// Operators of the requirements of a LabelSelector
+const (
+	selectorExists    = "exists"
+	selectorNotExists = "!"
+	selectorEquals    = "="
+	selectorNotEquals = "!="
+)
+
+type labelRequirement struct {
+	key      string
+	operator string
+	value    string
+}
+
+// LabelSelector selects the clusters by their labels. It is parsed from a
+// comma separated list of requirements, which all have to be met:
+//
+//	key         the cluster has the label key, whatever its value
+//	!key        the cluster does not have the label key
+//	key=value   the cluster has the label key with the value, also key==value
+//	key!=value  the cluster does not have the label key with the value
+//
+// A label name of the placement intents is thereby the selector of the
+// clusters with the label, as it was before the labels had values.
+type LabelSelector struct {
+	requirements []labelRequirement
+}
+
+// ParseLabelSelector parses a LabelSelector
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	s := LabelSelector{}
+	for _, r := range strings.Split(selector, ",") {
+		r = strings.TrimSpace(r)
+		var req labelRequirement
+		switch {
+		case strings.Contains(r, "!="):
+			kv := strings.SplitN(r, "!=", 2)
+			req = labelRequirement{key: kv[0], operator: selectorNotEquals, value: kv[1]}
+		case strings.Contains(r, "=="):
+			kv := strings.SplitN(r, "==", 2)
+			req = labelRequirement{key: kv[0], operator: selectorEquals, value: kv[1]}
+		case strings.Contains(r, "="):
+			kv := strings.SplitN(r, "=", 2)
+			req = labelRequirement{key: kv[0], operator: selectorEquals, value: kv[1]}
+		case strings.HasPrefix(r, "!"):
+			req = labelRequirement{key: r[1:], operator: selectorNotExists}
+		default:
+			req = labelRequirement{key: r, operator: selectorExists}
+		}
+		req.key = strings.TrimSpace(req.key)
+		req.value = strings.TrimSpace(req.value)
+		if !labelKeyPattern.MatchString(req.key) {
+			return LabelSelector{}, pkgerrors.Errorf("Invalid label selector %q: invalid key in %q", selector, r)
+		}
+		if !labelValuePattern.MatchString(req.value) {
+			return LabelSelector{}, pkgerrors.Errorf("Invalid label selector %q: invalid value in %q", selector, r)
+		}
+		s.requirements = append(s.requirements, req)
+	}
+	return s, nil
+}
+
+// exactLabelSelector returns the LabelSelector of the Clusters with the
+// label name, which is not parsed
+func exactLabelSelector(name string) LabelSelector {
+	return LabelSelector{
+		requirements: []labelRequirement{{key: name, operator: selectorExists}},
+	}
+}
+
+// Matches tells whether labels, the values of the labels by key, meet the
+// requirements of the LabelSelector
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range s.requirements {
+		v, ok := labels[r.key]
+		switch r.operator {
+		case selectorExists:
+			if !ok {
+				return false
+			}
+		case selectorNotExists:
+			if ok {
+				return false
+			}
+		case selectorEquals:
+			if !ok || v != r.value {
+				return false
+			}
+		case selectorNotEquals:
+			if ok && v == r.value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// requiredKey returns a label key the selected clusters all have, if any
+func (s LabelSelector) requiredKey() (string, bool) {
+	for _, r := range s.requirements {
+		if r.operator == selectorExists || r.operator == selectorEquals {
+			return r.key, true
+		}
+	}
+	return "", false
+}
+
+// LabelSet returns the values of the labels by key
+func LabelSet(labels []ClusterLabel) map[string]string {
+	set := make(map[string]string, len(labels))
+	for _, l := range labels {
+		set[l.LabelName] = l.Value
+	}
+	return set
+}
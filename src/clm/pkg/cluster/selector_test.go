@@ -0,0 +1,112 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+)
+
+func TestLabelSelector(t *testing.T) {
+	labels := map[string]string{"region": "eu", "edge": "", "tier": "core"}
+
+	testCases := []struct {
+		selector string
+		matches  bool
+	}{
+		{selector: "edge", matches: true},
+		{selector: "region=eu", matches: true},
+		{selector: "region==eu", matches: true},
+		{selector: "region=us", matches: false},
+		{selector: "region=eu,tier!=edge", matches: true},
+		{selector: "region=eu, tier!=core", matches: false},
+		{selector: "gpu!=true", matches: true},
+		{selector: "!gpu", matches: true},
+		{selector: "!edge", matches: false},
+		{selector: "edge=", matches: true},
+	}
+	for _, testCase := range testCases {
+		s, err := ParseLabelSelector(testCase.selector)
+		if err != nil {
+			t.Fatalf("ParseLabelSelector(%q) returned an error: %s", testCase.selector, err)
+		}
+		if got := s.Matches(labels); got != testCase.matches {
+			t.Errorf("Expected %q to match %v, got %v", testCase.selector, testCase.matches, got)
+		}
+	}
+
+	for _, selector := range []string{"", "region=eu,", "=eu", "region===eu", "region=e u", "!"} {
+		if _, err := ParseLabelSelector(selector); err == nil {
+			t.Errorf("Expected an error parsing %q", selector)
+		}
+	}
+}
+
+func TestGetClustersWithLabel(t *testing.T) {
+	// A label created before the labels had values has no value
+	db.DBconn = &db.MockDB{
+		Items: map[string]map[string][]byte{
+			fmt.Sprintf("%v", LabelKey{ClusterProviderName: "p1", ClusterLabelName: "region"}): {
+				"cluster": []byte("c1"),
+			},
+			fmt.Sprintf("%v", ClusterLabelKey{ClusterProviderName: "p1", ClusterName: "c1"}): {
+				"clustermetadata": []byte(`{"label-name":"region","value":"eu"}`),
+			},
+			fmt.Sprintf("%v", LabelKey{ClusterProviderName: "p1", ClusterLabelName: "edge"}): {
+				"cluster": []byte("c2"),
+			},
+			fmt.Sprintf("%v", ClusterLabelKey{ClusterProviderName: "p1", ClusterName: "c2"}): {
+				"clustermetadata": []byte(`{"label-name":"edge"}`),
+			},
+			// Label names like these were accepted before the labels had values
+			fmt.Sprintf("%v", LabelKey{ClusterProviderName: "p1", ClusterLabelName: "dc/east1"}): {
+				"cluster": []byte("c3"),
+			},
+			fmt.Sprintf("%v", ClusterLabelKey{ClusterProviderName: "p1", ClusterName: "c3"}): {
+				"clustermetadata": []byte(`{"label-name":"dc/east1"}`),
+			},
+		},
+	}
+
+	testCases := []struct {
+		selector string
+		expected []string
+	}{
+		{selector: "region=eu", expected: []string{"c1"}},
+		{selector: "region=eu,tier!=edge", expected: []string{"c1"}},
+		{selector: "region=us", expected: nil},
+		{selector: "edge", expected: []string{"c2"}},
+		{selector: "edge=", expected: []string{"c2"}},
+		{selector: "dc/east1", expected: []string{"c3"}},
+	}
+	for _, testCase := range testCases {
+		got, err := NewClusterClient().GetClustersWithLabel("p1", testCase.selector)
+		if err != nil {
+			t.Fatalf("GetClustersWithLabel(%q) returned an error: %s", testCase.selector, err)
+		}
+		if !reflect.DeepEqual(got, testCase.expected) {
+			t.Errorf("Expected %q to select %v, got %v", testCase.selector, testCase.expected, got)
+		}
+	}
+
+	if _, err := NewClusterClient().GetClustersWithLabel("p1", "zone.a1"); err == nil {
+		t.Errorf("Expected an error selecting by a label name no Cluster has")
+	}
+}
@@ -156,5 +156,7 @@ func NewRouter(
 	kvRouter.HandleFunc(
 		"/logical-clouds/{logical-cloud-name}/kv-pairs/{kv-pair-name}",
 		keyValueHandler.deleteHandler).Methods("DELETE")
+
+	router.HandleFunc("/v2/openapi.json", openAPISpec().Handler(router)).Methods("GET")
 	return router
 }
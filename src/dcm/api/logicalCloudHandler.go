@@ -25,7 +25,9 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/onap/multicloud-k8s/src/dcm/pkg/module"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	orch "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
+	pkgerrors "github.com/pkg/errors"
 )
 
 // logicalCloudHandler is used to store backend implementations objects
@@ -241,6 +243,10 @@ func (h logicalCloudHandler) applyHandler(w http.ResponseWriter, r *http.Request
 			http.Error(w, err.Error(), http.StatusConflict)
 			return
 		}
+		if _, ok := pkgerrors.Cause(err).(*db.VersionConflictError); ok {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
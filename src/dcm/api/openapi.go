@@ -0,0 +1,48 @@
+/*
+Copyright 2020 Intel Corporation.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/onap/multicloud-k8s/src/dcm/pkg/module"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/openapi"
+)
+
+const specLCPath = "/v2/projects/{project-name}/logical-clouds/{logical-cloud-name}"
+
+// openAPISpec documents the routes of NewRouter. The operations of the
+// collections and of their items are generated from the resources, the
+// other routes have an operation. Every route must have one, see
+// TestOpenAPISpec.
+func openAPISpec() openapi.Spec {
+	return openapi.Spec{
+		Title:   "EMCO Distributed Cloud Manager",
+		Version: "v2",
+		Resources: map[string]openapi.Resource{
+			"logical-clouds":     {Name: "logical cloud", Model: module.LogicalCloud{}, UpdateStatus: http.StatusCreated},
+			"cluster-references": {Name: "cluster reference", Model: module.Cluster{}},
+			"user-permissions":   {Name: "user permission", Model: module.UserPermission{}},
+			"cluster-quotas":     {Name: "cluster quota", Model: module.Quota{}},
+			"kv-pairs":           {Name: "key value pair", Model: module.KeyValue{}},
+		},
+		Operations: map[string]openapi.Operation{
+			"GET /v2/openapi.json": {Summary: "Get the OpenAPI specification of the service", Response: map[string]interface{}{}},
+
+			"POST " + specLCPath + "/apply":                                            {Summary: "Apply a logical cloud to its clusters"},
+			"POST " + specLCPath + "/terminate":                                        {Summary: "Terminate a logical cloud"},
+			"GET " + specLCPath + "/cluster-references/{cluster-reference}/kubeconfig": {Summary: "Get the kubeconfig of a cluster of an applied logical cloud", Response: "", ContentType: "application/yaml"},
+		},
+	}
+}
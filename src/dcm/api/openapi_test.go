@@ -0,0 +1,26 @@
+/*
+Copyright 2020 Intel Corporation.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+)
+
+// TestOpenAPISpec fails when a route is added to NewRouter without an
+// operation in openAPISpec
+func TestOpenAPISpec(t *testing.T) {
+	if err := openAPISpec().Check(NewRouter(nil, nil, nil, nil, nil)); err != nil {
+		t.Fatalf("The OpenAPI specification does not match the routes: %s", err)
+	}
+}
@@ -3,24 +3,32 @@ module github.com/onap/multicloud-k8s/src/dcm
 require (
 	github.com/Masterminds/semver v1.5.0 // indirect
 	github.com/Masterminds/sprig v2.22.0+incompatible // indirect
-	github.com/gorilla/handlers v1.3.0
+	github.com/gorilla/handlers v1.4.0
 	github.com/gorilla/mux v1.7.3
+	github.com/jmoiron/sqlx v1.2.0 // indirect
+	github.com/lib/pq v1.3.0 // indirect
 	github.com/onap/multicloud-k8s/src/clm v0.0.0-20251205073433-cd019185faf1
 	github.com/onap/multicloud-k8s/src/monitor v0.0.0-20251205073433-cd019185faf1
 	github.com/onap/multicloud-k8s/src/orchestrator v0.0.0-20251205113806-246f4e4b1e9f
 	github.com/pkg/errors v0.9.1
+	github.com/rubenv/sql-migrate v0.0.0-20200212082348-64f95ea68aa3 // indirect
 	github.com/stretchr/testify v1.7.0
 	github.com/technosophos/moniker v0.0.0-20210218184952-3ea787d3943b // indirect
-	gopkg.in/yaml.v2 v2.3.0
+	gopkg.in/yaml.v2 v2.4.0
 	k8s.io/api v0.18.2
 	k8s.io/apimachinery v0.18.2
+	k8s.io/kubernetes v1.16.9 // indirect
 )
 
 replace (
 	github.com/Sirupsen/logrus => github.com/sirupsen/logrus v1.9.3
+	github.com/docker/docker => github.com/moby/moby v0.7.3-0.20190826074503-38ab9da00309
 	github.com/onap/multicloud-k8s/src/clm => ../clm
 	github.com/onap/multicloud-k8s/src/monitor => ../monitor
 	github.com/onap/multicloud-k8s/src/orchestrator => ../orchestrator
+	// operator-sdk, which monitor requires, raises helm v3 to v3.2.4; v3.0.3
+	// is the release built against the pinned client-go
+	helm.sh/helm/v3 v3.2.4 => helm.sh/helm/v3 v3.0.3
 	k8s.io/api => k8s.io/api v0.16.9
 	k8s.io/apiextensions-apiserver => k8s.io/apiextensions-apiserver v0.16.9
 	k8s.io/apimachinery => k8s.io/apimachinery v0.16.10-beta.0
@@ -43,6 +51,7 @@ replace (
 	k8s.io/legacy-cloud-providers => k8s.io/legacy-cloud-providers v0.16.9
 	k8s.io/metrics => k8s.io/metrics v0.16.9
 	k8s.io/sample-apiserver => k8s.io/sample-apiserver v0.16.9
+	vbom.ml/util => github.com/fvbommel/util v0.0.0-20180919145318-efcd4e0f9787
 )
 
 go 1.16
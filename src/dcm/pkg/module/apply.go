@@ -32,6 +32,7 @@ import (
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/grpc/installappclient"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/module/controller"
 	pkgerrors "github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
@@ -341,7 +342,16 @@ func Apply(project string, logicalcloud LogicalCloud, clusterList []Cluster,
 		Project:          project,
 	}
 
+	// The version is read before the context, so that a concurrent Apply
+	// can't replace the context between the check below and the update of
+	// the logical cloud
+	version, err := db.DBconn.GetVersion(lcclient.storeName, lckey)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error getting the version of the Logical Cloud")
+	}
+
 	// Check if there was a previous context for this logical cloud
+	var previous *appcontext.AppContext
 	ac, cid, err := lcclient.util.GetLogicalCloudContext(lcclient.storeName, lckey, lcclient.tagMeta, project, logicalCloudName)
 	if cid != "" {
 		// Make sure rsync status for this logical cloud is Terminated,
@@ -349,16 +359,9 @@ func Apply(project string, logicalcloud LogicalCloud, clusterList []Cluster,
 		acStatus, _ := lcclient.util.GetAppContextStatus(ac)
 		switch acStatus.Status {
 		case appcontext.AppContextStatusEnum.Terminated:
-			// We now know Logical Cloud has terminated, so let's update the entry before we process the apply
-			err = db.DBconn.RemoveTag(lcclient.storeName, lckey, lcclient.tagContext)
-			if err != nil {
-				return pkgerrors.Wrap(err, "Error removing lccontext tag from Logical Cloud")
-			}
-			// And fully delete the old AppContext
-			err := ac.DeleteCompositeApp()
-			if err != nil {
-				return pkgerrors.Wrap(err, "Error deleting AppContext CompositeApp Logical Cloud")
-			}
+			// We now know Logical Cloud has terminated. The old AppContext is
+			// deleted once the new one replaced it in the entry
+			previous = &ac
 		case appcontext.AppContextStatusEnum.Terminating:
 			return pkgerrors.New("The Logical Cloud can't be re-applied yet, it is being terminated.")
 		case appcontext.AppContextStatusEnum.Instantiated:
@@ -393,6 +396,10 @@ func Apply(project string, logicalcloud LogicalCloud, clusterList []Cluster,
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error Creating User CSR and Key for logical cloud")
 	}
+	encryptedKey, err := secret.Encrypt(key)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error encrypting private key for logical cloud")
+	}
 
 	approval, err := createApprovalSubresource(logicalcloud)
 
@@ -443,7 +450,7 @@ func Apply(project string, logicalcloud LogicalCloud, clusterList []Cluster,
 		}
 
 		// Add private key to MongoDB
-		err = db.DBconn.Insert("orchestrator", lckey, nil, "privatekey", key)
+		version, err = db.DBconn.InsertIfVersion("orchestrator", lckey, nil, "privatekey", encryptedKey, version)
 		if err != nil {
 			return cleanupCompositeApp(context, err, "Error adding private key to DB", details)
 		}
@@ -521,11 +528,19 @@ func Apply(project string, logicalcloud LogicalCloud, clusterList []Cluster,
 		}
 	}
 	// save the context in the logicalcloud db record
-	err = db.DBconn.Insert("orchestrator", lckey, nil, "lccontext", ctxVal)
+	_, err = db.DBconn.InsertIfVersion("orchestrator", lckey, nil, "lccontext", ctxVal, version)
 	if err != nil {
 		return cleanupCompositeApp(context, err, "Error adding AppContext to DB", []string{logicalCloudName, ctxVal.(string)})
 	}
 
+	// and fully delete the old AppContext
+	if previous != nil {
+		err = previous.DeleteCompositeApp()
+		if err != nil {
+			return pkgerrors.Wrap(err, "Error deleting AppContext CompositeApp Logical Cloud")
+		}
+	}
+
 	// call resource synchronizer to instantiate the CRs in the cluster
 	err = callRsyncInstall(ctxVal)
 	if err != nil {
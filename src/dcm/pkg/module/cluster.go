@@ -24,6 +24,7 @@ import (
 	clm "github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
 	rb "github.com/onap/multicloud-k8s/src/monitor/pkg/apis/k8splugin/v1alpha1"
 	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
 	pkgerrors "github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
@@ -371,7 +372,10 @@ func (v *ClusterClient) GetClusterConfig(project, logicalCloud, clusterReference
 	}
 
 	// all data needed for final kubeconfig:
-	privateKey := string(privateKeyData[0])
+	privateKey, err := secret.Decrypt(string(privateKeyData[0]))
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Failed decrypting private key")
+	}
 	signedCert := cluster.Specification.Certificate
 	clusterCert := adminKubeConfig.Clusters[0].ClusterDef.CertificateAuthorityData
 	clusterAddr := adminKubeConfig.Clusters[0].ClusterDef.Server
@@ -21,6 +21,7 @@ import (
 
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
 
 	pkgerrors "github.com/pkg/errors"
@@ -267,6 +268,51 @@ func (v *LogicalCloudClient) Update(project, logicalCloudName string, c LogicalC
 	return c, nil
 }
 
+// ReencryptSecrets re-encrypts the user private keys of the Logical Clouds
+// with the primary secret key, and returns the number of private keys
+// updated. It is run by the secret migration command of dcm.
+func (v *LogicalCloudClient) ReencryptSecrets() (int, error) {
+	projects, err := module.NewProjectClient().GetAllProjects()
+	if err != nil {
+		return 0, pkgerrors.Wrap(err, "Getting projects")
+	}
+
+	count := 0
+	for _, p := range projects {
+		lcs, err := v.GetAll(p.MetaData.Name)
+		if err != nil {
+			return count, err
+		}
+		for _, lc := range lcs {
+			key := LogicalCloudKey{
+				Project:          p.MetaData.Name,
+				LogicalCloudName: lc.MetaData.LogicalCloudName,
+			}
+			values, err := v.util.DBFind(v.storeName, key, "privatekey")
+			if err != nil || len(values) == 0 || len(values[0]) == 0 {
+				continue
+			}
+			privateKey, changed, err := secret.Reencrypt(string(values[0]))
+			if err != nil {
+				return count, pkgerrors.Wrapf(err, "Re-encrypting private key of Logical Cloud %s", lc.MetaData.LogicalCloudName)
+			}
+			if !changed {
+				continue
+			}
+			version, err := db.DBconn.GetVersion(v.storeName, key)
+			if err != nil {
+				return count, pkgerrors.Wrap(err, "Getting Logical Cloud version")
+			}
+			_, err = db.DBconn.InsertIfVersion(v.storeName, key, nil, "privatekey", privateKey, version)
+			if err != nil {
+				return count, pkgerrors.Wrap(err, "Updating private key")
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
 // GetLogicalCloudContext returns the AppContext for corresponding provider and name
 func (d DBService) GetLogicalCloudContext(storeName string, key db.Key, meta string, project string, name string) (appcontext.AppContext, string, error) {
 
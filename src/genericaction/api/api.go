@@ -0,0 +1,83 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package api
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gorilla/mux"
+	moduleLib "github.com/onap/multicloud-k8s/src/genericaction/pkg/module"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+)
+
+var moduleClient *moduleLib.Client
+
+// For the given client and testClient, if the testClient is not null and
+// implements the client manager interface corresponding to client, then
+// return the testClient, otherwise return the client.
+func setClient(client, testClient interface{}) interface{} {
+	switch cl := client.(type) {
+	case *moduleLib.GenericK8sIntentClient:
+		if testClient != nil && reflect.TypeOf(testClient).Implements(reflect.TypeOf((*moduleLib.GenericK8sIntentManager)(nil)).Elem()) {
+			c, ok := testClient.(moduleLib.GenericK8sIntentManager)
+			if ok {
+				return c
+			}
+		}
+	case *moduleLib.ResourcePatchClient:
+		if testClient != nil && reflect.TypeOf(testClient).Implements(reflect.TypeOf((*moduleLib.ResourcePatchManager)(nil)).Elem()) {
+			c, ok := testClient.(moduleLib.ResourcePatchManager)
+			if ok {
+				return c
+			}
+		}
+	default:
+		log.Warn("Unknown client type", log.Fields{"type": fmt.Sprintf("%T", cl)})
+	}
+	return client
+}
+
+// NewRouter creates a router that registers the various urls that are supported
+// testClient parameter allows unit testing for a given client
+func NewRouter(testClient interface{}) *mux.Router {
+
+	moduleClient = moduleLib.NewClient()
+
+	router := mux.NewRouter().PathPrefix("/v2").Subrouter()
+
+	generick8sintentHandler := generick8sintentHandler{
+		client: setClient(moduleClient.GenericK8sIntent, testClient).(moduleLib.GenericK8sIntentManager),
+	}
+	router.HandleFunc("/projects/{project}/composite-apps/{composite-app-name}/{version}/deployment-intent-groups/{deployment-intent-group-name}/generic-k8s-intents", generick8sintentHandler.createHandler).Methods("POST")
+	router.HandleFunc("/projects/{project}/composite-apps/{composite-app-name}/{version}/deployment-intent-groups/{deployment-intent-group-name}/generic-k8s-intents", generick8sintentHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project}/composite-apps/{composite-app-name}/{version}/deployment-intent-groups/{deployment-intent-group-name}/generic-k8s-intents/{name}", generick8sintentHandler.putHandler).Methods("PUT")
+	router.HandleFunc("/projects/{project}/composite-apps/{composite-app-name}/{version}/deployment-intent-groups/{deployment-intent-group-name}/generic-k8s-intents/{name}", generick8sintentHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project}/composite-apps/{composite-app-name}/{version}/deployment-intent-groups/{deployment-intent-group-name}/generic-k8s-intents/{name}", generick8sintentHandler.deleteHandler).Methods("DELETE")
+
+	resourcepatchHandler := resourcepatchHandler{
+		client: setClient(moduleClient.ResourcePatch, testClient).(moduleLib.ResourcePatchManager),
+	}
+	router.HandleFunc("/projects/{project}/composite-apps/{composite-app-name}/{version}/deployment-intent-groups/{deployment-intent-group-name}/generic-k8s-intents/{generic-k8s-intent}/patches", resourcepatchHandler.createHandler).Methods("POST")
+	router.HandleFunc("/projects/{project}/composite-apps/{composite-app-name}/{version}/deployment-intent-groups/{deployment-intent-group-name}/generic-k8s-intents/{generic-k8s-intent}/patches", resourcepatchHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project}/composite-apps/{composite-app-name}/{version}/deployment-intent-groups/{deployment-intent-group-name}/generic-k8s-intents/{generic-k8s-intent}/patches/{name}", resourcepatchHandler.putHandler).Methods("PUT")
+	router.HandleFunc("/projects/{project}/composite-apps/{composite-app-name}/{version}/deployment-intent-groups/{deployment-intent-group-name}/generic-k8s-intents/{generic-k8s-intent}/patches/{name}", resourcepatchHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project}/composite-apps/{composite-app-name}/{version}/deployment-intent-groups/{deployment-intent-group-name}/generic-k8s-intents/{generic-k8s-intent}/patches/{name}", resourcepatchHandler.deleteHandler).Methods("DELETE")
+
+	router.HandleFunc("/openapi.json", openAPISpec().Handler(router)).Methods("GET")
+
+	return router
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	moduleLib "github.com/onap/multicloud-k8s/src/genericaction/pkg/module"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/openapi"
+)
+
+// openAPISpec documents the routes of NewRouter. The operations of the
+// collections and of their items are generated from the resources, the
+// other routes have an operation. Every route must have one, see
+// TestOpenAPISpec.
+func openAPISpec() openapi.Spec {
+	return openapi.Spec{
+		Title:   "EMCO Generic Action Controller",
+		Version: "v2",
+		Resources: map[string]openapi.Resource{
+			"generic-k8s-intents": {Name: "generic k8s intent", Schema: genericK8sIntJSONFile, Model: moduleLib.GenericK8sIntent{}, UpdateStatus: http.StatusCreated},
+			"patches":             {Name: "resource patch", Plural: "resource patches", Schema: resourcePatchJSONFile, Model: moduleLib.ResourcePatch{}, UpdateStatus: http.StatusCreated},
+		},
+		Operations: map[string]openapi.Operation{
+			"GET /v2/openapi.json": {Summary: "Get the OpenAPI specification of the service", Response: map[string]interface{}{}},
+		},
+	}
+}
@@ -0,0 +1,249 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	moduleLib "github.com/onap/multicloud-k8s/src/genericaction/pkg/module"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/validation"
+	pkgerrors "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/gorilla/mux"
+)
+
+var resourcePatchJSONFile string = "json-schemas/resource-patch.json"
+
+// Used to store backend implementations objects
+// Also simplifies mocking for unit testing purposes
+type resourcepatchHandler struct {
+	// Interface that implements resource patch operations
+	// We will set this variable with a mock interface for testing
+	client moduleLib.ResourcePatchManager
+}
+
+// Check for valid format of input parameters
+func validateResourcePatchInputs(rp moduleLib.ResourcePatch) error {
+	// validate metadata
+	err := moduleLib.IsValidMetadata(rp.Metadata)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Invalid resource patch metadata")
+	}
+
+	errs := validation.IsValidName(rp.Spec.App)
+	if len(errs) > 0 {
+		return pkgerrors.Errorf("Invalid application name = [%v], errors: %v", rp.Spec.App, errs)
+	}
+
+	if rp.Spec.LabelSelector != "" {
+		_, err = labels.Parse(rp.Spec.LabelSelector)
+		if err != nil {
+			return pkgerrors.Wrapf(err, "Invalid label selector = [%v]", rp.Spec.LabelSelector)
+		}
+	}
+
+	if rp.Spec.ClusterProvider == "" {
+		if rp.Spec.Cluster != "" || rp.Spec.ClusterLabel != "" {
+			return pkgerrors.New("A cluster or cluster label scope requires a cluster provider")
+		}
+	} else if (rp.Spec.Cluster == "") == (rp.Spec.ClusterLabel == "") {
+		return pkgerrors.New("A cluster provider scope requires exactly one of cluster or cluster label")
+	}
+
+	switch rp.Spec.PatchType {
+	case moduleLib.PatchTypeJSON:
+		var ops []map[string]interface{}
+		if err := json.Unmarshal(rp.Spec.Patch, &ops); err != nil {
+			return pkgerrors.Wrap(err, "A json patch must be a list of operations")
+		}
+	case moduleLib.PatchTypeMerge, moduleLib.PatchTypeStrategic:
+		var obj map[string]interface{}
+		if err := json.Unmarshal(rp.Spec.Patch, &obj); err != nil || obj == nil {
+			return pkgerrors.Errorf("A %v patch must be an object", rp.Spec.PatchType)
+		}
+	default:
+		return pkgerrors.Errorf("Invalid patch type = [%v]", rp.Spec.PatchType)
+	}
+	return nil
+}
+
+// Create handles creation of the ResourcePatch entry in the database
+func (h resourcepatchHandler) createHandler(w http.ResponseWriter, r *http.Request) {
+	var rp moduleLib.ResourcePatch
+	vars := mux.Vars(r)
+	project := vars["project"]
+	compositeApp := vars["composite-app-name"]
+	compositeAppVersion := vars["version"]
+	deployIntentGroup := vars["deployment-intent-group-name"]
+	genericK8sIntent := vars["generic-k8s-intent"]
+
+	err := json.NewDecoder(r.Body).Decode(&rp)
+
+	switch {
+	case err == io.EOF:
+		http.Error(w, "Empty body", http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	err, httpError := validation.ValidateJsonSchemaData(resourcePatchJSONFile, rp)
+	if err != nil {
+		http.Error(w, err.Error(), httpError)
+		return
+	}
+
+	// Name is required.
+	if rp.Metadata.Name == "" {
+		http.Error(w, "Missing name in POST request", http.StatusBadRequest)
+		return
+	}
+
+	err = validateResourcePatchInputs(rp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ret, err := h.client.CreateResourcePatch(rp, project, compositeApp, compositeAppVersion, deployIntentGroup, genericK8sIntent, false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Put handles creation/update of the ResourcePatch entry in the database
+func (h resourcepatchHandler) putHandler(w http.ResponseWriter, r *http.Request) {
+	var rp moduleLib.ResourcePatch
+	vars := mux.Vars(r)
+	name := vars["name"]
+	project := vars["project"]
+	compositeApp := vars["composite-app-name"]
+	compositeAppVersion := vars["version"]
+	deployIntentGroup := vars["deployment-intent-group-name"]
+	genericK8sIntent := vars["generic-k8s-intent"]
+
+	err := json.NewDecoder(r.Body).Decode(&rp)
+
+	switch {
+	case err == io.EOF:
+		http.Error(w, "Empty body", http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Name is required.
+	if rp.Metadata.Name == "" {
+		http.Error(w, "Missing name in PUT request", http.StatusBadRequest)
+		return
+	}
+
+	// Name in URL should match name in body
+	if rp.Metadata.Name != name {
+		http.Error(w, "Mismatched name in PUT request", http.StatusBadRequest)
+		return
+	}
+
+	err = validateResourcePatchInputs(rp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ret, err := h.client.CreateResourcePatch(rp, project, compositeApp, compositeAppVersion, deployIntentGroup, genericK8sIntent, true)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Get handles GET operations on a particular ResourcePatch Name
+// Returns a ResourcePatch
+func (h resourcepatchHandler) getHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	project := vars["project"]
+	compositeApp := vars["composite-app-name"]
+	compositeAppVersion := vars["version"]
+	deployIntentGroup := vars["deployment-intent-group-name"]
+	genericK8sIntent := vars["generic-k8s-intent"]
+	var ret interface{}
+	var err error
+
+	if len(name) == 0 {
+		ret, err = h.client.GetResourcePatches(project, compositeApp, compositeAppVersion, deployIntentGroup, genericK8sIntent)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else {
+		ret, err = h.client.GetResourcePatch(name, project, compositeApp, compositeAppVersion, deployIntentGroup, genericK8sIntent)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Delete handles DELETE operations on a particular ResourcePatch Name
+func (h resourcepatchHandler) deleteHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	project := vars["project"]
+	compositeApp := vars["composite-app-name"]
+	compositeAppVersion := vars["version"]
+	deployIntentGroup := vars["deployment-intent-group-name"]
+	genericK8sIntent := vars["generic-k8s-intent"]
+
+	err := h.client.DeleteResourcePatch(name, project, compositeApp, compositeAppVersion, deployIntentGroup, genericK8sIntent)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
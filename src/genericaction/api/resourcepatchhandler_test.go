@@ -0,0 +1,77 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	moduleLib "github.com/onap/multicloud-k8s/src/genericaction/pkg/module"
+)
+
+func TestValidateResourcePatchInputs(t *testing.T) {
+	valid := moduleLib.ResourcePatchSpec{
+		App:       "app1",
+		PatchType: moduleLib.PatchTypeMerge,
+		Patch:     json.RawMessage(`{"metadata": {"labels": {"a": "b"}}}`),
+	}
+
+	t.Run("Valid Patches", func(t *testing.T) {
+		specs := []func(s *moduleLib.ResourcePatchSpec){
+			func(s *moduleLib.ResourcePatchSpec) {},
+			func(s *moduleLib.ResourcePatchSpec) {
+				s.PatchType = moduleLib.PatchTypeJSON
+				s.Patch = json.RawMessage(`[{"op": "add", "path": "/metadata/labels/a", "value": "b"}]`)
+			},
+			func(s *moduleLib.ResourcePatchSpec) { s.PatchType = moduleLib.PatchTypeStrategic },
+			func(s *moduleLib.ResourcePatchSpec) { s.LabelSelector = "app=web,tier notin (test)" },
+			func(s *moduleLib.ResourcePatchSpec) { s.ClusterProvider, s.Cluster = "provider1", "cluster1" },
+			func(s *moduleLib.ResourcePatchSpec) { s.ClusterProvider, s.ClusterLabel = "provider1", "edge" },
+		}
+		for i, set := range specs {
+			spec := valid
+			set(&spec)
+			err := validateResourcePatchInputs(moduleLib.ResourcePatch{Metadata: moduleLib.Metadata{Name: "patch1"}, Spec: spec})
+			if err != nil {
+				t.Errorf("Valid resource patch %d failed to pass: %v %v", i, spec, err)
+			}
+		}
+	})
+
+	t.Run("Invalid Patches", func(t *testing.T) {
+		specs := []func(s *moduleLib.ResourcePatchSpec){
+			func(s *moduleLib.ResourcePatchSpec) { s.App = "" },
+			func(s *moduleLib.ResourcePatchSpec) { s.PatchType = "apply" },
+			func(s *moduleLib.ResourcePatchSpec) { s.PatchType = moduleLib.PatchTypeJSON },
+			func(s *moduleLib.ResourcePatchSpec) { s.Patch = json.RawMessage(`[]`) },
+			func(s *moduleLib.ResourcePatchSpec) { s.LabelSelector = "tier in (test" },
+			func(s *moduleLib.ResourcePatchSpec) { s.Cluster = "cluster1" },
+			func(s *moduleLib.ResourcePatchSpec) { s.ClusterProvider = "provider1" },
+			func(s *moduleLib.ResourcePatchSpec) {
+				s.ClusterProvider, s.Cluster, s.ClusterLabel = "provider1", "cluster1", "edge"
+			},
+		}
+		for i, set := range specs {
+			spec := valid
+			set(&spec)
+			err := validateResourcePatchInputs(moduleLib.ResourcePatch{Metadata: moduleLib.Metadata{Name: "patch1"}, Spec: spec})
+			if err == nil {
+				t.Errorf("Invalid resource patch %d passed: %v", i, spec)
+			}
+		}
+	})
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 Intel Corporation.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/gorilla/handlers"
+	"github.com/onap/multicloud-k8s/src/genericaction/api"
+	register "github.com/onap/multicloud-k8s/src/genericaction/pkg/grpc"
+	"github.com/onap/multicloud-k8s/src/genericaction/pkg/grpc/contextupdateserver"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/controllersdk"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/audit"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/auth"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
+	contextDb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/contextdb"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/metrics"
+)
+
+func startGrpcServer() error {
+	_, port := register.GetServerHostPort()
+
+	log.Println("Starting Generic Action Controller gRPC Server")
+	err := controllersdk.StartGrpcServer("genericaction", port, contextupdateserver.NewController())
+	if err != nil {
+		log.Fatalf("genericaction grpc server is not serving %v", err)
+	}
+	return err
+}
+
+func main() {
+	rand.Seed(time.Now().UnixNano())
+
+	err := db.InitializeDatabaseConnection("mco")
+	if err != nil {
+		log.Println("Unable to initialize database connection...")
+		log.Println(err)
+		log.Fatalln("Exiting...")
+	}
+	err = contextDb.InitializeContextDatabase()
+	if err != nil {
+		log.Println("Unable to initialize database connection...")
+		log.Println(err)
+		log.Fatalln("Exiting...")
+	}
+	metrics.InstrumentDatabases()
+
+	httpRouter := api.NewRouter(nil)
+	httpRouter.Use(metrics.Middleware("genericaction"))
+	httpRouter.Use(audit.Middleware("genericaction"))
+	loggedRouter := handlers.LoggingHandler(os.Stdout, metrics.Handler(httpRouter))
+	log.Println("Starting Generic Action Controller")
+
+	httpServer := &http.Server{
+		Handler: loggedRouter,
+		Addr:    ":" + config.GetConfiguration().ServicePort,
+	}
+
+	go func() {
+		err := startGrpcServer()
+		if err != nil {
+			log.Fatalf("GRPC server failed to start")
+		}
+	}()
+
+	connectionsClose := make(chan struct{})
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt)
+		<-c
+		httpServer.Shutdown(context.Background())
+		close(connectionsClose)
+	}()
+
+	tlsConfig, err := auth.GetTLSConfig("ca.cert", "server.cert", "server.key")
+	if err != nil {
+		log.Println("Error Getting TLS Configuration. Starting without TLS...")
+		log.Fatal(httpServer.ListenAndServe())
+	} else {
+		httpServer.TLSConfig = tlsConfig
+		// empty strings because tlsconfig already has this information
+		err = httpServer.ListenAndServeTLS("", "")
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package action
+
+import (
+	"strings"
+
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/genericaction/pkg/module"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/controllersdk"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	"k8s.io/apimachinery/pkg/labels"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// UpdateAppContext applies the resource patches of the supplied intent
+// against the given AppContext ID
+func UpdateAppContext(intentName, appContextId string) error {
+	ac, caMeta, err := controllersdk.LoadAppContext(appContextId)
+	if err != nil {
+		return err
+	}
+
+	rps, err := module.NewResourcePatchClient().GetResourcePatches(caMeta.Project, caMeta.CompositeApp, caMeta.Version, caMeta.DeploymentIntentGroup, intentName)
+	if err != nil {
+		return pkgerrors.Wrapf(err, "Error getting Resource Patches for Generic K8s Intent %v for %v/%v%v/%v not found", intentName, caMeta.Project, caMeta.CompositeApp, caMeta.DeploymentIntentGroup, caMeta.Version)
+	}
+
+	for _, rp := range rps {
+		n, err := applyResourcePatch(ac, rp)
+		if err != nil {
+			return pkgerrors.Wrapf(err, "Error applying Resource Patch %v of Generic K8s Intent %v", rp.Metadata.Name, intentName)
+		}
+		if n == 0 {
+			log.Warn("No resources matched resource patch", log.Fields{
+				"project":                 caMeta.Project,
+				"composite app":           caMeta.CompositeApp,
+				"composite app version":   caMeta.Version,
+				"deployment intent group": caMeta.DeploymentIntentGroup,
+				"generic k8s intent":      intentName,
+				"resource patch":          rp.Metadata.Name,
+				"app":                     rp.Spec.App,
+			})
+		}
+	}
+
+	return nil
+}
+
+// applyResourcePatch patches every resource of the AppContext selected by the
+// resource patch and returns the number of resources patched
+func applyResourcePatch(ac *appcontext.AppContext, rp module.ResourcePatch) (int, error) {
+	selector := labels.Everything()
+	if rp.Spec.LabelSelector != "" {
+		s, err := labels.Parse(rp.Spec.LabelSelector)
+		if err != nil {
+			return 0, pkgerrors.Wrapf(err, "Invalid label selector %v", rp.Spec.LabelSelector)
+		}
+		selector = s
+	}
+
+	clusters, err := scopedClusters(rp.Spec)
+	if err != nil {
+		return 0, err
+	}
+
+	patched := 0
+	err = controllersdk.ForEachAppResource(ac, rp.Spec.App, func(r controllersdk.Resource) error {
+		if clusters != nil && !clusters[r.Cluster] {
+			return nil
+		}
+		if rp.Spec.Kind != "" && !strings.EqualFold(r.Kind(), rp.Spec.Kind) {
+			return nil
+		}
+		if rp.Spec.Name != "" && r.ObjectName() != rp.Spec.Name {
+			return nil
+		}
+
+		obj, err := r.Object()
+		if err != nil {
+			return err
+		}
+		if !selector.Matches(labels.Set(obj.GetLabels())) {
+			return nil
+		}
+
+		obj, err = ApplyPatch(obj, rp.Spec)
+		if err != nil {
+			return pkgerrors.Wrapf(err, "Error patching resource %v of app %v on cluster %v", r.Name, r.App, r.Cluster)
+		}
+		err = controllersdk.UpdateResourceObject(ac, r, obj)
+		if err != nil {
+			return err
+		}
+		patched++
+		return nil
+	})
+	return patched, err
+}
+
+// scopedClusters returns the set of AppContext cluster names, <provider>+<cluster>,
+// the resource patch is scoped to, or nil if it applies to all clusters
+func scopedClusters(spec module.ResourcePatchSpec) (map[string]bool, error) {
+	if spec.ClusterProvider == "" {
+		return nil, nil
+	}
+
+	var names []string
+	switch {
+	case spec.Cluster != "":
+		names = []string{spec.Cluster}
+	case spec.ClusterLabel != "":
+		cl, err := cluster.NewClusterClient().GetClustersWithLabel(spec.ClusterProvider, spec.ClusterLabel)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "Error getting clusters of provider %v with label %v", spec.ClusterProvider, spec.ClusterLabel)
+		}
+		names = cl
+	default:
+		return nil, pkgerrors.Errorf("Cluster provider %v requires a cluster or a cluster label", spec.ClusterProvider)
+	}
+
+	clusters := make(map[string]bool, len(names))
+	for _, n := range names {
+		clusters[spec.ClusterProvider+"+"+n] = true
+	}
+	return clusters, nil
+}
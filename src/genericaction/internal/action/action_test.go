@@ -0,0 +1,234 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/genericaction/pkg/module"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/controllersdk"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/contextdb"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+)
+
+const deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    tier: frontend
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`
+
+const configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+data:
+  key: value
+`
+
+var testMeta = appcontext.CompositeAppMeta{
+	Project:               "p1",
+	CompositeApp:          "ca1",
+	Version:               "v1",
+	Release:               "r1",
+	DeploymentIntentGroup: "dig1",
+}
+
+// setPatch installs a mock database holding the resource patch of intent1
+func setPatch(t *testing.T, rp module.ResourcePatch) {
+	t.Helper()
+	key := module.ResourcePatchKey{
+		Project:             testMeta.Project,
+		CompositeApp:        testMeta.CompositeApp,
+		CompositeAppVersion: testMeta.Version,
+		DigName:             testMeta.DeploymentIntentGroup,
+		GenericK8sIntent:    "intent1",
+		ResourcePatch:       "",
+	}
+	b, err := json.Marshal(rp)
+	if err != nil {
+		t.Fatalf("Error marshalling resource patch %s", err)
+	}
+	db.DBconn = &db.MockDB{
+		Items: map[string]map[string][]byte{
+			fmt.Sprintf("%v", key): {"resourcepatchmetadata": b},
+		},
+	}
+}
+
+func newTestAppContext(t *testing.T) string {
+	t.Helper()
+	contextdb.Db = &contextdb.MockEtcd{}
+	id, err := controllersdk.NewFakeAppContext(testMeta, []controllersdk.FakeResource{
+		{App: "app1", Cluster: "provider1+cluster1", Name: "web+Deployment", Value: deployment},
+		{App: "app1", Cluster: "provider1+cluster1", Name: "web+ConfigMap", Value: configMap},
+		{App: "app1", Cluster: "provider1+cluster2", Name: "web+Deployment", Value: deployment},
+	})
+	if err != nil {
+		t.Fatalf("NewFakeAppContext returned an unexpected error %s", err)
+	}
+	return id
+}
+
+func getResource(t *testing.T, id, cluster, name string) string {
+	t.Helper()
+	ac, _, err := controllersdk.LoadAppContext(id)
+	if err != nil {
+		t.Fatalf("LoadAppContext returned an unexpected error %s", err)
+	}
+	resources, err := controllersdk.GetResources(ac, "app1", cluster)
+	if err != nil {
+		t.Fatalf("GetResources returned an unexpected error %s", err)
+	}
+	for _, r := range resources {
+		if r.Name == name {
+			return r.Value
+		}
+	}
+	t.Fatalf("Resource %v not found on cluster %v", name, cluster)
+	return ""
+}
+
+func TestUpdateAppContext(t *testing.T) {
+	testCases := []struct {
+		label    string
+		spec     module.ResourcePatchSpec
+		expected map[string]string
+		absent   map[string]string
+		expErr   string
+	}{
+		{
+			label: "JSON patch of a kind",
+			spec: module.ResourcePatchSpec{
+				App:       "app1",
+				Kind:      "Deployment",
+				PatchType: module.PatchTypeJSON,
+				Patch:     json.RawMessage(`[{"op": "replace", "path": "/spec/replicas", "value": 3}]`),
+			},
+			expected: map[string]string{
+				"provider1+cluster1/web+Deployment": "replicas: 3",
+				"provider1+cluster2/web+Deployment": "replicas: 3",
+				"provider1+cluster1/web+ConfigMap":  "key: value",
+			},
+		},
+		{
+			label: "Merge patch scoped to a cluster",
+			spec: module.ResourcePatchSpec{
+				App:             "app1",
+				Name:            "web",
+				ClusterProvider: "provider1",
+				Cluster:         "cluster2",
+				PatchType:       module.PatchTypeMerge,
+				Patch:           json.RawMessage(`{"metadata": {"annotations": {"patched": "yes"}}}`),
+			},
+			expected: map[string]string{
+				"provider1+cluster2/web+Deployment": "patched: \"yes\"",
+			},
+			absent: map[string]string{
+				"provider1+cluster1/web+Deployment": "patched",
+				"provider1+cluster1/web+ConfigMap":  "patched",
+			},
+		},
+		{
+			label: "Strategic merge patch with a label selector",
+			spec: module.ResourcePatchSpec{
+				App:           "app1",
+				LabelSelector: "tier=frontend",
+				PatchType:     module.PatchTypeStrategic,
+				Patch:         json.RawMessage(`{"spec": {"template": {"spec": {"containers": [{"name": "web", "image": "nginx:1.19"}]}}}}`),
+			},
+			expected: map[string]string{
+				"provider1+cluster1/web+Deployment": "image: nginx:1.19",
+			},
+			absent: map[string]string{
+				"provider1+cluster1/web+ConfigMap": "nginx:1.19",
+			},
+		},
+		{
+			label: "Strategic merge patch of a core kind",
+			spec: module.ResourcePatchSpec{
+				App:       "app1",
+				Kind:      "ConfigMap",
+				PatchType: module.PatchTypeStrategic,
+				Patch:     json.RawMessage(`{"data": {"key": "other"}}`),
+			},
+			expected: map[string]string{
+				"provider1+cluster1/web+ConfigMap": "key: other",
+			},
+		},
+		{
+			label: "Invalid JSON patch",
+			spec: module.ResourcePatchSpec{
+				App:       "app1",
+				Kind:      "Deployment",
+				PatchType: module.PatchTypeJSON,
+				Patch:     json.RawMessage(`[{"op": "remove", "path": "/spec/missing"}]`),
+			},
+			expErr: "Error patching resource web+Deployment",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			id := newTestAppContext(t)
+			setPatch(t, module.ResourcePatch{
+				Metadata: module.Metadata{Name: "patch1"},
+				Spec:     testCase.spec,
+			})
+
+			err := UpdateAppContext("intent1", id)
+			if err != nil {
+				if testCase.expErr == "" {
+					t.Fatalf("UpdateAppContext returned an unexpected error %s", err)
+				}
+				if strings.Contains(err.Error(), testCase.expErr) == false {
+					t.Fatalf("UpdateAppContext returned an unexpected error %s", err)
+				}
+				return
+			}
+			if testCase.expErr != "" {
+				t.Fatalf("UpdateAppContext did not return the expected error %s", testCase.expErr)
+			}
+			for res, exp := range testCase.expected {
+				parts := strings.SplitN(res, "/", 2)
+				value := getResource(t, id, parts[0], parts[1])
+				if strings.Contains(value, exp) == false {
+					t.Errorf("Resource %v was not patched as expected: %v not in\n%v", res, exp, value)
+				}
+			}
+			for res, unexp := range testCase.absent {
+				parts := strings.SplitN(res, "/", 2)
+				value := getResource(t, id, parts[0], parts[1])
+				if strings.Contains(value, unexp) {
+					t.Errorf("Resource %v was unexpectedly patched: %v in\n%v", res, unexp, value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package action
+
+import (
+	"github.com/onap/multicloud-k8s/src/genericaction/pkg/module"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/controllersdk"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// ApplyPatch applies the patch of the spec to the object according to its
+// patch type
+func ApplyPatch(obj *unstructured.Unstructured, spec module.ResourcePatchSpec) (*unstructured.Unstructured, error) {
+	switch spec.PatchType {
+	case module.PatchTypeJSON:
+		return controllersdk.ApplyJSONPatch(obj, spec.Patch)
+	case module.PatchTypeMerge:
+		return controllersdk.ApplyMergePatch(obj, spec.Patch)
+	case module.PatchTypeStrategic:
+		return applyStrategicMergePatch(obj, spec.Patch)
+	}
+	return nil, pkgerrors.Errorf("Unsupported patch type: %v", spec.PatchType)
+}
+
+// applyStrategicMergePatch applies a strategic merge patch to the object.
+// The merge strategy comes from the Go type of the object, so only the
+// built-in Kubernetes kinds can be patched this way.
+func applyStrategicMergePatch(obj *unstructured.Unstructured, patch []byte) (*unstructured.Unstructured, error) {
+	gvk := obj.GroupVersionKind()
+	schema, err := scheme.Scheme.New(gvk)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Strategic merge patch is not supported for %v, use a merge patch", gvk)
+	}
+
+	doc, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error encoding resource")
+	}
+	patched, err := strategicpatch.StrategicMergePatch(doc, patch, schema)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error applying strategic merge patch")
+	}
+	result := &unstructured.Unstructured{}
+	err = result.UnmarshalJSON(patched)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error decoding patched resource")
+	}
+	return result, nil
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2020 Intel Corporation.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contextupdateserver
+
+import (
+	"github.com/onap/multicloud-k8s/src/genericaction/internal/action"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/controllersdk"
+	contextpb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/grpc/contextupdate"
+)
+
+const controllerName = "genericaction"
+
+// genericactionController applies the resource patches of generic k8s intents
+type genericactionController struct{}
+
+// UpdateAppContext applies the generic k8s intent to the AppContext.
+// The patches are applied to the resources as rendered for the AppContext,
+// so on an update they are applied to the new AppContext just as on instantiation.
+func (c genericactionController) UpdateAppContext(intentName, appContextId string) error {
+	return action.UpdateAppContext(intentName, appContextId)
+}
+
+// NewController returns the genericaction controller
+func NewController() controllersdk.Controller {
+	return genericactionController{}
+}
+
+// NewContextUpdateServer exported
+func NewContextupdateServer() contextpb.ContextupdateServer {
+	return controllersdk.NewContextupdateServer(controllerName, NewController())
+}
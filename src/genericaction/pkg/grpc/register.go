@@ -0,0 +1,28 @@
+/*
+Copyright 2020 Intel Corporation.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc
+
+import (
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/controllersdk"
+)
+
+const default_port = 9033
+const default_genericaction_name = "genericaction"
+const ENV_GENERICACTION_NAME = "GENERICACTION_NAME"
+
+func GetServerHostPort() (string, int) {
+	// expect name of this genericaction program to be in env variable "GENERICACTION_NAME" - e.g. GENERICACTION_NAME="genericaction"
+	// and its service host and port in GENERICACTION_SERVICE_HOST and GENERICACTION_SERVICE_PORT
+	return controllersdk.GetServerHostPort(ENV_GENERICACTION_NAME, default_genericaction_name, default_port)
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// GenericK8sIntent groups the resource patches applied to a deployment intent group
+type GenericK8sIntent struct {
+	Metadata Metadata `json:"metadata"`
+}
+
+// GenericK8sIntentKey is the key structure that is used in the database
+type GenericK8sIntentKey struct {
+	GenericK8sIntent    string `json:"generick8sintent"`
+	Project             string `json:"project"`
+	CompositeApp        string `json:"compositeapp"`
+	CompositeAppVersion string `json:"compositeappversion"`
+	DigName             string `json:"deploymentintentgroup"`
+}
+
+// Manager is an interface exposing the GenericK8sIntent functionality
+type GenericK8sIntentManager interface {
+	CreateGenericK8sIntent(gki GenericK8sIntent, project, compositeapp, compositeappversion, dig string, exists bool) (GenericK8sIntent, error)
+	GetGenericK8sIntent(name, project, compositeapp, compositeappversion, dig string) (GenericK8sIntent, error)
+	GetGenericK8sIntents(project, compositeapp, compositeappversion, dig string) ([]GenericK8sIntent, error)
+	DeleteGenericK8sIntent(name, project, compositeapp, compositeappversion, dig string) error
+}
+
+// GenericK8sIntentClient implements the Manager
+// It will also be used to maintain some localized state
+type GenericK8sIntentClient struct {
+	db ClientDbInfo
+}
+
+// NewGenericK8sIntentClient returns an instance of the GenericK8sIntentClient
+// which implements the Manager
+func NewGenericK8sIntentClient() *GenericK8sIntentClient {
+	return &GenericK8sIntentClient{
+		db: ClientDbInfo{
+			storeName: "orchestrator",
+			tagMeta:   "generick8sintentmetadata",
+		},
+	}
+}
+
+// CreateGenericK8sIntent - create a new GenericK8sIntent
+func (v *GenericK8sIntentClient) CreateGenericK8sIntent(gki GenericK8sIntent, project, compositeapp, compositeappversion, dig string, exists bool) (GenericK8sIntent, error) {
+
+	//Construct key and tag to select the entry
+	key := GenericK8sIntentKey{
+		GenericK8sIntent:    gki.Metadata.Name,
+		Project:             project,
+		CompositeApp:        compositeapp,
+		CompositeAppVersion: compositeappversion,
+		DigName:             dig,
+	}
+
+	//Check if this GenericK8sIntent already exists
+	_, err := v.GetGenericK8sIntent(gki.Metadata.Name, project, compositeapp, compositeappversion, dig)
+	if err == nil && !exists {
+		return GenericK8sIntent{}, pkgerrors.New("GenericK8sIntent already exists")
+	}
+
+	err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagMeta, gki)
+	if err != nil {
+		return GenericK8sIntent{}, pkgerrors.Wrap(err, "Creating DB Entry")
+	}
+
+	return gki, nil
+}
+
+// GetGenericK8sIntent returns the GenericK8sIntent for corresponding name
+func (v *GenericK8sIntentClient) GetGenericK8sIntent(name, project, compositeapp, compositeappversion, dig string) (GenericK8sIntent, error) {
+
+	//Construct key and tag to select the entry
+	key := GenericK8sIntentKey{
+		GenericK8sIntent:    name,
+		Project:             project,
+		CompositeApp:        compositeapp,
+		CompositeAppVersion: compositeappversion,
+		DigName:             dig,
+	}
+
+	value, err := db.DBconn.Find(v.db.storeName, key, v.db.tagMeta)
+	if err != nil {
+		return GenericK8sIntent{}, pkgerrors.Wrap(err, "Get GenericK8sIntent")
+	}
+
+	//value is a byte array
+	if value != nil {
+		gki := GenericK8sIntent{}
+		err = db.DBconn.Unmarshal(value[0], &gki)
+		if err != nil {
+			return GenericK8sIntent{}, pkgerrors.Wrap(err, "Unmarshalling Value")
+		}
+		return gki, nil
+	}
+
+	return GenericK8sIntent{}, pkgerrors.New("Error getting GenericK8sIntent")
+}
+
+// GetGenericK8sIntentList returns all of the GenericK8sIntent for corresponding name
+func (v *GenericK8sIntentClient) GetGenericK8sIntents(project, compositeapp, compositeappversion, dig string) ([]GenericK8sIntent, error) {
+
+	//Construct key and tag to select the entry
+	key := GenericK8sIntentKey{
+		GenericK8sIntent:    "",
+		Project:             project,
+		CompositeApp:        compositeapp,
+		CompositeAppVersion: compositeappversion,
+		DigName:             dig,
+	}
+
+	var resp []GenericK8sIntent
+	values, err := db.DBconn.Find(v.db.storeName, key, v.db.tagMeta)
+	if err != nil {
+		return []GenericK8sIntent{}, pkgerrors.Wrap(err, "Get GenericK8sIntents")
+	}
+
+	for _, value := range values {
+		gki := GenericK8sIntent{}
+		err = db.DBconn.Unmarshal(value, &gki)
+		if err != nil {
+			return []GenericK8sIntent{}, pkgerrors.Wrap(err, "Unmarshalling Value")
+		}
+		resp = append(resp, gki)
+	}
+
+	return resp, nil
+}
+
+// Delete the  GenericK8sIntent from database
+func (v *GenericK8sIntentClient) DeleteGenericK8sIntent(name, project, compositeapp, compositeappversion, dig string) error {
+
+	//Construct key and tag to select the entry
+	key := GenericK8sIntentKey{
+		GenericK8sIntent:    name,
+		Project:             project,
+		CompositeApp:        compositeapp,
+		CompositeAppVersion: compositeappversion,
+		DigName:             dig,
+	}
+
+	err := db.DBconn.Remove(v.db.storeName, key)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Delete GenericK8sIntent Entry;")
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"encoding/json"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Supported patch types
+const (
+	PatchTypeJSON      = "json"
+	PatchTypeMerge     = "merge"
+	PatchTypeStrategic = "strategic"
+)
+
+// ResourcePatch is a patch applied to the resources selected by its spec
+type ResourcePatch struct {
+	Metadata Metadata          `json:"metadata"`
+	Spec     ResourcePatchSpec `json:"spec"`
+}
+
+// ResourcePatchSpec selects the resources to patch and holds the patch.
+// Resources are selected by app and optionally narrowed by kind, name and
+// label selector. The patch applies to all clusters of the app unless it is
+// scoped to a cluster or to the clusters with a label of a cluster provider.
+type ResourcePatchSpec struct {
+	App             string          `json:"app"`
+	Kind            string          `json:"kind,omitempty"`
+	Name            string          `json:"name,omitempty"`
+	LabelSelector   string          `json:"labelSelector,omitempty"`
+	ClusterProvider string          `json:"clusterProvider,omitempty"`
+	Cluster         string          `json:"cluster,omitempty"`
+	ClusterLabel    string          `json:"clusterLabel,omitempty"`
+	PatchType       string          `json:"patchType"`
+	Patch           json.RawMessage `json:"patch"`
+}
+
+// ResourcePatchKey is the key structure that is used in the database
+type ResourcePatchKey struct {
+	Project             string `json:"project"`
+	CompositeApp        string `json:"compositeapp"`
+	CompositeAppVersion string `json:"compositeappversion"`
+	DigName             string `json:"deploymentintentgroup"`
+	GenericK8sIntent    string `json:"generick8sintent"`
+	ResourcePatch       string `json:"resourcepatch"`
+}
+
+// Manager is an interface exposing the ResourcePatch functionality
+type ResourcePatchManager interface {
+	CreateResourcePatch(rp ResourcePatch, project, compositeapp, compositeappversion, dig, generick8sintent string, exists bool) (ResourcePatch, error)
+	GetResourcePatch(name, project, compositeapp, compositeappversion, dig, generick8sintent string) (ResourcePatch, error)
+	GetResourcePatches(project, compositeapp, compositeappversion, dig, generick8sintent string) ([]ResourcePatch, error)
+	DeleteResourcePatch(name, project, compositeapp, compositeappversion, dig, generick8sintent string) error
+}
+
+// ResourcePatchClient implements the Manager
+// It will also be used to maintain some localized state
+type ResourcePatchClient struct {
+	db ClientDbInfo
+}
+
+// NewResourcePatchClient returns an instance of the ResourcePatchClient
+// which implements the Manager
+func NewResourcePatchClient() *ResourcePatchClient {
+	return &ResourcePatchClient{
+		db: ClientDbInfo{
+			storeName: "orchestrator",
+			tagMeta:   "resourcepatchmetadata",
+		},
+	}
+}
+
+// CreateResourcePatch - create a new ResourcePatch
+func (v *ResourcePatchClient) CreateResourcePatch(rp ResourcePatch, project, compositeapp, compositeappversion, dig, generick8sintent string, exists bool) (ResourcePatch, error) {
+
+	//Construct key and tag to select the entry
+	key := ResourcePatchKey{
+		Project:             project,
+		CompositeApp:        compositeapp,
+		CompositeAppVersion: compositeappversion,
+		DigName:             dig,
+		GenericK8sIntent:    generick8sintent,
+		ResourcePatch:       rp.Metadata.Name,
+	}
+
+	//Check if the Generic K8s Intent exists
+	_, err := NewGenericK8sIntentClient().GetGenericK8sIntent(generick8sintent, project, compositeapp, compositeappversion, dig)
+	if err != nil {
+		return ResourcePatch{}, pkgerrors.Errorf("GenericK8sIntent %v does not exist", generick8sintent)
+	}
+
+	//Check if this ResourcePatch already exists
+	_, err = v.GetResourcePatch(rp.Metadata.Name, project, compositeapp, compositeappversion, dig, generick8sintent)
+	if err == nil && !exists {
+		return ResourcePatch{}, pkgerrors.New("ResourcePatch already exists")
+	}
+
+	err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagMeta, rp)
+	if err != nil {
+		return ResourcePatch{}, pkgerrors.Wrap(err, "Creating DB Entry")
+	}
+
+	return rp, nil
+}
+
+// GetResourcePatch returns the ResourcePatch for corresponding name
+func (v *ResourcePatchClient) GetResourcePatch(name, project, compositeapp, compositeappversion, dig, generick8sintent string) (ResourcePatch, error) {
+
+	//Construct key and tag to select the entry
+	key := ResourcePatchKey{
+		Project:             project,
+		CompositeApp:        compositeapp,
+		CompositeAppVersion: compositeappversion,
+		DigName:             dig,
+		GenericK8sIntent:    generick8sintent,
+		ResourcePatch:       name,
+	}
+
+	value, err := db.DBconn.Find(v.db.storeName, key, v.db.tagMeta)
+	if err != nil {
+		return ResourcePatch{}, pkgerrors.Wrap(err, "Get ResourcePatch")
+	}
+
+	//value is a byte array
+	if value != nil {
+		rp := ResourcePatch{}
+		err = db.DBconn.Unmarshal(value[0], &rp)
+		if err != nil {
+			return ResourcePatch{}, pkgerrors.Wrap(err, "Unmarshalling Value")
+		}
+		return rp, nil
+	}
+
+	return ResourcePatch{}, pkgerrors.New("Error getting ResourcePatch")
+}
+
+// GetResourcePatches returns all of the ResourcePatches of the GenericK8sIntent
+func (v *ResourcePatchClient) GetResourcePatches(project, compositeapp, compositeappversion, dig, generick8sintent string) ([]ResourcePatch, error) {
+
+	//Construct key and tag to select the entry
+	key := ResourcePatchKey{
+		Project:             project,
+		CompositeApp:        compositeapp,
+		CompositeAppVersion: compositeappversion,
+		DigName:             dig,
+		GenericK8sIntent:    generick8sintent,
+		ResourcePatch:       "",
+	}
+
+	var resp []ResourcePatch
+	values, err := db.DBconn.Find(v.db.storeName, key, v.db.tagMeta)
+	if err != nil {
+		return []ResourcePatch{}, pkgerrors.Wrap(err, "Get ResourcePatches")
+	}
+
+	for _, value := range values {
+		rp := ResourcePatch{}
+		err = db.DBconn.Unmarshal(value, &rp)
+		if err != nil {
+			return []ResourcePatch{}, pkgerrors.Wrap(err, "Unmarshalling Value")
+		}
+		resp = append(resp, rp)
+	}
+
+	return resp, nil
+}
+
+// Delete the ResourcePatch from database
+func (v *ResourcePatchClient) DeleteResourcePatch(name, project, compositeapp, compositeappversion, dig, generick8sintent string) error {
+
+	//Construct key and tag to select the entry
+	key := ResourcePatchKey{
+		Project:             project,
+		CompositeApp:        compositeapp,
+		CompositeAppVersion: compositeappversion,
+		DigName:             dig,
+		GenericK8sIntent:    generick8sintent,
+		ResourcePatch:       name,
+	}
+
+	err := db.DBconn.Remove(v.db.storeName, key)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Delete ResourcePatch Entry;")
+	}
+
+	return nil
+}
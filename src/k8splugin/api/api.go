@@ -129,6 +129,13 @@ func NewRouter(defClient rb.DefinitionManager,
 	resRouter.HandleFunc("/definition/{rbname}/{rbversion}", defHandler.updateHandler).Methods("PUT")
 	resRouter.HandleFunc("/definition/{rbname}/{rbversion}", defHandler.deleteHandler).Methods("DELETE")
 
+	//Setup the credentials of the repositories the definitions reference
+	credHandler := repoCredentialHandler{client: rb.NewRepoCredentialClient()}
+	resRouter.HandleFunc("/credential", credHandler.createHandler).Methods("POST")
+	resRouter.HandleFunc("/credential", credHandler.listHandler).Methods("GET")
+	resRouter.HandleFunc("/credential/{credname}", credHandler.getHandler).Methods("GET")
+	resRouter.HandleFunc("/credential/{credname}", credHandler.deleteHandler).Methods("DELETE")
+
 	//Setup resource bundle profile routes
 	if profileClient == nil {
 		profileClient = rb.NewProfileClient()
@@ -184,5 +191,7 @@ func NewRouter(defClient rb.DefinitionManager,
 	// Add healthcheck path
 	instRouter.HandleFunc("/healthcheck", healthCheckHandler).Methods("GET")
 
+	router.HandleFunc("/v2/openapi.json", openAPISpec().Handler(router)).Methods("GET")
+
 	return router
 }
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/onap/multicloud-k8s/src/k8splugin/internal/app"
+	"github.com/onap/multicloud-k8s/src/k8splugin/internal/connection"
+	"github.com/onap/multicloud-k8s/src/k8splugin/internal/healthcheck"
+	"github.com/onap/multicloud-k8s/src/k8splugin/internal/rb"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/openapi"
+)
+
+const (
+	specInstancePath   = "/v1/instance/{instID}"
+	specConfigPath     = specInstancePath + "/config/{cfgname}"
+	specDefinitionPath = "/v1/rb/definition/{rbname}/{rbversion}"
+	specBrokerPath     = "/{cloud-owner}/{cloud-region}/infra_workload"
+)
+
+// openAPISpec documents the routes of NewRouter. Every route must have an
+// operation, see TestOpenAPISpec.
+func openAPISpec() openapi.Spec {
+	return openapi.Spec{
+		Title:   "Multicloud K8s Plugin",
+		Version: "v1",
+		Operations: map[string]openapi.Operation{
+			"GET /v2/openapi.json": {Summary: "Get the OpenAPI specification of the service", Response: map[string]interface{}{}},
+			"GET /v1/healthcheck":  {Summary: "Check the health of the service"},
+
+			"POST /v1/instance":                                           {Summary: "Create an instance of a resource bundle", Request: app.InstanceRequest{}, Response: app.InstanceResponse{}, Status: http.StatusCreated},
+			"GET /v1/instance":                                            {Summary: "List the instances", Response: []app.InstanceMiniResponse{}},
+			"GET " + specInstancePath:                                     {Summary: "Get an instance, with all its data when full is true", Response: app.InstanceResponse{}, Queries: []string{"full"}},
+			"DELETE " + specInstancePath:                                  {Summary: "Delete an instance", Status: http.StatusAccepted},
+			"POST " + specInstancePath + "/upgrade":                       {Summary: "Upgrade an instance", Request: app.UpgradeRequest{}, Response: app.InstanceResponse{}, Status: http.StatusCreated},
+			"GET " + specInstancePath + "/query":                          {Summary: "Query the resources of an instance", Response: app.InstanceStatus{}},
+			"GET " + specInstancePath + "/status":                         {Summary: "Get the status of an instance", Response: app.InstanceStatus{}},
+			"GET " + specInstancePath + "/status/subscription":            {Summary: "List the status subscriptions of an instance", Response: []app.StatusSubscription{}},
+			"POST " + specInstancePath + "/status/subscription":           {Summary: "Subscribe to the status of an instance", Request: app.SubscriptionRequest{}, Response: app.StatusSubscription{}, Status: http.StatusCreated},
+			"GET " + specInstancePath + "/status/subscription/{subID}":    {Summary: "Get a status subscription", Response: app.StatusSubscription{}},
+			"PUT " + specInstancePath + "/status/subscription/{subID}":    {Summary: "Update a status subscription", Request: app.SubscriptionRequest{}, Response: app.StatusSubscription{}},
+			"DELETE " + specInstancePath + "/status/subscription/{subID}": {Summary: "Delete a status subscription", Status: http.StatusAccepted},
+			"GET " + specInstancePath + "/healthcheck":                    {Summary: "List the health checks of an instance", Response: healthcheck.InstanceHCOverview{}},
+			"POST " + specInstancePath + "/healthcheck":                   {Summary: "Start a health check of an instance", Response: healthcheck.InstanceMiniHCStatus{}, Status: http.StatusCreated},
+			"GET " + specInstancePath + "/healthcheck/{hcID}":             {Summary: "Get a health check of an instance", Response: healthcheck.InstanceHCStatus{}},
+			"DELETE " + specInstancePath + "/healthcheck/{hcID}":          {Summary: "Delete a health check of an instance", Status: http.StatusAccepted},
+			"GET /v1/query": {Summary: "Query the resources of the instances", Response: app.QueryStatus{}},
+
+			"POST " + specInstancePath + "/config":            {Summary: "Create a configuration of an instance", Request: app.Config{}, Response: app.ConfigResult{}, Status: http.StatusCreated},
+			"GET " + specInstancePath + "/config":             {Summary: "List the configurations of an instance", Response: []app.Config{}},
+			"GET " + specConfigPath:                           {Summary: "Get a configuration", Response: app.Config{}},
+			"PUT " + specConfigPath:                           {Summary: "Update a configuration", Request: app.Config{}, Response: app.ConfigResult{}, Status: http.StatusCreated},
+			"DELETE " + specConfigPath:                        {Summary: "Delete a configuration and its resources, or the configuration only when deleteConfigOnly is true", Status: http.StatusAccepted, Queries: []string{"deleteConfigOnly"}},
+			"POST " + specConfigPath + "/delete":              {Summary: "Delete the resources of a configuration", Response: app.ConfigResult{}},
+			"POST " + specConfigPath + "/rollback":            {Summary: "Roll a configuration back to a version or a tag", Request: app.ConfigRollback{}.AnyOf, Response: app.ConfigResult{}},
+			"POST " + specConfigPath + "/tagit":               {Summary: "Tag the current version of a configuration", Request: app.ConfigTagit{}, Response: app.ConfigTag{}},
+			"GET " + specConfigPath + "/tag":                  {Summary: "List the tags of a configuration", Response: []app.ConfigTag{}},
+			"GET " + specConfigPath + "/tag/{tagname}":        {Summary: "Get a configuration by its tag", Response: app.Config{}},
+			"GET " + specConfigPath + "/version":              {Summary: "List the versions of a configuration", Response: []app.Config{}},
+			"GET " + specConfigPath + "/version/{cfgversion}": {Summary: "Get a version of a configuration", Response: app.Config{}},
+
+			"POST " + specBrokerPath:                 {Summary: "Create an instance through the multicloud broker", Request: brokerRequest{}, Response: brokerPOSTResponse{}, Status: http.StatusCreated},
+			"GET " + specBrokerPath:                  {Summary: "Find an instance by its name through the multicloud broker", Response: brokerGETResponse{}},
+			"GET " + specBrokerPath + "/{instID}":    {Summary: "Get an instance through the multicloud broker", Response: brokerGETResponse{}},
+			"DELETE " + specBrokerPath + "/{instID}": {Summary: "Delete an instance through the multicloud broker", Response: brokerDELETEResponse{}, Status: http.StatusAccepted},
+
+			"POST /v1/connectivity-info":              {Summary: "Register the connectivity information of a cloud region", Request: connection.Connection{}, Files: []string{"file"}, Response: connection.Connection{}, Status: http.StatusCreated},
+			"GET /v1/connectivity-info/{connname}":    {Summary: "Get the connectivity information of a cloud region", Response: connection.Connection{}},
+			"DELETE /v1/connectivity-info/{connname}": {Summary: "Delete the connectivity information of a cloud region", Status: http.StatusNoContent},
+
+			"POST /v1/rb/definition":                  {Summary: "Create a resource bundle definition", Request: rb.Definition{}, Response: rb.Definition{}, Status: http.StatusCreated},
+			"GET /v1/rb/definition":                   {Summary: "List the resource bundle definitions", Response: []rb.Definition{}},
+			"GET /v1/rb/definition/{rbname}":          {Summary: "List the versions of a resource bundle definition", Response: []rb.Definition{}},
+			"GET " + specDefinitionPath:               {Summary: "Get a resource bundle definition", Response: rb.Definition{}},
+			"PUT " + specDefinitionPath:               {Summary: "Update a resource bundle definition", Request: rb.Definition{}, Response: rb.Definition{}, Status: http.StatusCreated},
+			"DELETE " + specDefinitionPath:            {Summary: "Delete a resource bundle definition", Status: http.StatusNoContent},
+			"POST " + specDefinitionPath + "/content": {Summary: "Upload the helm chart of a resource bundle definition", Request: []byte{}, RequestContentType: "application/octet-stream"},
+			"POST /v1/rb/credential":                  {Summary: "Create a repository credential", Request: rb.RepoCredential{}, Response: rb.RepoCredential{}, Status: http.StatusCreated},
+			"GET /v1/rb/credential":                   {Summary: "List the repository credentials", Response: []rb.RepoCredential{}},
+			"GET /v1/rb/credential/{credname}":        {Summary: "Get a repository credential", Response: rb.RepoCredential{}},
+			"DELETE /v1/rb/credential/{credname}":     {Summary: "Delete a repository credential", Status: http.StatusNoContent},
+
+			"POST " + specDefinitionPath + "/profile":                  {Summary: "Create a profile", Request: rb.Profile{}, Response: rb.Profile{}, Status: http.StatusCreated},
+			"GET " + specDefinitionPath + "/profile":                   {Summary: "List the profiles", Response: []rb.Profile{}},
+			"GET " + specDefinitionPath + "/profile/{prname}":          {Summary: "Get a profile", Response: rb.Profile{}},
+			"PUT " + specDefinitionPath + "/profile/{prname}":          {Summary: "Update a profile", Request: rb.Profile{}, Response: rb.Profile{}},
+			"DELETE " + specDefinitionPath + "/profile/{prname}":       {Summary: "Delete a profile", Status: http.StatusNoContent},
+			"POST " + specDefinitionPath + "/profile/{prname}/content": {Summary: "Upload the overrides of a profile", Request: []byte{}, RequestContentType: "application/octet-stream"},
+
+			"POST " + specDefinitionPath + "/config-template":                 {Summary: "Create a configuration template", Request: rb.ConfigTemplate{}, Response: rb.ConfigTemplate{}, Status: http.StatusCreated},
+			"GET " + specDefinitionPath + "/config-template":                  {Summary: "List the configuration templates", Response: []rb.ConfigTemplateList{}},
+			"GET " + specDefinitionPath + "/config-template/{tname}":          {Summary: "Get a configuration template", Response: rb.ConfigTemplate{}},
+			"PUT " + specDefinitionPath + "/config-template/{tname}":          {Summary: "Update a configuration template", Request: rb.ConfigTemplate{}, Response: rb.ConfigTemplate{}, Status: http.StatusCreated},
+			"DELETE " + specDefinitionPath + "/config-template/{tname}":       {Summary: "Delete a configuration template", Status: http.StatusNoContent},
+			"POST " + specDefinitionPath + "/config-template/{tname}/content": {Summary: "Upload the helm chart of a configuration template", Request: []byte{}, RequestContentType: "application/octet-stream"},
+		},
+	}
+}
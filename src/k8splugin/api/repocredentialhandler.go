@@ -0,0 +1,134 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/onap/multicloud-k8s/src/k8splugin/internal/rb"
+
+	"github.com/gorilla/mux"
+)
+
+// repoCredentialHandler is used to store backend implementations objects
+// Also simplifies mocking for unit testing purposes
+type repoCredentialHandler struct {
+	// Interface that implements repository credential operations
+	// We will set this variable with a mock interface for testing
+	client rb.RepoCredentialManager
+}
+
+// maskRepoCredential leaves the password and the token out of the responses
+func maskRepoCredential(c rb.RepoCredential) rb.RepoCredential {
+	c.Password = ""
+	c.Token = ""
+	return c
+}
+
+// createHandler handles creation of the credential entry in the database
+func (h repoCredentialHandler) createHandler(w http.ResponseWriter, r *http.Request) {
+	var v rb.RepoCredential
+
+	err := json.NewDecoder(r.Body).Decode(&v)
+	switch {
+	case err == io.EOF:
+		http.Error(w, "Empty body", http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Name is required.
+	if v.Name == "" {
+		http.Error(w, "Missing name in POST request", http.StatusBadRequest)
+		return
+	}
+
+	ret, err := h.client.Create(r.Context(), v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(maskRepoCredential(ret))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// listHandler handles GET operations on all the credentials
+// The passwords and tokens are not returned
+func (h repoCredentialHandler) listHandler(w http.ResponseWriter, r *http.Request) {
+	ret, err := h.client.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for i := range ret {
+		ret[i] = maskRepoCredential(ret[i])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// getHandler handles GET operations on a particular name
+// The password and token are not returned
+func (h repoCredentialHandler) getHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["credname"]
+
+	ret, err := h.client.Get(r.Context(), name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(maskRepoCredential(ret))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// deleteHandler handles DELETE operations on a particular credential
+func (h repoCredentialHandler) deleteHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["credname"]
+
+	err := h.client.Delete(r.Context(), name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
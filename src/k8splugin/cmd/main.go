@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"math/rand"
 	"net/http"
@@ -26,19 +27,44 @@ import (
 	"github.com/onap/multicloud-k8s/src/k8splugin/api"
 	"github.com/onap/multicloud-k8s/src/k8splugin/internal/auth"
 	"github.com/onap/multicloud-k8s/src/k8splugin/internal/config"
+	"github.com/onap/multicloud-k8s/src/k8splugin/internal/connection"
+	"github.com/onap/multicloud-k8s/src/k8splugin/internal/rb"
 	"github.com/onap/multicloud-k8s/src/k8splugin/internal/tracing"
 	"github.com/onap/multicloud-k8s/src/k8splugin/internal/utils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
 
 	"github.com/gorilla/handlers"
 )
 
 func main() {
+	reencrypt := flag.Bool("reencrypt-secrets", false, "Re-encrypt the secrets stored in the database with the primary key, and exit")
+	rotate := flag.Bool("rotate-secret-key", false, "Create a new primary key before re-encrypting the secrets, and exit")
+	flag.Parse()
 
 	err := utils.CheckInitialSettings()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	conf := config.GetConfiguration()
+	secret.Configure(secret.Config{KeyProvider: conf.SecretKeyProvider, KeyFile: conf.SecretKeyFile})
+
+	if *reencrypt || *rotate {
+		n, err := secret.Migrate(*rotate, func() (int, error) {
+			n, err := connection.NewConnectionClient().ReencryptSecrets(context.Background())
+			if err != nil {
+				return n, err
+			}
+			m, err := rb.NewRepoCredentialClient().ReencryptSecrets(context.Background())
+			return n + m, err
+		})
+		if err != nil {
+			log.Fatalln("Error re-encrypting secrets:", err)
+		}
+		log.Printf("Re-encrypted %d secrets", n)
+		return
+	}
+
 	rand.Seed(time.Now().UnixNano())
 
 	i := tracing.NewInstrumentation()
@@ -3,10 +3,11 @@ module github.com/onap/multicloud-k8s/src/k8splugin
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/ghodss/yaml v1.0.0
-	github.com/gorilla/handlers v1.3.0
+	github.com/gorilla/handlers v1.4.0
 	github.com/gorilla/mux v1.8.0
 	github.com/hashicorp/consul/api v1.5.0
 	github.com/moby/moby v23.0.18+incompatible
+	github.com/onap/multicloud-k8s/src/orchestrator v0.0.0-20251205113806-246f4e4b1e9f
 	github.com/pkg/errors v0.9.1
 	github.com/sirupsen/logrus v1.7.0
 	github.com/spf13/cobra v1.1.3 // indirect
@@ -25,13 +26,15 @@ require (
 	k8s.io/apiextensions-apiserver v0.20.1
 	k8s.io/apimachinery v0.20.1
 	k8s.io/cli-runtime v0.20.1
-	k8s.io/client-go v0.20.1
+	k8s.io/client-go v12.0.0+incompatible
 	rsc.io/letsencrypt v0.0.3 // indirect
 )
 
 replace (
 	github.com/containerd/containerd => github.com/containerd/containerd v1.4.3
 	github.com/hashicorp/consul => github.com/hashicorp/consul v1.5.1
+	github.com/onap/multicloud-k8s/src/monitor => ../monitor
+	github.com/onap/multicloud-k8s/src/orchestrator => ../orchestrator
 	k8s.io/api => k8s.io/api v0.19.4
 	k8s.io/apiextensions-apiserver => k8s.io/apiextensions-apiserver v0.19.4
 	k8s.io/apimachinery => k8s.io/apimachinery v0.19.4
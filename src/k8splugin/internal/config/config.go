@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
 )
 
@@ -39,6 +40,10 @@ type Configuration struct {
 	EtcdCAFile          string `json:"etcd-ca-file"`
 	ServicePort         string `json:"service-port"`
 	KubernetesLabelName string `json:"kubernetes-label-name"`
+	ChartCacheDir       string `json:"chart-cache-dir"`
+	ChartTokenHosts     string `json:"chart-token-hosts"`
+	SecretKeyProvider   string `json:"secret-key-provider"`
+	SecretKeyFile       string `json:"secret-key-file"`
 }
 
 // Config is the structure that stores the configuration
@@ -88,6 +93,10 @@ func defaultConfiguration() *Configuration {
 		EtcdCAFile:          "",
 		ServicePort:         "9015",
 		KubernetesLabelName: "k8splugin.io/rb-instance-id",
+		ChartCacheDir:       filepath.Join(os.TempDir(), "k8splugin-chart-cache"),
+		ChartTokenHosts:     "",
+		SecretKeyProvider:   "",
+		SecretKeyFile:       "secret-keys.json",
 	}
 }
 
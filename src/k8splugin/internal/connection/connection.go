@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 
 	"github.com/onap/multicloud-k8s/src/k8splugin/internal/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
 
 	pkgerrors "github.com/pkg/errors"
 )
@@ -41,6 +42,10 @@ type ConnectivityRecordList struct {
 	ConnectivityRecords []map[string]string `json:"connectivity-records"`
 }
 
+// secretRecordFields are the connectivity record fields which are encrypted
+// in the database
+var secretRecordFields = []string{"password", "private-key"}
+
 // ConnectionKey is the key structure that is used in the database
 type ConnectionKey struct {
 	CloudRegion string `json:"cloud-region"`
@@ -93,7 +98,12 @@ func (v *ConnectionClient) Create(ctx context.Context, c Connection) (Connection
 		return Connection{}, pkgerrors.New("Connection already exists")
 	}
 
-	err = db.DBconn.Create(ctx, v.storeName, key, v.tagMeta, c)
+	encrypted, _, err := transformSecrets(c, secret.Encrypt)
+	if err != nil {
+		return Connection{}, pkgerrors.Wrap(err, "Encrypting Connection")
+	}
+
+	err = db.DBconn.Create(ctx, v.storeName, key, v.tagMeta, encrypted)
 	if err != nil {
 		return Connection{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -118,6 +128,10 @@ func (v *ConnectionClient) Get(ctx context.Context, name string) (Connection, er
 		if err != nil {
 			return Connection{}, pkgerrors.Wrap(err, "Unmarshaling Value")
 		}
+		c, _, err = transformSecrets(c, secret.Decrypt)
+		if err != nil {
+			return Connection{}, pkgerrors.Wrap(err, "Decrypting Connection")
+		}
 		return c, nil
 	}
 
@@ -169,6 +183,81 @@ func (v *ConnectionClient) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
+// transformSecrets returns a copy of the Connection with the kubeconfig and
+// the secret connectivity record fields transformed by f, and whether any of
+// them changed
+func transformSecrets(c Connection, f func(string) (string, error)) (Connection, bool, error) {
+	changed := false
+	apply := func(value string) (string, error) {
+		out, err := f(value)
+		if err == nil && out != value {
+			changed = true
+		}
+		return out, err
+	}
+
+	out := c
+	var err error
+	out.Kubeconfig, err = apply(c.Kubeconfig)
+	if err != nil {
+		return Connection{}, false, err
+	}
+	out.OtherConnectivityList.ConnectivityRecords = nil
+	for _, record := range c.OtherConnectivityList.ConnectivityRecords {
+		r := map[string]string{}
+		for k, value := range record {
+			r[k] = value
+		}
+		for _, k := range secretRecordFields {
+			if value, ok := r[k]; ok {
+				r[k], err = apply(value)
+				if err != nil {
+					return Connection{}, false, err
+				}
+			}
+		}
+		out.OtherConnectivityList.ConnectivityRecords = append(out.OtherConnectivityList.ConnectivityRecords, r)
+	}
+	return out, changed, nil
+}
+
+// ReencryptSecrets re-encrypts the secrets of the Connections with the
+// primary secret key, and returns the number of Connections updated. It is
+// run by the secret migration command of the plugin.
+func (v *ConnectionClient) ReencryptSecrets(ctx context.Context) (int, error) {
+	values, err := db.DBconn.ReadAll(ctx, v.storeName, v.tagMeta)
+	if err != nil {
+		return 0, pkgerrors.Wrap(err, "Listing Connections")
+	}
+
+	count := 0
+	for _, value := range values {
+		c := Connection{}
+		err = db.DBconn.Unmarshal(value, &c)
+		if err != nil {
+			return count, pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		reencrypt := func(value string) (string, error) {
+			out, _, err := secret.Reencrypt(value)
+			return out, err
+		}
+		reencrypted, changed, err := transformSecrets(c, reencrypt)
+		if err != nil {
+			return count, pkgerrors.Wrapf(err, "Re-encrypting Connection %s", c.CloudRegion)
+		}
+		if !changed {
+			continue
+		}
+		key := ConnectionKey{CloudRegion: c.CloudRegion}
+		err = db.DBconn.Update(ctx, v.storeName, key, v.tagMeta, reencrypted)
+		if err != nil {
+			return count, pkgerrors.Wrap(err, "Updating DB Entry")
+		}
+		count++
+	}
+	return count, nil
+}
+
 // Download the connection information onto a kubeconfig file
 // The file is named after the name of the connection and will
 // be placed in the provided parent directory
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/onap/multicloud-k8s/src/k8splugin/internal/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// RepoCredential authenticates against the Helm repositories and OCI
+// registries that the definitions reference. It contains either a username
+// and password, or a token.
+type RepoCredential struct {
+	Name     string `json:"name"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// transform applies f, which encrypts or decrypts, to the password and the
+// token
+func (c RepoCredential) transform(f func(string) (string, error)) (RepoCredential, error) {
+	var err error
+	c.Password, err = f(c.Password)
+	if err != nil {
+		return RepoCredential{}, pkgerrors.Wrap(err, "Password")
+	}
+	c.Token, err = f(c.Token)
+	if err != nil {
+		return RepoCredential{}, pkgerrors.Wrap(err, "Token")
+	}
+	return c, nil
+}
+
+// RepoCredentialKey is the key structure that is used in the database
+type RepoCredentialKey struct {
+	Name string `json:"name"`
+}
+
+// We will use json marshalling to convert to string to
+// preserve the underlying structure.
+func (k RepoCredentialKey) String() string {
+	out, err := json.Marshal(k)
+	if err != nil {
+		return ""
+	}
+
+	return string(out)
+}
+
+// RepoCredentialManager is an interface exposes the repository credential functionality
+type RepoCredentialManager interface {
+	Create(ctx context.Context, c RepoCredential) (RepoCredential, error)
+	List(ctx context.Context) ([]RepoCredential, error)
+	Get(ctx context.Context, name string) (RepoCredential, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// RepoCredentialClient implements the RepoCredentialManager
+type RepoCredentialClient struct {
+	storeName string
+	tagMeta   string
+}
+
+// NewRepoCredentialClient returns an instance of the RepoCredentialClient
+// which implements the RepoCredentialManager
+// Uses rbdef collection in underlying db
+func NewRepoCredentialClient() *RepoCredentialClient {
+	return &RepoCredentialClient{
+		storeName: "rbdef",
+		tagMeta:   "repocredential",
+	}
+}
+
+// Create an entry for the credential in the database
+func (v *RepoCredentialClient) Create(ctx context.Context, c RepoCredential) (RepoCredential, error) {
+	if c.Name == "" {
+		return RepoCredential{}, pkgerrors.New("Name is required for a repository credential")
+	}
+	if c.Token == "" && (c.Username == "" || c.Password == "") {
+		return RepoCredential{}, pkgerrors.New("A repository credential requires a username and password, or a token")
+	}
+
+	key := RepoCredentialKey{Name: c.Name}
+	_, err := v.Get(ctx, c.Name)
+	if err == nil {
+		return RepoCredential{}, pkgerrors.New("Repository credential already exists")
+	}
+
+	// The password and the token are encrypted at rest
+	encrypted, err := c.transform(secret.Encrypt)
+	if err != nil {
+		return RepoCredential{}, pkgerrors.Wrap(err, "Encrypting Repository Credential")
+	}
+	err = db.DBconn.Create(ctx, v.storeName, key, v.tagMeta, encrypted)
+	if err != nil {
+		return RepoCredential{}, pkgerrors.Wrap(err, "Creating DB Entry")
+	}
+	return c, nil
+}
+
+// List all the credentials in the database
+func (v *RepoCredentialClient) List(ctx context.Context) ([]RepoCredential, error) {
+	res, err := db.DBconn.ReadAll(ctx, v.storeName, v.tagMeta)
+	if err != nil {
+		return []RepoCredential{}, pkgerrors.Wrap(err, "Listing Repository Credentials")
+	}
+
+	results := []RepoCredential{}
+	for _, value := range res {
+		c := RepoCredential{}
+		err = db.DBconn.Unmarshal(value, &c)
+		if err != nil {
+			return []RepoCredential{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		c, err = c.transform(secret.Decrypt)
+		if err != nil {
+			return []RepoCredential{}, pkgerrors.Wrap(err, "Decrypting Repository Credential")
+		}
+		results = append(results, c)
+	}
+	return results, nil
+}
+
+// Get returns the credential with the name
+func (v *RepoCredentialClient) Get(ctx context.Context, name string) (RepoCredential, error) {
+	key := RepoCredentialKey{Name: name}
+	value, err := db.DBconn.Read(ctx, v.storeName, key, v.tagMeta)
+	if err != nil {
+		return RepoCredential{}, pkgerrors.Wrap(err, "Get Repository Credential")
+	}
+
+	if value != nil {
+		c := RepoCredential{}
+		err = db.DBconn.Unmarshal(value, &c)
+		if err != nil {
+			return RepoCredential{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		c, err = c.transform(secret.Decrypt)
+		if err != nil {
+			return RepoCredential{}, pkgerrors.Wrap(err, "Decrypting Repository Credential")
+		}
+		return c, nil
+	}
+
+	return RepoCredential{}, pkgerrors.New("Error getting Repository Credential")
+}
+
+// ReencryptSecrets re-encrypts the passwords and the tokens of the
+// credentials with the primary secret key, and returns the number of
+// credentials updated. It is run by the secret migration command of the
+// plugin.
+func (v *RepoCredentialClient) ReencryptSecrets(ctx context.Context) (int, error) {
+	values, err := db.DBconn.ReadAll(ctx, v.storeName, v.tagMeta)
+	if err != nil {
+		return 0, pkgerrors.Wrap(err, "Listing Repository Credentials")
+	}
+
+	count := 0
+	for _, value := range values {
+		c := RepoCredential{}
+		err = db.DBconn.Unmarshal(value, &c)
+		if err != nil {
+			return count, pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		changed := false
+		reencrypt := func(value string) (string, error) {
+			out, ch, err := secret.Reencrypt(value)
+			changed = changed || ch
+			return out, err
+		}
+		c, err = c.transform(reencrypt)
+		if err != nil {
+			return count, pkgerrors.Wrapf(err, "Re-encrypting Repository Credential %s", c.Name)
+		}
+		if !changed {
+			continue
+		}
+		key := RepoCredentialKey{Name: c.Name}
+		err = db.DBconn.Update(ctx, v.storeName, key, v.tagMeta, c)
+		if err != nil {
+			return count, pkgerrors.Wrap(err, "Updating DB Entry")
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Delete the credential from the database
+func (v *RepoCredentialClient) Delete(ctx context.Context, name string) error {
+	key := RepoCredentialKey{Name: name}
+	err := db.DBconn.Delete(ctx, v.storeName, key, v.tagMeta)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Delete Repository Credential")
+	}
+	return nil
+}
@@ -26,8 +26,10 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/onap/multicloud-k8s/src/k8splugin/internal/config"
 	"github.com/onap/multicloud-k8s/src/k8splugin/internal/db"
 	"github.com/onap/multicloud-k8s/src/k8splugin/internal/logutils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/utils/chartsource"
 
 	pkgerrors "github.com/pkg/errors"
 )
@@ -40,6 +42,7 @@ type Definition struct {
 	ChartName   string            `json:"chart-name"`
 	Description string            `json:"description"`
 	Labels      map[string]string `json:"labels"`
+	Source      *ChartSource      `json:"source,omitempty"`
 }
 
 // DefinitionKey is the key structure that is used in the database
@@ -74,27 +77,51 @@ type DefinitionManager interface {
 type DefinitionClient struct {
 	storeName           string
 	tagMeta, tagContent string
+	fetcher             *chartsource.Fetcher
 }
 
 // NewDefinitionClient returns an instance of the DefinitionClient
 // which implements the DefinitionManager
 // Uses rbdef collection in underlying db
 func NewDefinitionClient() *DefinitionClient {
+	conf := config.GetConfiguration()
 	return &DefinitionClient{
 		storeName:  "rbdef",
 		tagMeta:    "defmetadata",
 		tagContent: "defcontent",
+		fetcher:    chartsource.NewFetcher(nil, conf.ChartCacheDir).TrustTokenHosts(conf.ChartTokenHosts),
 	}
 }
 
+// checkSource validates the source of the definition and names its chart
+// after the chart it references when no name was provided
+func checkSource(def *Definition) error {
+	if def.Source == nil {
+		return nil
+	}
+	err := def.Source.Validate()
+	if err != nil {
+		return pkgerrors.Wrap(err, "Invalid Definition source")
+	}
+	if def.ChartName == "" {
+		def.ChartName = chartsource.ChartName(def.Source.toChartSource())
+	}
+	return nil
+}
+
 // Create an entry for the resource in the database`
 func (v *DefinitionClient) Create(ctx context.Context, def Definition) (Definition, error) {
 
 	//Construct composite key consisting of name and version
 	key := DefinitionKey{RBName: def.RBName, RBVersion: def.RBVersion}
 
+	err := checkSource(&def)
+	if err != nil {
+		return Definition{}, err
+	}
+
 	//Check if this definition already exists
-	_, err := v.Get(ctx, def.RBName, def.RBVersion)
+	_, err = v.Get(ctx, def.RBName, def.RBVersion)
 	if err == nil {
 		return Definition{}, pkgerrors.New("Definition already exists")
 	}
@@ -147,8 +174,13 @@ func (v *DefinitionClient) Update(ctx context.Context, def Definition) (Definiti
 	//Construct composite key consisting of name and version
 	key := DefinitionKey{RBName: def.RBName, RBVersion: def.RBVersion}
 
+	err := checkSource(&def)
+	if err != nil {
+		return Definition{}, err
+	}
+
 	//Check if this definition already exists
-	_, err := v.Get(ctx, def.RBName, def.RBVersion)
+	_, err = v.Get(ctx, def.RBName, def.RBVersion)
 	if err != nil {
 		return Definition{}, pkgerrors.New("Definition does not exists")
 	}
@@ -255,6 +287,10 @@ func (v *DefinitionClient) Upload(ctx context.Context, name string, version stri
 		return pkgerrors.Errorf("Invalid Definition ID provided: %s", err.Error())
 	}
 
+	if def.Source != nil {
+		return pkgerrors.New("Definition references its chart in a source, no content can be uploaded")
+	}
+
 	err = isTarGz(bytes.NewBuffer(inp))
 	if err != nil {
 		return pkgerrors.Errorf("Error in file format: %s", err.Error())
@@ -310,15 +346,20 @@ func (v *DefinitionClient) Upload(ctx context.Context, name string, version stri
 // Download the contents of the resource bundle definition from DB
 // Returns a byte array of the contents which is used by the
 // ExtractTarBall code to create the folder structure on disk
+// The chart of a definition with a source is fetched instead, and its
+// digest is pinned the first time so that later instances use the same chart
 func (v *DefinitionClient) Download(ctx context.Context, name string, version string) ([]byte, error) {
 
-	//ignore the returned data here
 	//Check if id is valid
-	_, err := v.Get(ctx, name, version)
+	def, err := v.Get(ctx, name, version)
 	if err != nil {
 		return nil, pkgerrors.Errorf("Invalid Definition ID provided: %s", err.Error())
 	}
 
+	if def.Source != nil {
+		return v.downloadFromSource(ctx, def)
+	}
+
 	//Construct the composite key to select the entry
 	key := DefinitionKey{RBName: name, RBVersion: version}
 	value, err := db.DBconn.Read(ctx, v.storeName, key, v.tagContent)
@@ -339,3 +380,37 @@ func (v *DefinitionClient) Download(ctx context.Context, name string, version st
 	}
 	return nil, pkgerrors.New("Error downloading Definition content")
 }
+
+// downloadFromSource fetches the chart of the definition from its source
+func (v *DefinitionClient) downloadFromSource(ctx context.Context, def Definition) ([]byte, error) {
+	var creds *RepoCredential
+	if def.Source.Credential != "" {
+		c, err := NewRepoCredentialClient().Get(ctx, def.Source.Credential)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "Getting credential %s", def.Source.Credential)
+		}
+		creds = &c
+	}
+
+	content, digest, err := fetchChart(v.fetcher, *def.Source, creds)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Fetching Definition chart")
+	}
+
+	if def.Source.Digest == "" {
+		src := *def.Source
+		src.Digest = digest
+		def.Source = &src
+		key := DefinitionKey{RBName: def.RBName, RBVersion: def.RBVersion}
+		err = db.DBconn.Update(ctx, v.storeName, key, v.tagMeta, def)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Pinning Definition chart digest")
+		}
+		logutils.Info("Pinned Definition chart", logutils.Fields{
+			"rb-name":    def.RBName,
+			"rb-version": def.RBVersion,
+			"digest":     digest,
+		})
+	}
+	return content, nil
+}
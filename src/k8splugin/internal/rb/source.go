@@ -0,0 +1,71 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rb
+
+import (
+	"github.com/onap/multicloud-k8s/src/orchestrator/utils/chartsource"
+)
+
+// Supported chart source types
+const (
+	SourceTypeHelmRepo = chartsource.TypeHelmRepo
+	SourceTypeOCI      = chartsource.TypeOCI
+)
+
+// ChartSource references the chart of a definition in a Helm repository
+// or an OCI registry instead of an uploaded tarball. Digest pins the
+// content of the chart once it has been fetched.
+type ChartSource struct {
+	Type       string `json:"type"`
+	URL        string `json:"url,omitempty"`
+	Chart      string `json:"chart,omitempty"`
+	Version    string `json:"version,omitempty"`
+	Reference  string `json:"reference,omitempty"`
+	Credential string `json:"credential,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+}
+
+// toChartSource converts the source of a definition
+func (s ChartSource) toChartSource() chartsource.Source {
+	return chartsource.Source{
+		Type:      s.Type,
+		URL:       s.URL,
+		Chart:     s.Chart,
+		Version:   s.Version,
+		Reference: s.Reference,
+		Digest:    s.Digest,
+	}
+}
+
+// Validate checks that the source has the fields its type requires
+func (s ChartSource) Validate() error {
+	return chartsource.Validate(s.toChartSource())
+}
+
+// fetchChart fetches the chart of the source with the fetcher, and returns
+// it along with its digest
+func fetchChart(f *chartsource.Fetcher, src ChartSource, c *RepoCredential) ([]byte, string, error) {
+	var creds *chartsource.Credentials
+	if c != nil {
+		creds = &chartsource.Credentials{
+			Username: c.Username,
+			Password: c.Password,
+			Token:    c.Token,
+		}
+	}
+	return f.Fetch(src.toChartSource(), creds)
+}
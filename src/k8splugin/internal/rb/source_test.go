@@ -0,0 +1,133 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/utils/chartsource"
+)
+
+func TestFetchChartFromRepo(t *testing.T) {
+	chart := []byte("chart content")
+	requests := 0
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/index.yaml":
+			fmt.Fprint(w, "entries:\n  vault:\n  - version: 1.0.0\n    urls:\n    - charts/vault-1.0.0.tgz\n")
+		case "/charts/vault-1.0.0.tgz":
+			w.Write(chart)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cacheDir, err := ioutil.TempDir("", "chart-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	f := chartsource.NewFetcher(srv.Client(), cacheDir)
+	src := ChartSource{Type: SourceTypeHelmRepo, URL: srv.URL, Chart: "vault", Version: "1.0.0"}
+	creds := &RepoCredential{Name: "repo", Token: "secret"}
+
+	content, digest, err := fetchChart(f, src, creds)
+	if err != nil {
+		t.Fatalf("fetchChart returned an error (%s)", err)
+	}
+	if string(content) != string(chart) || digest != chartsource.Digest(chart) {
+		t.Fatalf("Unexpected chart %q with digest %s", content, digest)
+	}
+
+	// The pinned chart comes from the cache
+	requests = 0
+	src.Digest = digest
+	_, _, err = fetchChart(f, src, creds)
+	if err != nil || requests != 0 {
+		t.Fatalf("Expected the pinned chart from the cache, got %d requests (%v)", requests, err)
+	}
+
+	// A chart that does not match the pinned digest is rejected
+	src.Digest = chartsource.Digest([]byte("other content"))
+	_, _, err = fetchChart(f, src, creds)
+	if err == nil || !strings.Contains(err.Error(), "does not match the pinned digest") {
+		t.Fatalf("Expected a digest mismatch error, got %v", err)
+	}
+}
+
+func TestCheckSource(t *testing.T) {
+	testCases := []struct {
+		label     string
+		source    ChartSource
+		chartName string
+		expectErr bool
+	}{
+		{
+			label:     "Helm repository",
+			source:    ChartSource{Type: SourceTypeHelmRepo, URL: "https://charts.example.com", Chart: "vault", Version: "1.0.0"},
+			chartName: "vault",
+		},
+		{
+			label:     "OCI registry",
+			source:    ChartSource{Type: SourceTypeOCI, Reference: "oci://registry.example.com/charts/vault:1.0.0"},
+			chartName: "vault",
+		},
+		{
+			label:     "Missing chart version",
+			source:    ChartSource{Type: SourceTypeHelmRepo, URL: "https://charts.example.com", Chart: "vault"},
+			expectErr: true,
+		},
+		{
+			label:     "OCI reference without tag",
+			source:    ChartSource{Type: SourceTypeOCI, Reference: "registry.example.com/charts/vault"},
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			src := testCase.source
+			def := Definition{RBName: "testresourcebundle", RBVersion: "v1", Source: &src}
+			err := checkSource(&def)
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkSource returned an error (%s)", err)
+			}
+			if def.ChartName != testCase.chartName {
+				t.Fatalf("Expected chart name %s, got %s", testCase.chartName, def.ChartName)
+			}
+		})
+	}
+}
@@ -94,5 +94,7 @@ func NewRouter(testClient interface{}) *mux.Router {
 	router.HandleFunc("/cluster-providers/{cluster-provider}/clusters/{cluster}/status",
 		schedulerHandler.statusSchedulerHandler).Queries("instance", "{instance}", "type", "{type}", "output", "{output}", "app", "{app}", "cluster", "{cluster}", "resource", "{resource}")
 
+	router.HandleFunc("/openapi.json", openAPISpec().Handler(router)).Methods("GET")
+
 	return router
 }
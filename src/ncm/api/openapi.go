@@ -0,0 +1,49 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	netintents "github.com/onap/multicloud-k8s/src/ncm/pkg/networkintents"
+	"github.com/onap/multicloud-k8s/src/ncm/pkg/scheduler"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/openapi"
+)
+
+const specSchedulerPath = "/v2/cluster-providers/{cluster-provider}/clusters/{cluster}"
+
+// openAPISpec documents the routes of NewRouter. The operations of the
+// collections and of their items are generated from the resources, the
+// other routes have an operation. Every route must have one, see
+// TestOpenAPISpec.
+func openAPISpec() openapi.Spec {
+	return openapi.Spec{
+		Title:   "EMCO Network Configuration Manager",
+		Version: "v2",
+		Resources: map[string]openapi.Resource{
+			"networks":          {Name: "virtual network", Schema: vnJSONFile, Model: netintents.Network{}, UpdateStatus: http.StatusCreated},
+			"provider-networks": {Name: "provider network", Schema: pnetJSONFile, Model: netintents.ProviderNet{}, UpdateStatus: http.StatusCreated},
+		},
+		Operations: map[string]openapi.Operation{
+			"GET /v2/openapi.json": {Summary: "Get the OpenAPI specification of the service", Response: map[string]interface{}{}},
+
+			"POST " + specSchedulerPath + "/apply":     {Summary: "Apply the networks of a cluster", Status: http.StatusNoContent},
+			"POST " + specSchedulerPath + "/terminate": {Summary: "Terminate the networks of a cluster", Status: http.StatusNoContent},
+			"GET " + specSchedulerPath + "/status":     {Summary: "Get the status of the networks of a cluster", Response: scheduler.ClusterStatus{}},
+		},
+	}
+}
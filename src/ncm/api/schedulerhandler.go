@@ -23,9 +23,11 @@ import (
 	"strings"
 
 	"github.com/onap/multicloud-k8s/src/ncm/pkg/scheduler"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/validation"
 
 	"github.com/gorilla/mux"
+	pkgerrors "github.com/pkg/errors"
 )
 
 // Used to store backend implementations objects
@@ -36,6 +38,16 @@ type schedulerHandler struct {
 	client scheduler.SchedulerManager
 }
 
+// writeSchedulerError responds with 409 when the state of the cluster was
+// modified by a concurrent request
+func writeSchedulerError(w http.ResponseWriter, err error) {
+	if _, ok := pkgerrors.Cause(err).(*db.VersionConflictError); ok {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
 //  applyClusterHandler handles requests to apply network intents for a cluster
 func (h schedulerHandler) applySchedulerHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -44,7 +56,7 @@ func (h schedulerHandler) applySchedulerHandler(w http.ResponseWriter, r *http.R
 
 	err := h.client.ApplyNetworkIntents(provider, cluster)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeSchedulerError(w, err)
 		return
 	}
 
@@ -59,7 +71,7 @@ func (h schedulerHandler) terminateSchedulerHandler(w http.ResponseWriter, r *ht
 
 	err := h.client.TerminateNetworkIntents(provider, cluster)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeSchedulerError(w, err)
 		return
 	}
 
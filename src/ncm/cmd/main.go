@@ -24,10 +24,12 @@ import (
 
 	"github.com/gorilla/handlers"
 	"github.com/onap/multicloud-k8s/src/ncm/api"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/audit"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/auth"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/contextdb"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/metrics"
 )
 
 func main() {
@@ -45,9 +47,12 @@ func main() {
 		log.Println(err)
 		log.Fatalln("Exiting...")
 	}
+	metrics.InstrumentDatabases()
 
 	httpRouter := api.NewRouter(nil)
-	loggedRouter := handlers.LoggingHandler(os.Stdout, httpRouter)
+	httpRouter.Use(metrics.Middleware("ncm"))
+	httpRouter.Use(audit.Middleware("ncm"))
+	loggedRouter := handlers.LoggingHandler(os.Stdout, metrics.Handler(httpRouter))
 	log.Println("Starting Network Customization Manager")
 
 	httpServer := &http.Server{
@@ -2,12 +2,12 @@ module github.com/onap/multicloud-k8s/src/ncm
 
 require (
 	github.com/emicklei/go-restful v2.10.0+incompatible // indirect
-	github.com/gorilla/handlers v1.3.0
+	github.com/gorilla/handlers v1.4.0
 	github.com/gorilla/mux v1.7.3
 	github.com/onap/multicloud-k8s/src/clm v0.0.0-20251205073433-cd019185faf1
 	github.com/onap/multicloud-k8s/src/orchestrator v0.0.0-20251205113806-246f4e4b1e9f
 	github.com/pkg/errors v0.9.1
-	gopkg.in/yaml.v2 v2.3.0
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 replace (
@@ -136,10 +136,39 @@ func callRsyncUninstall(contextid interface{}) error {
 	return nil
 }
 
+// getClusterState returns the stateInfo of the cluster with the version it
+// was read at. The version is read first, so that an update of the stateInfo
+// at the version can't overwrite a concurrent update.
+func (v *SchedulerClient) getClusterState(clusterProvider, cluster string) (state.StateInfo, int64, error) {
+	key := clusterPkg.ClusterKey{
+		ClusterProviderName: clusterProvider,
+		ClusterName:         cluster,
+	}
+	version, err := db.DBconn.GetVersion(v.db.StoreName, key)
+	if err != nil {
+		return state.StateInfo{}, 0, err
+	}
+	s, err := clusterPkg.NewClusterClient().GetClusterState(clusterProvider, cluster)
+	if err != nil {
+		return state.StateInfo{}, 0, err
+	}
+	return s, version, nil
+}
+
+// saveClusterState updates the stateInfo of the cluster if it is still at the
+// version, and returns the new version
+func (v *SchedulerClient) saveClusterState(clusterProvider, cluster string, s state.StateInfo, version int64) (int64, error) {
+	key := clusterPkg.ClusterKey{
+		ClusterProviderName: clusterProvider,
+		ClusterName:         cluster,
+	}
+	return db.DBconn.InsertIfVersion(v.db.StoreName, key, nil, v.db.TagState, s, version)
+}
+
 // Apply Network Intents associated with a cluster
 func (v *SchedulerClient) ApplyNetworkIntents(clusterProvider, cluster string) error {
 
-	s, err := clusterPkg.NewClusterClient().GetClusterState(clusterProvider, cluster)
+	s, version, err := v.getClusterState(clusterProvider, cluster)
 	if err != nil {
 		return pkgerrors.Errorf("Error finding cluster: %v %v", clusterProvider, cluster)
 	}
@@ -232,37 +261,39 @@ func (v *SchedulerClient) ApplyNetworkIntents(clusterProvider, cluster string) e
 		return pkgerrors.Wrap(err, "Error adding Cluster to AppContext")
 	}
 
-	// call resource synchronizer to instantiate the CRs in the cluster
-	err = callRsyncInstall(ctxVal)
-	if err != nil {
-		deleteAppContext(ac)
-		return err
-	}
-
-	// update the StateInfo in the cluster db record
-	key := clusterPkg.ClusterKey{
-		ClusterProviderName: clusterProvider,
-		ClusterName:         cluster,
-	}
+	// update the StateInfo in the cluster db record before rsync is called,
+	// so that a concurrent Apply or Terminate fails with a conflict
+	previous := s
 	a := state.ActionEntry{
 		State:     state.StateEnum.Applied,
 		ContextId: ctxVal.(string),
 		TimeStamp: time.Now(),
 	}
-	s.Actions = append(s.Actions, a)
+	s.Actions = append(s.Actions[:len(s.Actions):len(s.Actions)], a)
 
-	err = db.DBconn.Insert(v.db.StoreName, key, nil, v.db.TagState, s)
+	version, err = v.saveClusterState(clusterProvider, cluster, s, version)
 	if err != nil {
 		log.Warn(":: Error updating Cluster state in DB ::", log.Fields{"Error": err.Error(), "cluster": cluster, "cluster provider": clusterProvider, "AppContext": ctxVal.(string)})
+		deleteAppContext(ac)
 		return pkgerrors.Wrap(err, "Error updating the stateInfo of cluster after Apply on network intents: "+cluster)
 	}
 
+	// call resource synchronizer to instantiate the CRs in the cluster
+	err = callRsyncInstall(ctxVal)
+	if err != nil {
+		if _, rerr := v.saveClusterState(clusterProvider, cluster, previous, version); rerr != nil {
+			log.Warn(":: Error restoring Cluster state in DB ::", log.Fields{"Error": rerr.Error(), "cluster": cluster, "cluster provider": clusterProvider})
+		}
+		deleteAppContext(ac)
+		return err
+	}
+
 	return nil
 }
 
 // Terminate Network Intents associated with a cluster
 func (v *SchedulerClient) TerminateNetworkIntents(clusterProvider, cluster string) error {
-	s, err := clusterPkg.NewClusterClient().GetClusterState(clusterProvider, cluster)
+	s, version, err := v.getClusterState(clusterProvider, cluster)
 	if err != nil {
 		return pkgerrors.Wrapf(err, "Error finding StateInfo for cluster: %v, %v", clusterProvider, cluster)
 	}
@@ -285,29 +316,30 @@ func (v *SchedulerClient) TerminateNetworkIntents(clusterProvider, cluster strin
 		return pkgerrors.Wrap(err, "Cluster is in an invalid state: "+cluster+" "+stateVal)
 	}
 
-	// call resource synchronizer to terminate the CRs in the cluster
+	// update StateInfo before rsync is called, so that a concurrent Apply
+	// or Terminate fails with a conflict
+	previous := s
 	contextId := state.GetLastContextIdFromStateInfo(s)
-	err = callRsyncUninstall(contextId)
-	if err != nil {
-		return err
-	}
-
-	// update StateInfo
-	key := clusterPkg.ClusterKey{
-		ClusterProviderName: clusterProvider,
-		ClusterName:         cluster,
-	}
 	a := state.ActionEntry{
 		State:     state.StateEnum.Terminated,
 		ContextId: contextId,
 		TimeStamp: time.Now(),
 	}
-	s.Actions = append(s.Actions, a)
-	err = db.DBconn.Insert(v.db.StoreName, key, nil, v.db.TagState, s)
+	s.Actions = append(s.Actions[:len(s.Actions):len(s.Actions)], a)
+	version, err = v.saveClusterState(clusterProvider, cluster, s, version)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error updating the stateInfo of cluster: "+cluster)
 	}
 
+	// call resource synchronizer to terminate the CRs in the cluster
+	err = callRsyncUninstall(contextId)
+	if err != nil {
+		if _, rerr := v.saveClusterState(clusterProvider, cluster, previous, version); rerr != nil {
+			log.Warn(":: Error restoring Cluster state in DB ::", log.Fields{"Error": rerr.Error(), "cluster": cluster, "cluster provider": clusterProvider})
+		}
+		return err
+	}
+
 	return nil
 }
 
@@ -131,12 +131,17 @@ func (h intentHandler) getAllIntentsHandler(w http.ResponseWriter, r *http.Reque
 	v := vars["composite-app-version"]
 	di := vars["deployment-intent-group-name"]
 
-	mapOfIntents, err := h.client.GetAllIntents(p, ca, v, di)
+	o, ok := listOptions(w, r)
+	if !ok {
+		return
+	}
+	mapOfIntents, next, err := h.client.ListIntents(p, ca, v, di, o)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		listError(w, err, http.StatusInternalServerError)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
+	setContinueToken(w, next)
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(mapOfIntents)
 	if err != nil {
@@ -18,6 +18,7 @@ package api
 
 import (
 	"github.com/gorilla/mux"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/audit"
 	moduleLib "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
 	controller "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module/controller"
 )
@@ -61,6 +62,24 @@ func NewRouter(projectClient moduleLib.ProjectManager,
 	router.HandleFunc("/projects", projHandler.getHandler).Methods("GET")
 	router.HandleFunc("/projects/{project-name}", projHandler.deleteHandler).Methods("DELETE")
 
+	//setting routes for repository credentials
+	repoCredentialHandler := repoCredentialHandler{
+		client: moduleClient.RepoCredential,
+	}
+	router.HandleFunc("/projects/{project-name}/repo-credentials", repoCredentialHandler.createHandler).Methods("POST")
+	router.HandleFunc("/projects/{project-name}/repo-credentials", repoCredentialHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/repo-credentials/{repo-credential-name}", repoCredentialHandler.updateHandler).Methods("PUT")
+	router.HandleFunc("/projects/{project-name}/repo-credentials/{repo-credential-name}", repoCredentialHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/repo-credentials/{repo-credential-name}", repoCredentialHandler.deleteHandler).Methods("DELETE")
+
+	//setting routes for the approval policy
+	approvalPolicyHandler := approvalPolicyHandler{
+		client: moduleClient.ApprovalPolicy,
+	}
+	router.HandleFunc("/projects/{project-name}/approval-policy", approvalPolicyHandler.updateHandler).Methods("PUT")
+	router.HandleFunc("/projects/{project-name}/approval-policy", approvalPolicyHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/approval-policy", approvalPolicyHandler.deleteHandler).Methods("DELETE")
+
 	//setting routes for compositeApp
 	if compositeAppClient == nil {
 		compositeAppClient = moduleClient.CompositeApp
@@ -120,6 +139,11 @@ func NewRouter(projectClient moduleLib.ProjectManager,
 	router.HandleFunc("/controllers/{controller-name}", controlHandler.getHandler).Methods("GET")
 	router.HandleFunc("/controllers/{controller-name}", controlHandler.deleteHandler).Methods("DELETE")
 
+	auditHandler := auditHandler{
+		client: audit.NewAuditClient(),
+	}
+	router.HandleFunc("/audit", auditHandler.getHandler).Methods("GET")
+
 	//setting routes for genericPlacementIntent
 	if genericPlacementIntentClient == nil {
 		genericPlacementIntentClient = moduleClient.GenericPlacementIntent
@@ -180,6 +204,22 @@ func NewRouter(projectClient moduleLib.ProjectManager,
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/intents/", intentHandler.getIntentByNameHandler).Queries("intent", "{intent}")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/intents/{intent-name}", intentHandler.deleteIntentHandler).Methods("DELETE")
 
+	//setting routes for Schedules
+	scheduleHandler := scheduleHandler{
+		client: moduleClient.Schedule,
+	}
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/schedules", scheduleHandler.createHandler).Methods("POST")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/schedules", scheduleHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/schedules/{schedule-name}", scheduleHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/schedules/{schedule-name}", scheduleHandler.deleteHandler).Methods("DELETE")
+
+	//setting routes for the drain of clusters
+	drainHandler := drainHandler{
+		client: moduleClient.ClusterDrain,
+	}
+	router.HandleFunc("/cluster-providers/{provider-name}/clusters/{cluster-name}/drain", drainHandler.drainHandler).Methods("POST")
+	router.HandleFunc("/cluster-providers/{provider-name}/clusters/{cluster-name}/drain", drainHandler.getHandler).Methods("GET")
+
 	// setting routes for Instantiation
 	if instantiationClient == nil {
 		instantiationClient = moduleClient.Instantiation
@@ -190,12 +230,17 @@ func NewRouter(projectClient moduleLib.ProjectManager,
 	}
 
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/approve", instantiationHandler.approveHandler).Methods("POST")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/reject", instantiationHandler.rejectHandler).Methods("POST")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/validate", instantiationHandler.validateHandler).Methods("GET")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/terminate", instantiationHandler.terminateHandler).Methods("POST")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/instantiate", instantiationHandler.instantiateHandler).Methods("POST")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/terminate", instantiationHandler.terminateHandler).Methods("POST")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/update", instantiationHandler.updateHandler).Methods("POST")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/status", instantiationHandler.statusHandler).Methods("GET")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/status",
 		instantiationHandler.statusHandler).Queries("instance", "{instance}", "type", "{type}", "output", "{output}", "app", "{app}", "cluster", "{cluster}", "resource", "{resource}")
 
+	router.HandleFunc("/openapi.json", openAPISpec().Handler(router)).Methods("GET")
+
 	return router
 }
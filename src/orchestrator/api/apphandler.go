@@ -33,7 +33,7 @@ import (
 	"github.com/gorilla/mux"
 )
 
-var appJSONFile string = "json-schemas/metadata.json"
+var appJSONFile string = "json-schemas/app.json"
 
 // appHandler to store backend implementations objects
 // Also simplifies mocking for unit testing purposes
@@ -81,30 +81,44 @@ func (h appHandler) createAppHandler(w http.ResponseWriter, r *http.Request) {
 
 	//Read the file section and ignore the header
 	file, _, err := r.FormFile("file")
-	if err != nil {
-		http.Error(w, "Unable to process file", http.StatusUnprocessableEntity)
-		return
-	}
+	if a.Spec.Source != nil {
+		// Apps with a source reference their chart instead of uploading it
+		if err == nil {
+			file.Close()
+			http.Error(w, "An app with a source has no file", http.StatusBadRequest)
+			return
+		}
+		err = moduleLib.ValidateAppSource(*a.Spec.Source)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		if err != nil {
+			http.Error(w, "Unable to process file", http.StatusUnprocessableEntity)
+			return
+		}
 
-	defer file.Close()
-	//Convert the file content to base64 for storage
-	content, err := ioutil.ReadAll(file)
-	if err != nil {
-		http.Error(w, "Unable to read file", http.StatusUnprocessableEntity)
-		return
-	}
-	// Limit file Size to 1 GB
-	if len(content) > 1073741824 {
-		http.Error(w, "File Size Exceeds 1 GB", http.StatusUnprocessableEntity)
-		return
-	}
-	err = validation.IsTarGz(bytes.NewBuffer(content))
-	if err != nil {
-		http.Error(w, "Error in file format", http.StatusUnprocessableEntity)
-		return
-	}
+		defer file.Close()
+		//Convert the file content to base64 for storage
+		content, err := ioutil.ReadAll(file)
+		if err != nil {
+			http.Error(w, "Unable to read file", http.StatusUnprocessableEntity)
+			return
+		}
+		// Limit file Size to 1 GB
+		if len(content) > 1073741824 {
+			http.Error(w, "File Size Exceeds 1 GB", http.StatusUnprocessableEntity)
+			return
+		}
+		err = validation.IsTarGz(bytes.NewBuffer(content))
+		if err != nil {
+			http.Error(w, "Error in file format", http.StatusUnprocessableEntity)
+			return
+		}
 
-	ac.FileContent = base64.StdEncoding.EncodeToString(content)
+		ac.FileContent = base64.StdEncoding.EncodeToString(content)
+	}
 
 	vars := mux.Vars(r)
 	projectName := vars["project-name"]
@@ -139,9 +153,13 @@ func (h appHandler) getAppHandler(w http.ResponseWriter, r *http.Request) {
 	if len(name) == 0 {
 		var retList []moduleLib.App
 
-		ret, err := h.client.GetApps(projectName, compositeAppName, compositeAppVersion)
+		o, ok := listOptions(w, r)
+		if !ok {
+			return
+		}
+		ret, next, err := h.client.ListApps(projectName, compositeAppName, compositeAppVersion, o)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			listError(w, err, http.StatusInternalServerError)
 			return
 		}
 
@@ -150,6 +168,7 @@ func (h appHandler) getAppHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		w.Header().Set("Content-Type", "application/json")
+		setContinueToken(w, next)
 		w.WriteHeader(http.StatusOK)
 		err = json.NewEncoder(w).Encode(retList)
 		if err != nil {
@@ -25,6 +25,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	moduleLib "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
 
 	pkgerrors "github.com/pkg/errors"
@@ -64,12 +65,19 @@ func (m *mockAppManager) GetApps(p, cN, cV string) ([]moduleLib.App, error) {
 	return m.Items, nil
 }
 
+func (m *mockAppManager) ListApps(p, cN, cV string, o db.ListOptions) ([]moduleLib.App, string, error) {
+	if m.Err != nil {
+		return []moduleLib.App{}, "", m.Err
+	}
+	return m.Items, "", nil
+}
+
 func (m *mockAppManager) DeleteApp(name, p, cN, cV string) error {
 	return m.Err
 }
 
 func init() {
-	appJSONFile = "../json-schemas/metadata.json"
+	appJSONFile = "../json-schemas/app.json"
 }
 
 // makeTarGz produces a minimal valid gzip-compressed tar archive.
@@ -134,6 +142,60 @@ func TestAppCreateHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("Create Kustomize App", func(t *testing.T) {
+		body, contentType := buildAppMultipart(t,
+			`{"metadata":{"name":"testApp"},"spec":{"format":"kustomize"}}`, makeTarGz(t))
+		request := httptest.NewRequest("POST", url, body)
+		request.Header.Set("Content-Type", contentType)
+		client := &mockAppManager{Items: []moduleLib.App{
+			{Metadata: moduleLib.AppMetaData{Name: "testApp"}, Spec: moduleLib.AppSpec{Format: moduleLib.AppFormatKustomize}},
+		}}
+		resp := executeRequest(request, NewRouter(nil, nil, client, nil, nil, nil, nil, nil, nil, nil, nil))
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected %d; Got: %d", http.StatusCreated, resp.StatusCode)
+		}
+	})
+
+	t.Run("Unsupported app format", func(t *testing.T) {
+		body, contentType := buildAppMultipart(t,
+			`{"metadata":{"name":"testApp"},"spec":{"format":"jsonnet"}}`, makeTarGz(t))
+		request := httptest.NewRequest("POST", url, body)
+		request.Header.Set("Content-Type", contentType)
+		client := &mockAppManager{}
+		resp := executeRequest(request, NewRouter(nil, nil, client, nil, nil, nil, nil, nil, nil, nil, nil))
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("Expected %d; Got: %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+
+	t.Run("Create App from Helm repository", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		writer.WriteField("metadata", `{"metadata":{"name":"testApp"},"spec":{"source":{"type":"helm-repo","url":"https://charts.example.com","chart":"testchart","version":"1.0.0"}}}`)
+		writer.Close()
+		request := httptest.NewRequest("POST", url, body)
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+		client := &mockAppManager{Items: []moduleLib.App{
+			{Metadata: moduleLib.AppMetaData{Name: "testApp"}},
+		}}
+		resp := executeRequest(request, NewRouter(nil, nil, client, nil, nil, nil, nil, nil, nil, nil, nil))
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected %d; Got: %d", http.StatusCreated, resp.StatusCode)
+		}
+	})
+
+	t.Run("App with a source and a file", func(t *testing.T) {
+		body, contentType := buildAppMultipart(t,
+			`{"metadata":{"name":"testApp"},"spec":{"source":{"type":"oci","reference":"registry.example.com/charts/testchart:1.0.0"}}}`, makeTarGz(t))
+		request := httptest.NewRequest("POST", url, body)
+		request.Header.Set("Content-Type", contentType)
+		client := &mockAppManager{}
+		resp := executeRequest(request, NewRouter(nil, nil, client, nil, nil, nil, nil, nil, nil, nil, nil))
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("Expected %d; Got: %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+
 	t.Run("Bad metadata JSON", func(t *testing.T) {
 		body, contentType := buildAppMultipart(t, `{"metadata": }`, makeTarGz(t))
 		request := httptest.NewRequest("POST", url, body)
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/audit"
+)
+
+// Used to store backend implementations objects
+// Also simplifies mocking for unit testing purposes
+type auditHandler struct {
+	client audit.AuditManager
+}
+
+// parseTimeParam parses an optional RFC3339 query parameter
+func parseTimeParam(r *http.Request, name string) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// getHandler handles GET /audit?project=&service=&from=&to=
+func (h auditHandler) getHandler(w http.ResponseWriter, r *http.Request) {
+	project := r.URL.Query().Get("project")
+	service := r.URL.Query().Get("service")
+
+	from, err := parseTimeParam(r, "from")
+	if err != nil {
+		http.Error(w, "Invalid from time, expected RFC3339", http.StatusBadRequest)
+		return
+	}
+	to, err := parseTimeParam(r, "to")
+	if err != nil {
+		http.Error(w, "Invalid to time, expected RFC3339", http.StatusBadRequest)
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		http.Error(w, "Invalid time range, to is before from", http.StatusBadRequest)
+		return
+	}
+
+	ret, err := h.client.GetEntries(project, service, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -104,9 +104,13 @@ func (h compositeAppHandler) getAllCompositeAppsHandler(w http.ResponseWriter, r
 
 	var caList []moduleLib.CompositeApp
 
-	cApps, err := h.client.GetAllCompositeApps(pName)
+	o, ok := listOptions(w, r)
+	if !ok {
+		return
+	}
+	cApps, next, err := h.client.ListCompositeApps(pName, o)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		listError(w, err, http.StatusNotFound)
 		return
 	}
 
@@ -114,6 +118,7 @@ func (h compositeAppHandler) getAllCompositeAppsHandler(w http.ResponseWriter, r
 		caList = append(caList, moduleLib.CompositeApp{Metadata: cApp.Metadata, Spec: cApp.Spec})
 	}
 	w.Header().Set("Content-Type", "application/json")
+	setContinueToken(w, next)
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(caList)
 	if err != nil {
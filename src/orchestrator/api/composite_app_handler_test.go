@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	moduleLib "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
 
 	pkgerrors "github.com/pkg/errors"
@@ -56,6 +57,13 @@ func (m *mockCompositeAppManager) GetAllCompositeApps(p string) ([]moduleLib.Com
 	return m.Items, nil
 }
 
+func (m *mockCompositeAppManager) ListCompositeApps(p string, o db.ListOptions) ([]moduleLib.CompositeApp, string, error) {
+	if m.Err != nil {
+		return []moduleLib.CompositeApp{}, "", m.Err
+	}
+	return m.Items, "", nil
+}
+
 func (m *mockCompositeAppManager) DeleteCompositeApp(name, version, p string) error {
 	return m.Err
 }
@@ -72,6 +72,11 @@ func validateControllerInputs(c controller.Controller) error {
 		return pkgerrors.Errorf("Invalid controller priority = [%v], errors: %v", c.Spec.Priority, errs)
 	}
 
+	_, err = c.Spec.ConnOptions()
+	if err != nil {
+		return pkgerrors.Wrap(err, "Invalid controller connection settings")
+	}
+
 	return nil
 }
 
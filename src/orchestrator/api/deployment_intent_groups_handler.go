@@ -25,6 +25,7 @@ import (
 	moduleLib "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
 
 	"github.com/gorilla/mux"
+	pkgerrors "github.com/pkg/errors"
 )
 
 var dpiJSONFile string = "json-schemas/deployment-group-intent.json"
@@ -36,6 +37,19 @@ type deploymentIntentGroupHandler struct {
 	client moduleLib.DeploymentIntentGroupManager
 }
 
+// writeValuesValidationError responds with the violations of a
+// ValuesValidationError, and returns false for any other error
+func writeValuesValidationError(w http.ResponseWriter, err error) bool {
+	vErr, ok := pkgerrors.Cause(err).(*moduleLib.ValuesValidationError)
+	if !ok {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(vErr)
+	return true
+}
+
 // createDeploymentIntentGroupHandler handles the create operation of DeploymentIntentGroup
 func (h deploymentIntentGroupHandler) createDeploymentIntentGroupHandler(w http.ResponseWriter, r *http.Request) {
 
@@ -65,6 +79,9 @@ func (h deploymentIntentGroupHandler) createDeploymentIntentGroupHandler(w http.
 
 	dIntent, createErr := h.client.CreateDeploymentIntentGroup(d, projectName, compositeAppName, version)
 	if createErr != nil {
+		if writeValuesValidationError(w, createErr) {
+			return
+		}
 		http.Error(w, createErr.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -105,6 +122,12 @@ func (h deploymentIntentGroupHandler) getDeploymentIntentGroupHandler(w http.Res
 		return
 	}
 
+	version, err := h.client.GetDeploymentIntentGroupVersion(di, p, ca, v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	dIntentGrp, err := h.client.GetDeploymentIntentGroup(di, p, ca, v)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -112,6 +135,7 @@ func (h deploymentIntentGroupHandler) getDeploymentIntentGroupHandler(w http.Res
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setETag(w, version)
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(dIntentGrp)
 	if err != nil {
@@ -132,12 +156,17 @@ func (h deploymentIntentGroupHandler) getAllDeploymentIntentGroupsHandler(w http
 	ca := vars["composite-app-name"]
 	v := vars["composite-app-version"]
 
-	diList, err := h.client.GetAllDeploymentIntentGroups(p, ca, v)
+	o, ok := listOptions(w, r, "state")
+	if !ok {
+		return
+	}
+	diList, next, err := h.client.ListDeploymentIntentGroups(p, ca, v, o)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		listError(w, err, http.StatusNotFound)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
+	setContinueToken(w, next)
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(diList)
 	if err != nil {
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// checkIfMatch responds with 412 when the If-Match header of the request does
+// not match the current version of the resource. The version is only read
+// when the request has the header.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, version func() (int64, error)) bool {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return true
+	}
+	current, err := version()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return false
+	}
+	if !db.IfMatch(ifMatch, current) {
+		http.Error(w, "The resource was modified, its version is "+db.ETag(current), http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+// setETag returns the version of the resource in the ETag header. The
+// version is read before the resource, so that the ETag is never newer than
+// the body of the response.
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", db.ETag(version))
+}
+
+// isConflict reports whether the resource was modified by a concurrent request
+func isConflict(err error) bool {
+	_, ok := pkgerrors.Cause(err).(*db.VersionConflictError)
+	return ok
+}
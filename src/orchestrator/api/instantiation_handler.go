@@ -18,13 +18,17 @@ package api
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/audit"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/validation"
 	moduleLib "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
+	pkgerrors "github.com/pkg/errors"
 )
 
 /* Used to store backend implementation objects
@@ -34,6 +38,67 @@ type instantiationHandler struct {
 	client moduleLib.InstantiationManager
 }
 
+// ifMatchClient returns the client whose operations only change the
+// DeploymentIntentGroup at a version the If-Match header of the request matches
+func (h instantiationHandler) ifMatchClient(r *http.Request) moduleLib.InstantiationManager {
+	return h.client.IfMatch(r.Header.Get("If-Match"))
+}
+
+// conflictStatus is the status of a request whose DeploymentIntentGroup was
+// modified: 412 when the request has an If-Match header, 409 otherwise
+func conflictStatus(r *http.Request) int {
+	if r.Header.Get("If-Match") != "" {
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusConflict
+}
+
+// writeActionError writes the response of a failed lifecycle operation
+func writeActionError(w http.ResponseWriter, r *http.Request, err error) {
+	if isConflict(err) {
+		http.Error(w, err.Error(), conflictStatus(r))
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// decodeApproval returns the decision of the caller, with the comment of the
+// request body when there is one. Only the callers with a verified identity,
+// a client certificate or the identity set by a trusted proxy, can decide.
+func decodeApproval(w http.ResponseWriter, r *http.Request) (moduleLib.Approval, bool) {
+	var a moduleLib.Approval
+
+	approver, ok := audit.GetVerifiedPrincipal(r)
+	if !ok {
+		http.Error(w, "The identity of the approver is not verified", http.StatusUnauthorized)
+		return a, false
+	}
+	err := json.NewDecoder(r.Body).Decode(&a)
+	if err != nil && err != io.EOF {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return a, false
+	}
+	a.Approver = approver
+	a.Roles = audit.GetRoles(r)
+	return a, true
+}
+
+// writeApprovalError writes the response of a failed approval or rejection
+func writeApprovalError(w http.ResponseWriter, r *http.Request, err error) {
+	switch e := pkgerrors.Cause(err).(type) {
+	case *moduleLib.ValidationReportError:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(e.Report)
+	case *moduleLib.ApproverNotAllowedError:
+		http.Error(w, e.Error(), http.StatusForbidden)
+	case *db.VersionConflictError:
+		http.Error(w, err.Error(), conflictStatus(r))
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func (h instantiationHandler) approveHandler(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
@@ -42,13 +107,71 @@ func (h instantiationHandler) approveHandler(w http.ResponseWriter, r *http.Requ
 	v := vars["composite-app-version"]
 	di := vars["deployment-intent-group-name"]
 
-	iErr := h.client.Approve(p, ca, v, di)
+	a, ok := decodeApproval(w, r)
+	if !ok {
+		return
+	}
+	status, iErr := h.ifMatchClient(r).Approve(p, ca, v, di, a)
+	if iErr != nil {
+		writeApprovalError(w, r, iErr)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	iErr = json.NewEncoder(w).Encode(status)
 	if iErr != nil {
 		http.Error(w, iErr.Error(), http.StatusInternalServerError)
 		return
 	}
+}
+
+func (h instantiationHandler) rejectHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	p := vars["project-name"]
+	ca := vars["composite-app-name"]
+	v := vars["composite-app-version"]
+	di := vars["deployment-intent-group-name"]
+
+	a, ok := decodeApproval(w, r)
+	if !ok {
+		return
+	}
+	status, iErr := h.ifMatchClient(r).Reject(p, ca, v, di, a)
+	if iErr != nil {
+		writeApprovalError(w, r, iErr)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusAccepted)
+	iErr = json.NewEncoder(w).Encode(status)
+	if iErr != nil {
+		http.Error(w, iErr.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// validateHandler returns the report of the validation of the DeploymentIntentGroup
+func (h instantiationHandler) validateHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	p := vars["project-name"]
+	ca := vars["composite-app-name"]
+	v := vars["composite-app-version"]
+	di := vars["deployment-intent-group-name"]
 
+	report, iErr := h.client.Validate(p, ca, v, di)
+	if iErr != nil {
+		http.Error(w, iErr.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	iErr = json.NewEncoder(w).Encode(report)
+	if iErr != nil {
+		http.Error(w, iErr.Error(), http.StatusInternalServerError)
+		return
+	}
 }
 
 func (h instantiationHandler) instantiateHandler(w http.ResponseWriter, r *http.Request) {
@@ -59,9 +182,9 @@ func (h instantiationHandler) instantiateHandler(w http.ResponseWriter, r *http.
 	v := vars["composite-app-version"]
 	di := vars["deployment-intent-group-name"]
 
-	iErr := h.client.Instantiate(p, ca, v, di)
+	iErr := h.ifMatchClient(r).Instantiate(p, ca, v, di)
 	if iErr != nil {
-		http.Error(w, iErr.Error(), http.StatusInternalServerError)
+		writeActionError(w, r, iErr)
 		return
 	}
 	w.WriteHeader(http.StatusAccepted)
@@ -76,9 +199,26 @@ func (h instantiationHandler) terminateHandler(w http.ResponseWriter, r *http.Re
 	v := vars["composite-app-version"]
 	di := vars["deployment-intent-group-name"]
 
-	iErr := h.client.Terminate(p, ca, v, di)
+	iErr := h.ifMatchClient(r).Terminate(p, ca, v, di)
 	if iErr != nil {
-		http.Error(w, iErr.Error(), http.StatusInternalServerError)
+		writeActionError(w, r, iErr)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+
+}
+
+func (h instantiationHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	p := vars["project-name"]
+	ca := vars["composite-app-name"]
+	v := vars["composite-app-version"]
+	di := vars["deployment-intent-group-name"]
+
+	iErr := h.ifMatchClient(r).Update(p, ca, v, di)
+	if iErr != nil {
+		writeActionError(w, r, iErr)
 		return
 	}
 	w.WriteHeader(http.StatusAccepted)
@@ -0,0 +1,314 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	moduleLib "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+type mockInstantiationManager struct {
+	// Only the methods under test are implemented
+	moduleLib.InstantiationManager
+	Report   moduleLib.ValidationReport
+	Approved moduleLib.ApprovalStatus
+	Approval moduleLib.Approval
+	Err      error
+	// Version is the version of the DeploymentIntentGroup the If-Match
+	// header is checked against
+	Version int64
+	ifMatch string
+}
+
+func (m *mockInstantiationManager) IfMatch(header string) moduleLib.InstantiationManager {
+	m.ifMatch = header
+	return m
+}
+
+// err returns the error of the operations
+func (m *mockInstantiationManager) err() error {
+	if !db.IfMatch(m.ifMatch, m.Version) {
+		return &db.VersionConflictError{Version: m.Version}
+	}
+	return m.Err
+}
+
+func (m *mockInstantiationManager) Approve(p string, ca string, v string, di string, a moduleLib.Approval) (moduleLib.ApprovalStatus, error) {
+	m.Approval = a
+	if err := m.err(); err != nil {
+		return moduleLib.ApprovalStatus{}, err
+	}
+	if !m.Report.Valid {
+		return moduleLib.ApprovalStatus{}, &moduleLib.ValidationReportError{Report: m.Report}
+	}
+	return m.Approved, nil
+}
+
+func (m *mockInstantiationManager) Reject(p string, ca string, v string, di string, a moduleLib.Approval) (moduleLib.ApprovalStatus, error) {
+	m.Approval = a
+	if err := m.err(); err != nil {
+		return moduleLib.ApprovalStatus{}, err
+	}
+	return m.Approved, nil
+}
+
+func (m *mockInstantiationManager) Instantiate(p string, ca string, v string, di string) error {
+	return m.err()
+}
+
+func (m *mockInstantiationManager) Terminate(p string, ca string, v string, di string) error {
+	return m.err()
+}
+
+func (m *mockInstantiationManager) Validate(p string, ca string, v string, di string) (moduleLib.ValidationReport, error) {
+	if m.Err != nil {
+		return moduleLib.ValidationReport{}, m.Err
+	}
+	return m.Report, nil
+}
+
+const digPath = "/v2/projects/testProject/composite-apps/testCompositeApp/v1/deployment-intent-groups/testDig"
+
+func TestValidateHandler(t *testing.T) {
+	config.GetConfiguration().AuditTrustedProxies = "192.0.2.1"
+	defer func() { config.GetConfiguration().AuditTrustedProxies = "" }()
+
+	invalid := moduleLib.ValidationReport{
+		Valid: false,
+		Errors: []moduleLib.ValidationIssue{
+			{Kind: moduleLib.IssueKindController, Name: "ovnaction", Message: "Controller is not registered"},
+		},
+	}
+	valid := moduleLib.ValidationReport{Valid: true, Errors: []moduleLib.ValidationIssue{}}
+
+	testCases := []struct {
+		label        string
+		method       string
+		path         string
+		expectedCode int
+		expected     *moduleLib.ValidationReport
+		client       *mockInstantiationManager
+	}{
+		{
+			label:        "Validate valid DeploymentIntentGroup",
+			method:       "GET",
+			path:         "/validate",
+			expectedCode: http.StatusOK,
+			expected:     &valid,
+			client:       &mockInstantiationManager{Report: valid},
+		},
+		{
+			label:        "Validate invalid DeploymentIntentGroup",
+			method:       "GET",
+			path:         "/validate",
+			expectedCode: http.StatusOK,
+			expected:     &invalid,
+			client:       &mockInstantiationManager{Report: invalid},
+		},
+		{
+			label:        "Validate Manager Error",
+			method:       "GET",
+			path:         "/validate",
+			expectedCode: http.StatusInternalServerError,
+			client:       &mockInstantiationManager{Err: pkgerrors.New("Internal Error")},
+		},
+		{
+			label:        "Approve valid DeploymentIntentGroup",
+			method:       "POST",
+			path:         "/approve",
+			expectedCode: http.StatusAccepted,
+			client:       &mockInstantiationManager{Report: valid, Approved: moduleLib.ApprovalStatus{State: "Approved"}},
+		},
+		{
+			label:        "Approve invalid DeploymentIntentGroup",
+			method:       "POST",
+			path:         "/approve",
+			expectedCode: http.StatusUnprocessableEntity,
+			expected:     &invalid,
+			client:       &mockInstantiationManager{Report: invalid},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, digPath+testCase.path, nil)
+			request.Header.Set("X-Auth-Request-User", "alice")
+			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, testCase.client))
+
+			if resp.StatusCode != testCase.expectedCode {
+				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
+			}
+
+			if testCase.expected != nil {
+				got := moduleLib.ValidationReport{}
+				json.NewDecoder(resp.Body).Decode(&got)
+				if reflect.DeepEqual(*testCase.expected, got) == false {
+					t.Errorf("handler returned unexpected body: got %v; expected %v", got, *testCase.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestApproveAndRejectHandlers(t *testing.T) {
+	// The identity headers are trusted from the address of the httptest requests
+	config.GetConfiguration().AuditTrustedProxies = "192.0.2.1"
+	defer func() { config.GetConfiguration().AuditTrustedProxies = "" }()
+
+	valid := moduleLib.ValidationReport{Valid: true, Errors: []moduleLib.ValidationIssue{}}
+	pending := moduleLib.ApprovalStatus{
+		State:             "Created",
+		Approvals:         1,
+		RequiredApprovals: 2,
+		Entries: []state.ApprovalEntry{
+			{Approver: "alice", Roles: []string{"release-manager"}, Decision: state.DecisionEnum.Approve, Comment: "looks good"},
+		},
+	}
+
+	testCases := []struct {
+		label            string
+		path             string
+		body             string
+		expectedCode     int
+		expectedStatus   *moduleLib.ApprovalStatus
+		expectedApproval moduleLib.Approval
+		remote           string
+		basic            string
+		client           *mockInstantiationManager
+	}{
+		{
+			label:            "Approve with comment",
+			path:             "/approve",
+			body:             `{"comment": "looks good"}`,
+			expectedCode:     http.StatusAccepted,
+			expectedStatus:   &pending,
+			expectedApproval: moduleLib.Approval{Approver: "alice", Roles: []string{"release-manager", "ops"}, Comment: "looks good"},
+			client:           &mockInstantiationManager{Report: valid, Approved: pending},
+		},
+		{
+			label:            "Reject without body",
+			path:             "/reject",
+			expectedCode:     http.StatusAccepted,
+			expectedStatus:   &pending,
+			expectedApproval: moduleLib.Approval{Approver: "alice", Roles: []string{"release-manager", "ops"}},
+			client:           &mockInstantiationManager{Approved: pending},
+		},
+		{
+			label:            "Approver not allowed",
+			path:             "/approve",
+			expectedCode:     http.StatusForbidden,
+			expectedApproval: moduleLib.Approval{Approver: "alice", Roles: []string{"release-manager", "ops"}},
+			client:           &mockInstantiationManager{Err: &moduleLib.ApproverNotAllowedError{Reason: "no role"}},
+		},
+		{
+			label:        "Identity header from an untrusted address",
+			path:         "/approve",
+			expectedCode: http.StatusUnauthorized,
+			remote:       "198.51.100.7:1234",
+			client:       &mockInstantiationManager{Approved: pending},
+		},
+		{
+			label:        "Basic auth is not verified",
+			path:         "/approve",
+			expectedCode: http.StatusUnauthorized,
+			remote:       "198.51.100.7:1234",
+			basic:        "alice",
+			client:       &mockInstantiationManager{Approved: pending},
+		},
+		{
+			label:        "Malformed body",
+			path:         "/reject",
+			body:         `{"comment": `,
+			expectedCode: http.StatusUnprocessableEntity,
+			client:       &mockInstantiationManager{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			request := httptest.NewRequest("POST", digPath+testCase.path, strings.NewReader(testCase.body))
+			request.Header.Set("X-Auth-Request-User", "alice")
+			request.Header.Set("X-Auth-Request-Groups", "release-manager, ops")
+			if testCase.remote != "" {
+				request.RemoteAddr = testCase.remote
+			}
+			if testCase.basic != "" {
+				request.SetBasicAuth(testCase.basic, "secret")
+			}
+			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, testCase.client))
+
+			if resp.StatusCode != testCase.expectedCode {
+				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
+			}
+			if !reflect.DeepEqual(testCase.client.Approval, testCase.expectedApproval) {
+				t.Errorf("handler passed unexpected approval: got %v; expected %v", testCase.client.Approval, testCase.expectedApproval)
+			}
+			if testCase.expectedStatus != nil {
+				got := moduleLib.ApprovalStatus{}
+				json.NewDecoder(resp.Body).Decode(&got)
+				if reflect.DeepEqual(*testCase.expectedStatus, got) == false {
+					t.Errorf("handler returned unexpected body: got %v; expected %v", got, *testCase.expectedStatus)
+				}
+			}
+		})
+	}
+}
+
+func TestLifecycleHandlerVersions(t *testing.T) {
+	conflict := pkgerrors.Wrap(&db.VersionConflictError{Version: 3}, "Error updating the stateInfo of the DeploymentIntentGroup")
+
+	testCases := []struct {
+		label        string
+		path         string
+		ifMatch      string
+		expectedCode int
+		client       *mockInstantiationManager
+	}{
+		{label: "Instantiate without If-Match", path: "/instantiate", expectedCode: http.StatusAccepted, client: &mockInstantiationManager{Version: 3}},
+		{label: "Instantiate at the current version", path: "/instantiate", ifMatch: `"3"`, expectedCode: http.StatusAccepted, client: &mockInstantiationManager{Version: 3}},
+		{label: "Instantiate at a stale version", path: "/instantiate", ifMatch: `"2"`, expectedCode: http.StatusPreconditionFailed, client: &mockInstantiationManager{Version: 3}},
+		{label: "Terminate modified before its write", path: "/terminate", ifMatch: `"3"`, expectedCode: http.StatusPreconditionFailed, client: &mockInstantiationManager{Version: 3, Err: conflict}},
+		{label: "Concurrent terminate", path: "/terminate", expectedCode: http.StatusConflict, client: &mockInstantiationManager{Version: 3, Err: conflict}},
+		{label: "Terminate failure", path: "/terminate", expectedCode: http.StatusInternalServerError, client: &mockInstantiationManager{Err: pkgerrors.New("rsync unavailable")}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			request := httptest.NewRequest("POST", digPath+testCase.path, nil)
+			if testCase.ifMatch != "" {
+				request.Header.Set("If-Match", testCase.ifMatch)
+			}
+			digClient := &mockDeploymentIntentGroupManager{Version: 3}
+			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, digClient, nil, nil, nil, testCase.client))
+
+			if resp.StatusCode != testCase.expectedCode {
+				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -18,11 +18,13 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	moduleLib "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
 
@@ -128,6 +130,13 @@ func (m *mockIntentManager) GetAllIntents(p, ca, v, di string) (moduleLib.ListOf
 	return moduleLib.ListOfIntents{}, nil
 }
 
+func (m *mockIntentManager) ListIntents(p, ca, v, di string, o db.ListOptions) (moduleLib.ListOfIntents, string, error) {
+	if m.Err != nil {
+		return moduleLib.ListOfIntents{}, "", m.Err
+	}
+	return moduleLib.ListOfIntents{}, "", nil
+}
+
 func (m *mockIntentManager) GetIntentByName(i, p, ca, v, di string) (moduleLib.IntentSpecData, error) {
 	if m.Err != nil {
 		return moduleLib.IntentSpecData{}, m.Err
@@ -142,8 +151,9 @@ func (m *mockIntentManager) DeleteIntent(i, p, ca, v, di string) error {
 // ----- DeploymentIntentGroup mock -----
 
 type mockDeploymentIntentGroupManager struct {
-	Items []moduleLib.DeploymentIntentGroup
-	Err   error
+	Items   []moduleLib.DeploymentIntentGroup
+	Version int64
+	Err     error
 }
 
 func (m *mockDeploymentIntentGroupManager) CreateDeploymentIntentGroup(d moduleLib.DeploymentIntentGroup, p, ca, v string) (moduleLib.DeploymentIntentGroup, error) {
@@ -164,6 +174,10 @@ func (m *mockDeploymentIntentGroupManager) GetDeploymentIntentGroupState(di, p,
 	return state.StateInfo{}, m.Err
 }
 
+func (m *mockDeploymentIntentGroupManager) GetDeploymentIntentGroupVersion(di, p, ca, v string) (int64, error) {
+	return m.Version, m.Err
+}
+
 func (m *mockDeploymentIntentGroupManager) DeleteDeploymentIntentGroup(di, p, ca, v string) error {
 	return m.Err
 }
@@ -175,6 +189,13 @@ func (m *mockDeploymentIntentGroupManager) GetAllDeploymentIntentGroups(p, ca, v
 	return m.Items, nil
 }
 
+func (m *mockDeploymentIntentGroupManager) ListDeploymentIntentGroups(p, ca, v string, o db.ListOptions) ([]moduleLib.DeploymentIntentGroup, string, error) {
+	if m.Err != nil {
+		return []moduleLib.DeploymentIntentGroup{}, "", m.Err
+	}
+	return m.Items, "", nil
+}
+
 func init() {
 	gpiJSONFile = "../json-schemas/generic-placement-intent.json"
 	appIntentJSONFile = "../json-schemas/generic-placement-intent-app.json"
@@ -404,6 +425,31 @@ func TestDeploymentIntentGroupHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("Create with cluster scoped override values", func(t *testing.T) {
+		reader := bytes.NewBuffer([]byte(`{"metadata":{"name":"testDig"},"spec":{"profile":"prof","version":"v1","logical-cloud":"lc","override-values":[{"app-name":"app1","values":{"k":"v"}},{"app-name":"app1","cluster-provider":"p1","cluster-label":"edge","values":{"k":"edge"}}]}}`))
+		request := httptest.NewRequest("POST", base, reader)
+		client := &mockDeploymentIntentGroupManager{Items: digs}
+		resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, client, nil, nil, nil, nil))
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected %d; Got: %d", http.StatusCreated, resp.StatusCode)
+		}
+	})
+
+	t.Run("Create with invalid override scope", func(t *testing.T) {
+		for _, scope := range []string{
+			`"cluster":"c1"`,
+			`"cluster-provider":"p1","cluster":"c1","cluster-label":"edge"`,
+		} {
+			reader := bytes.NewBuffer([]byte(`{"metadata":{"name":"testDig"},"spec":{"profile":"prof","version":"v1","logical-cloud":"lc","override-values":[{"app-name":"app1",` + scope + `,"values":{"k":"v"}}]}}`))
+			request := httptest.NewRequest("POST", base, reader)
+			client := &mockDeploymentIntentGroupManager{Items: digs}
+			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, client, nil, nil, nil, nil))
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("Scope %s: Expected %d; Got: %d", scope, http.StatusBadRequest, resp.StatusCode)
+			}
+		}
+	})
+
 	t.Run("Create bad JSON", func(t *testing.T) {
 		var reader io.Reader
 		request := httptest.NewRequest("POST", base, reader)
@@ -424,6 +470,25 @@ func TestDeploymentIntentGroupHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("Create with values violating the chart schema", func(t *testing.T) {
+		reader := bytes.NewBuffer([]byte(`{"metadata":{"name":"testDig"},"spec":{"profile":"prof","version":"v1","logical-cloud":"lc","override-values":[{"app-name":"app1","values":{"service.port":0,"ingress":{"enabled":true}}}]}}`))
+		request := httptest.NewRequest("POST", base, reader)
+		client := &mockDeploymentIntentGroupManager{Err: pkgerrors.Wrap(&moduleLib.ValuesValidationError{
+			Violations: []moduleLib.ValuesViolation{
+				{App: "app1", Pointer: "/service/port", Message: "Must be greater than or equal to 1"},
+			},
+		}, "Validating")}
+		resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, client, nil, nil, nil, nil))
+		if resp.StatusCode != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected %d; Got: %d", http.StatusUnprocessableEntity, resp.StatusCode)
+		}
+		got := moduleLib.ValuesValidationError{}
+		json.NewDecoder(resp.Body).Decode(&got)
+		if len(got.Violations) != 1 || got.Violations[0].Pointer != "/service/port" {
+			t.Fatalf("Unexpected violations %v", got.Violations)
+		}
+	})
+
 	t.Run("Get success", func(t *testing.T) {
 		request := httptest.NewRequest("GET", base+"/testDig", nil)
 		client := &mockDeploymentIntentGroupManager{Items: digs}
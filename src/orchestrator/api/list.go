@@ -0,0 +1,54 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// continueTokenHeader carries the continue token of the next page of a list
+const continueTokenHeader = "X-Continue-Token"
+
+// listOptions reads the list options of the request, with the filters the
+// list supports. It responds with 400 when they are invalid.
+func listOptions(w http.ResponseWriter, r *http.Request, filters ...string) (db.ListOptions, bool) {
+	o, err := db.ListOptionsFromQuery(r.URL.Query(), filters...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return db.ListOptions{}, false
+	}
+	return o, true
+}
+
+// listError responds with 400 to the list options the list does not
+// support, and with code to the other errors
+func listError(w http.ResponseWriter, err error, code int) {
+	if _, ok := pkgerrors.Cause(err).(*db.ListOptionsError); ok {
+		code = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), code)
+}
+
+// setContinueToken returns the continue token of the next page, if any
+func setContinueToken(w http.ResponseWriter, next string) {
+	if next != "" {
+		w.Header().Set(continueTokenHeader, next)
+	}
+}
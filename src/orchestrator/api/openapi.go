@@ -0,0 +1,74 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/audit"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/openapi"
+	moduleLib "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
+	controller "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module/controller"
+)
+
+const (
+	specDIGPath = "/v2/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}"
+)
+
+// listQueries are the query parameters of the paginated lists
+var listQueries = []string{"limit", "continue", "sort"}
+
+// openAPISpec documents the routes of NewRouter. The operations of the
+// collections and of their items are generated from the resources, the
+// other routes have an operation. Every route must have one, see
+// TestOpenAPISpec.
+func openAPISpec() openapi.Spec {
+	return openapi.Spec{
+		Title:   "EMCO Orchestrator",
+		Version: "v2",
+		Resources: map[string]openapi.Resource{
+			"projects":                  {Name: "project", Schema: projectJSONFile, Model: moduleLib.Project{}, Queries: listQueries},
+			"controllers":               {Name: "controller", Schema: controllerJSONFile, Model: controller.Controller{}, UpdateStatus: http.StatusCreated},
+			"audit":                     {Name: "audit entry", Plural: "audit entries", Model: audit.Entry{}, Queries: []string{"project", "service", "from", "to"}},
+			"repo-credentials":          {Name: "repository credential", Schema: repoCredentialJSONFile, Model: moduleLib.RepoCredential{}},
+			"approval-policy":           {Name: "approval policy of the project", Schema: approvalPolicyJSONFile, Model: moduleLib.ApprovalPolicy{}, Singleton: true},
+			"composite-apps":            {Name: "composite application", Schema: caJSONFile, Model: moduleLib.CompositeApp{}, Queries: listQueries},
+			"apps":                      {Name: "application", Schema: appJSONFile, Files: []string{"file"}, Model: moduleLib.App{}, Queries: listQueries},
+			"composite-profiles":        {Name: "composite profile", Schema: caprofileJSONFile, Model: moduleLib.CompositeProfile{}},
+			"profiles":                  {Name: "application profile", Schema: appProfileJSONFile, Files: []string{"file"}, Model: moduleLib.AppProfile{}},
+			"deployment-intent-groups":  {Name: "deployment intent group", Schema: dpiJSONFile, Model: moduleLib.DeploymentIntentGroup{}, Queries: append([]string{"state"}, listQueries...)},
+			"generic-placement-intents": {Name: "generic placement intent", Schema: gpiJSONFile, Model: moduleLib.GenericPlacementIntent{}},
+			"app-intents":               {Name: "application placement intent", Schema: appIntentJSONFile, Model: moduleLib.AppIntent{}, List: moduleLib.ApplicationsAndClusterInfo{}, Find: moduleLib.SpecData{}},
+			"intents":                   {Name: "intent", Schema: addIntentJSONFile, Model: moduleLib.Intent{}, List: moduleLib.ListOfIntents{}, Find: moduleLib.IntentSpecData{}, Queries: listQueries},
+			"schedules":                 {Name: "schedule", Schema: scheduleJSONFile, Model: moduleLib.Schedule{}},
+		},
+		Operations: map[string]openapi.Operation{
+			"GET /v2/openapi.json": {Summary: "Get the OpenAPI specification of the service", Response: map[string]interface{}{}},
+
+			"POST /v2/cluster-providers/{provider-name}/clusters/{cluster-name}/drain": {Summary: "Cordon a cluster and move the apps of the anyOf groups away from it", Response: moduleLib.ClusterDrain{}, Status: http.StatusAccepted},
+			"GET /v2/cluster-providers/{provider-name}/clusters/{cluster-name}/drain":  {Summary: "Get the progress of the drain of a cluster", Response: moduleLib.ClusterDrain{}},
+
+			"POST " + specDIGPath + "/approve":     {Summary: "Approve the deployment intent group", Request: moduleLib.Approval{}, Response: moduleLib.ApprovalStatus{}, Status: http.StatusAccepted},
+			"POST " + specDIGPath + "/reject":      {Summary: "Reject the deployment intent group", Request: moduleLib.Approval{}, Response: moduleLib.ApprovalStatus{}, Status: http.StatusAccepted},
+			"GET " + specDIGPath + "/validate":     {Summary: "Validate the deployment intent group", Response: moduleLib.ValidationReport{}},
+			"POST " + specDIGPath + "/instantiate": {Summary: "Instantiate the deployment intent group", Status: http.StatusAccepted},
+			"POST " + specDIGPath + "/update":      {Summary: "Update the instantiated deployment intent group", Status: http.StatusAccepted},
+			"POST " + specDIGPath + "/terminate":   {Summary: "Terminate the deployment intent group", Status: http.StatusAccepted},
+			"GET " + specDIGPath + "/status":       {Summary: "Get the status of the deployment intent group", Response: moduleLib.DeploymentStatus{}},
+		},
+	}
+}
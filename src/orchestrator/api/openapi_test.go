@@ -0,0 +1,67 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestOpenAPISpec fails when a route is added to NewRouter without an
+// operation in openAPISpec
+func TestOpenAPISpec(t *testing.T) {
+	router := NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	if err := openAPISpec().Check(router); err != nil {
+		t.Fatalf("The OpenAPI specification does not match the routes: %s", err)
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	projectJSONFile = "../json-schemas/metadata.json"
+	request := httptest.NewRequest("GET", "/v2/openapi.json", nil)
+	resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected %d; Got: %d", http.StatusOK, resp.StatusCode)
+	}
+
+	var doc struct {
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	err := json.NewDecoder(resp.Body).Decode(&doc)
+	if err != nil {
+		t.Fatalf("Failed to decode the OpenAPI document: %s", err)
+	}
+	if doc.Paths["/v2/projects/{project-name}"]["put"] == nil {
+		t.Errorf("The OpenAPI document is missing the project update")
+	}
+	intents := "/v2/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/intents/"
+	if doc.Paths[intents+"{intent-name}"]["get"]["summary"] != "Get an intent" || doc.Paths[intents]["get"]["summary"] != "Find the intents by intent" {
+		t.Errorf("The OpenAPI document has unexpected summaries of the intents, got %v and %v",
+			doc.Paths[intents+"{intent-name}"]["get"]["summary"], doc.Paths[intents]["get"]["summary"])
+	}
+	if doc.Components.Schemas["metadata"]["properties"] == nil {
+		t.Errorf("The OpenAPI document is missing the metadata json-schema, got %v", doc.Components.Schemas["metadata"])
+	}
+	if doc.Components.Schemas["Project"] == nil {
+		t.Errorf("The OpenAPI document is missing the Project model")
+	}
+}
@@ -101,13 +101,18 @@ func (h projectHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ret, err := h.client.CreateProject(p, true)
+	ret, version, err := h.client.UpdateProject(p, r.Header.Get("If-Match"))
+	if isConflict(err) {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setETag(w, version)
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(ret)
 	if err != nil {
@@ -126,9 +131,13 @@ func (h projectHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 	if len(name) == 0 {
 		var pList []moduleLib.Project
 
-		projects, err := h.client.GetAllProjects()
+		o, ok := listOptions(w, r)
+		if !ok {
+			return
+		}
+		projects, next, err := h.client.ListProjects(o)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			listError(w, err, http.StatusNotFound)
 			return
 		}
 
@@ -137,6 +146,7 @@ func (h projectHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		w.Header().Set("Content-Type", "application/json")
+		setContinueToken(w, next)
 		w.WriteHeader(http.StatusOK)
 		err = json.NewEncoder(w).Encode(pList)
 		if err != nil {
@@ -147,6 +157,12 @@ func (h projectHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 
 	}
 
+	version, err := h.client.GetProjectVersion(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
 	ret, err := h.client.GetProject(name)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
@@ -154,6 +170,7 @@ func (h projectHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setETag(w, version)
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(ret)
 	if err != nil {
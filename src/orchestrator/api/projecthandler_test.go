@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	moduleLib "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
 
 	pkgerrors "github.com/pkg/errors"
@@ -36,8 +37,10 @@ import (
 type mockProjectManager struct {
 	// Items and err will be used to customize each test
 	// via a localized instantiation of mockProjectManager
-	Items []moduleLib.Project
-	Err   error
+	Items   []moduleLib.Project
+	Next    string
+	Version int64
+	Err     error
 }
 
 func (m *mockProjectManager) CreateProject(inp moduleLib.Project, exists bool) (moduleLib.Project, error) {
@@ -48,6 +51,17 @@ func (m *mockProjectManager) CreateProject(inp moduleLib.Project, exists bool) (
 	return m.Items[0], nil
 }
 
+func (m *mockProjectManager) UpdateProject(inp moduleLib.Project, ifMatch string) (moduleLib.Project, int64, error) {
+	if m.Err != nil {
+		return moduleLib.Project{}, 0, m.Err
+	}
+	if !db.IfMatch(ifMatch, m.Version) {
+		return moduleLib.Project{}, 0, &db.VersionConflictError{Version: m.Version}
+	}
+
+	return m.Items[0], m.Version + 1, nil
+}
+
 func (m *mockProjectManager) GetProject(name string) (moduleLib.Project, error) {
 	if m.Err != nil {
 		return moduleLib.Project{}, m.Err
@@ -56,6 +70,10 @@ func (m *mockProjectManager) GetProject(name string) (moduleLib.Project, error)
 	return m.Items[0], nil
 }
 
+func (m *mockProjectManager) GetProjectVersion(name string) (int64, error) {
+	return m.Version, m.Err
+}
+
 func (m *mockProjectManager) DeleteProject(name string) error {
 	return m.Err
 }
@@ -64,6 +82,13 @@ func (m *mockProjectManager) GetAllProjects() ([]moduleLib.Project, error) {
 	return []moduleLib.Project{}, m.Err
 }
 
+func (m *mockProjectManager) ListProjects(o db.ListOptions) ([]moduleLib.Project, string, error) {
+	if m.Err != nil {
+		return []moduleLib.Project{}, "", m.Err
+	}
+	return m.Items, m.Next, nil
+}
+
 func init() {
 	projectJSONFile = "../json-schemas/metadata.json"
 }
@@ -151,6 +176,8 @@ func TestProjectCreateHandler(t *testing.T) {
 func TestProjectUpdateHandler(t *testing.T) {
 	testCases := []struct {
 		label, name   string
+		ifMatch       string
+		expectedETag  string
 		reader        io.Reader
 		expected      moduleLib.Project
 		expectedCode  int
@@ -183,6 +210,45 @@ func TestProjectUpdateHandler(t *testing.T) {
 			}`)),
 			projectClient: &mockProjectManager{},
 		},
+		{
+			label:        "Stale If-Match Failure",
+			name:         "testProject",
+			ifMatch:      `"4"`,
+			expectedCode: http.StatusPreconditionFailed,
+			reader: bytes.NewBuffer([]byte(`{
+				"metadata" : {
+					"name": "testProject"
+				}
+			}`)),
+			projectClient: &mockProjectManager{Version: 5},
+		},
+		{
+			label:        "Update Project at the current version",
+			name:         "testProject",
+			ifMatch:      `"5"`,
+			expectedCode: http.StatusOK,
+			expectedETag: `"6"`,
+			reader: bytes.NewBuffer([]byte(`{
+				"metadata" : {
+					"name": "testProject"
+				}
+			}`)),
+			expected: moduleLib.Project{
+				MetaData: moduleLib.ProjectMetaData{
+					Name: "testProject",
+				},
+			},
+			projectClient: &mockProjectManager{
+				Version: 5,
+				Items: []moduleLib.Project{
+					{
+						MetaData: moduleLib.ProjectMetaData{
+							Name: "testProject",
+						},
+					},
+				},
+			},
+		},
 		{
 			label:        "Update Project",
 			name:         "testProject",
@@ -220,6 +286,9 @@ func TestProjectUpdateHandler(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.label, func(t *testing.T) {
 			request := httptest.NewRequest("PUT", "/v2/projects/"+testCase.name, testCase.reader)
+			if testCase.ifMatch != "" {
+				request.Header.Set("If-Match", testCase.ifMatch)
+			}
 			resp := executeRequest(request, NewRouter(testCase.projectClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
 
 			//Check returned code
@@ -227,6 +296,12 @@ func TestProjectUpdateHandler(t *testing.T) {
 				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
 			}
 
+			if testCase.expectedETag != "" {
+				if etag := resp.Header.Get("ETag"); etag != testCase.expectedETag {
+					t.Errorf("updateHandler returned unexpected ETag %s", etag)
+				}
+			}
+
 			//Check returned body only if statusOK
 			if resp.StatusCode == http.StatusOK {
 				got := moduleLib.Project{}
@@ -273,6 +348,7 @@ func TestProjectGetHandler(t *testing.T) {
 						},
 					},
 				},
+				Version: 7,
 			},
 		},
 		{
@@ -305,6 +381,73 @@ func TestProjectGetHandler(t *testing.T) {
 					t.Errorf("listHandler returned unexpected body: got %v;"+
 						" expected %v", got, testCase.expected)
 				}
+				if etag := resp.Header.Get("ETag"); etag != `"7"` {
+					t.Errorf("getHandler returned unexpected ETag %s", etag)
+				}
+			}
+		})
+	}
+}
+
+func TestProjectListHandler(t *testing.T) {
+	testCases := []struct {
+		label         string
+		query         string
+		expectedCode  int
+		expectedNext  string
+		projectClient *mockProjectManager
+	}{
+		{
+			label:        "List Projects With Next Page",
+			query:        "?limit=1",
+			expectedCode: http.StatusOK,
+			expectedNext: "MQ",
+			projectClient: &mockProjectManager{
+				Items: []moduleLib.Project{{MetaData: moduleLib.ProjectMetaData{Name: "testProject"}}},
+				Next:  "MQ",
+			},
+		},
+		{
+			label:        "List Last Page Of Projects",
+			query:        "?limit=1&continue=MQ",
+			expectedCode: http.StatusOK,
+			projectClient: &mockProjectManager{
+				Items: []moduleLib.Project{{MetaData: moduleLib.ProjectMetaData{Name: "testProject"}}},
+			},
+		},
+		{
+			label:         "Invalid Limit",
+			query:         "?limit=0",
+			expectedCode:  http.StatusBadRequest,
+			projectClient: &mockProjectManager{},
+		},
+		{
+			label:        "Unsupported Sort",
+			query:        "?sort=color",
+			expectedCode: http.StatusBadRequest,
+			projectClient: &mockProjectManager{
+				Err: pkgerrors.Wrap(&db.ListOptionsError{Reason: "Unsupported sort"}, "Get Projects"),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/v2/projects"+testCase.query, nil)
+			resp := executeRequest(request, NewRouter(testCase.projectClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
+
+			if resp.StatusCode != testCase.expectedCode {
+				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
+			}
+			if next := resp.Header.Get("X-Continue-Token"); next != testCase.expectedNext {
+				t.Errorf("Expected continue token %q; Got: %q", testCase.expectedNext, next)
+			}
+			if resp.StatusCode == http.StatusOK {
+				got := []moduleLib.Project{}
+				json.NewDecoder(resp.Body).Decode(&got)
+				if !reflect.DeepEqual(testCase.projectClient.Items, got) {
+					t.Errorf("listHandler returned unexpected body: got %v; expected %v", got, testCase.projectClient.Items)
+				}
 			}
 		})
 	}
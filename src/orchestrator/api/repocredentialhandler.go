@@ -0,0 +1,183 @@
+/*
+ * Copyright 2019 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/validation"
+	moduleLib "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
+)
+
+var repoCredentialJSONFile string = "json-schemas/repo-credential.json"
+
+// Used to store backend implementations objects
+// Also simplifies mocking for unit testing purposes
+type repoCredentialHandler struct {
+	// Interface that implements RepoCredential operations
+	// We will set this variable with a mock interface for testing
+	client moduleLib.RepoCredentialManager
+}
+
+// maskRepoCredential leaves the password and the token out of the responses
+func maskRepoCredential(rc moduleLib.RepoCredential) moduleLib.RepoCredential {
+	rc.Spec.Password = ""
+	rc.Spec.Token = ""
+	return rc
+}
+
+// decodeRepoCredential decodes and validates the RepoCredential of the request
+func decodeRepoCredential(w http.ResponseWriter, r *http.Request) (moduleLib.RepoCredential, bool) {
+	var rc moduleLib.RepoCredential
+
+	err := json.NewDecoder(r.Body).Decode(&rc)
+	switch {
+	case err == io.EOF:
+		http.Error(w, "Empty body", http.StatusBadRequest)
+		return rc, false
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return rc, false
+	}
+
+	// Verify JSON Body
+	err, httpError := validation.ValidateJsonSchemaData(repoCredentialJSONFile, rc)
+	if err != nil {
+		http.Error(w, err.Error(), httpError)
+		return rc, false
+	}
+	return rc, true
+}
+
+// Create handles creation of the RepoCredential entry in the database
+func (h repoCredentialHandler) createHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	project := vars["project-name"]
+
+	rc, ok := decodeRepoCredential(w, r)
+	if !ok {
+		return
+	}
+
+	ret, err := h.client.CreateRepoCredential(rc, project, false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(maskRepoCredential(ret))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Update handles updating the RepoCredential entry in the database
+func (h repoCredentialHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	project := vars["project-name"]
+	name := vars["repo-credential-name"]
+
+	rc, ok := decodeRepoCredential(w, r)
+	if !ok {
+		return
+	}
+
+	// Name in URL should match name in body
+	if rc.Metadata.Name != name {
+		http.Error(w, "Mismatched name in PUT request", http.StatusBadRequest)
+		return
+	}
+
+	ret, err := h.client.CreateRepoCredential(rc, project, true)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(maskRepoCredential(ret))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Get handles GET operations on a particular RepoCredential Name,
+// or on all the RepoCredentials of the project. The password and the
+// token are not returned
+func (h repoCredentialHandler) getHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	project := vars["project-name"]
+	name := vars["repo-credential-name"]
+
+	var ret interface{}
+	if len(name) == 0 {
+		list, err := h.client.GetAllRepoCredentials(project)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		masked := []moduleLib.RepoCredential{}
+		for _, rc := range list {
+			masked = append(masked, maskRepoCredential(rc))
+		}
+		ret = masked
+	} else {
+		rc, err := h.client.GetRepoCredential(name, project)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		ret = maskRepoCredential(rc)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err := json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Delete handles DELETE operations on a particular RepoCredential Name
+func (h repoCredentialHandler) deleteHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	project := vars["project-name"]
+	name := vars["repo-credential-name"]
+
+	_, err := h.client.GetRepoCredential(name, project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	err = h.client.DeleteRepoCredential(name, project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
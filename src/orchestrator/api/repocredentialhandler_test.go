@@ -0,0 +1,184 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	moduleLib "github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+type mockRepoCredentialManager struct {
+	// Items and err will be used to customize each test
+	// via a localized instantiation of mockRepoCredentialManager
+	Items []moduleLib.RepoCredential
+	Err   error
+}
+
+func (m *mockRepoCredentialManager) CreateRepoCredential(rc moduleLib.RepoCredential, p string, exists bool) (moduleLib.RepoCredential, error) {
+	if m.Err != nil {
+		return moduleLib.RepoCredential{}, m.Err
+	}
+	return rc, nil
+}
+
+func (m *mockRepoCredentialManager) GetRepoCredential(name string, p string) (moduleLib.RepoCredential, error) {
+	if m.Err != nil {
+		return moduleLib.RepoCredential{}, m.Err
+	}
+	return m.Items[0], nil
+}
+
+func (m *mockRepoCredentialManager) GetAllRepoCredentials(p string) ([]moduleLib.RepoCredential, error) {
+	return m.Items, m.Err
+}
+
+func (m *mockRepoCredentialManager) DeleteRepoCredential(name string, p string) error {
+	return m.Err
+}
+
+func init() {
+	repoCredentialJSONFile = "../json-schemas/repo-credential.json"
+}
+
+// repoCredentialRouter routes the requests to a repoCredentialHandler
+// with the given client
+func repoCredentialRouter(client moduleLib.RepoCredentialManager) *mux.Router {
+	h := repoCredentialHandler{client: client}
+	router := mux.NewRouter().PathPrefix("/v2").Subrouter()
+	router.HandleFunc("/projects/{project-name}/repo-credentials", h.createHandler).Methods("POST")
+	router.HandleFunc("/projects/{project-name}/repo-credentials", h.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/repo-credentials/{repo-credential-name}", h.updateHandler).Methods("PUT")
+	router.HandleFunc("/projects/{project-name}/repo-credentials/{repo-credential-name}", h.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/repo-credentials/{repo-credential-name}", h.deleteHandler).Methods("DELETE")
+	return router
+}
+
+func TestRepoCredentialHandler(t *testing.T) {
+	url := "/v2/projects/testProject/repo-credentials"
+	testCred := moduleLib.RepoCredential{
+		Metadata: moduleLib.RepoCredentialMetaData{Name: "charts"},
+		Spec:     moduleLib.RepoCredentialSpec{Username: "user", Password: "pass"},
+	}
+
+	testCases := []struct {
+		label        string
+		method       string
+		url          string
+		body         string
+		expectedCode int
+		client       *mockRepoCredentialManager
+	}{
+		{
+			label:        "Create Credential",
+			method:       "POST",
+			url:          url,
+			body:         `{"metadata":{"name":"charts"},"spec":{"username":"user","password":"pass"}}`,
+			expectedCode: http.StatusCreated,
+			client:       &mockRepoCredentialManager{},
+		},
+		{
+			label:        "Create Credential without password",
+			method:       "POST",
+			url:          url,
+			body:         `{"metadata":{"name":"charts"},"spec":{"username":"user"}}`,
+			expectedCode: http.StatusBadRequest,
+			client:       &mockRepoCredentialManager{},
+		},
+		{
+			label:        "Create Credential Manager Error",
+			method:       "POST",
+			url:          url,
+			body:         `{"metadata":{"name":"charts"},"spec":{"token":"secret"}}`,
+			expectedCode: http.StatusInternalServerError,
+			client:       &mockRepoCredentialManager{Err: pkgerrors.New("Internal Error")},
+		},
+		{
+			label:        "Update Credential with mismatched name",
+			method:       "PUT",
+			url:          url + "/other",
+			body:         `{"metadata":{"name":"charts"},"spec":{"token":"secret"}}`,
+			expectedCode: http.StatusBadRequest,
+			client:       &mockRepoCredentialManager{},
+		},
+		{
+			label:        "Update Credential",
+			method:       "PUT",
+			url:          url + "/charts",
+			body:         `{"metadata":{"name":"charts"},"spec":{"token":"secret"}}`,
+			expectedCode: http.StatusOK,
+			client:       &mockRepoCredentialManager{},
+		},
+		{
+			label:        "Get Credential",
+			method:       "GET",
+			url:          url + "/charts",
+			expectedCode: http.StatusOK,
+			client:       &mockRepoCredentialManager{Items: []moduleLib.RepoCredential{testCred}},
+		},
+		{
+			label:        "Get All Credentials",
+			method:       "GET",
+			url:          url,
+			expectedCode: http.StatusOK,
+			client:       &mockRepoCredentialManager{Items: []moduleLib.RepoCredential{testCred}},
+		},
+		{
+			label:        "Get Missing Credential",
+			method:       "GET",
+			url:          url + "/charts",
+			expectedCode: http.StatusNotFound,
+			client:       &mockRepoCredentialManager{Err: pkgerrors.New("RepoCredential not found")},
+		},
+		{
+			label:        "Delete Credential",
+			method:       "DELETE",
+			url:          url + "/charts",
+			expectedCode: http.StatusNoContent,
+			client:       &mockRepoCredentialManager{Items: []moduleLib.RepoCredential{testCred}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, testCase.url, bytes.NewBufferString(testCase.body))
+			resp := executeRequest(request, repoCredentialRouter(testCase.client))
+
+			//Check returned code
+			if resp.StatusCode != testCase.expectedCode {
+				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
+			}
+
+			//The secrets are never returned
+			if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+				var got interface{}
+				json.NewDecoder(resp.Body).Decode(&got)
+				out, _ := json.Marshal(got)
+				if bytes.Contains(out, []byte("pass")) || bytes.Contains(out, []byte("secret")) {
+					t.Fatalf("Response contains a secret: %s", out)
+				}
+			}
+		})
+	}
+}
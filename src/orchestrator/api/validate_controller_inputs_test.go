@@ -96,6 +96,51 @@ func TestValidateControllerInputs(t *testing.T) {
 			expectError: true,
 			errContains: "Invalid controller priority",
 		},
+		{
+			label: "Valid connection settings",
+			inp: func() controller.Controller {
+				c := validController
+				c.Spec.Timeout = "30s"
+				c.Spec.Retries = 3
+				c.Spec.RetryBackoff = "500ms"
+				c.Spec.TLS = &controller.ControllerTLS{
+					CertFile: "/certs/tls.crt",
+					KeyFile:  "/certs/tls.key",
+				}
+				return c
+			}(),
+			expectError: false,
+		},
+		{
+			label: "Invalid timeout",
+			inp: func() controller.Controller {
+				c := validController
+				c.Spec.Timeout = "10"
+				return c
+			}(),
+			expectError: true,
+			errContains: "Invalid controller timeout",
+		},
+		{
+			label: "Invalid retries",
+			inp: func() controller.Controller {
+				c := validController
+				c.Spec.Retries = 100
+				return c
+			}(),
+			expectError: true,
+			errContains: "Invalid controller retries",
+		},
+		{
+			label: "Client certificate without key",
+			inp: func() controller.Controller {
+				c := validController
+				c.Spec.TLS = &controller.ControllerTLS{CertFile: "/certs/tls.crt"}
+				return c
+			}(),
+			expectError: true,
+			errContains: "must be set together",
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -15,24 +15,38 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"math/rand"
 	"net/http"
 	"os"
 	"os/signal"
 	"time"
+	// Schedules are evaluated in the time zones of the clusters, which the
+	// image does not necessarily provide
+	_ "time/tzdata"
 
 	"github.com/gorilla/handlers"
 	"github.com/onap/multicloud-k8s/src/orchestrator/api"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/audit"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/auth"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/contextdb"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/metrics"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/rpc"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/module"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/module/controller"
 )
 
 func main() {
+	reencrypt := flag.Bool("reencrypt-secrets", false, "Re-encrypt the secrets stored in the database with the primary key, and exit")
+	rotate := flag.Bool("rotate-secret-key", false, "Create a new primary key before re-encrypting the secrets, and exit")
+	flag.Parse()
+
+	conf := config.GetConfiguration()
+	secret.Configure(secret.Config{KeyProvider: conf.SecretKeyProvider, KeyFile: conf.SecretKeyFile})
 
 	rand.Seed(time.Now().UnixNano())
 
@@ -48,9 +62,29 @@ func main() {
 		log.Println(err)
 		log.Fatalln("Exiting...")
 	}
+	metrics.InstrumentDatabases()
+
+	if *reencrypt || *rotate {
+		n, err := secret.Migrate(*rotate, module.NewRepoCredentialClient().ReencryptSecrets)
+		if err != nil {
+			log.Fatalln("Error re-encrypting secrets:", err)
+		}
+		log.Printf("Re-encrypted %d secrets", n)
+		return
+	}
+
+	// The deployment intent groups are listed by their state once stored
+	// with it, before they are served or scheduled
+	_, err = module.NewDeploymentIntentGroupClient().BackfillStates()
+	if err != nil {
+		log.Println("Unable to store the state of the deployment intent groups...")
+		log.Println(err)
+	}
 
 	httpRouter := api.NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
-	loggedRouter := handlers.LoggingHandler(os.Stdout, httpRouter)
+	httpRouter.Use(metrics.Middleware("orchestrator"))
+	httpRouter.Use(audit.Middleware("orchestrator"))
+	loggedRouter := handlers.LoggingHandler(os.Stdout, metrics.Handler(httpRouter))
 	log.Println("Starting Kubernetes Multicloud API")
 
 	httpServer := &http.Server{
@@ -59,6 +93,14 @@ func main() {
 	}
 
 	controller.NewControllerClient().InitControllers()
+	controller.NewControllerClient().StartHealthChecks()
+	audit.StartRetention(time.Hour)
+	module.StartScheduler(time.Minute)
+	err = module.NewClusterDrainClient().RecoverDrains()
+	if err != nil {
+		log.Println("Unable to recover the interrupted cluster drains...")
+		log.Println(err)
+	}
 
 	connectionsClose := make(chan struct{})
 	go func() {
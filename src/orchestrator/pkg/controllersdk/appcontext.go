@@ -0,0 +1,179 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllersdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// resourceSeparator separates the name and kind in an AppContext resource name
+const resourceSeparator = "+"
+
+// Resource is a resource of an app on a cluster in an AppContext
+type Resource struct {
+	App     string
+	Cluster string
+	// Name is the AppContext resource name, <name>+<kind>
+	Name   string
+	Handle interface{}
+	Value  string
+}
+
+// ObjectName returns the name of the Kubernetes object of the resource
+func (r Resource) ObjectName() string {
+	i := strings.LastIndex(r.Name, resourceSeparator)
+	if i < 0 {
+		return r.Name
+	}
+	return r.Name[:i]
+}
+
+// Kind returns the kind of the Kubernetes object of the resource
+func (r Resource) Kind() string {
+	i := strings.LastIndex(r.Name, resourceSeparator)
+	if i < 0 {
+		return ""
+	}
+	return r.Name[i+1:]
+}
+
+// LoadAppContext loads the AppContext with the given id along with the
+// metadata of its composite app
+func LoadAppContext(appContextId string) (*appcontext.AppContext, appcontext.CompositeAppMeta, error) {
+	ac := &appcontext.AppContext{}
+	_, err := ac.LoadAppContext(appContextId)
+	if err != nil {
+		return nil, appcontext.CompositeAppMeta{}, pkgerrors.Wrapf(err, "Error getting AppContext with Id: %v", appContextId)
+	}
+	meta, err := ac.GetCompositeAppMeta()
+	if err != nil {
+		return nil, appcontext.CompositeAppMeta{}, pkgerrors.Wrapf(err, "Error getting metadata for AppContext with Id: %v", appContextId)
+	}
+	return ac, meta, nil
+}
+
+// GetApps returns the apps of the AppContext in deployment order
+func GetApps(ac *appcontext.AppContext) ([]string, error) {
+	v, err := ac.GetAppInstruction("order")
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error getting app order instruction")
+	}
+	var appOrder struct {
+		Apporder []string `json:"apporder"`
+	}
+	err = json.Unmarshal([]byte(fmt.Sprintf("%v", v)), &appOrder)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error unmarshalling app order instruction")
+	}
+	return appOrder.Apporder, nil
+}
+
+// GetClusters returns the clusters an app of the AppContext is placed on
+func GetClusters(ac *appcontext.AppContext, app string) ([]string, error) {
+	clusters, err := ac.GetClusterNames(app)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Error getting clusters for app %v", app)
+	}
+	return clusters, nil
+}
+
+// GetResources returns the resources of an app on a cluster in deployment order
+func GetResources(ac *appcontext.AppContext, app, cluster string) ([]Resource, error) {
+	v, err := ac.GetResourceInstruction(app, cluster, "order")
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Error getting resource order instruction for app %v cluster %v", app, cluster)
+	}
+	var resOrder struct {
+		Resorder []string `json:"resorder"`
+	}
+	err = json.Unmarshal([]byte(fmt.Sprintf("%v", v)), &resOrder)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Error unmarshalling resource order instruction for app %v cluster %v", app, cluster)
+	}
+
+	resources := make([]Resource, 0, len(resOrder.Resorder))
+	for _, name := range resOrder.Resorder {
+		rh, err := ac.GetResourceHandle(app, cluster, name)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "Error getting handle of resource %v", name)
+		}
+		val, err := ac.GetValue(rh)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "Error getting value of resource %v", name)
+		}
+		resources = append(resources, Resource{
+			App:     app,
+			Cluster: cluster,
+			Name:    name,
+			Handle:  rh,
+			Value:   fmt.Sprintf("%v", val),
+		})
+	}
+	return resources, nil
+}
+
+// ForEachResource calls fn for every resource of every app on every cluster
+// of the AppContext. Iteration stops at the first error returned by fn.
+func ForEachResource(ac *appcontext.AppContext, fn func(r Resource) error) error {
+	apps, err := GetApps(ac)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		err = ForEachAppResource(ac, app, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachAppResource calls fn for every resource of an app on every cluster
+// it is placed on. Iteration stops at the first error returned by fn.
+func ForEachAppResource(ac *appcontext.AppContext, app string, fn func(r Resource) error) error {
+	clusters, err := GetClusters(ac, app)
+	if err != nil {
+		return err
+	}
+	for _, cluster := range clusters {
+		resources, err := GetResources(ac, app, cluster)
+		if err != nil {
+			return err
+		}
+		for _, r := range resources {
+			err = fn(r)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateResource replaces the value of a resource in the AppContext
+func UpdateResource(ac *appcontext.AppContext, r Resource, value string) error {
+	err := ac.UpdateResourceValue(r.Handle, value)
+	if err != nil {
+		return pkgerrors.Wrapf(err, "Error updating resource %v of app %v on cluster %v", r.Name, r.App, r.Cluster)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllersdk
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
+	contextpb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/grpc/contextupdate"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/contextdb"
+	pkgerrors "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      annotations:
+        existing: "true"
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`
+
+const service = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+var testMeta = appcontext.CompositeAppMeta{
+	Project:               "p1",
+	CompositeApp:          "ca1",
+	Version:               "v1",
+	Release:               "r1",
+	DeploymentIntentGroup: "dig1",
+}
+
+func newTestAppContext(t *testing.T) string {
+	t.Helper()
+	contextdb.Db = &contextdb.MockEtcd{}
+	id, err := NewFakeAppContext(testMeta, []FakeResource{
+		{App: "app1", Cluster: "provider1+cluster1", Name: "web+Deployment", Value: deployment},
+		{App: "app1", Cluster: "provider1+cluster1", Name: "web+Service", Value: service},
+		{App: "app1", Cluster: "provider1+cluster2", Name: "web+Deployment", Value: deployment},
+		{App: "app2", Cluster: "provider1+cluster1", Name: "web+Service", Value: service},
+	})
+	if err != nil {
+		t.Fatalf("NewFakeAppContext returned an unexpected error %s", err)
+	}
+	return id
+}
+
+func TestForEachResource(t *testing.T) {
+	id := newTestAppContext(t)
+
+	ac, meta, err := LoadAppContext(id)
+	if err != nil {
+		t.Fatalf("LoadAppContext returned an unexpected error %s", err)
+	}
+	if reflect.DeepEqual(meta, testMeta) == false {
+		t.Errorf("LoadAppContext returned unexpected meta: got %v; expected %v", meta, testMeta)
+	}
+
+	var got []string
+	err = ForEachResource(ac, func(r Resource) error {
+		got = append(got, r.App+"/"+r.Cluster+"/"+r.ObjectName()+"/"+r.Kind())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachResource returned an unexpected error %s", err)
+	}
+	expected := []string{
+		"app1/provider1+cluster1/web/Deployment",
+		"app1/provider1+cluster1/web/Service",
+		"app1/provider1+cluster2/web/Deployment",
+		"app2/provider1+cluster1/web/Service",
+	}
+	// clusters are not ordered within an app
+	if len(got) != len(expected) || got[len(got)-1] != expected[len(expected)-1] {
+		t.Errorf("ForEachResource visited unexpected resources: got %v; expected %v", got, expected)
+	}
+	for _, e := range expected {
+		found := false
+		for _, g := range got {
+			if g == e {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("ForEachResource did not visit %v", e)
+		}
+	}
+
+	err = ForEachResource(ac, func(r Resource) error {
+		return pkgerrors.New("stop")
+	})
+	if err == nil || err.Error() != "stop" {
+		t.Errorf("ForEachResource did not return the callback error: %v", err)
+	}
+}
+
+func TestUpdateResourceObject(t *testing.T) {
+	id := newTestAppContext(t)
+	ac, _, err := LoadAppContext(id)
+	if err != nil {
+		t.Fatalf("LoadAppContext returned an unexpected error %s", err)
+	}
+
+	err = ForEachAppResource(ac, "app1", func(r Resource) error {
+		if r.Kind() != "Deployment" {
+			return nil
+		}
+		obj, err := r.Object()
+		if err != nil {
+			return err
+		}
+		AddLabels(obj, map[string]string{"emco": "yes"})
+		err = AddPodAnnotations(obj, map[string]string{"added": "true"})
+		if err != nil {
+			return err
+		}
+		return UpdateResourceObject(ac, r, obj)
+	})
+	if err != nil {
+		t.Fatalf("Updating resources returned an unexpected error %s", err)
+	}
+
+	resources, err := GetResources(ac, "app1", "provider1+cluster1")
+	if err != nil {
+		t.Fatalf("GetResources returned an unexpected error %s", err)
+	}
+	obj, err := resources[0].Object()
+	if err != nil {
+		t.Fatalf("Object returned an unexpected error %s", err)
+	}
+	if obj.GetLabels()["emco"] != "yes" {
+		t.Errorf("Label not stored in AppContext: %v", obj.GetLabels())
+	}
+	if !strings.Contains(resources[0].Value, "added: \"true\"") || !strings.Contains(resources[0].Value, "existing: \"true\"") {
+		t.Errorf("Pod annotations not stored in AppContext: %v", resources[0].Value)
+	}
+}
+
+func TestPatches(t *testing.T) {
+	obj, err := DecodeResource(deployment)
+	if err != nil {
+		t.Fatalf("DecodeResource returned an unexpected error %s", err)
+	}
+
+	merged, err := ApplyMergePatch(obj, []byte(`{"spec":{"replicas":3}}`))
+	if err != nil {
+		t.Fatalf("ApplyMergePatch returned an unexpected error %s", err)
+	}
+	replicas, _, _ := unstructured.NestedInt64(merged.Object, "spec", "replicas")
+	if replicas != 3 {
+		t.Errorf("ApplyMergePatch did not set replicas: %v", merged.Object)
+	}
+
+	patched, err := ApplyJSONPatch(obj, []byte(`[{"op":"replace","path":"/metadata/name","value":"web2"}]`))
+	if err != nil {
+		t.Fatalf("ApplyJSONPatch returned an unexpected error %s", err)
+	}
+	if patched.GetName() != "web2" || obj.GetName() != "web" {
+		t.Errorf("ApplyJSONPatch returned %v, original %v", patched.GetName(), obj.GetName())
+	}
+
+	_, err = ApplyJSONPatch(obj, []byte(`[{"op":"remove","path":"/spec/missing"}]`))
+	if err == nil {
+		t.Errorf("ApplyJSONPatch expected an error for a missing path")
+	}
+
+	svc, _ := DecodeResource(service)
+	if AddPodAnnotations(svc, map[string]string{"a": "b"}) == nil {
+		t.Errorf("AddPodAnnotations expected an error for a Service")
+	}
+}
+
+type testController struct {
+	err   error
+	calls []string
+}
+
+func (c *testController) UpdateAppContext(intentName, appContextId string) error {
+	c.calls = append(c.calls, "update:"+intentName+":"+appContextId)
+	return c.err
+}
+
+type testTerminator struct {
+	testController
+}
+
+func (c *testTerminator) TerminateAppContext(intentName, appContextId string) error {
+	c.calls = append(c.calls, "terminate:"+intentName+":"+appContextId)
+	return c.err
+}
+
+func (c *testTerminator) UpdateAppContextFrom(intentName, oldAppContextId, appContextId string) error {
+	c.calls = append(c.calls, "updatefrom:"+intentName+":"+oldAppContextId+":"+appContextId)
+	return c.err
+}
+
+func TestContextupdateServer(t *testing.T) {
+	ctx := context.Background()
+
+	c := &testController{}
+	srv := NewContextupdateServer("test", c)
+	resp, err := srv.UpdateAppContext(ctx, &contextpb.ContextUpdateRequest{AppContext: "ac2", IntentName: "i1", UpdateFromAppContext: "ac1"})
+	if err != nil || !resp.AppContextUpdated {
+		t.Fatalf("UpdateAppContext returned %v, %v", resp, err)
+	}
+	tresp, err := srv.TerminateAppContext(ctx, &contextpb.TerminateRequest{AppContext: "ac2", IntentName: "i1"})
+	if err != nil || !tresp.AppContextTerminated {
+		t.Fatalf("TerminateAppContext returned %v, %v", tresp, err)
+	}
+	if reflect.DeepEqual(c.calls, []string{"update:i1:ac2"}) == false {
+		t.Errorf("Unexpected controller calls %v", c.calls)
+	}
+
+	tc := &testTerminator{}
+	srv = NewContextupdateServer("test", tc)
+	srv.UpdateAppContext(ctx, &contextpb.ContextUpdateRequest{AppContext: "ac2", IntentName: "i1", UpdateFromAppContext: "ac1"})
+	srv.TerminateAppContext(ctx, &contextpb.TerminateRequest{AppContext: "ac2", IntentName: "i1"})
+	if reflect.DeepEqual(tc.calls, []string{"updatefrom:i1:ac1:ac2", "terminate:i1:ac2"}) == false {
+		t.Errorf("Unexpected controller calls %v", tc.calls)
+	}
+
+	tc = &testTerminator{testController{err: pkgerrors.New("failed")}}
+	srv = NewContextupdateServer("test", tc)
+	resp, err = srv.UpdateAppContext(ctx, &contextpb.ContextUpdateRequest{AppContext: "ac1", IntentName: "i1"})
+	if err != nil || resp.AppContextUpdated || resp.AppContextUpdateMessage != "failed" {
+		t.Errorf("UpdateAppContext returned %v, %v", resp, err)
+	}
+	tresp, err = srv.TerminateAppContext(ctx, &contextpb.TerminateRequest{AppContext: "ac1", IntentName: "i1"})
+	if err != nil || tresp.AppContextTerminated {
+		t.Errorf("TerminateAppContext returned %v, %v", tresp, err)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllersdk
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/contextdb"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// FakeResource is a resource of an app on a cluster in a fake AppContext.
+// Name is the AppContext resource name, <name>+<kind>.
+type FakeResource struct {
+	App     string
+	Cluster string
+	Name    string
+	Value   string
+}
+
+// NewFakeAppContext creates an AppContext holding the given resources in an
+// in-memory context database, for the unit tests of controllers. Apps,
+// clusters and resources keep the order in which they are first listed.
+// It returns the id of the AppContext, to be passed to the controller.
+func NewFakeAppContext(meta appcontext.CompositeAppMeta, resources []FakeResource) (string, error) {
+	if _, ok := contextdb.Db.(*contextdb.MockEtcd); !ok {
+		contextdb.Db = &contextdb.MockEtcd{}
+	}
+
+	ac := appcontext.AppContext{}
+	ctxval, err := ac.InitAppContext()
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error creating AppContext")
+	}
+	compositeHandle, err := ac.CreateCompositeApp()
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error creating AppContext CompositeApp")
+	}
+	err = ac.AddCompositeAppMeta(meta)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error adding CompositeApp meta to AppContext")
+	}
+
+	var apps []string
+	appHandles := make(map[string]interface{})
+	type clusterKey struct{ app, cluster string }
+	var clusters []clusterKey
+	clusterHandles := make(map[clusterKey]interface{})
+	resOrder := make(map[clusterKey][]string)
+
+	for _, r := range resources {
+		ah, ok := appHandles[r.App]
+		if !ok {
+			ah, err = ac.AddApp(compositeHandle, r.App)
+			if err != nil {
+				return "", pkgerrors.Wrapf(err, "Error adding app %v to AppContext", r.App)
+			}
+			appHandles[r.App] = ah
+			apps = append(apps, r.App)
+		}
+
+		ck := clusterKey{r.App, r.Cluster}
+		ch, ok := clusterHandles[ck]
+		if !ok {
+			ch, err = ac.AddCluster(ah, r.Cluster)
+			if err != nil {
+				return "", pkgerrors.Wrapf(err, "Error adding cluster %v to AppContext", r.Cluster)
+			}
+			clusterHandles[ck] = ch
+			clusters = append(clusters, ck)
+		}
+
+		_, err = ac.AddResource(ch, r.Name, r.Value)
+		if err != nil {
+			return "", pkgerrors.Wrapf(err, "Error adding resource %v to AppContext", r.Name)
+		}
+		resOrder[ck] = append(resOrder[ck], r.Name)
+	}
+
+	for _, ck := range clusters {
+		j, _ := json.Marshal(struct {
+			Resorder []string `json:"resorder"`
+		}{resOrder[ck]})
+		_, err = ac.AddInstruction(clusterHandles[ck], "resource", "order", string(j))
+		if err != nil {
+			return "", pkgerrors.Wrap(err, "Error adding resource order instruction to AppContext")
+		}
+	}
+
+	j, _ := json.Marshal(struct {
+		Apporder []string `json:"apporder"`
+	}{apps})
+	_, err = ac.AddInstruction(compositeHandle, "app", "order", string(j))
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error adding app order instruction to AppContext")
+	}
+
+	return fmt.Sprintf("%v", ctxval), nil
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package controllersdk provides the common parts of EMCO placement and
+// action controllers: the contextupdate gRPC server, iteration over the
+// resources of an AppContext and helpers to patch those resources.
+package controllersdk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	contextpb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/grpc/contextupdate"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/testdata"
+)
+
+const defaultHost = "localhost"
+
+// Controller is implemented by every placement and action controller.
+// UpdateAppContext applies the named intent to the AppContext.
+type Controller interface {
+	UpdateAppContext(intentName, appContextId string) error
+}
+
+// Updater is implemented by controllers which need to know the AppContext
+// being replaced when a deployment intent group is updated. Controllers which
+// do not implement it get UpdateAppContext called with the new AppContext.
+type Updater interface {
+	UpdateAppContextFrom(intentName, oldAppContextId, appContextId string) error
+}
+
+// Terminator is implemented by controllers which hold state for an intent
+// that must be released when its deployment intent group is terminated.
+type Terminator interface {
+	TerminateAppContext(intentName, appContextId string) error
+}
+
+type contextupdateServer struct {
+	contextpb.UnimplementedContextupdateServer
+	name       string
+	controller Controller
+}
+
+func (cs *contextupdateServer) UpdateAppContext(ctx context.Context, req *contextpb.ContextUpdateRequest) (*contextpb.ContextUpdateResponse, error) {
+	log.Info("Received Update App Context request", log.Fields{
+		"Controller":             cs.name,
+		"AppContextId":           req.AppContext,
+		"IntentName":             req.IntentName,
+		"UpdateFromAppContextId": req.UpdateFromAppContext,
+	})
+
+	var err error
+	if u, ok := cs.controller.(Updater); ok && req.UpdateFromAppContext != "" {
+		err = u.UpdateAppContextFrom(req.IntentName, req.UpdateFromAppContext, req.AppContext)
+	} else {
+		err = cs.controller.UpdateAppContext(req.IntentName, req.AppContext)
+	}
+
+	if err != nil {
+		return &contextpb.ContextUpdateResponse{AppContextUpdated: false, AppContextUpdateMessage: err.Error()}, nil
+	}
+
+	return &contextpb.ContextUpdateResponse{AppContextUpdated: true, AppContextUpdateMessage: fmt.Sprintf("Successful application of intent %v to %v", req.IntentName, req.AppContext)}, nil
+}
+
+func (cs *contextupdateServer) TerminateAppContext(ctx context.Context, req *contextpb.TerminateRequest) (*contextpb.TerminateResponse, error) {
+	log.Info("Received Terminate App Context request", log.Fields{
+		"Controller":   cs.name,
+		"AppContextId": req.AppContext,
+		"IntentName":   req.IntentName,
+	})
+
+	if t, ok := cs.controller.(Terminator); ok {
+		err := t.TerminateAppContext(req.IntentName, req.AppContext)
+		if err != nil {
+			return &contextpb.TerminateResponse{AppContextTerminated: false, AppContextTerminateMessage: err.Error()}, nil
+		}
+	}
+
+	return &contextpb.TerminateResponse{AppContextTerminated: true, AppContextTerminateMessage: fmt.Sprintf("Successful termination of intent %v for %v", req.IntentName, req.AppContext)}, nil
+}
+
+// NewContextupdateServer returns the contextupdate gRPC service of the named
+// controller, dispatching to the Controller and the optional Updater and
+// Terminator interfaces it implements.
+func NewContextupdateServer(name string, c Controller) contextpb.ContextupdateServer {
+	return &contextupdateServer{
+		name:       name,
+		controller: c,
+	}
+}
+
+// GetServerHostPort returns the host and port of the controller's gRPC server.
+// The service name is read from the nameEnv environment variable, defaulting
+// to defaultName, and the host and port from <NAME>_SERVICE_HOST and
+// <NAME>_SERVICE_PORT, defaulting to localhost and defaultPort.
+func GetServerHostPort(nameEnv, defaultName string, defaultPort int) (string, int) {
+	serviceName := os.Getenv(nameEnv)
+	if serviceName == "" {
+		serviceName = defaultName
+		log.Info("Using default name for service", log.Fields{
+			"Name": serviceName,
+		})
+	}
+
+	host := os.Getenv(strings.ToUpper(serviceName) + "_SERVICE_HOST")
+	if host == "" {
+		host = defaultHost
+		log.Info("Using default host for gRPC controller", log.Fields{
+			"Name": serviceName,
+			"Host": host,
+		})
+	}
+
+	port, err := strconv.Atoi(os.Getenv(strings.ToUpper(serviceName) + "_SERVICE_PORT"))
+	if err != nil || port < 0 {
+		port = defaultPort
+		log.Info("Using default port for gRPC controller", log.Fields{
+			"Name": serviceName,
+			"Port": port,
+		})
+	}
+	return host, port
+}
+
+// serverOptions returns the gRPC server options, with TLS credentials when
+// grpc-enable-tls is set in the configuration
+func serverOptions() ([]grpc.ServerOption, error) {
+	if !strings.Contains(config.GetConfiguration().GrpcEnableTLS, "enable") {
+		return nil, nil
+	}
+
+	certFile := config.GetConfiguration().GrpcServerCert
+	keyFile := config.GetConfiguration().GrpcServerKey
+	if certFile == "" {
+		certFile = testdata.Path("server.pem")
+	}
+	if keyFile == "" {
+		keyFile = testdata.Path("server.key")
+	}
+	creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Could not generate credentials")
+	}
+	return []grpc.ServerOption{grpc.Creds(creds)}, nil
+}
+
+// NewGrpcServer returns a gRPC server, using TLS if configured, with the
+// contextupdate service of the controller and the gRPC health service
+// registered.
+func NewGrpcServer(name string, c Controller) (*grpc.Server, error) {
+	opts, err := serverOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+	contextpb.RegisterContextupdateServer(grpcServer, NewContextupdateServer(name, c))
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	return grpcServer, nil
+}
+
+// StartGrpcServer serves the controller's gRPC services on the given port.
+// It only returns when the server stops.
+func StartGrpcServer(name string, port int, c Controller) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return pkgerrors.Wrapf(err, "Could not listen to port %d", port)
+	}
+
+	grpcServer, err := NewGrpcServer(name, c)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Starting gRPC server", log.Fields{
+		"Controller": name,
+		"Port":       port,
+	})
+	return grpcServer.Serve(lis)
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllersdk
+
+import (
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
+	pkgerrors "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// podTemplatePaths are the paths to the pod template of the workload kinds
+var podTemplatePaths = map[string][]string{
+	"Deployment":            {"spec", "template"},
+	"DaemonSet":             {"spec", "template"},
+	"StatefulSet":           {"spec", "template"},
+	"ReplicaSet":            {"spec", "template"},
+	"ReplicationController": {"spec", "template"},
+	"Job":                   {"spec", "template"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template"},
+}
+
+// DecodeResource decodes a YAML or JSON resource into an unstructured object
+func DecodeResource(value string) (*unstructured.Unstructured, error) {
+	j, err := yaml.YAMLToJSON([]byte(value))
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error converting resource to JSON")
+	}
+	obj := &unstructured.Unstructured{}
+	err = obj.UnmarshalJSON(j)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error decoding resource")
+	}
+	return obj, nil
+}
+
+// EncodeResource encodes an unstructured object into YAML
+func EncodeResource(obj *unstructured.Unstructured) (string, error) {
+	j, err := obj.MarshalJSON()
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error encoding resource")
+	}
+	y, err := yaml.JSONToYAML(j)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error converting resource to YAML")
+	}
+	return string(y), nil
+}
+
+// Object decodes the value of the resource into an unstructured object
+func (r Resource) Object() (*unstructured.Unstructured, error) {
+	return DecodeResource(r.Value)
+}
+
+// UpdateResourceObject replaces the value of a resource in the AppContext
+// with the encoded object
+func UpdateResourceObject(ac *appcontext.AppContext, r Resource, obj *unstructured.Unstructured) error {
+	value, err := EncodeResource(obj)
+	if err != nil {
+		return err
+	}
+	return UpdateResource(ac, r, value)
+}
+
+func mergeStringMap(m map[string]string, add map[string]string) map[string]string {
+	if m == nil {
+		m = make(map[string]string)
+	}
+	for k, v := range add {
+		m[k] = v
+	}
+	return m
+}
+
+// AddAnnotations adds the annotations to the object, replacing existing
+// annotations with the same keys
+func AddAnnotations(obj *unstructured.Unstructured, annotations map[string]string) {
+	obj.SetAnnotations(mergeStringMap(obj.GetAnnotations(), annotations))
+}
+
+// AddLabels adds the labels to the object, replacing existing labels with
+// the same keys
+func AddLabels(obj *unstructured.Unstructured, labels map[string]string) {
+	obj.SetLabels(mergeStringMap(obj.GetLabels(), labels))
+}
+
+// AddPodAnnotations adds the annotations to the pods of the object: to the
+// object itself for a Pod and to the pod template for the workload kinds.
+func AddPodAnnotations(obj *unstructured.Unstructured, annotations map[string]string) error {
+	if obj.GetKind() == "Pod" {
+		AddAnnotations(obj, annotations)
+		return nil
+	}
+
+	path, ok := podTemplatePaths[obj.GetKind()]
+	if !ok {
+		return pkgerrors.Errorf("Resource kind %v has no pods", obj.GetKind())
+	}
+
+	annotationsPath := append(append([]string{}, path...), "metadata", "annotations")
+	existing, _, err := unstructured.NestedStringMap(obj.Object, annotationsPath...)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error getting pod template annotations")
+	}
+	err = unstructured.SetNestedStringMap(obj.Object, mergeStringMap(existing, annotations), annotationsPath...)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error setting pod template annotations")
+	}
+	return nil
+}
+
+// ApplyMergePatch applies an RFC 7386 JSON merge patch to the object
+func ApplyMergePatch(obj *unstructured.Unstructured, patch []byte) (*unstructured.Unstructured, error) {
+	return applyPatch(obj, func(doc []byte) ([]byte, error) {
+		return jsonpatch.MergePatch(doc, patch)
+	})
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON patch to the object
+func ApplyJSONPatch(obj *unstructured.Unstructured, patch []byte) (*unstructured.Unstructured, error) {
+	p, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error decoding JSON patch")
+	}
+	return applyPatch(obj, p.Apply)
+}
+
+func applyPatch(obj *unstructured.Unstructured, apply func(doc []byte) ([]byte, error)) (*unstructured.Unstructured, error) {
+	doc, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error encoding resource")
+	}
+	patched, err := apply(doc)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error applying patch")
+	}
+	result := &unstructured.Unstructured{}
+	err = result.UnmarshalJSON(patched)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error decoding patched resource")
+	}
+	return result, nil
+}
@@ -52,13 +52,16 @@ type ClusterWithLabel struct {
 	ClusterLabel string
 }
 
-// IntentStruc consists of AllOfArray and AnyOfArray
+// IntentStruc consists of AllOfArray and AnyOfArray. When SkipNotReadyClusters
+// is set, the clusters which clm last found NotReady are left out.
 type IntentStruc struct {
-	AllOfArray []AllOf `json:"allOf,omitempty"`
-	AnyOfArray []AnyOf `json:"anyOf,omitempty"`
+	AllOfArray           []AllOf `json:"allOf,omitempty"`
+	AnyOfArray           []AnyOf `json:"anyOf,omitempty"`
+	SkipNotReadyClusters bool    `json:"skipNotReadyClusters,omitempty"`
 }
 
-// AllOf consists if ProviderName, ClusterName, ClusterLabelName and AnyOfArray. Any of them can be empty
+// AllOf consists if ProviderName, ClusterName, ClusterLabelName and AnyOfArray. Any of them can be empty.
+// ClusterLabelName is a label selector of the clusters, see cluster.LabelSelector
 type AllOf struct {
 	ProviderName     string  `json:"provider-name,omitempty"`
 	ClusterName      string  `json:"cluster-name,omitempty"`
@@ -73,9 +76,50 @@ type AnyOf struct {
 	ClusterLabelName string `json:"cluster-label-name,omitempty"`
 }
 
+// clusterNotReady tells whether clm last found the cluster NotReady. The
+// clusters which have not been probed yet are not skipped.
+func clusterNotReady(pn, cn string) (bool, error) {
+	s, err := cluster.NewClusterClient().GetClusterStatus(pn, cn)
+	if err != nil {
+		return false, pkgerrors.Wrap(err, "Error getting cluster status")
+	}
+	if s.State == cluster.ClusterStateNotReady {
+		log.Printf("Skipped Cluster: %s which is not ready: %s", cn, s.Message)
+		return true, nil
+	}
+	return false, nil
+}
+
+// clusterExcluded tells whether a cluster is left out of the placement:
+// when it is cordoned and skipCordoned is set, or when it is NotReady and
+// skipNotReady is set
+func clusterExcluded(pn, cn string, skipNotReady, skipCordoned bool) (bool, error) {
+	if skipCordoned {
+		cordoned, err := cluster.NewClusterClient().IsClusterCordoned(pn, cn)
+		if err != nil {
+			return false, pkgerrors.Wrap(err, "Error getting cluster cordon")
+		}
+		if cordoned {
+			log.Printf("Skipped Cluster: %s which is cordoned", cn)
+			return true, nil
+		}
+	}
+	if skipNotReady {
+		return clusterNotReady(pn, cn)
+	}
+	return false, nil
+}
+
 // intentResolverHelper helps to populate the cluster lists
-func intentResolverHelper(pn, cn, cln string, clustersWithName []ClusterWithName) ([]ClusterWithName, error) {
+func intentResolverHelper(pn, cn, cln string, skipNotReady, skipCordoned bool, clustersWithName []ClusterWithName) ([]ClusterWithName, error) {
 	if cln == "" && cn != "" {
+		excluded, err := clusterExcluded(pn, cn, skipNotReady, skipCordoned)
+		if err != nil {
+			return []ClusterWithName{}, err
+		}
+		if excluded {
+			return clustersWithName, nil
+		}
 		eachClusterWithName := ClusterWithName{pn, cn}
 		clustersWithName = append(clustersWithName, eachClusterWithName)
 		log.Printf("Added Cluster: %s ", cn)
@@ -88,6 +132,13 @@ func intentResolverHelper(pn, cn, cln string, clustersWithName []ClusterWithName
 		}
 		// Populate the clustersWithName array with the clusternames found above
 		for _, eachClusterName := range clusterNamesList {
+			excluded, err := clusterExcluded(pn, eachClusterName, skipNotReady, skipCordoned)
+			if err != nil {
+				return []ClusterWithName{}, err
+			}
+			if excluded {
+				continue
+			}
 			eachClusterWithPN := ClusterWithName{pn, eachClusterName}
 			clustersWithName = append(clustersWithName, eachClusterWithPN)
 			log.Printf("Added Cluster :: %s through its label: %s ", eachClusterName, cln)
@@ -96,21 +147,43 @@ func intentResolverHelper(pn, cn, cln string, clustersWithName []ClusterWithName
 	return clustersWithName, nil
 }
 
+// warnCordoned logs a warning when a cluster named by an allOf intent is
+// cordoned. The cluster is required by the intent, and is kept.
+func warnCordoned(pn, cn string) error {
+	cordoned, err := cluster.NewClusterClient().IsClusterCordoned(pn, cn)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error getting cluster cordon")
+	}
+	if cordoned {
+		log.Printf("Warning: Cluster: %s is cordoned but required by the intent", cn)
+	}
+	return nil
+}
+
 // IntentResolver shall help to resolve the given intent into 2 lists of clusters where the app need to be deployed.
+// The cordoned clusters are left out of the anyOf groups and of the allOf
+// label matches. The clusters named by allOf are required, and are kept.
 func IntentResolver(intent IntentStruc) (ClusterList, error) {
 	var mc []ClusterWithName
 	var err error
 	var cg []ClusterGroup
 	index := 0
 	for _, eachAllOf := range intent.AllOfArray {
-		mc, err = intentResolverHelper(eachAllOf.ProviderName, eachAllOf.ClusterName, eachAllOf.ClusterLabelName, mc)
+		named := eachAllOf.ClusterLabelName == "" && eachAllOf.ClusterName != ""
+		if named {
+			err = warnCordoned(eachAllOf.ProviderName, eachAllOf.ClusterName)
+			if err != nil {
+				return ClusterList{}, pkgerrors.Wrap(err, "intentResolverHelper error")
+			}
+		}
+		mc, err = intentResolverHelper(eachAllOf.ProviderName, eachAllOf.ClusterName, eachAllOf.ClusterLabelName, intent.SkipNotReadyClusters, !named, mc)
 		if err != nil {
 			return ClusterList{}, pkgerrors.Wrap(err, "intentResolverHelper error")
 		}
 		if len(eachAllOf.AnyOfArray) > 0 {
 			for _, eachAnyOf := range eachAllOf.AnyOfArray {
 				var opc []ClusterWithName
-				opc, err = intentResolverHelper(eachAnyOf.ProviderName, eachAnyOf.ClusterName, eachAnyOf.ClusterLabelName, opc)
+				opc, err = intentResolverHelper(eachAnyOf.ProviderName, eachAnyOf.ClusterName, eachAnyOf.ClusterLabelName, intent.SkipNotReadyClusters, true, opc)
 				index++
 				if err != nil {
 					return ClusterList{}, pkgerrors.Wrap(err, "intentResolverHelper error")
@@ -123,7 +196,7 @@ func IntentResolver(intent IntentStruc) (ClusterList, error) {
 	if len(intent.AnyOfArray) > 0 {
 		var opc []ClusterWithName
 		for _, eachAnyOf := range intent.AnyOfArray {
-			opc, err = intentResolverHelper(eachAnyOf.ProviderName, eachAnyOf.ClusterName, eachAnyOf.ClusterLabelName, opc)
+			opc, err = intentResolverHelper(eachAnyOf.ProviderName, eachAnyOf.ClusterName, eachAnyOf.ClusterLabelName, intent.SkipNotReadyClusters, true, opc)
 			index++
 			if err != nil {
 				return ClusterList{}, pkgerrors.Wrap(err, "intentResolverHelper error")
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gpic
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+)
+
+func TestIntentResolverSkipNotReadyClusters(t *testing.T) {
+	// The mock database keys its items with the string of the key
+	key := func(c string) string {
+		return fmt.Sprintf("%v", cluster.ClusterKey{ClusterProviderName: "p1", ClusterName: c})
+	}
+	db.DBconn = &db.MockDB{
+		Items: map[string]map[string][]byte{
+			key("c1"): {"clusterstatus": []byte(`{"state":"Ready"}`)},
+			key("c2"): {"clusterstatus": []byte(`{"state":"NotReady"}`)},
+			key("c3"): {},
+		},
+	}
+
+	intent := IntentStruc{
+		AllOfArray: []AllOf{
+			{ProviderName: "p1", ClusterName: "c1"},
+			{ProviderName: "p1", ClusterName: "c2"},
+			{ProviderName: "p1", ClusterName: "c3"},
+		},
+	}
+
+	testCases := []struct {
+		label    string
+		skip     bool
+		expected []ClusterWithName
+	}{
+		{
+			label:    "Keep NotReady clusters",
+			expected: []ClusterWithName{{"p1", "c1"}, {"p1", "c2"}, {"p1", "c3"}},
+		},
+		{
+			label:    "Skip NotReady clusters",
+			skip:     true,
+			expected: []ClusterWithName{{"p1", "c1"}, {"p1", "c3"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			intent.SkipNotReadyClusters = testCase.skip
+			l, err := IntentResolver(intent)
+			if err != nil {
+				t.Fatalf("IntentResolver returned an error: %s", err)
+			}
+			if !reflect.DeepEqual(l.MandatoryClusters, testCase.expected) {
+				t.Errorf("Expected clusters %v, got %v", testCase.expected, l.MandatoryClusters)
+			}
+		})
+	}
+}
+
+func TestIntentResolverCordonedClusters(t *testing.T) {
+	key := func(c string) string {
+		return fmt.Sprintf("%v", cluster.ClusterKey{ClusterProviderName: "p1", ClusterName: c})
+	}
+	labelKey := fmt.Sprintf("%v", cluster.LabelKey{ClusterProviderName: "p1", ClusterLabelName: "edge"})
+	clusterLabelKey := fmt.Sprintf("%v", cluster.ClusterLabelKey{ClusterProviderName: "p1", ClusterName: "c1"})
+	db.DBconn = &db.MockDB{
+		Items: map[string]map[string][]byte{
+			key("c1"):       {"clustercordon": []byte(`{"cordoned":true}`)},
+			key("c2"):       {},
+			labelKey:        {"cluster": []byte("c1")},
+			clusterLabelKey: {"clustermetadata": []byte(`{"label-name":"edge"}`)},
+		},
+	}
+
+	intent := IntentStruc{
+		AllOfArray: []AllOf{
+			{ProviderName: "p1", ClusterName: "c1"},
+			{ProviderName: "p1", ClusterLabelName: "edge"},
+		},
+		AnyOfArray: []AnyOf{
+			{ProviderName: "p1", ClusterName: "c1"},
+			{ProviderName: "p1", ClusterName: "c2"},
+		},
+	}
+	l, err := IntentResolver(intent)
+	if err != nil {
+		t.Fatalf("IntentResolver returned an error: %s", err)
+	}
+
+	// A cordoned cluster named by allOf is kept, the one matched by the
+	// allOf label is left out
+	if !reflect.DeepEqual(l.MandatoryClusters, []ClusterWithName{{"p1", "c1"}}) {
+		t.Errorf("Expected the cordoned allOf cluster to be kept, got %v", l.MandatoryClusters)
+	}
+	for _, g := range l.ClusterGroups {
+		for _, c := range g.OptionalClusters {
+			if c.ClusterName == "c1" {
+				t.Errorf("Expected the cordoned cluster to be left out of the anyOf groups, got %v", l.ClusterGroups)
+			}
+		}
+	}
+}
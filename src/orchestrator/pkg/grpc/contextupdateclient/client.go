@@ -15,35 +15,44 @@ package contextupdateclient
 
 import (
 	"context"
-	"time"
 
 	contextpb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/grpc/contextupdate"
 	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/rpc"
 	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // InvokeContextUpdate will make the grpc call to the specified controller
 // The controller will take the specified intentName and update the AppContext
 // appropriatly based on its operation as a placement or action controller.
 func InvokeContextUpdate(controllerName, intentName, appContextId string) error {
+	return InvokeContextUpdateFrom(controllerName, intentName, "", appContextId)
+}
+
+// InvokeContextUpdateFrom makes the grpc call to update the AppContext of a
+// deployment intent group which replaces a previous AppContext. The controller
+// may use the previous AppContext to release what it no longer needs.
+func InvokeContextUpdateFrom(controllerName, intentName, oldAppContextId, appContextId string) error {
 	var err error
-	var rpcClient contextpb.ContextupdateClient
 	var updateRes *contextpb.ContextUpdateResponse
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
 
-	conn := rpc.GetRpcConn(controllerName)
+	if rpc.GetRpcConn(controllerName) == nil {
+		return pkgerrors.Errorf("ContextUpdate Failed - Could not get ContextupdateClient: %v", controllerName)
+	}
 
-	if conn != nil {
-		rpcClient = contextpb.NewContextupdateClient(conn)
+	err = rpc.InvokeWithRetry(controllerName, func(ctx context.Context, conn *grpc.ClientConn) error {
+		var err error
+		rpcClient := contextpb.NewContextupdateClient(conn)
 		updateReq := new(contextpb.ContextUpdateRequest)
 		updateReq.AppContext = appContextId
 		updateReq.IntentName = intentName
+		updateReq.UpdateFromAppContext = oldAppContextId
 		updateRes, err = rpcClient.UpdateAppContext(ctx, updateReq)
-	} else {
-		return pkgerrors.Errorf("ContextUpdate Failed - Could not get ContextupdateClient: %v", controllerName)
-	}
+		return err
+	})
 
 	if err == nil {
 		if updateRes.AppContextUpdated {
@@ -60,3 +69,47 @@ func InvokeContextUpdate(controllerName, intentName, appContextId string) error
 	}
 	return err
 }
+
+// InvokeContextTerminate will make the grpc call to the specified controller
+// to notify it that the deployment intent group of the AppContext has been
+// terminated, so that the controller can release what it holds for the intent.
+// Controllers which do not implement the call are skipped.
+func InvokeContextTerminate(controllerName, intentName, appContextId string) error {
+	var err error
+	var terminateRes *contextpb.TerminateResponse
+
+	if rpc.GetRpcConn(controllerName) == nil {
+		return pkgerrors.Errorf("ContextTerminate Failed - Could not get ContextupdateClient: %v", controllerName)
+	}
+
+	err = rpc.InvokeWithRetry(controllerName, func(ctx context.Context, conn *grpc.ClientConn) error {
+		var err error
+		rpcClient := contextpb.NewContextupdateClient(conn)
+		terminateReq := new(contextpb.TerminateRequest)
+		terminateReq.AppContext = appContextId
+		terminateReq.IntentName = intentName
+		terminateRes, err = rpcClient.TerminateAppContext(ctx, terminateReq)
+		return err
+	})
+	if status.Code(err) == codes.Unimplemented {
+		log.Info("ContextTerminate not implemented by controller", log.Fields{
+			"Controller": controllerName,
+			"AppContext": appContextId,
+		})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !terminateRes.AppContextTerminated {
+		return pkgerrors.Errorf("ContextTerminate Failed: %v", terminateRes.AppContextTerminateMessage)
+	}
+	log.Info("ContextTerminate Passed", log.Fields{
+		"Controller": controllerName,
+		"Intent":     intentName,
+		"AppContext": appContextId,
+		"Message":    terminateRes.AppContextTerminateMessage,
+	})
+	return nil
+}
@@ -32,11 +32,13 @@ import (
 // transport on a loopback ephemeral port.
 type stubContextupdateServer struct {
 	contextpb.UnimplementedContextupdateServer
-	updated bool
-	message string
+	updated    bool
+	message    string
+	updateFrom string
 }
 
 func (s *stubContextupdateServer) UpdateAppContext(ctx context.Context, req *contextpb.ContextUpdateRequest) (*contextpb.ContextUpdateResponse, error) {
+	s.updateFrom = req.UpdateFromAppContext
 	return &contextpb.ContextUpdateResponse{
 		AppContextUpdated:       s.updated,
 		AppContextUpdateMessage: s.message,
@@ -91,3 +93,63 @@ func TestInvokeContextUpdate_NoConnection(t *testing.T) {
 		t.Error("InvokeContextUpdate() = nil, want error when controller connection is missing")
 	}
 }
+
+// stubTerminateServer additionally implements TerminateAppContext
+type stubTerminateServer struct {
+	stubContextupdateServer
+	terminated bool
+}
+
+func (s *stubTerminateServer) TerminateAppContext(ctx context.Context, req *contextpb.TerminateRequest) (*contextpb.TerminateResponse, error) {
+	return &contextpb.TerminateResponse{
+		AppContextTerminated:       s.terminated,
+		AppContextTerminateMessage: s.message,
+	}, nil
+}
+
+func TestInvokeContextUpdateFrom_PassesPreviousContext(t *testing.T) {
+	srv := &stubContextupdateServer{updated: true, message: "applied"}
+	cleanup := startStubController(t, "ovnaction", srv)
+	defer cleanup()
+
+	if err := InvokeContextUpdateFrom("ovnaction", "intent-1", "appcontext-1", "appcontext-2"); err != nil {
+		t.Fatalf("InvokeContextUpdateFrom() = %v, want nil", err)
+	}
+	if srv.updateFrom != "appcontext-1" {
+		t.Errorf("UpdateFromAppContext = %q, want %q", srv.updateFrom, "appcontext-1")
+	}
+}
+
+func TestInvokeContextTerminate(t *testing.T) {
+	testCases := []struct {
+		label       string
+		srv         contextpb.ContextupdateServer
+		expectError bool
+	}{
+		{
+			label: "Terminated",
+			srv:   &stubTerminateServer{terminated: true},
+		},
+		{
+			label:       "Controller reports failure",
+			srv:         &stubTerminateServer{terminated: false, stubContextupdateServer: stubContextupdateServer{message: "busy"}},
+			expectError: true,
+		},
+		{
+			label: "Terminate not implemented",
+			srv:   &stubContextupdateServer{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			cleanup := startStubController(t, "ovnaction", testCase.srv)
+			defer cleanup()
+
+			err := InvokeContextTerminate("ovnaction", "intent-1", "appcontext-1")
+			if (err != nil) != testCase.expectError {
+				t.Errorf("InvokeContextTerminate() = %v, expectError %v", err, testCase.expectError)
+			}
+		})
+	}
+}
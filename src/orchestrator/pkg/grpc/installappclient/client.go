@@ -16,12 +16,12 @@ package installappclient
 import (
 	"context"
 	"sync"
-	"time"
 
 	installpb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/grpc/installapp"
 	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/rpc"
 	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/grpc"
 )
 
 const rsyncName = "rsync"
@@ -69,10 +69,7 @@ func NewRsyncInfo(rName, h string, pN int) RsyncInfo {
 // prepared in the app context.
 func InvokeInstallApp(appContextId string) error {
 	var err error
-	var rpcClient installpb.InstallappClient
 	var installRes *installpb.InstallAppResponse
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
 
 	conn := rpc.GetRpcConn(rsyncName)
 	if conn == nil {
@@ -81,10 +78,14 @@ func InvokeInstallApp(appContextId string) error {
 	}
 
 	if conn != nil {
-		rpcClient = installpb.NewInstallappClient(conn)
-		installReq := new(installpb.InstallAppRequest)
-		installReq.AppContext = appContextId
-		installRes, err = rpcClient.InstallApp(ctx, installReq)
+		err = rpc.InvokeWithRetry(rsyncName, func(ctx context.Context, conn *grpc.ClientConn) error {
+			var err error
+			rpcClient := installpb.NewInstallappClient(conn)
+			installReq := new(installpb.InstallAppRequest)
+			installReq.AppContext = appContextId
+			installRes, err = rpcClient.InstallApp(ctx, installReq)
+			return err
+		})
 		if err == nil {
 			log.Info("Response from InstappApp GRPC call", log.Fields{
 				"Succeeded": installRes.AppContextInstalled,
@@ -111,18 +112,19 @@ func InvokeInstallApp(appContextId string) error {
 
 func InvokeUninstallApp(appContextId string) error {
 	var err error
-	var rpcClient installpb.InstallappClient
 	var uninstallRes *installpb.UninstallAppResponse
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
 
-	conn := rpc.GetRpcConn("rsync")
+	conn := rpc.GetRpcConn(rsyncName)
 
 	if conn != nil {
-		rpcClient = installpb.NewInstallappClient(conn)
-		uninstallReq := new(installpb.UninstallAppRequest)
-		uninstallReq.AppContext = appContextId
-		uninstallRes, err = rpcClient.UninstallApp(ctx, uninstallReq)
+		err = rpc.InvokeWithRetry(rsyncName, func(ctx context.Context, conn *grpc.ClientConn) error {
+			var err error
+			rpcClient := installpb.NewInstallappClient(conn)
+			uninstallReq := new(installpb.UninstallAppRequest)
+			uninstallReq.AppContext = appContextId
+			uninstallRes, err = rpcClient.UninstallApp(ctx, uninstallReq)
+			return err
+		})
 		if err == nil {
 			log.Info("Response from UninstappApp GRPC call", log.Fields{
 				"Succeeded": uninstallRes.AppContextUninstalled,
@@ -0,0 +1,247 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Entry records a single mutating request handled by an EMCO service
+type Entry struct {
+	Id          string            `json:"id"`
+	Service     string            `json:"service"`
+	Project     string            `json:"project,omitempty"`
+	Principal   string            `json:"principal"`
+	Method      string            `json:"method"`
+	Path        string            `json:"path"`
+	PathVars    map[string]string `json:"path-vars,omitempty"`
+	ResourceKey string            `json:"resource-key"`
+	Action      string            `json:"action"`
+	ResultCode  int               `json:"result-code"`
+	BodyDigest  string            `json:"body-digest,omitempty"`
+	BodySize    int64             `json:"body-size,omitempty"`
+	TimeStamp   time.Time         `json:"time"`
+}
+
+// EntryKey is the key structure that is used in the database
+type EntryKey struct {
+	Project string `json:"project"`
+	EntryId string `json:"auditid"`
+}
+
+// We will use json marshalling to convert to string to
+// preserve the underlying structure.
+func (k EntryKey) String() string {
+	out, err := json.Marshal(k)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// AuditManager is an interface exposing the audit log functionality
+type AuditManager interface {
+	Record(e Entry) error
+	GetEntries(project, service string, from, to time.Time) ([]Entry, error)
+	Prune(before time.Time) (int, error)
+}
+
+// AuditClient implements the AuditManager
+type AuditClient struct {
+	storeName string
+	tagEntry  string
+}
+
+// NewAuditClient returns an instance of the AuditClient
+func NewAuditClient() *AuditClient {
+	return &AuditClient{
+		storeName: "audit",
+		tagEntry:  "auditentry",
+	}
+}
+
+// newEntryId returns an id which sorts by creation time
+func newEntryId(t time.Time) string {
+	return fmt.Sprintf("%019d-%08x", t.UnixNano(), rand.Uint32())
+}
+
+// entryIdRange returns the range of the ids of the entries created between
+// from and to. A zero from or to leaves that end of the range open.
+func entryIdRange(from, to time.Time) db.Range {
+	r := db.Range{}
+	if !from.IsZero() {
+		r.Min = fmt.Sprintf("%019d", from.UnixNano())
+	}
+	if !to.IsZero() {
+		r.Max = fmt.Sprintf("%019d-ffffffff", to.UnixNano())
+	}
+	return r
+}
+
+// entryQuery holds the fields of the entries the entries are filtered by
+type entryQuery struct {
+	Service string `json:"service"`
+}
+
+// pruneBatch is the number of the entries removed at a time
+const pruneBatch = 1000
+
+// Record stores an audit entry in the database
+func (c *AuditClient) Record(e Entry) error {
+	if e.TimeStamp.IsZero() {
+		e.TimeStamp = time.Now()
+	}
+	if e.Id == "" {
+		e.Id = newEntryId(e.TimeStamp)
+	}
+
+	key := EntryKey{
+		Project: e.Project,
+		EntryId: e.Id,
+	}
+
+	err := db.DBconn.Insert(c.storeName, key, entryQuery{Service: e.Service}, c.tagEntry, e)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Creating audit DB Entry")
+	}
+	return nil
+}
+
+// find returns the audit entries of the project matching the options,
+// oldest first. An empty project matches every entry.
+func (c *AuditClient) find(project string, opts db.FindOptions) ([]Entry, error) {
+	key := EntryKey{
+		Project: project,
+		EntryId: "",
+	}
+
+	opts.Sort = []db.SortField{{Field: "auditid"}}
+	values, err := db.DBconn.FindWithOptions(c.storeName, key, c.tagEntry, opts)
+	if err != nil {
+		return []Entry{}, pkgerrors.Wrap(err, "Get audit entries")
+	}
+
+	resp := []Entry{}
+	for _, value := range values {
+		e := Entry{}
+		err = db.DBconn.Unmarshal(value, &e)
+		if err != nil {
+			return []Entry{}, pkgerrors.Wrap(err, "Unmarshalling audit entry")
+		}
+		resp = append(resp, e)
+	}
+	return resp, nil
+}
+
+// GetEntries returns the audit entries recorded between from and to,
+// oldest first. Empty project and service match every entry and a zero
+// from or to leaves that end of the time range open.
+func (c *AuditClient) GetEntries(project, service string, from, to time.Time) ([]Entry, error) {
+	opts := db.FindOptions{
+		Ranges: map[string]db.Range{"auditid": entryIdRange(from, to)},
+	}
+	if service != "" {
+		opts.Filter = map[string][]string{"service": {service}}
+	}
+	return c.find(project, opts)
+}
+
+// Prune removes the audit entries recorded before the given time and
+// returns the number of entries removed
+func (c *AuditClient) Prune(before time.Time) (int, error) {
+	// The entries recorded at before are kept
+	r := db.Range{Max: fmt.Sprintf("%019d", before.UnixNano())}
+
+	removed := 0
+	for {
+		entries, err := c.find("", db.FindOptions{
+			Ranges: map[string]db.Range{"auditid": r},
+			Limit:  pruneBatch,
+		})
+		if err != nil {
+			return removed, err
+		}
+		for _, e := range entries {
+			key := EntryKey{
+				Project: e.Project,
+				EntryId: e.Id,
+			}
+			err = db.DBconn.Remove(c.storeName, key)
+			if err != nil {
+				return removed, pkgerrors.Wrap(err, "Delete audit Entry")
+			}
+			removed++
+		}
+		if len(entries) < pruneBatch {
+			return removed, nil
+		}
+	}
+}
+
+// GetRetention returns the configured audit retention period.
+// A zero duration means entries are kept forever.
+func GetRetention() (time.Duration, error) {
+	r := config.GetConfiguration().AuditRetention
+	if r == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(r)
+	if err != nil {
+		return 0, pkgerrors.Wrap(err, "Invalid audit retention "+r)
+	}
+	if d < 0 {
+		return 0, pkgerrors.New("Invalid audit retention " + r)
+	}
+	return d, nil
+}
+
+// StartRetention periodically removes the audit entries which are older
+// than the configured retention period. It returns immediately if
+// retention is disabled.
+func StartRetention(interval time.Duration) {
+	retention, err := GetRetention()
+	if err != nil {
+		log.Error("Audit retention disabled", log.Fields{"Error": err})
+		return
+	}
+	if retention == 0 {
+		log.Info("Audit retention disabled, keeping all entries", log.Fields{})
+		return
+	}
+
+	c := NewAuditClient()
+	go func() {
+		for {
+			n, err := c.Prune(time.Now().Add(-retention))
+			if err != nil {
+				log.Error("Error pruning audit entries", log.Fields{"Error": err})
+			} else if n > 0 {
+				log.Info("Pruned audit entries", log.Fields{"Removed": n})
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntryIdRange(t *testing.T) {
+	from := time.Unix(1000, 0)
+	to := time.Unix(2000, 0)
+	r := entryIdRange(from, to)
+
+	testCases := []struct {
+		label    string
+		time     time.Time
+		expected bool
+	}{
+		{label: "Before the range", time: from.Add(-time.Nanosecond), expected: false},
+		{label: "At the start of the range", time: from, expected: true},
+		{label: "Within the range", time: from.Add(time.Second), expected: true},
+		{label: "At the end of the range", time: to, expected: true},
+		{label: "After the range", time: to.Add(time.Nanosecond), expected: false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			id := newEntryId(testCase.time)
+			if got := id >= r.Min && id <= r.Max; got != testCase.expected {
+				t.Errorf("Entry id %s in range %v is %v, expected %v", id, r, got, testCase.expected)
+			}
+		})
+	}
+
+	if open := entryIdRange(time.Time{}, time.Time{}); open.Min != "" || open.Max != "" {
+		t.Errorf("Expected an open range, got %v", open)
+	}
+}
@@ -0,0 +1,315 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+)
+
+// Lifecycle operations which are invoked by a POST on a sub-resource
+// of the object they operate on, e.g. .../deployment-intent-groups/{name}/approve
+var lifecycleActions = map[string]bool{
+	"approve":     true,
+	"reject":      true,
+	"instantiate": true,
+	"terminate":   true,
+	"apply":       true,
+	"update":      true,
+}
+
+// Path variables which carry the project name in the various services
+var projectVars = []string{"project-name", "project"}
+
+// AnonymousPrincipal is the principal of the callers which are not identified
+const AnonymousPrincipal = "anonymous"
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// maxBodyHead is the size of the start of the body kept to find the name of
+// the created resource. The rest of the body is only hashed.
+const maxBodyHead = 1 << 20
+
+// bodyRecorder hashes the request body as the handler reads it
+type bodyRecorder struct {
+	io.ReadCloser
+	hash hash.Hash
+	size int64
+	head bytes.Buffer
+}
+
+func newBodyRecorder(body io.ReadCloser) *bodyRecorder {
+	return &bodyRecorder{ReadCloser: body, hash: sha256.New()}
+}
+
+func (b *bodyRecorder) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	b.size += int64(n)
+	if room := maxBodyHead - b.head.Len(); room > 0 {
+		if room > n {
+			room = n
+		}
+		b.head.Write(p[:room])
+	}
+	return n, err
+}
+
+// digest returns the digest of the whole body. The part the handler left
+// unread is read and hashed first.
+func (b *bodyRecorder) digest() string {
+	io.Copy(ioutil.Discard, b)
+	if b.size == 0 {
+		return ""
+	}
+	return "sha256:" + hex.EncodeToString(b.hash.Sum(nil))
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Middleware returns a mux middleware which records every mutating request
+// served by the router in the audit log, tagged with the service name.
+func Middleware(service string) mux.MiddlewareFunc {
+	return MiddlewareWithManager(service, NewAuditClient())
+}
+
+// MiddlewareWithManager is Middleware with a caller supplied AuditManager
+func MiddlewareWithManager(service string, m AuditManager) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isMutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Body == nil {
+				r.Body = http.NoBody
+			}
+			// The body is hashed while the handler reads it, so that it is
+			// never held in memory
+			body := newBodyRecorder(r.Body)
+			r.Body = body
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			e := newEntry(service, r, body, rec.status)
+			err := m.Record(e)
+			if err != nil {
+				log.Error("Error recording audit entry", log.Fields{
+					"Error":    err,
+					"Service":  service,
+					"Path":     e.Path,
+					"Action":   e.Action,
+					"Resource": e.ResourceKey,
+				})
+			}
+		})
+	}
+}
+
+func newEntry(service string, r *http.Request, body *bodyRecorder, status int) Entry {
+	vars := mux.Vars(r)
+	project := ""
+	for _, pv := range projectVars {
+		if p, ok := vars[pv]; ok {
+			project = p
+			break
+		}
+	}
+
+	digest := body.digest()
+	action, resource := actionAndResource(r, body.head.Bytes())
+	e := Entry{
+		Service:     service,
+		Project:     project,
+		Principal:   GetPrincipal(r),
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		PathVars:    vars,
+		ResourceKey: resource,
+		Action:      action,
+		ResultCode:  status,
+		BodyDigest:  digest,
+		BodySize:    body.size,
+		TimeStamp:   time.Now(),
+	}
+	return e
+}
+
+// UnverifiedPrefix labels the principals which are not verified, such as
+// the username of basic auth, whose password is not checked
+const UnverifiedPrefix = "unverified:"
+
+// GetVerifiedPrincipal identifies the caller from a verified identity: the
+// verified TLS client certificate takes precedence over the identity header
+// set by a trusted authenticating proxy. It returns false when the caller is
+// not verified.
+func GetVerifiedPrincipal(r *http.Request) (string, bool) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		if cn := r.TLS.VerifiedChains[0][0].Subject.CommonName; cn != "" {
+			return cn, true
+		}
+	}
+	if h := config.GetConfiguration().AuditUserHeader; h != "" && fromTrustedProxy(r) {
+		if u := r.Header.Get(h); u != "" {
+			return u, true
+		}
+	}
+	return "", false
+}
+
+// GetPrincipal identifies the caller. The verified identity takes precedence
+// over basic auth, whose username is labelled as unverified.
+func GetPrincipal(r *http.Request) string {
+	if u, ok := GetVerifiedPrincipal(r); ok {
+		return u
+	}
+	if u, _, ok := r.BasicAuth(); ok && u != "" {
+		return UnverifiedPrefix + u
+	}
+	return AnonymousPrincipal
+}
+
+// GetRoles returns the roles of the caller, which a trusted authenticating
+// proxy sets as a comma separated list in the roles header
+func GetRoles(r *http.Request) []string {
+	var roles []string
+	h := config.GetConfiguration().AuditRolesHeader
+	if h == "" || !fromTrustedProxy(r) {
+		return roles
+	}
+	for _, role := range strings.Split(r.Header.Get(h), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// fromTrustedProxy reports whether the request comes from one of the
+// addresses or networks of the comma separated audit-trusted-proxies. The
+// identity headers can be set by any caller, and are only trusted from the
+// authenticating proxies, none by default.
+func fromTrustedProxy(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range strings.Split(config.GetConfiguration().AuditTrustedProxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if strings.Contains(proxy, "/") {
+			_, n, err := net.ParseCIDR(proxy)
+			if err == nil && n.Contains(ip) {
+				return true
+			}
+		} else if p := net.ParseIP(proxy); p != nil && p.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// actionAndResource derives the action and the key of the resource it
+// was applied to from the request method and path.
+func actionAndResource(r *http.Request, body []byte) (string, string) {
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v2"), "/")
+	idx := strings.LastIndex(path, "/")
+	last := path[idx+1:]
+
+	switch r.Method {
+	case http.MethodPut, http.MethodPatch:
+		return "update", path
+	case http.MethodDelete:
+		return "delete", path
+	}
+
+	if lifecycleActions[last] {
+		return last, path[:idx]
+	}
+	if name := bodyName(r, body); name != "" {
+		return "create", path + "/" + name
+	}
+	return "create", path
+}
+
+// bodyName returns metadata.name from a JSON body or from the metadata
+// part of a multipart body
+func bodyName(r *http.Request, body []byte) string {
+	var obj struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if err != nil {
+				return ""
+			}
+			if p.FormName() == "metadata" {
+				err = json.NewDecoder(p).Decode(&obj)
+				if err != nil {
+					return ""
+				}
+				return obj.Metadata.Name
+			}
+		}
+	}
+
+	if json.Unmarshal(body, &obj) != nil {
+		return ""
+	}
+	return obj.Metadata.Name
+}
@@ -0,0 +1,270 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
+)
+
+type mockAuditManager struct {
+	Items []Entry
+}
+
+func (m *mockAuditManager) Record(e Entry) error {
+	m.Items = append(m.Items, e)
+	return nil
+}
+
+func (m *mockAuditManager) GetEntries(project, service string, from, to time.Time) ([]Entry, error) {
+	return m.Items, nil
+}
+
+func (m *mockAuditManager) Prune(before time.Time) (int, error) {
+	return 0, nil
+}
+
+func newTestRouter(m AuditManager) *mux.Router {
+	router := mux.NewRouter().PathPrefix("/v2").Subrouter()
+	dig := "/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups"
+	handler := func(code int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			// handlers must still be able to read the body
+			ioutil.ReadAll(r.Body)
+			w.WriteHeader(code)
+		}
+	}
+	router.HandleFunc(dig, handler(http.StatusCreated)).Methods("POST")
+	router.HandleFunc(dig+"/{deployment-intent-group-name}", handler(http.StatusOK)).Methods("GET")
+	router.HandleFunc(dig+"/{deployment-intent-group-name}", handler(http.StatusNoContent)).Methods("DELETE")
+	router.HandleFunc(dig+"/{deployment-intent-group-name}/approve", handler(http.StatusAccepted)).Methods("POST")
+	router.HandleFunc("/cluster-providers/{provider-name}/clusters/{name}", handler(http.StatusConflict)).Methods("PUT")
+	router.Use(MiddlewareWithManager("test", m))
+	return router
+}
+
+func TestMiddleware(t *testing.T) {
+	// httptest requests come from 192.0.2.1
+	config.GetConfiguration().AuditTrustedProxies = "127.0.0.1, 192.0.2.0/24"
+	defer func() { config.GetConfiguration().AuditTrustedProxies = "" }()
+
+	digPath := "/v2/projects/p1/composite-apps/ca/v1/deployment-intent-groups"
+	testCases := []struct {
+		label    string
+		method   string
+		path     string
+		body     string
+		header   map[string]string
+		remote   string
+		basic    string
+		recorded bool
+		expected Entry
+	}{
+		{
+			label:    "Reads are not audited",
+			method:   "GET",
+			path:     digPath + "/dig1",
+			recorded: false,
+		},
+		{
+			label:    "Create with metadata name",
+			method:   "POST",
+			path:     digPath,
+			body:     `{"metadata":{"name":"dig1"}}`,
+			header:   map[string]string{"X-Auth-Request-User": "alice"},
+			recorded: true,
+			expected: Entry{
+				Project:     "p1",
+				Principal:   "alice",
+				Action:      "create",
+				ResourceKey: "/projects/p1/composite-apps/ca/v1/deployment-intent-groups/dig1",
+				ResultCode:  http.StatusCreated,
+			},
+		},
+		{
+			label:    "Identity header from an untrusted address",
+			method:   "POST",
+			path:     digPath,
+			body:     `{"metadata":{"name":"dig1"}}`,
+			header:   map[string]string{"X-Auth-Request-User": "alice"},
+			remote:   "198.51.100.7:1234",
+			recorded: true,
+			expected: Entry{
+				Project:     "p1",
+				Principal:   AnonymousPrincipal,
+				Action:      "create",
+				ResourceKey: "/projects/p1/composite-apps/ca/v1/deployment-intent-groups/dig1",
+				ResultCode:  http.StatusCreated,
+			},
+		},
+		{
+			label:    "Basic auth is not verified",
+			method:   "POST",
+			path:     digPath,
+			body:     `{"metadata":{"name":"dig1"}}`,
+			basic:    "bob",
+			remote:   "198.51.100.7:1234",
+			recorded: true,
+			expected: Entry{
+				Project:     "p1",
+				Principal:   UnverifiedPrefix + "bob",
+				Action:      "create",
+				ResourceKey: "/projects/p1/composite-apps/ca/v1/deployment-intent-groups/dig1",
+				ResultCode:  http.StatusCreated,
+			},
+		},
+		{
+			label:    "Lifecycle action",
+			method:   "POST",
+			path:     digPath + "/dig1/approve",
+			recorded: true,
+			expected: Entry{
+				Project:     "p1",
+				Principal:   AnonymousPrincipal,
+				Action:      "approve",
+				ResourceKey: "/projects/p1/composite-apps/ca/v1/deployment-intent-groups/dig1",
+				ResultCode:  http.StatusAccepted,
+			},
+		},
+		{
+			label:    "Delete",
+			method:   "DELETE",
+			path:     digPath + "/dig1",
+			recorded: true,
+			expected: Entry{
+				Project:     "p1",
+				Principal:   AnonymousPrincipal,
+				Action:      "delete",
+				ResourceKey: "/projects/p1/composite-apps/ca/v1/deployment-intent-groups/dig1",
+				ResultCode:  http.StatusNoContent,
+			},
+		},
+		{
+			label:    "Failed update without project",
+			method:   "PUT",
+			path:     "/v2/cluster-providers/cp1/clusters/c1",
+			body:     `{"metadata":{"name":"c1"}}`,
+			recorded: true,
+			expected: Entry{
+				Principal:   AnonymousPrincipal,
+				Action:      "update",
+				ResourceKey: "/cluster-providers/cp1/clusters/c1",
+				ResultCode:  http.StatusConflict,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			m := &mockAuditManager{}
+			request := httptest.NewRequest(testCase.method, testCase.path, bytes.NewBufferString(testCase.body))
+			for k, v := range testCase.header {
+				request.Header.Set(k, v)
+			}
+			if testCase.remote != "" {
+				request.RemoteAddr = testCase.remote
+			}
+			if testCase.basic != "" {
+				request.SetBasicAuth(testCase.basic, "secret")
+			}
+			recorder := httptest.NewRecorder()
+			newTestRouter(m).ServeHTTP(recorder, request)
+
+			if !testCase.recorded {
+				if len(m.Items) != 0 {
+					t.Fatalf("Expected no audit entry, got %v", m.Items)
+				}
+				return
+			}
+			if len(m.Items) != 1 {
+				t.Fatalf("Expected one audit entry, got %d", len(m.Items))
+			}
+			got := m.Items[0]
+			if got.Service != "test" || got.Method != testCase.method || got.Path != testCase.path {
+				t.Errorf("Unexpected request details %v", got)
+			}
+			if got.Project != testCase.expected.Project ||
+				got.Principal != testCase.expected.Principal ||
+				got.Action != testCase.expected.Action ||
+				got.ResourceKey != testCase.expected.ResourceKey ||
+				got.ResultCode != testCase.expected.ResultCode {
+				t.Errorf("Expected %+v\n got %+v", testCase.expected, got)
+			}
+			if testCase.body != "" && !strings.HasPrefix(got.BodyDigest, "sha256:") {
+				t.Errorf("Expected a body digest, got %q", got.BodyDigest)
+			}
+			if got.BodySize != int64(len(testCase.body)) {
+				t.Errorf("Expected a body size of %d, got %d", len(testCase.body), got.BodySize)
+			}
+			if testCase.body == "" && got.BodyDigest != "" {
+				t.Errorf("Expected no body digest, got %q", got.BodyDigest)
+			}
+		})
+	}
+}
+
+func TestMultipartBodyName(t *testing.T) {
+	body := "--xyz\r\n" +
+		"Content-Disposition: form-data; name=\"metadata\"\r\n\r\n" +
+		`{"metadata":{"name":"cluster1"}}` + "\r\n" +
+		"--xyz\r\n" +
+		"Content-Disposition: form-data; name=\"file\"; filename=\"kubeconfig\"\r\n\r\n" +
+		"content\r\n" +
+		"--xyz--\r\n"
+	request := httptest.NewRequest("POST", "/v2/cluster-providers/cp1/clusters", strings.NewReader(body))
+	request.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+
+	action, resource := actionAndResource(request, []byte(body))
+	if action != "create" || resource != "/cluster-providers/cp1/clusters/cluster1" {
+		t.Fatalf("Unexpected action %q resource %q", action, resource)
+	}
+}
+
+func TestBodyDigest(t *testing.T) {
+	body := strings.Repeat("x", maxBodyHead+10)
+	m := &mockAuditManager{}
+	router := mux.NewRouter()
+	// the handler leaves the body unread
+	router.HandleFunc("/v2/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}).Methods("POST")
+	router.Use(MiddlewareWithManager("test", m))
+
+	request := httptest.NewRequest("POST", "/v2/projects", strings.NewReader(body))
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	if len(m.Items) != 1 {
+		t.Fatalf("Expected one audit entry, got %d", len(m.Items))
+	}
+	sum := sha256.Sum256([]byte(body))
+	if expected := "sha256:" + hex.EncodeToString(sum[:]); m.Items[0].BodyDigest != expected {
+		t.Errorf("Expected the digest %s of the whole body, got %s", expected, m.Items[0].BodyDigest)
+	}
+	if m.Items[0].BodySize != int64(len(body)) {
+		t.Errorf("Expected a body size of %d, got %d", len(body), m.Items[0].BodySize)
+	}
+}
@@ -20,31 +20,44 @@ import (
 	"encoding/json"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
 )
 
 // Configuration loads up all the values that are used to configure
 // backend implementations
 type Configuration struct {
-	CAFile                 string `json:"ca-file"`
-	ServerCert             string `json:"server-cert"`
-	ServerKey              string `json:"server-key"`
-	Password               string `json:"password"`
-	DatabaseIP             string `json:"database-ip"`
-	DatabaseType           string `json:"database-type"`
-	PluginDir              string `json:"plugin-dir"`
-	EtcdIP                 string `json:"etcd-ip"`
-	EtcdCert               string `json:"etcd-cert"`
-	EtcdKey                string `json:"etcd-key"`
-	EtcdCAFile             string `json:"etcd-ca-file"`
-	GrpcServerCert         string `json:"grpc-server-cert"`
-	GrpcServerKey          string `json:"grpc-server-key"`
-	GrpcCAFile             string `json:"grpc-ca-file"`
-	GrpcEnableTLS          string `json:"grpc-enable-tls"`
-	GrpcServerNameOverride string `json:"grpc-server-name-override"`
-	ServicePort            string `json:"service-port"`
-	KubernetesLabelName    string `json:"kubernetes-label-name"`
-	LogLevel               string `json:"log-level"`
+	CAFile                   string `json:"ca-file"`
+	ServerCert               string `json:"server-cert"`
+	ServerKey                string `json:"server-key"`
+	Password                 string `json:"password"`
+	DatabaseIP               string `json:"database-ip"`
+	DatabaseType             string `json:"database-type"`
+	PluginDir                string `json:"plugin-dir"`
+	EtcdIP                   string `json:"etcd-ip"`
+	EtcdCert                 string `json:"etcd-cert"`
+	EtcdKey                  string `json:"etcd-key"`
+	EtcdCAFile               string `json:"etcd-ca-file"`
+	GrpcServerCert           string `json:"grpc-server-cert"`
+	GrpcServerKey            string `json:"grpc-server-key"`
+	GrpcCAFile               string `json:"grpc-ca-file"`
+	GrpcEnableTLS            string `json:"grpc-enable-tls"`
+	GrpcServerNameOverride   string `json:"grpc-server-name-override"`
+	ServicePort              string `json:"service-port"`
+	KubernetesLabelName      string `json:"kubernetes-label-name"`
+	LogLevel                 string `json:"log-level"`
+	AuditUserHeader          string `json:"audit-user-header"`
+	AuditTrustedProxies      string `json:"audit-trusted-proxies"`
+	AuditRolesHeader         string `json:"audit-roles-header"`
+	AuditRetention           string `json:"audit-retention"`
+	ControllerHealthInterval string `json:"controller-health-interval"`
+	ClusterHealthInterval    string `json:"cluster-health-interval"`
+	ClusterHealthWorkers     string `json:"cluster-health-workers"`
+	SecretKeyProvider        string `json:"secret-key-provider"`
+	SecretKeyFile            string `json:"secret-key-file"`
+	ChartCacheDir            string `json:"chart-cache-dir"`
+	ChartTokenHosts          string `json:"chart-token-hosts"`
+	MetricsPort              string `json:"metrics-port"`
 }
 
 // Config is the structure that stores the configuration
@@ -81,26 +94,37 @@ func defaultConfiguration() *Configuration {
 	}
 
 	return &Configuration{
-		CAFile:                 "ca.cert",
-		ServerCert:             "server.cert",
-		ServerKey:              "server.key",
-		Password:               "",
-		DatabaseIP:             "127.0.0.1",
-		DatabaseType:           "mongo",
-		PluginDir:              cwd,
-		EtcdIP:                 "127.0.0.1",
-		EtcdCert:               "",
-		EtcdKey:                "",
-		EtcdCAFile:             "",
-		GrpcServerCert:         "",
-		GrpcServerKey:          "",
-		GrpcCAFile:             "",
-		GrpcEnableTLS:          "disable",
-		GrpcServerNameOverride: "",
-		ServicePort:            "9015",
-		KubernetesLabelName:    "orchestrator.io/rb-instance-id",
-		LogLevel:               "warn",
-
+		CAFile:                   "ca.cert",
+		ServerCert:               "server.cert",
+		ServerKey:                "server.key",
+		Password:                 "",
+		DatabaseIP:               "127.0.0.1",
+		DatabaseType:             "mongo",
+		PluginDir:                cwd,
+		EtcdIP:                   "127.0.0.1",
+		EtcdCert:                 "",
+		EtcdKey:                  "",
+		EtcdCAFile:               "",
+		GrpcServerCert:           "",
+		GrpcServerKey:            "",
+		GrpcCAFile:               "",
+		GrpcEnableTLS:            "disable",
+		GrpcServerNameOverride:   "",
+		ServicePort:              "9015",
+		KubernetesLabelName:      "orchestrator.io/rb-instance-id",
+		LogLevel:                 "warn",
+		AuditUserHeader:          "X-Auth-Request-User",
+		AuditTrustedProxies:      "",
+		AuditRolesHeader:         "X-Auth-Request-Groups",
+		AuditRetention:           "2160h",
+		ControllerHealthInterval: "30s",
+		ClusterHealthInterval:    "60s",
+		ClusterHealthWorkers:     "10",
+		SecretKeyProvider:        "",
+		SecretKeyFile:            "secret-keys.json",
+		ChartCacheDir:            filepath.Join(os.TempDir(), "emco-chart-cache"),
+		ChartTokenHosts:          "",
+		MetricsPort:              "9090",
 	}
 }
 
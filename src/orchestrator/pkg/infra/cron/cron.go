@@ -0,0 +1,192 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cron parses the five field cron expressions of the schedules
+// and computes their next occurrence
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Expression is a parsed cron expression of the form
+// "minute hour day-of-month month day-of-week". Each field is a comma
+// separated list of *, a value or a range a-b, optionally with a step /n.
+// Day of week 0 and 7 are Sunday. As in cron, a day matches when either
+// the day of month or the day of week matches if both are restricted.
+type Expression struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+type field struct {
+	min, max int
+}
+
+var (
+	minuteField = field{0, 59}
+	hourField   = field{0, 23}
+	domField    = field{1, 31}
+	monthField  = field{1, 12}
+	dowField    = field{0, 7}
+)
+
+// searchLimit bounds the search of the next occurrence, for expressions
+// like "0 0 30 2 *" that never match
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// Parse parses a five field cron expression
+func Parse(expr string) (*Expression, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, pkgerrors.Errorf("Cron expression %q does not have 5 fields", expr)
+	}
+
+	var e Expression
+	var err error
+	if e.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if e.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if e.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if e.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if e.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// Sunday is both 0 and 7
+	if e.dow&(1<<7) != 0 {
+		e.dow |= 1
+	}
+	e.domStar = strings.HasPrefix(fields[2], "*")
+	e.dowStar = strings.HasPrefix(fields[4], "*")
+	return &e, nil
+}
+
+// parse returns the bit set of the values of the field expression
+func (f field) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s < 1 {
+				return 0, pkgerrors.Errorf("Invalid step in cron field %q", expr)
+			}
+			rng, step = part[:i], s
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, pkgerrors.Errorf("Invalid range in cron field %q", expr)
+			}
+		default:
+			v, err := f.value(rng)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// A single value with a step, like 5/15, runs up to the maximum
+			hi = v
+			if step > 1 {
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (f field) value(s string) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, pkgerrors.Errorf("Invalid cron value %q, expected %d-%d", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (e *Expression) dayMatches(t time.Time) bool {
+	dom := e.dom&(1<<uint(t.Day())) != 0
+	dow := e.dow&(1<<uint(t.Weekday())) != 0
+	if e.domStar || e.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first occurrence of the expression strictly after t, in
+// the location of t. It returns the zero time when there is none.
+func (e *Expression) Next(t time.Time) time.Time {
+	loc := t.Location()
+	limit := t.Add(searchLimit)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		if e.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !e.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if e.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if e.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Prev returns the last occurrence of the expression at or before t, in the
+// location of t, searching back at most the given duration. It returns the
+// zero time when there is none.
+func (e *Expression) Prev(t time.Time, within time.Duration) time.Time {
+	start := t.Add(-within).Add(-time.Minute)
+	var last time.Time
+	for n := e.Next(start); !n.IsZero() && !n.After(t); n = e.Next(n) {
+		last = n
+	}
+	return last
+}
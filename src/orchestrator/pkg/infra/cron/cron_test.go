@@ -0,0 +1,90 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// Monday
+	from := time.Date(2020, 6, 1, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{expr: "* * * * *", expected: time.Date(2020, 6, 1, 10, 31, 0, 0, time.UTC)},
+		{expr: "0 2 * * *", expected: time.Date(2020, 6, 2, 2, 0, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", expected: time.Date(2020, 6, 1, 10, 45, 0, 0, time.UTC)},
+		{expr: "0 22-23 * * 1-5", expected: time.Date(2020, 6, 1, 22, 0, 0, 0, time.UTC)},
+		{expr: "30 1 * * 0", expected: time.Date(2020, 6, 7, 1, 30, 0, 0, time.UTC)},
+		{expr: "30 1 * * 7", expected: time.Date(2020, 6, 7, 1, 30, 0, 0, time.UTC)},
+		{expr: "0 0 1 1 *", expected: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 15 * 6", expected: time.Date(2020, 6, 6, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", expected: time.Time{}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expr, func(t *testing.T) {
+			e, err := Parse(testCase.expr)
+			if err != nil {
+				t.Fatalf("Parse returned an unexpected error %s", err)
+			}
+			got := e.Next(from)
+			if !got.Equal(testCase.expected) {
+				t.Errorf("Next returned %v, expected %v", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestNextInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+9", 9*60*60)
+	e, err := Parse("0 2 * * *")
+	if err != nil {
+		t.Fatalf("Parse returned an unexpected error %s", err)
+	}
+	got := e.Next(time.Date(2020, 6, 1, 10, 30, 0, 0, time.UTC).In(loc))
+	expected := time.Date(2020, 6, 1, 17, 0, 0, 0, time.UTC)
+	if !got.Equal(expected) {
+		t.Errorf("Next returned %v, expected %v", got, expected)
+	}
+}
+
+func TestPrev(t *testing.T) {
+	e, err := Parse("0 22 * * *")
+	if err != nil {
+		t.Fatalf("Parse returned an unexpected error %s", err)
+	}
+	at := time.Date(2020, 6, 2, 1, 0, 0, 0, time.UTC)
+	if got := e.Prev(at, 4*time.Hour); !got.Equal(time.Date(2020, 6, 1, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("Prev returned %v", got)
+	}
+	if got := e.Prev(at, 2*time.Hour); !got.IsZero() {
+		t.Errorf("Prev returned %v, expected none", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse of %q expected an error", expr)
+		}
+	}
+}
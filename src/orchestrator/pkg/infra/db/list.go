@@ -0,0 +1,178 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// FindOptions filters, sorts and pages the documents returned by
+// FindWithOptions. The fields are the fields of the key or of the query
+// the documents were inserted with.
+type FindOptions struct {
+	// Filter maps a field to the values it may have
+	Filter map[string][]string
+	// Ranges maps a field to the range of its values
+	Ranges map[string]Range
+	// Missing lists the fields the documents do not have
+	Missing []string
+	Sort    []SortField
+	Skip    int64
+	// Limit of zero returns all the documents
+	Limit int64
+}
+
+// Range bounds the values of a field, which are compared as strings. The
+// bounds are inclusive, and an empty bound leaves that end open.
+type Range struct {
+	Min, Max string
+}
+
+// SortField is a field the documents are sorted by
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// ListOptions are the pagination, filtering and sorting parameters of a
+// list request. Sort is the name of a field, prefixed with "-" for the
+// descending order. Continue is the token returned with the previous page.
+type ListOptions struct {
+	Limit    int64
+	Continue string
+	Sort     string
+	Filters  map[string][]string
+}
+
+// MaxListLimit is the largest number of objects returned in a page
+const MaxListLimit = 1000
+
+// ListOptionsError is returned for list options the list does not support
+type ListOptionsError struct {
+	Reason string
+}
+
+func (e *ListOptionsError) Error() string {
+	return e.Reason
+}
+
+func listOptionsErrorf(format string, args ...interface{}) error {
+	return &ListOptionsError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// ListOptionsFromQuery reads the limit, continue and sort parameters and the
+// given filters from the query of a list request
+func ListOptionsFromQuery(q url.Values, filters ...string) (ListOptions, error) {
+	o := ListOptions{
+		Continue: q.Get("continue"),
+		Sort:     q.Get("sort"),
+	}
+	if l := q.Get("limit"); l != "" {
+		limit, err := strconv.ParseInt(l, 10, 64)
+		if err != nil || limit < 1 || limit > MaxListLimit {
+			return ListOptions{}, listOptionsErrorf("Invalid limit %q, expected 1-%d", l, MaxListLimit)
+		}
+		o.Limit = limit
+	}
+	for _, f := range filters {
+		if values, ok := q[f]; ok {
+			if o.Filters == nil {
+				o.Filters = map[string][]string{}
+			}
+			o.Filters[f] = values
+		}
+	}
+	return o, nil
+}
+
+// FindOptions converts the list options to the options of FindWithOptions.
+// fields maps the names of the filters and of the sort orders the list
+// supports to the fields of the documents. The documents are sorted by the
+// "name" field by default, so that the pages are stable.
+func (o ListOptions) FindOptions(fields map[string]string) (FindOptions, error) {
+	fo := FindOptions{}
+
+	skip, err := decodeContinue(o.Continue)
+	if err != nil {
+		return FindOptions{}, err
+	}
+	fo.Skip = skip
+	if o.Limit > 0 {
+		// One more document tells whether there is a next page
+		fo.Limit = o.Limit + 1
+	}
+
+	for name, values := range o.Filters {
+		field, ok := fields[name]
+		if !ok {
+			return FindOptions{}, listOptionsErrorf("Unsupported filter %q", name)
+		}
+		if fo.Filter == nil {
+			fo.Filter = map[string][]string{}
+		}
+		fo.Filter[field] = values
+	}
+
+	sort := o.Sort
+	if sort == "" {
+		sort = "name"
+	}
+	desc := strings.HasPrefix(sort, "-")
+	field, ok := fields[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		if o.Sort != "" {
+			return FindOptions{}, listOptionsErrorf("Unsupported sort %q", o.Sort)
+		}
+		return fo, nil
+	}
+	fo.Sort = []SortField{{Field: field, Descending: desc}}
+	return fo, nil
+}
+
+// Page trims the documents found with the FindOptions of the list options to
+// the page, and returns the continue token of the next page, which is empty
+// on the last page
+func (o ListOptions) Page(values [][]byte) ([][]byte, string) {
+	if o.Limit == 0 || int64(len(values)) <= o.Limit {
+		return values, ""
+	}
+	skip, _ := decodeContinue(o.Continue)
+	return values[:o.Limit], encodeContinue(skip + o.Limit)
+}
+
+func encodeContinue(skip int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(skip, 10)))
+}
+
+func decodeContinue(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, listOptionsErrorf("Invalid continue token")
+	}
+	skip, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil || skip < 0 {
+		return 0, listOptionsErrorf("Invalid continue token")
+	}
+	return skip, nil
+}
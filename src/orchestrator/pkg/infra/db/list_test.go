@@ -0,0 +1,95 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+var listTestFields = map[string]string{
+	"name":  "deploymentintentgroup",
+	"state": "state",
+}
+
+func TestListOptions(t *testing.T) {
+	testCases := []struct {
+		label    string
+		query    string
+		expected FindOptions
+		isErr    bool
+	}{
+		{
+			label:    "No options",
+			query:    "",
+			expected: FindOptions{Sort: []SortField{{Field: "deploymentintentgroup"}}},
+		},
+		{
+			label: "Page with filter and sort",
+			query: "limit=10&continue=" + encodeContinue(20) + "&state=Instantiated&sort=-name",
+			expected: FindOptions{
+				Filter: map[string][]string{"state": {"Instantiated"}},
+				Sort:   []SortField{{Field: "deploymentintentgroup", Descending: true}},
+				Skip:   20,
+				Limit:  11,
+			},
+		},
+		{label: "Invalid limit", query: "limit=0", isErr: true},
+		{label: "Invalid continue token", query: "continue=abc!", isErr: true},
+		{label: "Unsupported sort", query: "sort=version", isErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			q, _ := url.ParseQuery(testCase.query)
+			o, err := ListOptionsFromQuery(q, "state")
+			var fo FindOptions
+			if err == nil {
+				fo, err = o.FindOptions(listTestFields)
+			}
+			if testCase.isErr {
+				if _, ok := err.(*ListOptionsError); !ok {
+					t.Fatalf("Expected ListOptionsError, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error %s", err)
+			}
+			if !reflect.DeepEqual(fo, testCase.expected) {
+				t.Errorf("FindOptions returned %v, expected %v", fo, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestListOptionsPage(t *testing.T) {
+	values := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+
+	o := ListOptions{Limit: 2, Continue: encodeContinue(4)}
+	page, next := o.Page(values)
+	if len(page) != 2 || next != encodeContinue(6) {
+		t.Errorf("Page returned %d values and token %q", len(page), next)
+	}
+
+	o = ListOptions{Limit: 3}
+	page, next = o.Page(values)
+	if len(page) != 3 || next != "" {
+		t.Errorf("Page of the last page returned %d values and token %q", len(page), next)
+	}
+}
@@ -16,6 +16,7 @@ package db
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	pkgerrors "github.com/pkg/errors"
 )
@@ -34,7 +35,11 @@ func (m MockKey) String() string {
 type MockDB struct {
 	Store
 	Items map[string]map[string][]byte
-	Err   error
+	// Versions are the versions of the documents, 0 if absent
+	Versions map[string]int64
+	Err      error
+	// versionsLock guards the Versions written by InsertIfVersion
+	versionsLock sync.Mutex
 }
 
 func (m *MockDB) HealthCheck() error {
@@ -45,6 +50,32 @@ func (m *MockDB) Insert(table string, key Key, query interface{}, tag string, da
 	return m.Err
 }
 
+func (m *MockDB) InsertIfVersion(table string, key Key, query interface{}, tag string, data interface{}, version int64) (int64, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	m.versionsLock.Lock()
+	defer m.versionsLock.Unlock()
+	if m.Versions[fmt.Sprintf("%v", key)] != version {
+		return 0, &VersionConflictError{Version: version}
+	}
+	// The version is kept, so that the next write is made at the new version
+	if m.Versions == nil {
+		m.Versions = map[string]int64{}
+	}
+	m.Versions[fmt.Sprintf("%v", key)] = version + 1
+	return version + 1, nil
+}
+
+func (m *MockDB) GetVersion(table string, key Key) (int64, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	m.versionsLock.Lock()
+	defer m.versionsLock.Unlock()
+	return m.Versions[fmt.Sprintf("%v", key)], nil
+}
+
 // MockDB uses simple JSON and not BSON
 func (m *MockDB) Unmarshal(inp []byte, out interface{}) error {
 	err := json.Unmarshal(inp, out)
@@ -70,6 +101,29 @@ func (m *MockDB) Find(table string, key Key, tag string) ([][]byte, error) {
 	return nil, m.Err
 }
 
+// FindWithOptions pages the result of Find. The filters, the ranges and the
+// sort order are not applied, the items are expected to be seeded accordingly.
+// The Missing fields are looked up in the tags of the item.
+func (m *MockDB) FindWithOptions(table string, key Key, tag string, opts FindOptions) ([][]byte, error) {
+	values, err := m.Find(table, key, tag)
+	if err != nil || values == nil {
+		return values, err
+	}
+	for _, field := range opts.Missing {
+		if _, ok := m.Items[fmt.Sprintf("%v", key)][field]; ok {
+			return [][]byte{}, nil
+		}
+	}
+	if opts.Skip >= int64(len(values)) {
+		return [][]byte{}, nil
+	}
+	values = values[opts.Skip:]
+	if opts.Limit > 0 && opts.Limit < int64(len(values)) {
+		values = values[:opts.Limit]
+	}
+	return values, nil
+}
+
 func (m *MockDB) Remove(table string, key Key) error {
 	return m.Err
 }
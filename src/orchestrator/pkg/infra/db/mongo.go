@@ -108,7 +108,7 @@ func NewMongoStore(name string, store *mongo.Database) (Store, error) {
 // HealthCheck verifies if the database is up and running
 func (m *MongoStore) HealthCheck() error {
 
-	_, err := decodeBytes(m.db.RunCommand(context.Background(), bson.D{{"serverStatus", 1}}))
+	_, err := decodeBytes(m.db.RunCommand(context.Background(), bson.D{{Key: "serverStatus", Value: 1}}))
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error getting server status")
 	}
@@ -262,9 +262,12 @@ func (m *MongoStore) Insert(coll string, key Key, query interface{}, tag string,
 			ctx,
 			filter,
 			bson.D{
-				{"$set", bson.D{
-					{tag, data},
-					{"key", s},
+				{Key: "$set", Value: bson.D{
+					{Key: tag, Value: data},
+					{Key: "key", Value: s},
+				}},
+				{Key: "$inc", Value: bson.D{
+					{Key: versionField, Value: int64(1)},
 				}},
 			},
 			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)))
@@ -292,11 +295,139 @@ func (m *MongoStore) Insert(coll string, key Key, query interface{}, tag string,
 	return nil
 }
 
+// versionField is the field of the documents that holds their version
+const versionField = "resourceversion"
+
+// versionFilter matches the documents at the version. The documents stored
+// before the versions were introduced have no version field, and are at 0.
+func versionFilter(version int64) bson.M {
+	if version == 0 {
+		return bson.M{versionField: bson.M{"$in": bson.A{nil, int64(0)}}}
+	}
+	return bson.M{versionField: version}
+}
+
+// documentVersion returns the version of the document
+func documentVersion(doc bson.Raw) int64 {
+	val, err := doc.LookupErr(versionField)
+	if err != nil {
+		return 0
+	}
+	if v, ok := val.Int64OK(); ok {
+		return v
+	}
+	if v, ok := val.Int32OK(); ok {
+		return int64(v)
+	}
+	return 0
+}
+
+// InsertIfVersion updates the tag and the query fields of the document like
+// Insert, only if the document is at the version. The document is neither
+// created nor updated when it was modified since the version was read.
+func (m *MongoStore) InsertIfVersion(coll string, key Key, query interface{}, tag string, data interface{}, version int64) (int64, error) {
+	if data == nil || !m.validateParams(coll, key, tag) {
+		return 0, pkgerrors.New("No Data to store")
+	}
+
+	c := getCollection(coll, m)
+	ctx := context.Background()
+
+	filter, err := m.findFilter(key)
+	if err != nil {
+		return 0, err
+	}
+	filter["$and"] = append(filter["$and"].([]bson.M), versionFilter(version))
+
+	s, err := m.createKeyField(key)
+	if err != nil {
+		return 0, err
+	}
+	set := bson.D{
+		{Key: tag, Value: data},
+		{Key: "key", Value: s},
+	}
+	// The query fields are set with the tag, so that they can not be
+	// updated by a conflicting write
+	if query != nil {
+		update, err := m.updateFilter(query)
+		if err != nil {
+			return 0, err
+		}
+		fields := update["$set"].(bson.M)
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			set = append(set, primitive.E{Key: name, Value: fields[name]})
+		}
+	}
+
+	doc, err := decodeBytes(
+		c.FindOneAndUpdate(
+			ctx,
+			filter,
+			bson.D{
+				{Key: "$set", Value: set},
+				{Key: "$inc", Value: bson.D{
+					{Key: versionField, Value: int64(1)},
+				}},
+			},
+			options.FindOneAndUpdate().SetReturnDocument(options.After)))
+
+	if err == mongo.ErrNoDocuments {
+		return 0, &VersionConflictError{Version: version}
+	}
+	if err != nil {
+		return 0, pkgerrors.Errorf("Error updating master table: %s", err.Error())
+	}
+	return documentVersion(doc), nil
+}
+
+// GetVersion returns the version of the document matching the key, 0 if the
+// document does not exist or has no version
+func (m *MongoStore) GetVersion(coll string, key Key) (int64, error) {
+	if !m.validateParams(coll, key) {
+		return 0, pkgerrors.New("Mandatory fields are missing")
+	}
+
+	c := getCollection(coll, m)
+	ctx := context.Background()
+
+	filter, err := m.findFilter(key)
+	if err != nil {
+		return 0, err
+	}
+
+	doc, err := decodeBytes(
+		c.FindOne(
+			ctx,
+			filter,
+			options.FindOne().SetProjection(bson.D{{Key: versionField, Value: 1}})))
+
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, pkgerrors.Errorf("Error finding the version of the document: %s", err.Error())
+	}
+	return documentVersion(doc), nil
+}
+
 // Find method returns the data stored for this key and for this particular tag
 func (m *MongoStore) Find(coll string, key Key, tag string) ([][]byte, error) {
 
 	//result, err := m.findInternal(coll, key, tag, "")
 	//return result, err
+	return m.FindWithOptions(coll, key, tag, FindOptions{})
+}
+
+// FindWithOptions method returns the data stored for this key and for this
+// particular tag, in the documents matching the filters of the options,
+// sorted and paged by the options
+func (m *MongoStore) FindWithOptions(coll string, key Key, tag string, opts FindOptions) ([][]byte, error) {
 	if !m.validateParams(coll, key, tag) {
 		return nil, pkgerrors.New("Mandatory fields are missing")
 	}
@@ -308,13 +439,33 @@ func (m *MongoStore) Find(coll string, key Key, tag string) ([][]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	filter = addOptionsFilter(filter, opts)
+
 	// Find only the field requested
 	projection := bson.D{
-		{tag, 1},
-		{"_id", 0},
+		{Key: tag, Value: 1},
+		{Key: "_id", Value: 0},
+	}
+	findOptions := options.Find().SetProjection(projection)
+	if len(opts.Sort) > 0 {
+		sort := bson.D{}
+		for _, f := range opts.Sort {
+			order := 1
+			if f.Descending {
+				order = -1
+			}
+			sort = append(sort, bson.E{Key: f.Field, Value: order})
+		}
+		findOptions.SetSort(sort)
+	}
+	if opts.Skip > 0 {
+		findOptions.SetSkip(opts.Skip)
+	}
+	if opts.Limit > 0 {
+		findOptions.SetLimit(opts.Limit)
 	}
 
-	cursor, err := c.Find(context.Background(), filter, options.Find().SetProjection(projection))
+	cursor, err := c.Find(context.Background(), filter, findOptions)
 	if err != nil {
 		return nil, pkgerrors.Errorf("Error finding element: %s", err.Error())
 	}
@@ -339,6 +490,37 @@ func (m *MongoStore) Find(coll string, key Key, tag string) ([][]byte, error) {
 	return result, nil
 }
 
+// addOptionsFilter adds the filters of the options to the filter of the key
+func addOptionsFilter(filter primitive.M, opts FindOptions) primitive.M {
+	if len(opts.Filter) == 0 && len(opts.Ranges) == 0 && len(opts.Missing) == 0 {
+		return filter
+	}
+	and := filter["$and"].([]bson.M)
+	for field, values := range opts.Filter {
+		if len(values) == 1 {
+			and = append(and, bson.M{field: values[0]})
+		} else {
+			and = append(and, bson.M{field: bson.M{"$in": values}})
+		}
+	}
+	for field, r := range opts.Ranges {
+		bounds := bson.M{}
+		if r.Min != "" {
+			bounds["$gte"] = r.Min
+		}
+		if r.Max != "" {
+			bounds["$lte"] = r.Max
+		}
+		if len(bounds) > 0 {
+			and = append(and, bson.M{field: bounds})
+		}
+	}
+	for _, field := range opts.Missing {
+		and = append(and, bson.M{field: bson.M{"$exists": false}})
+	}
+	return bson.M{"$and": and}
+}
+
 // RemoveAll method to removes all the documet matching key
 func (m *MongoStore) RemoveAll(coll string, key Key) error {
 	if !m.validateParams(coll, key) {
@@ -397,8 +579,11 @@ func (m *MongoStore) RemoveTag(coll string, key Key, tag string) error {
 			ctx,
 			filter,
 			bson.D{
-				{"$unset", bson.D{
-					{tag, ""},
+				{Key: "$unset", Value: bson.D{
+					{Key: tag, Value: ""},
+				}},
+				{Key: "$inc", Value: bson.D{
+					{Key: versionField, Value: int64(1)},
 				}},
 			},
 			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)))
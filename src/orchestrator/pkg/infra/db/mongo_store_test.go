@@ -17,6 +17,7 @@
 package db
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -170,6 +171,56 @@ func TestMongoStoreFind(t *testing.T) {
 	})
 }
 
+// mockCollectionFind records the filter and the options of Find
+type mockCollectionFind struct {
+	mockCollection
+	filter  interface{}
+	options *options.FindOptions
+}
+
+func (c *mockCollectionFind) Find(ctx context.Context, filter interface{},
+	opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	c.filter = filter
+	c.options = opts[0]
+	return nil, c.Err
+}
+
+func TestMongoStoreFindWithOptions(t *testing.T) {
+	m := &MongoStore{}
+	key := storeTestKey{Project: "p1"}
+
+	coll := &mockCollectionFind{}
+	withMockedSeams(coll, nil, false, func() {
+		_, err := m.FindWithOptions("coll", key, "tag", FindOptions{
+			Filter:  map[string][]string{"state": {"Instantiated", "Terminated"}},
+			Ranges:  map[string]Range{"name": {Min: "a", Max: "m"}},
+			Missing: []string{"deleted"},
+			Sort:    []SortField{{Field: "project", Descending: true}},
+			Skip:    20,
+			Limit:   11,
+		})
+		if err != nil {
+			t.Fatalf("FindWithOptions returned an unexpected error: %s", err)
+		}
+	})
+
+	expectedFilter := bson.M{"$and": []bson.M{
+		{"project": "p1"},
+		{"state": bson.M{"$in": []string{"Instantiated", "Terminated"}}},
+		{"name": bson.M{"$gte": "a", "$lte": "m"}},
+		{"deleted": bson.M{"$exists": false}},
+	}}
+	if !reflect.DeepEqual(coll.filter, expectedFilter) {
+		t.Errorf("FindWithOptions used filter %v, expected %v", coll.filter, expectedFilter)
+	}
+	if !reflect.DeepEqual(coll.options.Sort, bson.D{{Key: "project", Value: -1}}) {
+		t.Errorf("FindWithOptions used sort %v", coll.options.Sort)
+	}
+	if *coll.options.Skip != 20 || *coll.options.Limit != 11 {
+		t.Errorf("FindWithOptions used skip %d and limit %d", *coll.options.Skip, *coll.options.Limit)
+	}
+}
+
 func TestMongoStoreRemove(t *testing.T) {
 	m := &MongoStore{}
 	key := storeTestKey{Project: "p1"}
@@ -260,3 +311,119 @@ func TestMongoStoreRemoveTag(t *testing.T) {
 		})
 	})
 }
+
+// mockCollectionUpdate records the filter and the update of FindOneAndUpdate
+type mockCollectionUpdate struct {
+	mockCollection
+	filter interface{}
+	update interface{}
+}
+
+func (c *mockCollectionUpdate) FindOneAndUpdate(ctx context.Context, filter interface{},
+	update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	c.filter = filter
+	c.update = update
+	return &mongo.SingleResult{}
+}
+
+// withDocument makes decodeBytes return the document, or the error
+func withDocument(doc bson.M, err error) {
+	decodeBytes = func(sr *mongo.SingleResult) (bson.Raw, error) {
+		if err != nil {
+			return nil, err
+		}
+		raw, _ := bson.Marshal(doc)
+		return raw, nil
+	}
+}
+
+func TestMongoStoreInsertIfVersion(t *testing.T) {
+	m := &MongoStore{}
+	key := storeTestKey{Project: "p1"}
+
+	t.Run("Update at the version", func(t *testing.T) {
+		coll := &mockCollectionUpdate{}
+		withMockedSeams(coll, nil, false, func() {
+			withDocument(bson.M{"resourceversion": int64(4)}, nil)
+			version, err := m.InsertIfVersion("coll", key, map[string]string{"state": "Instantiated"}, "tag", "data", 3)
+			if err != nil {
+				t.Fatalf("InsertIfVersion returned an unexpected error: %s", err)
+			}
+			if version != 4 {
+				t.Errorf("InsertIfVersion returned version %d, expected 4", version)
+			}
+		})
+		expectedFilter := bson.M{"$and": []bson.M{{"project": "p1"}, {"resourceversion": int64(3)}}}
+		if !reflect.DeepEqual(coll.filter, expectedFilter) {
+			t.Errorf("InsertIfVersion used filter %v, expected %v", coll.filter, expectedFilter)
+		}
+		expectedUpdate := bson.D{
+			{Key: "$set", Value: bson.D{{Key: "tag", Value: "data"}, {Key: "key", Value: "{project,}"}, {Key: "state", Value: "Instantiated"}}},
+			{Key: "$inc", Value: bson.D{{Key: "resourceversion", Value: int64(1)}}},
+		}
+		if !reflect.DeepEqual(coll.update, expectedUpdate) {
+			t.Errorf("InsertIfVersion used update %v, expected %v", coll.update, expectedUpdate)
+		}
+	})
+
+	t.Run("Update of a document without version", func(t *testing.T) {
+		coll := &mockCollectionUpdate{}
+		withMockedSeams(coll, nil, false, func() {
+			withDocument(bson.M{"resourceversion": int32(1)}, nil)
+			version, err := m.InsertIfVersion("coll", key, nil, "tag", "data", 0)
+			if err != nil || version != 1 {
+				t.Fatalf("InsertIfVersion returned %d, %v, expected 1", version, err)
+			}
+		})
+		expectedFilter := bson.M{"$and": []bson.M{{"project": "p1"}, {"resourceversion": bson.M{"$in": bson.A{nil, int64(0)}}}}}
+		if !reflect.DeepEqual(coll.filter, expectedFilter) {
+			t.Errorf("InsertIfVersion used filter %v, expected %v", coll.filter, expectedFilter)
+		}
+	})
+
+	t.Run("Conflicting update", func(t *testing.T) {
+		withMockedSeams(&mockCollectionUpdate{}, nil, false, func() {
+			withDocument(nil, mongo.ErrNoDocuments)
+			_, err := m.InsertIfVersion("coll", key, nil, "tag", "data", 3)
+			if _, ok := err.(*VersionConflictError); !ok {
+				t.Fatalf("InsertIfVersion expected VersionConflictError, got %v", err)
+			}
+		})
+	})
+}
+
+func TestMongoStoreGetVersion(t *testing.T) {
+	m := &MongoStore{}
+	key := storeTestKey{Project: "p1"}
+
+	testCases := []struct {
+		label    string
+		doc      bson.M
+		err      error
+		expected int64
+		fails    bool
+	}{
+		{label: "Versioned document", doc: bson.M{"resourceversion": int64(7)}, expected: 7},
+		{label: "Document without version", doc: bson.M{"project": "p1"}},
+		{label: "Missing document", err: mongo.ErrNoDocuments},
+		{label: "Find error surfaces", err: pkgerrors.New("decode failed"), fails: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			withMockedSeams(&mockCollection{}, nil, false, func() {
+				withDocument(testCase.doc, testCase.err)
+				version, err := m.GetVersion("coll", key)
+				if testCase.fails {
+					if err == nil {
+						t.Fatalf("GetVersion expected an error")
+					}
+					return
+				}
+				if err != nil || version != testCase.expected {
+					t.Fatalf("GetVersion returned %d, %v, expected %d", version, err, testCase.expected)
+				}
+			})
+		})
+	}
+}
@@ -42,9 +42,21 @@ type Store interface {
 	// Inserts and Updates a tag with key and also adds query fields if provided
 	Insert(coll string, key Key, query interface{}, tag string, data interface{}) error
 
+	// Inserts and Updates a tag like Insert, only if the document matching the
+	// key is at the version. Returns the new version of the document.
+	InsertIfVersion(coll string, key Key, query interface{}, tag string, data interface{}, version int64) (int64, error)
+
+	// Returns the version of the document matching the key, which is
+	// incremented by every update of the document
+	GetVersion(coll string, key Key) (int64, error)
+
 	// Find the document(s) with key and get the tag values from the document(s)
 	Find(coll string, key Key, tag string) ([][]byte, error)
 
+	// Find the document(s) with key, filtered, sorted and paged by the options,
+	// and get the tag values from the document(s)
+	FindWithOptions(coll string, key Key, tag string, opts FindOptions) ([][]byte, error)
+
 	// Removes the document(s) matching the key if no child reference in collection
 	Remove(coll string, key Key) error
 
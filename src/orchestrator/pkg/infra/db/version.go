@@ -0,0 +1,55 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionConflictError is returned by InsertIfVersion when the document was
+// modified since its version was read
+type VersionConflictError struct {
+	Version int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("The document was modified since version %d", e.Version)
+}
+
+// ETag returns the entity tag of the version of a document
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// IfMatch reports whether the If-Match header matches the version of a
+// document. An empty header matches any version.
+func IfMatch(header string, version int64) bool {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return true
+	}
+	etag := ETag(version)
+	for _, t := range strings.Split(header, ",") {
+		// The versions are compared strongly, weak tags never match
+		if strings.TrimSpace(t) == etag {
+			return true
+		}
+	}
+	return false
+}
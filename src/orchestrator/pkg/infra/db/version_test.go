@@ -0,0 +1,45 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"testing"
+)
+
+func TestIfMatch(t *testing.T) {
+	testCases := []struct {
+		header   string
+		expected bool
+	}{
+		{header: "", expected: true},
+		{header: "*", expected: true},
+		{header: `"5"`, expected: true},
+		{header: `"4", "5"`, expected: true},
+		{header: `"4"`},
+		{header: `W/"5"`},
+		{header: "5"},
+	}
+
+	for _, testCase := range testCases {
+		if got := IfMatch(testCase.header, 5); got != testCase.expected {
+			t.Errorf("IfMatch(%q, 5) returned %t, expected %t", testCase.header, got, testCase.expected)
+		}
+	}
+	if ETag(5) != `"5"` {
+		t.Errorf("ETag(5) returned %s", ETag(5))
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics exposes the Prometheus metrics of the EMCO services
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path is the path the metrics are served on
+const Path = "/metrics"
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "emco_http_requests_total",
+		Help: "Number of HTTP requests by service, route, method and status code.",
+	}, []string{"service", "route", "method", "code"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "emco_http_request_duration_seconds",
+		Help:    "Latency of the HTTP requests by service, route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"service", "route", "method"})
+
+	lifecycleDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "emco_lifecycle_operation_duration_seconds",
+		Help:    "Duration of the instantiate, update and terminate operations by outcome.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"operation", "result"})
+
+	controllerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "emco_controller_grpc_duration_seconds",
+		Help:    "Latency of the gRPC calls to the controllers by controller, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"controller", "method", "code"})
+
+	clusterApplyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "emco_rsync_cluster_apply_duration_seconds",
+		Help:    "Duration of rsync applying or deleting the resources of an app in a cluster.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"cluster", "operation", "result"})
+
+	resourceStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "emco_rsync_resources",
+		Help: "Number of resources in each RsyncStatus, of the resources rsync set the status of since it started.",
+	}, []string{"status"})
+
+	clusterReachable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "emco_cluster_reachable",
+		Help: "Whether rsync reached the cluster on its last attempt, 1 or 0.",
+	}, []string{"cluster"})
+
+	dbDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "emco_db_operation_duration_seconds",
+		Help:    "Latency of the mongo and etcd operations by backend, operation and outcome.",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14),
+	}, []string{"backend", "operation", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(httpRequests, httpDuration, lifecycleDuration, controllerDuration,
+		clusterApplyDuration, resourceStatus, clusterReachable, dbDuration)
+}
+
+// result is the outcome label of an operation
+func result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
+
+// Handler serves the metrics on Path, and the other requests with next
+func Handler(next http.Handler) http.Handler {
+	metrics := promhttp.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == Path {
+			metrics.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Serve serves the metrics on Path in the background, for the services
+// without an HTTP API
+func Serve(port string) {
+	mux := http.NewServeMux()
+	mux.Handle(Path, promhttp.Handler())
+	go func() {
+		err := http.ListenAndServe(":"+port, mux)
+		log.Error("Metrics server stopped", log.Fields{"Error": err, "Port": port})
+	}()
+}
+
+// ObserveLifecycleOperation records the duration and the outcome of an
+// instantiate, update or terminate operation started at start
+func ObserveLifecycleOperation(operation string, start time.Time, err error) {
+	lifecycleDuration.WithLabelValues(operation, result(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveClusterApply records the duration and the outcome of rsync applying
+// or deleting the resources of an app in a cluster, started at start
+func ObserveClusterApply(cluster, operation string, start time.Time, err error) {
+	clusterApplyDuration.WithLabelValues(cluster, operation, result(err)).Observe(time.Since(start).Seconds())
+}
+
+// resourceStatuses are the current RsyncStatus of the resources, by resource
+var resourceStatuses = struct {
+	sync.Mutex
+	m map[string]string
+}{m: map[string]string{}}
+
+// SetResourceStatus moves a resource to the RsyncStatus in the count of the
+// resources by status
+func SetResourceStatus(resource, status string) {
+	resourceStatuses.Lock()
+	defer resourceStatuses.Unlock()
+	if old, ok := resourceStatuses.m[resource]; ok {
+		if old == status {
+			return
+		}
+		resourceStatus.WithLabelValues(old).Dec()
+	}
+	resourceStatuses.m[resource] = status
+	resourceStatus.WithLabelValues(status).Inc()
+}
+
+// ForgetResourceStatus removes a resource which no longer exists from the
+// count of the resources by status
+func ForgetResourceStatus(resource string) {
+	resourceStatuses.Lock()
+	defer resourceStatuses.Unlock()
+	if old, ok := resourceStatuses.m[resource]; ok {
+		resourceStatus.WithLabelValues(old).Dec()
+		delete(resourceStatuses.m, resource)
+	}
+}
+
+// SetClusterReachable records whether rsync reached the cluster
+func SetClusterReachable(cluster string, reachable bool) {
+	v := 0.0
+	if reachable {
+		v = 1
+	}
+	clusterReachable.WithLabelValues(cluster).Set(v)
+}
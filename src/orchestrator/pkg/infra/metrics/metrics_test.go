@@ -0,0 +1,90 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/v2/projects/{project-name}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods("GET")
+	router.Use(Middleware("test"))
+	handler := Handler(router)
+
+	for _, name := range []string{"p1", "p2"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/v2/projects/"+name, nil))
+	}
+
+	got := testutil.ToFloat64(httpRequests.WithLabelValues("test", "/v2/projects/{project-name}", "GET", "404"))
+	if got != 2 {
+		t.Errorf("Middleware counted %v requests, expected 2", got)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", Path, nil))
+	body, _ := ioutil.ReadAll(rec.Body)
+	if rec.Code != http.StatusOK || !strings.Contains(string(body), `emco_http_requests_total{code="404",method="GET",route="/v2/projects/{project-name}",service="test"} 2`) {
+		t.Errorf("Handler returned unexpected metrics %d %s", rec.Code, body)
+	}
+}
+
+func TestStore(t *testing.T) {
+	s := NewStore(&db.MockDB{Err: pkgerrors.New("unavailable")})
+
+	before := testutil.CollectAndCount(dbDuration)
+	s.Find("orchestrator", db.Key(nil), "tag")
+	s.Remove("orchestrator", db.Key(nil))
+
+	if got := testutil.CollectAndCount(dbDuration); got != before+2 {
+		t.Errorf("Store recorded %d series, expected %d", got, before+2)
+	}
+	if _, err := s.Find("orchestrator", db.Key(nil), "tag"); err == nil {
+		t.Errorf("Store did not return the error of the wrapped Store")
+	}
+}
+
+func TestResourceStatus(t *testing.T) {
+	SetResourceStatus("r1", "Applied")
+	SetResourceStatus("r2", "Applied")
+	SetResourceStatus("r2", "Applied")
+	SetResourceStatus("r1", "Failed")
+	if got := testutil.ToFloat64(resourceStatus.WithLabelValues("Applied")); got != 1 {
+		t.Errorf("Expected 1 Applied resource, got %v", got)
+	}
+	if got := testutil.ToFloat64(resourceStatus.WithLabelValues("Failed")); got != 1 {
+		t.Errorf("Expected 1 Failed resource, got %v", got)
+	}
+
+	ForgetResourceStatus("r2")
+	ForgetResourceStatus("r3")
+	if got := testutil.ToFloat64(resourceStatus.WithLabelValues("Applied")); got != 0 {
+		t.Errorf("Expected no Applied resource, got %v", got)
+	}
+}
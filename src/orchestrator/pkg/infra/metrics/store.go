@@ -0,0 +1,168 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/contextdb"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+)
+
+func observeDb(backend, operation string, start time.Time, err error) {
+	dbDuration.WithLabelValues(backend, operation, result(err)).Observe(time.Since(start).Seconds())
+}
+
+// InstrumentDatabases wraps the initialized mongo and etcd connections, so
+// that the latency of their operations is recorded
+func InstrumentDatabases() {
+	if db.DBconn != nil {
+		db.DBconn = NewStore(db.DBconn)
+	}
+	if contextdb.Db != nil {
+		contextdb.Db = NewContextDb(contextdb.Db)
+	}
+}
+
+// Store records the latency of the operations of a db.Store
+type Store struct {
+	store db.Store
+}
+
+// NewStore returns the Store recording the latency of s
+func NewStore(s db.Store) *Store {
+	return &Store{store: s}
+}
+
+func (s *Store) HealthCheck() error {
+	start := time.Now()
+	err := s.store.HealthCheck()
+	observeDb("mongo", "HealthCheck", start, err)
+	return err
+}
+
+func (s *Store) Unmarshal(inp []byte, out interface{}) error {
+	return s.store.Unmarshal(inp, out)
+}
+
+func (s *Store) Insert(coll string, key db.Key, query interface{}, tag string, data interface{}) error {
+	start := time.Now()
+	err := s.store.Insert(coll, key, query, tag, data)
+	observeDb("mongo", "Insert", start, err)
+	return err
+}
+
+func (s *Store) InsertIfVersion(coll string, key db.Key, query interface{}, tag string, data interface{}, version int64) (int64, error) {
+	start := time.Now()
+	v, err := s.store.InsertIfVersion(coll, key, query, tag, data, version)
+	observeDb("mongo", "InsertIfVersion", start, err)
+	return v, err
+}
+
+func (s *Store) GetVersion(coll string, key db.Key) (int64, error) {
+	start := time.Now()
+	v, err := s.store.GetVersion(coll, key)
+	observeDb("mongo", "GetVersion", start, err)
+	return v, err
+}
+
+func (s *Store) Find(coll string, key db.Key, tag string) ([][]byte, error) {
+	start := time.Now()
+	values, err := s.store.Find(coll, key, tag)
+	observeDb("mongo", "Find", start, err)
+	return values, err
+}
+
+func (s *Store) FindWithOptions(coll string, key db.Key, tag string, opts db.FindOptions) ([][]byte, error) {
+	start := time.Now()
+	values, err := s.store.FindWithOptions(coll, key, tag, opts)
+	observeDb("mongo", "FindWithOptions", start, err)
+	return values, err
+}
+
+func (s *Store) Remove(coll string, key db.Key) error {
+	start := time.Now()
+	err := s.store.Remove(coll, key)
+	observeDb("mongo", "Remove", start, err)
+	return err
+}
+
+func (s *Store) RemoveAll(coll string, key db.Key) error {
+	start := time.Now()
+	err := s.store.RemoveAll(coll, key)
+	observeDb("mongo", "RemoveAll", start, err)
+	return err
+}
+
+func (s *Store) RemoveTag(coll string, key db.Key, tag string) error {
+	start := time.Now()
+	err := s.store.RemoveTag(coll, key, tag)
+	observeDb("mongo", "RemoveTag", start, err)
+	return err
+}
+
+// ContextDb records the latency of the operations of a contextdb.ContextDb
+type ContextDb struct {
+	db contextdb.ContextDb
+}
+
+// NewContextDb returns the ContextDb recording the latency of c
+func NewContextDb(c contextdb.ContextDb) *ContextDb {
+	return &ContextDb{db: c}
+}
+
+func (c *ContextDb) HealthCheck() error {
+	start := time.Now()
+	err := c.db.HealthCheck()
+	observeDb("etcd", "HealthCheck", start, err)
+	return err
+}
+
+func (c *ContextDb) Put(key string, value interface{}) error {
+	start := time.Now()
+	err := c.db.Put(key, value)
+	observeDb("etcd", "Put", start, err)
+	return err
+}
+
+func (c *ContextDb) Delete(key string) error {
+	start := time.Now()
+	err := c.db.Delete(key)
+	observeDb("etcd", "Delete", start, err)
+	return err
+}
+
+func (c *ContextDb) DeleteAll(key string) error {
+	start := time.Now()
+	err := c.db.DeleteAll(key)
+	observeDb("etcd", "DeleteAll", start, err)
+	return err
+}
+
+func (c *ContextDb) Get(key string, value interface{}) error {
+	start := time.Now()
+	err := c.db.Get(key, value)
+	observeDb("etcd", "Get", start, err)
+	return err
+}
+
+func (c *ContextDb) GetAllKeys(path string) ([]string, error) {
+	start := time.Now()
+	keys, err := c.db.GetAllKeys(path)
+	observeDb("etcd", "GetAllKeys", start, err)
+	return keys, err
+}
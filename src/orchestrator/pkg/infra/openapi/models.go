@@ -0,0 +1,150 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// models collects the schemas of the models of a document. The named struct
+// types are added to the schemas and referenced, the other types are
+// inlined.
+type models struct {
+	schemas map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+func newModels() *models {
+	return &models{
+		schemas: map[string]interface{}{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+// of returns the schema of the type of the value
+func (m *models) of(v interface{}) map[string]interface{} {
+	return m.schema(reflect.TypeOf(v))
+}
+
+// name returns the name of a struct type in the schemas. Types of different
+// packages with the same name are qualified with their package.
+func (m *models) name(t reflect.Type) string {
+	if n, ok := m.names[t]; ok {
+		return n
+	}
+	n := t.Name()
+	for other, on := range m.names {
+		if on == n && other != t {
+			n = path.Base(t.PkgPath()) + n
+			break
+		}
+	}
+	m.names[t] = n
+	return n
+}
+
+func (m *models) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is marshalled as a base64 string
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": m.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": m.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return m.object(t)
+		}
+		n := m.name(t)
+		if _, ok := m.schemas[n]; !ok {
+			// Reserve the name first, for the types referencing themselves
+			m.schemas[n] = map[string]interface{}{}
+			m.schemas[n] = m.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + n}
+	}
+	// interface{} and the types without a JSON representation accept anything
+	return map[string]interface{}{}
+}
+
+// object returns the schema of a struct, with the properties of its fields
+// named like encoding/json does
+func (m *models) object(t reflect.Type) map[string]interface{} {
+	props := map[string]interface{}{}
+	m.fields(t, props)
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+func (m *models) fields(t reflect.Type, props map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				m.fields(ft, props)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = m.schema(f.Type)
+	}
+}
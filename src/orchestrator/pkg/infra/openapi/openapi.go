@@ -0,0 +1,533 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package openapi generates the OpenAPI specification of a REST API from its
+// gorilla/mux router, the json-schemas its requests are validated with and
+// the types of its models
+package openapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Operation documents a route of the router for a method
+type Operation struct {
+	Summary string
+	// Schema is the json-schema file the request body is validated with
+	Schema string
+	// Request is a value of the type of the request body, when the body has
+	// no json-schema
+	Request interface{}
+	// RequestContentType of the request body, application/json by default.
+	// An application/octet-stream body is documented as binary.
+	RequestContentType string
+	// Files are the file fields of a multipart form request. The json
+	// described by Schema is then sent in its "metadata" field.
+	Files []string
+	// Queries are the query parameters the handler reads, besides the ones
+	// the route matches
+	Queries []string
+	// Response is a value of the type of the response body, nil when the
+	// response has no body
+	Response interface{}
+	// ContentType of the response body, application/json by default
+	ContentType string
+	// Status of a successful response, http.StatusOK by default
+	Status int
+}
+
+// Resource documents the routes of a collection and of its items. Their
+// operations are generated from the method and the path of the routes.
+type Resource struct {
+	// Name of an item, like "composite application"
+	Name string
+	// Plural of the name, the name followed by "s" by default
+	Plural string
+	// Schema is the json-schema file the created and updated items are
+	// validated with. The Model documents them when there is none.
+	Schema string
+	// Files are the file fields of the multipart form requests
+	Files []string
+	// Model is a value of the type of an item
+	Model interface{}
+	// List is a value of the type of the list of the collection, a slice of
+	// the Model by default
+	List interface{}
+	// Find is a value of the type of the response of the routes which find
+	// the items by their query parameters
+	Find interface{}
+	// Queries are the query parameters of the list, besides the ones the
+	// route matches
+	Queries []string
+	// UpdateStatus of a successful update, http.StatusOK by default
+	UpdateStatus int
+	// Singleton resources have no items, the route of the collection gets,
+	// sets and deletes the resource
+	Singleton bool
+}
+
+func (r Resource) plural() string {
+	if r.Plural != "" {
+		return r.Plural
+	}
+	return r.Name + "s"
+}
+
+// article returns the name with its indefinite article
+func (r Resource) article() string {
+	if strings.IndexAny(r.Name, "aeiou") == 0 {
+		return "an " + r.Name
+	}
+	return "a " + r.Name
+}
+
+// write returns the operation of a route creating or updating the resource
+func (r Resource) write(summary string, status int) Operation {
+	op := Operation{Summary: summary, Schema: r.Schema, Files: r.Files, Response: r.Model, Status: status}
+	if r.Schema == "" {
+		op.Request = r.Model
+	}
+	return op
+}
+
+func (r Resource) list() interface{} {
+	if r.List != nil {
+		return r.List
+	}
+	return reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(r.Model)), 0, 0).Interface()
+}
+
+// Spec documents the routes of a service. Resources are keyed by the path
+// segment of their collection, like "projects". Operations document the
+// other routes, and are keyed by the method and the path template of the
+// routes, like "POST /v2/projects/{project-name}/approve".
+type Spec struct {
+	Title      string
+	Version    string
+	Resources  map[string]Resource
+	Operations map[string]Operation
+}
+
+// resource returns the resource of a route, and whether the route is one of
+// its items: the path of the collection may be followed by the variables
+// naming an item, or by a "/" for the finds.
+func (s Spec) resource(r route) (string, Resource, bool, bool) {
+	parts := strings.Split(strings.TrimPrefix(r.path, "/"), "/")
+	i := len(parts) - 1
+	if parts[i] == "" {
+		i--
+	}
+	for i > 0 && strings.HasPrefix(parts[i], "{") {
+		i--
+	}
+	res, ok := s.Resources[parts[i]]
+	return parts[i], res, i < len(parts)-1, ok
+}
+
+// operation returns the operation of a route, the one of Operations or the
+// one generated from its resource
+func (s Spec) operation(r route) (Operation, bool) {
+	if op, ok := s.Operations[r.key()]; ok {
+		return op, true
+	}
+	_, res, item, ok := s.resource(r)
+	if !ok {
+		return Operation{}, false
+	}
+	switch {
+	case strings.HasSuffix(r.path, "/"):
+		if r.method != http.MethodGet || res.Find == nil || len(r.queries) == 0 {
+			return Operation{}, false
+		}
+		return Operation{Summary: "Find the " + res.plural() + " by " + strings.Join(r.queries, " and "), Response: res.Find}, true
+	case item && !res.Singleton:
+		switch r.method {
+		case http.MethodGet:
+			return Operation{Summary: "Get " + res.article(), Response: res.Model}, true
+		case http.MethodPut:
+			return res.write("Update "+res.article(), res.UpdateStatus), true
+		case http.MethodDelete:
+			return Operation{Summary: "Delete " + res.article(), Status: http.StatusNoContent}, true
+		}
+	case !item && res.Singleton:
+		switch r.method {
+		case http.MethodGet:
+			return Operation{Summary: "Get the " + res.Name, Response: res.Model}, true
+		case http.MethodPut:
+			return res.write("Set the "+res.Name, res.UpdateStatus), true
+		case http.MethodDelete:
+			return Operation{Summary: "Delete the " + res.Name, Status: http.StatusNoContent}, true
+		}
+	case !item:
+		switch r.method {
+		case http.MethodPost:
+			return res.write("Create "+res.article(), http.StatusCreated), true
+		case http.MethodGet:
+			return Operation{Summary: "List the " + res.plural(), Response: res.list(), Queries: res.Queries}, true
+		}
+	}
+	return Operation{}, false
+}
+
+// route is a method and a path template of the router, with the variables
+// of its queries
+type route struct {
+	method  string
+	path    string
+	queries []string
+}
+
+func (r route) key() string {
+	return r.method + " " + r.path
+}
+
+// normalizePath removes the patterns from the variables of a path template
+func normalizePath(tpl string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(tpl, "{")
+		if start < 0 {
+			b.WriteString(tpl)
+			return b.String()
+		}
+		end := strings.Index(tpl[start:], "}")
+		if end < 0 {
+			b.WriteString(tpl)
+			return b.String()
+		}
+		v := tpl[start+1 : start+end]
+		if i := strings.Index(v, ":"); i >= 0 {
+			v = v[:i]
+		}
+		b.WriteString(tpl[:start] + "{" + v + "}")
+		tpl = tpl[start+end+1:]
+	}
+}
+
+// pathVariables returns the names of the variables of a path template
+func pathVariables(path string) []string {
+	var vars []string
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			vars = append(vars, part[1:len(part)-1])
+		}
+	}
+	return vars
+}
+
+// routes returns the routes of the router, in the order of the keys. The
+// routes differing only by their queries are merged.
+func routes(router *mux.Router) ([]route, error) {
+	found := map[string]*route{}
+	err := router.Walk(func(r *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if r.GetHandler() == nil {
+			return nil
+		}
+		tpl, err := r.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := r.GetMethods()
+		if err != nil || len(methods) == 0 {
+			methods = []string{http.MethodGet}
+		}
+		var queries []string
+		if qt, err := r.GetQueriesTemplates(); err == nil {
+			for _, q := range qt {
+				queries = append(queries, strings.SplitN(q, "=", 2)[0])
+			}
+		}
+		for _, m := range methods {
+			rt := route{method: m, path: normalizePath(tpl)}
+			if existing, ok := found[rt.key()]; ok {
+				existing.queries = appendMissing(existing.queries, queries...)
+				continue
+			}
+			rt.queries = queries
+			found[rt.key()] = &rt
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for k := range found {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	list := make([]route, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, *found[k])
+	}
+	return list, nil
+}
+
+func appendMissing(list []string, values ...string) []string {
+	for _, v := range values {
+		missing := true
+		for _, l := range list {
+			if l == v {
+				missing = false
+				break
+			}
+		}
+		if missing {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// Check returns an error listing the routes of the router without an
+// operation in the spec, and the operations and the resources of the spec
+// without a route
+func (s Spec) Check(router *mux.Router) error {
+	list, err := routes(router)
+	if err != nil {
+		return err
+	}
+	var missing, unknown []string
+	seen := map[string]bool{}
+	for _, r := range list {
+		seen[r.key()] = true
+		if _, ok := s.operation(r); !ok {
+			missing = append(missing, r.key())
+			continue
+		}
+		if _, ok := s.Operations[r.key()]; !ok {
+			name, _, _, _ := s.resource(r)
+			seen[name] = true
+		}
+	}
+	for k := range s.Operations {
+		if !seen[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	for k := range s.Resources {
+		if !seen[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	if len(missing) == 0 && len(unknown) == 0 {
+		return nil
+	}
+	return pkgerrors.Errorf("Routes without an operation: %v, operations and resources without a route: %v", missing, unknown)
+}
+
+// Document generates the OpenAPI document of the routes of the router
+func (s Spec) Document(router *mux.Router) (map[string]interface{}, error) {
+	list, err := routes(router)
+	if err != nil {
+		return nil, err
+	}
+
+	models := newModels()
+	paths := map[string]interface{}{}
+	for _, r := range list {
+		op, _ := s.operation(r)
+
+		o := map[string]interface{}{
+			"operationId": operationID(r),
+			"responses":   responses(op, models),
+		}
+		if op.Summary != "" {
+			o["summary"] = op.Summary
+		}
+		if params := parameters(r, op); len(params) > 0 {
+			o["parameters"] = params
+		}
+		if body := requestBody(op, models); body != nil {
+			o["requestBody"] = body
+		}
+
+		item, ok := paths[r.path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[r.path] = item
+		}
+		item[strings.ToLower(r.method)] = o
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   s.Title,
+			"version": s.Version,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": models.schemas,
+		},
+	}, nil
+}
+
+// Handler serves the OpenAPI document of the routes of the router
+func (s Spec) Handler(router *mux.Router) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		doc, err := s.Document(router)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(doc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// operationID derives an identifier of the operation from its method and
+// the static parts of its path
+func operationID(r route) string {
+	id := strings.ToLower(r.method)
+	for _, part := range strings.Split(r.path, "/") {
+		if part == "" || strings.HasPrefix(part, "{") {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(part, func(c rune) bool { return c == '-' || c == '_' || c == '.' }) {
+			id += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	if vars := pathVariables(r.path); len(vars) > 0 && strings.HasSuffix(r.path, "}") {
+		id += "ByName"
+	}
+	return id
+}
+
+func parameters(r route, op Operation) []interface{} {
+	var params []interface{}
+	for _, v := range pathVariables(r.path) {
+		params = append(params, map[string]interface{}{
+			"name":     v,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	for _, q := range appendMissing(append([]string{}, r.queries...), op.Queries...) {
+		params = append(params, map[string]interface{}{
+			"name":     q,
+			"in":       "query",
+			"required": false,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	return params
+}
+
+func requestBody(op Operation, m *models) map[string]interface{} {
+	var schema map[string]interface{}
+	switch {
+	case op.Schema != "":
+		schema = m.file(op.Schema)
+	case op.Request != nil:
+		schema = m.of(op.Request)
+	default:
+		return nil
+	}
+	contentType := op.RequestContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if contentType == "application/octet-stream" {
+		schema = map[string]interface{}{"type": "string", "format": "binary"}
+	}
+	if len(op.Files) > 0 {
+		props := map[string]interface{}{"metadata": schema}
+		for _, f := range op.Files {
+			props[f] = map[string]interface{}{"type": "string", "format": "binary"}
+		}
+		return map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"multipart/form-data": map[string]interface{}{
+					"schema": map[string]interface{}{
+						"type":       "object",
+						"properties": props,
+						"required":   append([]string{"metadata"}, op.Files...),
+					},
+				},
+			},
+		}
+	}
+	return map[string]interface{}{
+		"required": true,
+		"content": map[string]interface{}{
+			contentType: map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+func responses(op Operation, m *models) map[string]interface{} {
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := map[string]interface{}{"description": http.StatusText(status)}
+	if op.Response != nil {
+		contentType := op.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		success["content"] = map[string]interface{}{
+			contentType: map[string]interface{}{"schema": m.of(op.Response)},
+		}
+	}
+	return map[string]interface{}{
+		statusCode(status): success,
+		"default":          map[string]interface{}{"description": "Error"},
+	}
+}
+
+func statusCode(status int) string {
+	b, _ := json.Marshal(status)
+	return string(b)
+}
+
+// file returns the reference to the json-schema file, which is added to the
+// schemas of the document. A file which can't be read is documented as an
+// object.
+func (m *models) file(name string) map[string]interface{} {
+	id := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	if _, ok := m.schemas[id]; !ok {
+		schema := map[string]interface{}{"type": "object"}
+		if b, err := ioutil.ReadFile(name); err == nil {
+			var s map[string]interface{}
+			if json.Unmarshal(b, &s) == nil {
+				delete(s, "$schema")
+				schema = s
+			}
+		}
+		m.schemas[id] = schema
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + id}
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type testMetadata struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Internal    string `json:"-"`
+}
+
+type testItem struct {
+	testMetadata
+	Labels  map[string]string `json:"labels"`
+	Created time.Time         `json:"created"`
+	Parent  *testItem         `json:"parent,omitempty"`
+}
+
+func testRouter() *mux.Router {
+	h := func(w http.ResponseWriter, r *http.Request) {}
+	router := mux.NewRouter().PathPrefix("/v2").Subrouter()
+	router.HandleFunc("/items", h).Methods("POST")
+	router.HandleFunc("/items", h).Methods("GET")
+	router.HandleFunc("/items", h).Queries("label", "{label}")
+	router.HandleFunc("/items/{item-name:[a-z]+}", h).Methods("GET", "DELETE")
+	return router
+}
+
+func TestCheck(t *testing.T) {
+	spec := Spec{Resources: map[string]Resource{
+		"items": {Name: "item", Model: testItem{}},
+	}}
+	if err := spec.Check(testRouter()); err != nil {
+		t.Fatalf("Check returned an unexpected error %s", err)
+	}
+
+	router := testRouter()
+	router.HandleFunc("/items/{item-name}/start", func(w http.ResponseWriter, r *http.Request) {}).Methods("POST")
+	spec.Resources["others"] = Resource{Name: "other"}
+	spec.Operations = map[string]Operation{"POST /v2/items/{item-name}/stop": {}}
+	err := spec.Check(router)
+	for _, k := range []string{"POST /v2/items/{item-name}/start", "POST /v2/items/{item-name}/stop", "others"} {
+		if err == nil || !strings.Contains(err.Error(), k) {
+			t.Fatalf("Check expected the missing and unknown operations and resources, got %v", err)
+		}
+	}
+}
+
+func TestOperation(t *testing.T) {
+	spec := Spec{
+		Resources: map[string]Resource{
+			"items":  {Name: "item", Model: testItem{}, Find: testItem{}},
+			"owner":  {Name: "owner of the item", Model: testMetadata{}, Singleton: true},
+			"phases": {Name: "phase", Model: testMetadata{}},
+		},
+		Operations: map[string]Operation{
+			"POST /v2/items/{item-name}/start": {Summary: "Start an item"},
+		},
+	}
+
+	testCases := []struct {
+		route    route
+		summary  string
+		response interface{}
+		status   int
+	}{
+		{route{method: "POST", path: "/v2/items"}, "Create an item", testItem{}, http.StatusCreated},
+		{route{method: "GET", path: "/v2/items"}, "List the items", []testItem{}, 0},
+		{route{method: "GET", path: "/v2/items/", queries: []string{"label"}}, "Find the items by label", testItem{}, 0},
+		{route{method: "GET", path: "/v2/items/{item-name}"}, "Get an item", testItem{}, 0},
+		{route{method: "DELETE", path: "/v2/items/{item-name}"}, "Delete an item", nil, http.StatusNoContent},
+		{route{method: "PUT", path: "/v2/items/{item-name}/owner"}, "Set the owner of the item", testMetadata{}, 0},
+		{route{method: "GET", path: "/v2/items/{item-name}/phases/{phase}/{version}"}, "Get a phase", testMetadata{}, 0},
+		{route{method: "POST", path: "/v2/items/{item-name}/start"}, "Start an item", nil, 0},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.route.key(), func(t *testing.T) {
+			op, ok := spec.operation(testCase.route)
+			if !ok {
+				t.Fatalf("operation returned no operation")
+			}
+			if op.Summary != testCase.summary || !reflect.DeepEqual(op.Response, testCase.response) || op.Status != testCase.status {
+				t.Errorf("operation returned unexpected operation %+v", op)
+			}
+		})
+	}
+
+	// The Model documents the request body of a resource without json-schema
+	if op, _ := spec.operation(route{method: "POST", path: "/v2/items"}); !reflect.DeepEqual(op.Request, testItem{}) {
+		t.Errorf("operation returned unexpected request %v", op.Request)
+	}
+	if _, ok := spec.operation(route{method: "POST", path: "/v2/items/{item-name}/stop"}); ok {
+		t.Errorf("operation returned an operation for a route without a resource")
+	}
+}
+
+func TestDocument(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	schema := filepath.Join(dir, "item.json")
+	ioutil.WriteFile(schema, []byte(`{"$schema": "http://json-schema.org/schema#", "type": "object", "required": ["name"]}`), 0644)
+
+	spec := Spec{Title: "Test", Version: "v2", Resources: map[string]Resource{
+		"items": {Name: "item", Schema: schema, Model: testItem{}, Queries: []string{"limit"}},
+	}}
+	router := testRouter()
+	router.HandleFunc("/items/{item-name}/start", func(w http.ResponseWriter, r *http.Request) {}).Methods("POST")
+	router.HandleFunc("/openapi.json", spec.Handler(router)).Methods("GET")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/v2/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Handler returned %d", rec.Code)
+	}
+	var doc struct {
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	json.NewDecoder(rec.Body).Decode(&doc)
+
+	post := doc.Paths["/v2/items"]["post"]
+	if post["summary"] != "Create an item" || post["requestBody"] == nil || post["responses"].(map[string]interface{})["201"] == nil {
+		t.Errorf("Document returned unexpected operation %v", post)
+	}
+	if _, ok := doc.Components.Schemas["item"]["$schema"]; ok || doc.Components.Schemas["item"]["type"] != "object" {
+		t.Errorf("Document returned unexpected json-schema %v", doc.Components.Schemas["item"])
+	}
+
+	props := doc.Components.Schemas["testItem"]["properties"].(map[string]interface{})
+	var names []string
+	for n := range props {
+		names = append(names, n)
+	}
+	for _, n := range []string{"name", "description", "labels", "created", "parent"} {
+		if props[n] == nil {
+			t.Errorf("Document is missing the property %s of the model, got %v", n, names)
+		}
+	}
+	if props["Internal"] != nil {
+		t.Errorf("Document returned the ignored field of the model")
+	}
+
+	params := doc.Paths["/v2/items"]["get"]["parameters"].([]interface{})
+	expected := []interface{}{
+		map[string]interface{}{"name": "label", "in": "query", "required": false, "schema": map[string]interface{}{"type": "string"}},
+		map[string]interface{}{"name": "limit", "in": "query", "required": false, "schema": map[string]interface{}{"type": "string"}},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("Document returned unexpected parameters %v", params)
+	}
+	if doc.Paths["/v2/items/{item-name}"]["delete"]["summary"] != "Delete an item" {
+		t.Errorf("Document returned unexpected operation %v", doc.Paths["/v2/items/{item-name}"]["delete"])
+	}
+	if doc.Paths["/v2/items/{item-name}/start"]["post"] == nil {
+		t.Errorf("Document is missing the route without an operation")
+	}
+}
@@ -15,6 +15,10 @@ package rpc
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,10 +26,13 @@ import (
 
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
 	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/metrics"
 	pkgerrors "github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/testdata"
 )
 
@@ -41,10 +48,54 @@ type InstallAppRequest interface {
 type InstallAppResponse interface {
 }
 
+// Defaults used for the call settings which are not set for a controller
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultRetryBackoff = time.Second
+	maxRetryBackoff     = 30 * time.Second
+)
+
+// TLSOptions holds the mutual TLS settings for the connection to a controller.
+// When they are not set, the global grpc-enable-tls configuration applies.
+type TLSOptions struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// ConnOptions holds the per controller call and transport settings
+type ConnOptions struct {
+	// Timeout is the deadline of a single call attempt
+	Timeout time.Duration
+	// Retries is the number of times a call which failed because the
+	// controller was unavailable is attempted again
+	Retries int
+	// RetryBackoff is the wait before the first retry, it doubles on
+	// every further retry
+	RetryBackoff time.Duration
+	TLS          *TLSOptions
+}
+
+// withDefaults returns the options with the unset values defaulted
+func (o ConnOptions) withDefaults() ConnOptions {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Retries < 0 {
+		o.Retries = 0
+	}
+	if o.RetryBackoff <= 0 {
+		o.RetryBackoff = DefaultRetryBackoff
+	}
+	return o
+}
+
 type rpcInfo struct {
 	conn *grpc.ClientConn
 	host string
 	port int
+	opts ConnOptions
 }
 
 var mutex = &sync.Mutex{}
@@ -73,12 +124,32 @@ func GetRpcConn(name string) *grpc.ClientConn {
 	return nil
 }
 
+// GetRpcConnOptions returns the call settings of the connection for a given
+// controller, with defaults for the settings which are not set.
+func GetRpcConnOptions(name string) ConnOptions {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if val, ok := rpcConnections[name]; ok {
+		return val.opts.withDefaults()
+	}
+	return ConnOptions{}.withDefaults()
+}
+
+// UpdateRpcConn creates or updates the connection for a given controller
+// with the default call settings.
 func UpdateRpcConn(name, host string, port int) {
+	UpdateRpcConnWithOptions(name, host, port, ConnOptions{})
+}
+
+// UpdateRpcConnWithOptions creates or updates the connection for a given
+// controller. The cached connection is replaced when the host, port or TLS
+// settings differ from the ones it was created with.
+func UpdateRpcConnWithOptions(name, host string, port int, opts ConnOptions) {
 	mutex.Lock()
 	defer mutex.Unlock()
 	if val, ok := rpcConnections[name]; ok {
 		// close connection if mismatch in update vs cached connect info
-		if val.host != host || val.port != port {
+		if val.host != host || val.port != port || !reflect.DeepEqual(val.opts.TLS, opts.TLS) {
 			log.Info("Closing RPC connection due to mismatch", log.Fields{
 				"Server":   name,
 				"Old Host": val.host,
@@ -99,11 +170,13 @@ func UpdateRpcConn(name, host string, port int) {
 			if val.conn.GetState() == connectivity.TransientFailure {
 				val.conn.ResetConnectBackoff()
 			}
+			val.opts = opts
+			rpcConnections[name] = val
 			return
 		}
 	}
 	// connect and update rpcConnection list - for new or modified connection
-	conn, err := createClientConn(host, port)
+	conn, err := createClientConn(name, host, port, opts.TLS)
 	if err != nil {
 		log.Warn("Failed to create RPC Client connection", log.Fields{
 			"Error": err,
@@ -117,6 +190,7 @@ func UpdateRpcConn(name, host string, port int) {
 			conn: conn,
 			host: host,
 			port: port,
+			opts: opts,
 		}
 	}
 }
@@ -156,8 +230,87 @@ func RemoveRpcConn(name string) {
 	mutex.Unlock()
 }
 
-// createConn creates the Rpc Client Connection
-func createClientConn(Host string, Port int) (*grpc.ClientConn, error) {
+// isRetryable reports whether a failed call may be attempted again. Only
+// calls which did not reach the controller are retried.
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+// InvokeWithRetry makes a call to the given controller using the call
+// settings of its connection. Every attempt gets its own deadline and the
+// connection is looked up again before each attempt so that a controller
+// which was re-registered in the meantime is reached at its new address.
+func InvokeWithRetry(name string, call func(ctx context.Context, conn *grpc.ClientConn) error) error {
+	opts := GetRpcConnOptions(name)
+	backoff := opts.RetryBackoff
+
+	var err error
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		if attempt > 0 {
+			log.Warn("Retrying RPC call", log.Fields{
+				"Controller": name,
+				"Attempt":    attempt,
+				"Backoff":    backoff.String(),
+				"Error":      err,
+			})
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > maxRetryBackoff {
+				backoff = maxRetryBackoff
+			}
+		}
+
+		conn := GetRpcConn(name)
+		if conn == nil {
+			return pkgerrors.Errorf("No RPC connection to controller: %v", name)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+		err = call(ctx, conn)
+		cancel()
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// tlsCredentials builds the transport credentials for a controller with its
+// own TLS settings. A client certificate is presented when one is configured.
+func tlsCredentials(t *TLSOptions) (credentials.TransportCredentials, error) {
+	tlsConfig := &tls.Config{
+		ServerName: t.ServerName,
+	}
+
+	if t.CAFile != "" {
+		ca, err := ioutil.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Reading CA file")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, pkgerrors.Errorf("No certificates found in CA file %v", t.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Loading client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// createConn creates the Rpc Client Connection to the named controller
+func createClientConn(name string, Host string, Port int, tlsOpts *TLSOptions) (*grpc.ClientConn, error) {
 	var err error
 	var tls bool
 	var opts []grpc.DialOption
@@ -173,7 +326,13 @@ func createClientConn(Host string, Port int) (*grpc.ClientConn, error) {
 
 	caFile := config.GetConfiguration().GrpcCAFile
 
-	if tls {
+	if tlsOpts != nil {
+		creds, err := tlsCredentials(tlsOpts)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Failed to create TLS credentials")
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else if tls {
 		if caFile == "" {
 			caFile = testdata.Path("ca.pem")
 		}
@@ -189,6 +348,7 @@ func createClientConn(Host string, Port int) (*grpc.ClientConn, error) {
 	} else {
 		opts = append(opts, grpc.WithInsecure())
 	}
+	opts = append(opts, grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor(name)))
 
 	conn, err := grpc.Dial(serverAddr, opts...)
 	if err != nil {
@@ -17,9 +17,15 @@
 package rpc
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // resetConnections clears the package-level connection map so each test starts
@@ -130,7 +136,7 @@ func TestCreateClientConn_Insecure(t *testing.T) {
 	// option path is taken and a lazy connection is returned without error.
 	config.SetConfigValue("GrpcEnableTLS", "disable")
 
-	conn, err := createClientConn("localhost", 9031)
+	conn, err := createClientConn("test", "localhost", 9031, nil)
 	if err != nil {
 		t.Fatalf("createClientConn err: %v", err)
 	}
@@ -148,7 +154,7 @@ func TestCreateClientConn_TLSFallsBackToTestdataCA(t *testing.T) {
 	config.SetConfigValue("GrpcCAFile", "")
 	defer config.SetConfigValue("GrpcEnableTLS", "disable")
 
-	conn, err := createClientConn("localhost", 9031)
+	conn, err := createClientConn("test", "localhost", 9031, nil)
 	if err != nil {
 		t.Fatalf("createClientConn (tls) err: %v", err)
 	}
@@ -157,3 +163,136 @@ func TestCreateClientConn_TLSFallsBackToTestdataCA(t *testing.T) {
 	}
 	_ = conn.Close()
 }
+
+func TestUpdateRpcConnWithOptions_CallSettingsKeepConnection(t *testing.T) {
+	resetConnections()
+	UpdateRpcConn("rsync", "localhost", 9031)
+	first := GetRpcConn("rsync")
+
+	// Changing only the call settings must not redial.
+	UpdateRpcConnWithOptions("rsync", "localhost", 9031, ConnOptions{
+		Timeout: 3 * time.Second,
+		Retries: 2,
+	})
+	second := GetRpcConn("rsync")
+
+	if first != second {
+		t.Error("expected the same *grpc.ClientConn when only call settings change")
+	}
+	opts := GetRpcConnOptions("rsync")
+	if opts.Timeout != 3*time.Second || opts.Retries != 2 || opts.RetryBackoff != DefaultRetryBackoff {
+		t.Errorf("GetRpcConnOptions = %+v, want updated settings with default backoff", opts)
+	}
+}
+
+func TestUpdateRpcConnWithOptions_ChangedTLSReplacesConnection(t *testing.T) {
+	resetConnections()
+	UpdateRpcConn("rsync", "localhost", 9031)
+	first := GetRpcConn("rsync")
+
+	UpdateRpcConnWithOptions("rsync", "localhost", 9031, ConnOptions{
+		TLS: &TLSOptions{ServerName: "rsync.emco"},
+	})
+	second := GetRpcConn("rsync")
+
+	if second == nil || first == second {
+		t.Error("expected a new *grpc.ClientConn after TLS settings change")
+	}
+}
+
+func TestUpdateRpcConnWithOptions_BadTLSFiles(t *testing.T) {
+	resetConnections()
+	UpdateRpcConnWithOptions("rsync", "localhost", 9031, ConnOptions{
+		TLS: &TLSOptions{CAFile: "/does/not/exist/ca.pem"},
+	})
+	if GetRpcConn("rsync") != nil {
+		t.Error("expected no connection when the CA file cannot be read")
+	}
+}
+
+func TestGetRpcConnOptions_Defaults(t *testing.T) {
+	resetConnections()
+	opts := GetRpcConnOptions("does-not-exist")
+	if opts.Timeout != DefaultTimeout || opts.Retries != 0 || opts.RetryBackoff != DefaultRetryBackoff {
+		t.Errorf("GetRpcConnOptions(unknown) = %+v, want defaults", opts)
+	}
+}
+
+func TestInvokeWithRetry(t *testing.T) {
+	testCases := []struct {
+		label         string
+		errs          []error
+		retries       int
+		expectedCalls int
+		expectError   bool
+	}{
+		{
+			label:         "Success on first attempt",
+			errs:          []error{nil},
+			retries:       2,
+			expectedCalls: 1,
+		},
+		{
+			label:         "Success after unavailable",
+			errs:          []error{status.Error(codes.Unavailable, "down"), nil},
+			retries:       2,
+			expectedCalls: 2,
+		},
+		{
+			label: "Retries exhausted",
+			errs: []error{
+				status.Error(codes.Unavailable, "down"),
+				status.Error(codes.Unavailable, "down"),
+				status.Error(codes.Unavailable, "down"),
+			},
+			retries:       2,
+			expectedCalls: 3,
+			expectError:   true,
+		},
+		{
+			label:         "Other errors are not retried",
+			errs:          []error{pkgerrors.New("bad request")},
+			retries:       2,
+			expectedCalls: 1,
+			expectError:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			resetConnections()
+			UpdateRpcConnWithOptions("rsync", "localhost", 9031, ConnOptions{
+				Timeout:      time.Second,
+				Retries:      testCase.retries,
+				RetryBackoff: time.Millisecond,
+			})
+
+			calls := 0
+			err := InvokeWithRetry("rsync", func(ctx context.Context, conn *grpc.ClientConn) error {
+				if _, ok := ctx.Deadline(); !ok {
+					t.Error("expected the call context to carry a deadline")
+				}
+				err := testCase.errs[calls]
+				calls++
+				return err
+			})
+			if calls != testCase.expectedCalls {
+				t.Errorf("InvokeWithRetry made %d calls, want %d", calls, testCase.expectedCalls)
+			}
+			if (err != nil) != testCase.expectError {
+				t.Errorf("InvokeWithRetry returned %v, expectError %v", err, testCase.expectError)
+			}
+		})
+	}
+}
+
+func TestInvokeWithRetry_NoConnection(t *testing.T) {
+	resetConnections()
+	err := InvokeWithRetry("does-not-exist", func(ctx context.Context, conn *grpc.ClientConn) error {
+		t.Error("call must not be made without a connection")
+		return nil
+	})
+	if err == nil {
+		t.Error("expected an error for an unknown controller")
+	}
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// KeyFile is the content of a key file. Keys holds the base64 encoded
+// 256 bit key encryption keys by id, and Primary is the id of the key the
+// new data keys are encrypted with.
+//
+// A key is rotated by adding a new key, making it the primary key and
+// re-encrypting the stored secrets. The former key can be removed once no
+// secret is encrypted with it anymore.
+type KeyFile struct {
+	Primary string            `json:"primary"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// keyRing holds the decoded keys of a key file
+type keyRing struct {
+	primary string
+	keys    map[string][]byte
+}
+
+func newKeyRing(kf KeyFile) (keyRing, error) {
+	r := keyRing{primary: kf.Primary, keys: map[string][]byte{}}
+	for id, k := range kf.Keys {
+		if id == "" || strings.Contains(id, ":") {
+			return keyRing{}, pkgerrors.Errorf("Invalid key id: %q", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(k)
+		if err != nil {
+			return keyRing{}, pkgerrors.Wrapf(err, "Decoding key %s", id)
+		}
+		if len(key) != dataKeySize {
+			return keyRing{}, pkgerrors.Errorf("Key %s is not a 256 bit key", id)
+		}
+		r.keys[id] = key
+	}
+	if _, ok := r.keys[r.primary]; !ok {
+		return keyRing{}, pkgerrors.Errorf("Primary key %q is not in the key file", r.primary)
+	}
+	return r, nil
+}
+
+func (r keyRing) keyFile() KeyFile {
+	kf := KeyFile{Primary: r.primary, Keys: map[string]string{}}
+	for id, k := range r.keys {
+		kf.Keys[id] = base64.StdEncoding.EncodeToString(k)
+	}
+	return kf
+}
+
+func (r keyRing) encrypt(plaintext []byte) (string, []byte, error) {
+	c, err := seal(r.keys[r.primary], plaintext)
+	return r.primary, c, err
+}
+
+func (r keyRing) decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	key, ok := r.keys[keyID]
+	if !ok {
+		return nil, pkgerrors.Errorf("Unknown key: %s", keyID)
+	}
+	return open(key, ciphertext)
+}
+
+func readKeyFile(file string) (KeyFile, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return KeyFile{}, pkgerrors.Wrap(err, "Reading key file")
+	}
+	kf := KeyFile{}
+	err = json.Unmarshal(b, &kf)
+	if err != nil {
+		return KeyFile{}, pkgerrors.Wrap(err, "Parsing key file")
+	}
+	return kf, nil
+}
+
+func newKey() ([]byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, pkgerrors.Wrap(err, "Generating key")
+	}
+	return key, nil
+}
+
+// loadKeyRing reads the key ring of a key file
+func loadKeyRing(file string) (keyRing, error) {
+	kf, err := readKeyFile(file)
+	if err != nil {
+		return keyRing{}, err
+	}
+	return newKeyRing(kf)
+}
+
+// FileKeyProvider is a KeyProvider with the keys of a local key file, which
+// is managed by the operator. The file is read again when a secret is
+// encrypted with a key it does not know, as the services sharing the file
+// see the keys added by a rotation that way.
+type FileKeyProvider struct {
+	mutex sync.Mutex
+	file  string
+	ring  keyRing
+}
+
+// NewFileKeyProvider returns the FileKeyProvider of a key file
+func NewFileKeyProvider(file string) (*FileKeyProvider, error) {
+	r, err := loadKeyRing(file)
+	if err != nil {
+		return nil, err
+	}
+	return &FileKeyProvider{file: file, ring: r}, nil
+}
+
+func (p *FileKeyProvider) PrimaryKeyID() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.ring.primary
+}
+
+func (p *FileKeyProvider) Encrypt(plaintext []byte) (string, []byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.ring.encrypt(plaintext)
+}
+
+func (p *FileKeyProvider) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return decryptReloading(&p.ring, p.file, keyID, ciphertext)
+}
+
+// decryptReloading decrypts with the key ring, which is loaded from the key
+// file again when it does not have the key
+func decryptReloading(r *keyRing, file, keyID string, ciphertext []byte) ([]byte, error) {
+	if _, ok := r.keys[keyID]; !ok {
+		reloaded, err := loadKeyRing(file)
+		if err == nil {
+			*r = reloaded
+		}
+	}
+	return r.decrypt(keyID, ciphertext)
+}
+
+// LocalKMS is a local stand-in for a key management service. It generates
+// its keys itself and keeps them in a key file, which is created on first
+// use. It is meant for the development and the test deployments. Like the
+// FileKeyProvider, it reads the file again for the keys it does not know.
+type LocalKMS struct {
+	mutex sync.Mutex
+	file  string
+	ring  keyRing
+}
+
+// NewLocalKMS returns the LocalKMS keeping its keys in the file
+func NewLocalKMS(file string) (*LocalKMS, error) {
+	k := &LocalKMS{file: file}
+	r, err := loadKeyRing(file)
+	if os.IsNotExist(pkgerrors.Cause(err)) {
+		k.ring = keyRing{keys: map[string][]byte{}}
+		if _, err := k.RotateKey(); err != nil {
+			return nil, err
+		}
+		return k, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	k.ring = r
+	return k, nil
+}
+
+func (k *LocalKMS) PrimaryKeyID() string {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return k.ring.primary
+}
+
+func (k *LocalKMS) Encrypt(plaintext []byte) (string, []byte, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return k.ring.encrypt(plaintext)
+}
+
+func (k *LocalKMS) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return decryptReloading(&k.ring, k.file, keyID, ciphertext)
+}
+
+// RotateKey generates a new primary key and saves it to the key file. The
+// former keys are kept to decrypt the secrets encrypted with them.
+func (k *LocalKMS) RotateKey() (string, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	key, err := newKey()
+	if err != nil {
+		return "", err
+	}
+	var id string
+	for n := len(k.ring.keys) + 1; ; n++ {
+		id = fmt.Sprintf("v%d", n)
+		if _, ok := k.ring.keys[id]; !ok {
+			break
+		}
+	}
+
+	keys := map[string][]byte{id: key}
+	for i, v := range k.ring.keys {
+		keys[i] = v
+	}
+	r := keyRing{primary: id, keys: keys}
+	b, err := json.MarshalIndent(r.keyFile(), "", "  ")
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Marshalling key file")
+	}
+	err = ioutil.WriteFile(k.file, b, 0600)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Writing key file")
+	}
+	k.ring = r
+	return id, nil
+}
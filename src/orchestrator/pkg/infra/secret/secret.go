@@ -0,0 +1,310 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package secret implements the envelope encryption of the secret fields of
+// the records stored in the database. Each value is encrypted with its own
+// data key, and the data key is encrypted with a key encryption key of the
+// configured KeyProvider.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+	"sync"
+
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// envelopePrefix marks the encrypted values. An encrypted value is
+// "enc:v1:<key id>:<encrypted data key>:<encrypted value>".
+const envelopePrefix = "enc:v1:"
+
+// dataKeySize is the size of the AES-256 data keys
+const dataKeySize = 32
+
+// KeyProvider encrypts the data keys with its key encryption keys. It is
+// the interface to a key management service.
+type KeyProvider interface {
+	// PrimaryKeyID returns the id of the key new data keys are encrypted with
+	PrimaryKeyID() string
+	// Encrypt encrypts with the primary key, and returns its id
+	Encrypt(plaintext []byte) (keyID string, ciphertext []byte, err error)
+	// Decrypt decrypts with the key of the id
+	Decrypt(keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// KeyRotator is implemented by the KeyProviders which can create a new
+// primary key themselves
+type KeyRotator interface {
+	// RotateKey creates a new primary key and returns its id
+	RotateKey() (string, error)
+}
+
+// Config selects the KeyProvider of a service: the kind of NewKeyProvider
+// and the file of its keys
+type Config struct {
+	KeyProvider string
+	KeyFile     string
+}
+
+var providerMutex = &sync.Mutex{}
+var providerConfig Config
+var provider KeyProvider
+var providerLoaded bool
+
+// NewKeyProvider returns the KeyProvider of a kind: "file" for a local key
+// file, "local-kms" for the local stand-in of a key management service, or
+// "" when the secrets are not encrypted
+func NewKeyProvider(kind, keyFile string) (KeyProvider, error) {
+	switch kind {
+	case "":
+		return nil, nil
+	case "file":
+		return NewFileKeyProvider(keyFile)
+	case "local-kms":
+		return NewLocalKMS(keyFile)
+	}
+	return nil, pkgerrors.Errorf("Unknown secret key provider: %s", kind)
+}
+
+// Configure sets the configuration of the KeyProvider, which is loaded when
+// a secret is first encrypted or decrypted. The services call it at startup
+// with their configuration, the secrets are not encrypted without it.
+func Configure(c Config) {
+	if c.KeyProvider == "" {
+		log.Warn("WARNING :: No secret key provider is configured, the secrets are stored in plaintext", log.Fields{})
+	}
+	providerMutex.Lock()
+	defer providerMutex.Unlock()
+	providerConfig = c
+	provider = nil
+	providerLoaded = false
+}
+
+// SetKeyProvider replaces the configured KeyProvider. A nil KeyProvider
+// disables the encryption of the new secrets.
+func SetKeyProvider(p KeyProvider) {
+	providerMutex.Lock()
+	defer providerMutex.Unlock()
+	provider = p
+	providerLoaded = true
+}
+
+// getKeyProvider returns the KeyProvider of the configuration
+func getKeyProvider() (KeyProvider, error) {
+	providerMutex.Lock()
+	defer providerMutex.Unlock()
+	if !providerLoaded {
+		p, err := NewKeyProvider(providerConfig.KeyProvider, providerConfig.KeyFile)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Loading secret key provider")
+		}
+		provider = p
+		providerLoaded = true
+	}
+	return provider, nil
+}
+
+// IsEncrypted tells whether a value is encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+// Encrypt encrypts a value with a new data key. The value is returned
+// unchanged when no KeyProvider is configured.
+func Encrypt(value string) (string, error) {
+	p, err := getKeyProvider()
+	if err != nil {
+		return "", err
+	}
+	if p == nil || value == "" {
+		return value, nil
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", pkgerrors.Wrap(err, "Generating data key")
+	}
+	sealed, err := seal(dataKey, []byte(value))
+	if err != nil {
+		return "", err
+	}
+	keyID, wrapped, err := p.Encrypt(dataKey)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Encrypting data key")
+	}
+
+	return envelopePrefix + keyID + ":" +
+		base64.StdEncoding.EncodeToString(wrapped) + ":" +
+		base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// parse returns the key id, the encrypted data key and the encrypted value
+// of an encrypted value
+func parse(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, envelopePrefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, pkgerrors.New("Invalid encrypted value")
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, pkgerrors.Wrap(err, "Decoding data key")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, pkgerrors.Wrap(err, "Decoding encrypted value")
+	}
+	return parts[0], wrapped, sealed, nil
+}
+
+// Decrypt decrypts an encrypted value. The values stored before the
+// encryption was enabled are returned unchanged.
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	keyID, wrapped, sealed, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	p, err := getKeyProvider()
+	if err != nil {
+		return "", err
+	}
+	if p == nil {
+		return "", pkgerrors.New("No secret key provider configured to decrypt the value")
+	}
+	dataKey, err := p.Decrypt(keyID, wrapped)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Decrypting data key")
+	}
+	plaintext, err := open(dataKey, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Reencrypt returns a value encrypted with the primary key of the
+// KeyProvider, and whether it differs from the given value. The values which
+// are not encrypted yet are encrypted.
+func Reencrypt(value string) (string, bool, error) {
+	p, err := getKeyProvider()
+	if err != nil {
+		return "", false, err
+	}
+	if p == nil || value == "" {
+		return value, false, nil
+	}
+	if IsEncrypted(value) {
+		keyID, _, _, err := parse(value)
+		if err != nil {
+			return "", false, err
+		}
+		if keyID == p.PrimaryKeyID() {
+			return value, false, nil
+		}
+	}
+
+	plaintext, err := Decrypt(value)
+	if err != nil {
+		return "", false, err
+	}
+	encrypted, err := Encrypt(plaintext)
+	if err != nil {
+		return "", false, err
+	}
+	return encrypted, true, nil
+}
+
+// RotateKey creates a new primary key with the configured KeyProvider
+func RotateKey() (string, error) {
+	p, err := getKeyProvider()
+	if err != nil {
+		return "", err
+	}
+	r, ok := p.(KeyRotator)
+	if !ok {
+		return "", pkgerrors.New("The secret key provider does not rotate its keys, add a new primary key to the key file instead")
+	}
+	return r.RotateKey()
+}
+
+// seal encrypts the plaintext with AES-GCM. The nonce is prepended to the
+// ciphertext.
+func seal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Creating cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Creating cipher")
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, pkgerrors.Wrap(err, "Generating nonce")
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts a ciphertext returned by seal
+func open(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Creating cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Creating cipher")
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, pkgerrors.New("Invalid ciphertext")
+	}
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Decrypting")
+	}
+	return plaintext, nil
+}
+
+// Migrate is the secret migration command of the services. It creates a new
+// primary key first when rotate is set, then runs reencrypt, which
+// re-encrypts the secrets of the service with Reencrypt and returns their
+// number.
+func Migrate(rotate bool, reencrypt func() (int, error)) (int, error) {
+	p, err := getKeyProvider()
+	if err != nil {
+		return 0, err
+	}
+	if p == nil {
+		return 0, pkgerrors.New("No secret key provider configured")
+	}
+	if rotate {
+		_, err := RotateKey()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return reencrypt()
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDisabled(t *testing.T) {
+	SetKeyProvider(nil)
+	v, err := Encrypt("kubeconfig")
+	if err != nil || v != "kubeconfig" {
+		t.Fatalf("Expected the value unchanged without a key provider, got %s %v", v, err)
+	}
+	v, err = Decrypt("kubeconfig")
+	if err != nil || v != "kubeconfig" {
+		t.Fatalf("Expected the value unchanged without a key provider, got %s %v", v, err)
+	}
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "keys.json")
+	key := func(b byte) string {
+		return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+	}
+	write := func(kf KeyFile) {
+		b, _ := json.Marshal(kf)
+		ioutil.WriteFile(file, b, 0600)
+	}
+
+	write(KeyFile{Primary: "k1", Keys: map[string]string{"k1": key('a')}})
+	p, err := NewFileKeyProvider(file)
+	if err != nil {
+		t.Fatalf("NewFileKeyProvider returned an error: %s", err)
+	}
+	SetKeyProvider(p)
+	defer SetKeyProvider(nil)
+
+	encrypted, err := Encrypt("kubeconfig")
+	if err != nil {
+		t.Fatalf("Encrypt returned an error: %s", err)
+	}
+	if !IsEncrypted(encrypted) || strings.Contains(encrypted, "kubeconfig") || !strings.HasPrefix(encrypted, "enc:v1:k1:") {
+		t.Fatalf("Unexpected encrypted value %s", encrypted)
+	}
+	decrypted, err := Decrypt(encrypted)
+	if err != nil || decrypted != "kubeconfig" {
+		t.Fatalf("Expected the decrypted value, got %s %v", decrypted, err)
+	}
+	if _, changed, _ := Reencrypt(encrypted); changed {
+		t.Errorf("Expected the value encrypted with the primary key to be kept")
+	}
+	if _, err := RotateKey(); err == nil {
+		t.Errorf("Expected the file key provider not to rotate its keys")
+	}
+
+	// Rotate the key in the key file. The key file is read again for the
+	// values encrypted with the keys the provider does not know.
+	write(KeyFile{Primary: "k2", Keys: map[string]string{"k1": key('a'), "k2": key('b')}})
+	rotated, err := NewFileKeyProvider(file)
+	if err != nil {
+		t.Fatalf("NewFileKeyProvider returned an error: %s", err)
+	}
+	SetKeyProvider(rotated)
+	reencrypted, changed, err := Reencrypt(encrypted)
+	if err != nil || !changed || !strings.HasPrefix(reencrypted, "enc:v1:k2:") {
+		t.Fatalf("Expected the value to be re-encrypted with k2, got %s %v %v", reencrypted, changed, err)
+	}
+	decrypted, err = Decrypt(reencrypted)
+	if err != nil || decrypted != "kubeconfig" {
+		t.Fatalf("Expected the decrypted value, got %s %v", decrypted, err)
+	}
+	SetKeyProvider(p)
+	decrypted, err = Decrypt(reencrypted)
+	if err != nil || decrypted != "kubeconfig" || p.PrimaryKeyID() != "k2" {
+		t.Fatalf("Expected the key file to be read again, got %s %v", decrypted, err)
+	}
+
+	// Values stored before the encryption was enabled
+	reencrypted, changed, err = Reencrypt("plain")
+	if err != nil || !changed || !IsEncrypted(reencrypted) {
+		t.Errorf("Expected the plain value to be encrypted, got %s %v %v", reencrypted, changed, err)
+	}
+
+	for _, kf := range []KeyFile{
+		{Primary: "k3", Keys: map[string]string{"k1": key('a')}},
+		{Primary: "k1", Keys: map[string]string{"k1": base64.StdEncoding.EncodeToString([]byte("short"))}},
+		{Primary: "k:1", Keys: map[string]string{"k:1": key('a')}},
+	} {
+		write(kf)
+		if _, err := NewFileKeyProvider(file); err == nil {
+			t.Errorf("Expected an error for the key file %v", kf)
+		}
+	}
+}
+
+func TestLocalKMS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "keys.json")
+
+	k, err := NewKeyProvider("local-kms", file)
+	if err != nil {
+		t.Fatalf("NewKeyProvider returned an error: %s", err)
+	}
+	SetKeyProvider(k)
+	defer SetKeyProvider(nil)
+
+	encrypted, err := Encrypt("private key")
+	if err != nil {
+		t.Fatalf("Encrypt returned an error: %s", err)
+	}
+
+	n, err := Migrate(true, func() (int, error) {
+		v, changed, err := Reencrypt(encrypted)
+		if err != nil || !changed {
+			return 0, err
+		}
+		encrypted = v
+		return 1, nil
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("Expected a secret to be re-encrypted, got %d %v", n, err)
+	}
+
+	// The keys are kept in the key file
+	k, err = NewLocalKMS(file)
+	if err != nil {
+		t.Fatalf("NewLocalKMS returned an error: %s", err)
+	}
+	SetKeyProvider(k)
+	if k.PrimaryKeyID() != "v2" || !strings.HasPrefix(encrypted, "enc:v1:v2:") {
+		t.Errorf("Expected the key v2 to be primary, got %s %s", k.PrimaryKeyID(), encrypted)
+	}
+	decrypted, err := Decrypt(encrypted)
+	if err != nil || decrypted != "private key" {
+		t.Fatalf("Expected the decrypted value, got %s %v", decrypted, err)
+	}
+
+	if _, err := NewKeyProvider("vault", file); err == nil {
+		t.Errorf("Expected an error for an unknown key provider")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	Configure(Config{KeyProvider: "local-kms", KeyFile: filepath.Join(dir, "keys.json")})
+	defer Configure(Config{})
+	encrypted, err := Encrypt("token")
+	if err != nil || !IsEncrypted(encrypted) {
+		t.Fatalf("Expected the value encrypted with the configured key provider, got %s %v", encrypted, err)
+	}
+
+	Configure(Config{KeyProvider: "vault"})
+	if _, err := Encrypt("token"); err == nil {
+		t.Errorf("Expected an error for an unknown key provider")
+	}
+}
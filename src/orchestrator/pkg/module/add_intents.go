@@ -59,6 +59,7 @@ type IntentManager interface {
 	AddIntent(a Intent, p string, ca string, v string, di string) (Intent, error)
 	GetIntent(i string, p string, ca string, v string, di string) (Intent, error)
 	GetAllIntents(p, ca, v, di string) (ListOfIntents, error)
+	ListIntents(p, ca, v, di string, o db.ListOptions) (ListOfIntents, string, error)
 	GetIntentByName(i, p, ca, v, di string) (IntentSpecData, error)
 	DeleteIntent(i string, p string, ca string, v string, di string) error
 }
@@ -202,6 +203,13 @@ GetAllIntents takes in projectName, CompositeAppName, CompositeAppVersion,
 DeploymentIntentName . It returns ListOfIntents.
 */
 func (c IntentClient) GetAllIntents(p string, ca string, v string, di string) (ListOfIntents, error) {
+	l, _, err := c.ListIntents(p, ca, v, di, db.ListOptions{})
+	return l, err
+}
+
+// ListIntents returns a page of the intents of the DeploymentIntentGroup,
+// and the continue token of the next page
+func (c IntentClient) ListIntents(p string, ca string, v string, di string, o db.ListOptions) (ListOfIntents, string, error) {
 	k := IntentKey{
 		Name:                  "",
 		Project:               p,
@@ -210,9 +218,9 @@ func (c IntentClient) GetAllIntents(p string, ca string, v string, di string) (L
 		DeploymentIntentGroup: di,
 	}
 
-	result, err := db.DBconn.Find(c.storeName, k, c.tagMetaData)
+	result, next, err := findPage(c.storeName, k, c.tagMetaData, o, map[string]string{"name": "intentname"})
 	if err != nil {
-		return ListOfIntents{}, pkgerrors.Wrap(err, "Get AppIntent error")
+		return ListOfIntents{}, "", pkgerrors.Wrap(err, "Get AppIntent error")
 	}
 	var a Intent
 	var listOfMapOfIntents []map[string]string
@@ -222,13 +230,13 @@ func (c IntentClient) GetAllIntents(p string, ca string, v string, di string) (L
 			a = Intent{}
 			err = db.DBconn.Unmarshal(result[i], &a)
 			if err != nil {
-				return ListOfIntents{}, pkgerrors.Wrap(err, "Unmarshalling Intent")
+				return ListOfIntents{}, "", pkgerrors.Wrap(err, "Unmarshalling Intent")
 			}
 			listOfMapOfIntents = append(listOfMapOfIntents, a.Spec.Intent)
 		}
-		return ListOfIntents{listOfMapOfIntents}, nil
+		return ListOfIntents{listOfMapOfIntents}, next, nil
 	}
-	return ListOfIntents{}, err
+	return ListOfIntents{}, next, err
 }
 
 // DeleteIntent deletes a given intent tied to project, composite app and deployment intent group
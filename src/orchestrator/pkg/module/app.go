@@ -24,9 +24,39 @@ import (
 	pkgerrors "github.com/pkg/errors"
 )
 
+// Supported formats of the app content
+const (
+	AppFormatHelm      = "helm"
+	AppFormatManifests = "manifests"
+	AppFormatKustomize = "kustomize"
+)
+
 // App contains metadata for Apps
 type App struct {
 	Metadata AppMetaData `json:"metadata"`
+	Spec     AppSpec     `json:"spec,omitempty"`
+}
+
+//AppSpec contains the format of the app content. Apps without a format
+//are Helm charts. Apps with a source reference their content instead of
+//uploading it
+type AppSpec struct {
+	Format string     `json:"format,omitempty"`
+	Source *AppSource `json:"source,omitempty"`
+}
+
+//AppSource references the chart of an app in a Helm repository, with
+//url, chart and version, or in an OCI registry, with reference. The
+//credential is the name of a RepoCredential of the project. The digest
+//pins the chart on the first instantiation, if it is not given
+type AppSource struct {
+	Type       string `json:"type"`
+	URL        string `json:"url,omitempty"`
+	Chart      string `json:"chart,omitempty"`
+	Version    string `json:"version,omitempty"`
+	Reference  string `json:"reference,omitempty"`
+	Credential string `json:"credential,omitempty"`
+	Digest     string `json:"digest,omitempty"`
 }
 
 //AppMetaData contains the parameters needed for Apps
@@ -66,6 +96,7 @@ type AppManager interface {
 	GetApp(name string, p string, cN string, cV string) (App, error)
 	GetAppContent(name string, p string, cN string, cV string) (AppContent, error)
 	GetApps(p string, cN string, cV string) ([]App, error)
+	ListApps(p string, cN string, cV string, o db.ListOptions) ([]App, string, error)
 	DeleteApp(name string, p string, cN string, cV string) error
 }
 
@@ -186,6 +217,13 @@ func (v *AppClient) GetAppContent(name string, p string, cN string, cV string) (
 
 // GetApps returns all Apps for given composite App
 func (v *AppClient) GetApps(project, compositeApp, compositeAppVersion string) ([]App, error) {
+	resp, _, err := v.ListApps(project, compositeApp, compositeAppVersion, db.ListOptions{})
+	return resp, err
+}
+
+// ListApps returns a page of the Apps of given composite App, and the
+// continue token of the next page
+func (v *AppClient) ListApps(project, compositeApp, compositeAppVersion string, o db.ListOptions) ([]App, string, error) {
 
 	key := AppKey{
 		App:                 "",
@@ -195,21 +233,21 @@ func (v *AppClient) GetApps(project, compositeApp, compositeAppVersion string) (
 	}
 
 	var resp []App
-	values, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	values, next, err := findPage(v.storeName, key, v.tagMeta, o, map[string]string{"name": "app"})
 	if err != nil {
-		return []App{}, pkgerrors.Wrap(err, "Get Apps")
+		return []App{}, "", pkgerrors.Wrap(err, "Get Apps")
 	}
 
 	for _, value := range values {
 		a := App{}
 		err = db.DBconn.Unmarshal(value, &a)
 		if err != nil {
-			return []App{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+			return []App{}, "", pkgerrors.Wrap(err, "Unmarshaling Value")
 		}
 		resp = append(resp, a)
 	}
 
-	return resp, nil
+	return resp, next, nil
 }
 
 // DeleteApp deletes the  App from database
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/utils/chartsource"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// newChartFetcher returns the fetcher of the charts that apps reference,
+// whose requests time out. It is a variable so that the tests can fetch
+// from a local server
+var newChartFetcher = func() *chartsource.Fetcher {
+	conf := config.GetConfiguration()
+	return chartsource.NewFetcher(nil, conf.ChartCacheDir).TrustTokenHosts(conf.ChartTokenHosts)
+}
+
+// toChartSource converts the source of an app
+func (s AppSource) toChartSource() chartsource.Source {
+	return chartsource.Source{
+		Type:      s.Type,
+		URL:       s.URL,
+		Chart:     s.Chart,
+		Version:   s.Version,
+		Reference: s.Reference,
+		Digest:    s.Digest,
+	}
+}
+
+// ValidateAppSource checks that the source of an app is complete
+func ValidateAppSource(s AppSource) error {
+	return chartsource.Validate(s.toChartSource())
+}
+
+// getAppContent returns the content of the app. The chart of an app with a
+// source is fetched, and its digest is pinned in the app the first time so
+// that later instantiations render the same chart.
+func getAppContent(app App, p, ca, v string) ([]byte, error) {
+	content, digest, err := fetchAppContent(app, p, ca, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if app.Spec.Source != nil && app.Spec.Source.Digest == "" {
+		err = NewAppClient().pinAppDigest(app, p, ca, v, digest)
+		if err != nil {
+			return nil, err
+		}
+		log.Info(":: Pinned the chart of the app ::", log.Fields{"appName": app.Metadata.Name, "digest": digest})
+	}
+	return content, nil
+}
+
+// fetchAppContent returns the content of the app and, for an app with a
+// source, the digest of its chart. Unlike getAppContent it does not change
+// the app.
+func fetchAppContent(app App, p, ca, v string) ([]byte, string, error) {
+	appName := app.Metadata.Name
+
+	if app.Spec.Source == nil {
+		aC, err := NewAppClient().GetAppContent(appName, p, ca, v)
+		if err != nil {
+			return nil, "", pkgerrors.Wrap(err, fmt.Sprint("Not finding the content of app:: ", appName))
+		}
+		appContent, err := base64.StdEncoding.DecodeString(aC.FileContent)
+		if err != nil {
+			return nil, "", pkgerrors.Wrap(err, "Fail to convert to byte array")
+		}
+		return appContent, "", nil
+	}
+
+	src := *app.Spec.Source
+	var creds *chartsource.Credentials
+	if src.Credential != "" {
+		rc, err := NewRepoCredentialClient().GetRepoCredential(src.Credential, p)
+		if err != nil {
+			return nil, "", pkgerrors.Wrapf(err, "Not finding the credential %s of app %s", src.Credential, appName)
+		}
+		creds = &chartsource.Credentials{
+			Username: rc.Spec.Username,
+			Password: rc.Spec.Password,
+			Token:    rc.Spec.Token,
+		}
+	}
+
+	content, digest, err := newChartFetcher().Fetch(src.toChartSource(), creds)
+	if err != nil {
+		return nil, "", pkgerrors.Wrapf(err, "Fetching the chart of app %s", appName)
+	}
+	return content, digest, nil
+}
+
+// pinAppDigest records the digest of the chart of the app
+func (v *AppClient) pinAppDigest(app App, p, cN, cV, digest string) error {
+	key := AppKey{
+		App:                 app.Metadata.Name,
+		Project:             p,
+		CompositeApp:        cN,
+		CompositeAppVersion: cV,
+	}
+	src := *app.Spec.Source
+	src.Digest = digest
+	app.Spec.Source = &src
+	err := db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, app)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Pinning the digest of the app")
+	}
+	return nil
+}
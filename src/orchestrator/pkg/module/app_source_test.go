@@ -0,0 +1,124 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/utils/chartsource"
+)
+
+// insertRecordingDB records the last data inserted in the MockDB
+type insertRecordingDB struct {
+	*db.MockDB
+	inserted interface{}
+}
+
+func (m *insertRecordingDB) Insert(table string, key db.Key, query interface{}, tag string, data interface{}) error {
+	m.inserted = data
+	return m.MockDB.Insert(table, key, query, tag, data)
+}
+
+func (m *insertRecordingDB) InsertIfVersion(table string, key db.Key, query interface{}, tag string, data interface{}, version int64) (int64, error) {
+	v, err := m.MockDB.InsertIfVersion(table, key, query, tag, data, version)
+	if err == nil {
+		m.inserted = data
+	}
+	return v, err
+}
+
+func TestGetAppContentFromSource(t *testing.T) {
+	chart := []byte("chart content")
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/index.yaml":
+			fmt.Fprint(w, "entries:\n  mychart:\n  - version: 1.0.0\n    urls:\n    - mychart-1.0.0.tgz\n")
+		case "/mychart-1.0.0.tgz":
+			w.Write(chart)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	defer func(f func() *chartsource.Fetcher) { newChartFetcher = f }(newChartFetcher)
+	newChartFetcher = func() *chartsource.Fetcher {
+		return chartsource.NewFetcher(srv.Client(), "")
+	}
+
+	rc, _ := json.Marshal(RepoCredential{
+		Metadata: RepoCredentialMetaData{Name: "charts"},
+		Spec:     RepoCredentialSpec{Username: "user", Password: "pass"},
+	})
+	mockdb := &insertRecordingDB{
+		MockDB: &db.MockDB{
+			Items: map[string]map[string][]byte{
+				RepoCredentialKey{RepoCredential: "charts", Project: "testProject"}.String(): {
+					"repocredentialmetadata": rc,
+				},
+			},
+		},
+	}
+	db.DBconn = mockdb
+
+	app := App{
+		Metadata: AppMetaData{Name: "testApp"},
+		Spec: AppSpec{
+			Source: &AppSource{
+				Type:       chartsource.TypeHelmRepo,
+				URL:        srv.URL,
+				Chart:      "mychart",
+				Version:    "1.0.0",
+				Credential: "charts",
+			},
+		},
+	}
+	content, err := getAppContent(app, "testProject", "testCompositeApp", "v1")
+	if err != nil {
+		t.Fatalf("getAppContent returned an error (%s)", err)
+	}
+	if string(content) != string(chart) {
+		t.Fatalf("Unexpected content %q", content)
+	}
+
+	pinned, ok := mockdb.inserted.(App)
+	if !ok || pinned.Spec.Source.Digest != chartsource.Digest(chart) {
+		t.Fatalf("Expected the digest of the chart to be pinned, got %v", mockdb.inserted)
+	}
+	if app.Spec.Source.Digest != "" {
+		t.Fatalf("Pinning modified the source of the caller")
+	}
+
+	// A pinned app is not pinned again
+	mockdb.inserted = nil
+	_, err = getAppContent(pinned, "testProject", "testCompositeApp", "v1")
+	if err != nil {
+		t.Fatalf("getAppContent of the pinned app returned an error (%s)", err)
+	}
+	if mockdb.inserted != nil {
+		t.Fatalf("Unexpected update of the pinned app")
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+/*
+This file deals with the approvals and rejections of DeploymentIntentGroups
+by the approvers the approval policy of their project requires
+*/
+
+import (
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/audit"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Approval is the decision of an approver on a DeploymentIntentGroup. The
+// approver and the roles are those of the caller, the comment is given by it.
+type Approval struct {
+	Approver string   `json:"-"`
+	Roles    []string `json:"-"`
+	Comment  string   `json:"comment,omitempty"`
+}
+
+// ApprovalStatus is the progress of the approval of a DeploymentIntentGroup
+type ApprovalStatus struct {
+	State             state.StateValue      `json:"state"`
+	Approvals         int                   `json:"approvals"`
+	RequiredApprovals int                   `json:"required-approvals"`
+	Entries           []state.ApprovalEntry `json:"entries"`
+}
+
+// ApproverNotAllowedError is returned when the caller can not approve or
+// reject the DeploymentIntentGroups of the project
+type ApproverNotAllowedError struct {
+	Reason string
+}
+
+func (e *ApproverNotAllowedError) Error() string {
+	return "Approver is not allowed: " + e.Reason
+}
+
+// checkApprover verifies that the approval policy of the project lets the
+// approver decide on its DeploymentIntentGroups
+func checkApprover(ap *ApprovalPolicy, a Approval) error {
+	if ap == nil {
+		return nil
+	}
+	if a.Approver == "" || a.Approver == audit.AnonymousPrincipal {
+		return &ApproverNotAllowedError{Reason: "the approval policy requires identified approvers"}
+	}
+	if len(ap.Spec.Roles) == 0 {
+		return nil
+	}
+	for _, r := range a.Roles {
+		for _, pr := range ap.Spec.Roles {
+			if r == pr {
+				return nil
+			}
+		}
+	}
+	return &ApproverNotAllowedError{Reason: a.Approver + " holds none of the roles of the approval policy"}
+}
+
+// requiredApprovals returns the number of approvals the policy requires, one
+// when the project has no policy
+func requiredApprovals(ap *ApprovalPolicy) int {
+	if ap == nil {
+		return 1
+	}
+	return ap.Spec.RequiredApprovals
+}
+
+/*
+countApprovals returns the number of distinct approvers which approved the
+DeploymentIntentGroup since it was created, or last terminated or rejected.
+Approvals given before then, and approvals older than the expiry
+of the policy, do not count.
+*/
+func countApprovals(s state.StateInfo, ap *ApprovalPolicy, now time.Time) (int, error) {
+	var since time.Time
+	for i := len(s.Actions) - 1; i >= 0; i-- {
+		if s.Actions[i].State != state.StateEnum.Approved {
+			since = s.Actions[i].TimeStamp
+			break
+		}
+	}
+	if ap != nil {
+		expiry, err := ap.expiry()
+		if err != nil {
+			return 0, err
+		}
+		if expiry > 0 && now.Add(-expiry).After(since) {
+			since = now.Add(-expiry)
+		}
+	}
+
+	approvers := map[string]bool{}
+	for _, e := range s.Approvals {
+		if e.TimeStamp.Before(since) {
+			continue
+		}
+		switch e.Decision {
+		case state.DecisionEnum.Reject:
+			approvers = map[string]bool{}
+		case state.DecisionEnum.Approve:
+			approvers[e.Approver] = true
+		}
+	}
+	return len(approvers), nil
+}
+
+// getApprovalPolicy returns the approval policy of the project, nil when it has none
+func getApprovalPolicy(p string) (*ApprovalPolicy, error) {
+	ap, err := NewApprovalPolicyClient().findApprovalPolicy(p)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error getting the approval policy of project "+p)
+	}
+	return ap, nil
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// ApprovalPolicy is the policy the approvals of the DeploymentIntentGroups
+// of a project have to satisfy. A project has at most one.
+type ApprovalPolicy struct {
+	Metadata ApprovalPolicyMetaData `json:"metadata"`
+	Spec     ApprovalPolicySpec     `json:"spec"`
+}
+
+// ApprovalPolicyMetaData contains the parameters for creating an approval policy
+type ApprovalPolicyMetaData struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	UserData1   string `json:"userData1"`
+	UserData2   string `json:"userData2"`
+}
+
+// ApprovalPolicySpec contains the number of distinct approvers that have to
+// approve, the roles of which an approver has to hold one, and the duration
+// after which an approval no longer counts
+type ApprovalPolicySpec struct {
+	RequiredApprovals int      `json:"required-approvals"`
+	Roles             []string `json:"roles,omitempty"`
+	Expiry            string   `json:"expiry,omitempty"`
+}
+
+// ApprovalPolicyKey is the key structure that is used in the database. The
+// approval policy of a project is always stored under approvalPolicyKeyName,
+// as a key of the project alone would select all the objects of the project.
+type ApprovalPolicyKey struct {
+	ApprovalPolicy string `json:"approvalpolicy"`
+	Project        string `json:"project"`
+}
+
+const approvalPolicyKeyName = "approvalpolicy"
+
+// We will use json marshalling to convert to string to
+// preserve the underlying structure.
+func (ak ApprovalPolicyKey) String() string {
+	out, err := json.Marshal(ak)
+	if err != nil {
+		return ""
+	}
+
+	return string(out)
+}
+
+// ApprovalPolicyManager is an interface exposes the ApprovalPolicy functionality
+type ApprovalPolicyManager interface {
+	CreateApprovalPolicy(ap ApprovalPolicy, p string) (ApprovalPolicy, error)
+	GetApprovalPolicy(p string) (ApprovalPolicy, error)
+	DeleteApprovalPolicy(p string) error
+}
+
+// ApprovalPolicyClient implements the ApprovalPolicyManager
+// It will also be used to maintain some localized state
+type ApprovalPolicyClient struct {
+	storeName string
+	tagMeta   string
+}
+
+// NewApprovalPolicyClient returns an instance of the ApprovalPolicyClient
+// which implements the ApprovalPolicyManager
+func NewApprovalPolicyClient() *ApprovalPolicyClient {
+	return &ApprovalPolicyClient{
+		storeName: "orchestrator",
+		tagMeta:   "approvalpolicymetadata",
+	}
+}
+
+// expiry returns the duration after which an approval no longer counts,
+// zero when approvals do not expire
+func (ap ApprovalPolicy) expiry() (time.Duration, error) {
+	if ap.Spec.Expiry == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(ap.Spec.Expiry)
+	if err != nil {
+		return 0, pkgerrors.Wrap(err, "Invalid expiry of the approval policy")
+	}
+	return d, nil
+}
+
+// CreateApprovalPolicy creates or replaces the approval policy of the project
+func (v *ApprovalPolicyClient) CreateApprovalPolicy(ap ApprovalPolicy, p string) (ApprovalPolicy, error) {
+
+	//Construct the composite key to select the entry
+	key := ApprovalPolicyKey{
+		ApprovalPolicy: approvalPolicyKeyName,
+		Project:        p,
+	}
+
+	//Check if the Project exists
+	_, err := NewProjectClient().GetProject(p)
+	if err != nil {
+		return ApprovalPolicy{}, pkgerrors.New("Unable to find the project")
+	}
+
+	if ap.Spec.RequiredApprovals < 1 {
+		return ApprovalPolicy{}, pkgerrors.New("The approval policy has to require at least one approval")
+	}
+	_, err = ap.expiry()
+	if err != nil {
+		return ApprovalPolicy{}, err
+	}
+
+	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, ap)
+	if err != nil {
+		return ApprovalPolicy{}, pkgerrors.Wrap(err, "Creating DB Entry")
+	}
+
+	return ap, nil
+}
+
+// GetApprovalPolicy returns the approval policy of the project
+func (v *ApprovalPolicyClient) GetApprovalPolicy(p string) (ApprovalPolicy, error) {
+	ap, err := v.findApprovalPolicy(p)
+	if err != nil {
+		return ApprovalPolicy{}, err
+	}
+	if ap == nil {
+		return ApprovalPolicy{}, pkgerrors.New("Error getting ApprovalPolicy")
+	}
+	return *ap, nil
+}
+
+// findApprovalPolicy returns the approval policy of the project, nil when
+// the project has none
+func (v *ApprovalPolicyClient) findApprovalPolicy(p string) (*ApprovalPolicy, error) {
+
+	//Construct the composite key to select the entry
+	key := ApprovalPolicyKey{
+		ApprovalPolicy: approvalPolicyKeyName,
+		Project:        p,
+	}
+	value, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Get ApprovalPolicy")
+	}
+
+	//value is a byte array
+	if len(value) == 0 || value[0] == nil {
+		return nil, nil
+	}
+	ap := ApprovalPolicy{}
+	err = db.DBconn.Unmarshal(value[0], &ap)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Unmarshaling Value")
+	}
+	return &ap, nil
+}
+
+// DeleteApprovalPolicy deletes the approval policy of the project
+func (v *ApprovalPolicyClient) DeleteApprovalPolicy(p string) error {
+
+	//Construct the composite key to select the entry
+	key := ApprovalPolicyKey{
+		ApprovalPolicy: approvalPolicyKeyName,
+		Project:        p,
+	}
+	err := db.DBconn.Remove(v.storeName, key)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Delete ApprovalPolicy Entry;")
+	}
+
+	return nil
+}
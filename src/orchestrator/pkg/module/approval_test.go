@@ -0,0 +1,253 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestCountApprovals(t *testing.T) {
+	t0 := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	created := state.ActionEntry{State: state.StateEnum.Created, TimeStamp: t0}
+	approve := func(who string, at time.Duration) state.ApprovalEntry {
+		return state.ApprovalEntry{Approver: who, Decision: state.DecisionEnum.Approve, TimeStamp: t0.Add(at)}
+	}
+	reject := func(who string, at time.Duration) state.ApprovalEntry {
+		return state.ApprovalEntry{Approver: who, Decision: state.DecisionEnum.Reject, TimeStamp: t0.Add(at)}
+	}
+
+	testCases := []struct {
+		label    string
+		s        state.StateInfo
+		policy   *ApprovalPolicy
+		expected int
+	}{
+		{
+			label: "Distinct approvers",
+			s: state.StateInfo{
+				Actions:   []state.ActionEntry{created},
+				Approvals: []state.ApprovalEntry{approve("alice", time.Hour), approve("alice", 2*time.Hour), approve("bob", 3*time.Hour)},
+			},
+			expected: 2,
+		},
+		{
+			label: "Rejection resets the approvals",
+			s: state.StateInfo{
+				Actions:   []state.ActionEntry{created},
+				Approvals: []state.ApprovalEntry{approve("alice", time.Hour), reject("carol", 2*time.Hour), approve("bob", 3*time.Hour)},
+			},
+			expected: 1,
+		},
+		{
+			label: "Approvals before the termination",
+			s: state.StateInfo{
+				Actions: []state.ActionEntry{
+					created,
+					{State: state.StateEnum.Approved, TimeStamp: t0.Add(time.Hour)},
+					{State: state.StateEnum.Instantiated, TimeStamp: t0.Add(2 * time.Hour)},
+					{State: state.StateEnum.Terminated, TimeStamp: t0.Add(3 * time.Hour)},
+				},
+				Approvals: []state.ApprovalEntry{approve("alice", time.Hour), approve("bob", 4*time.Hour)},
+			},
+			expected: 1,
+		},
+		{
+			label: "Approvals of an Approved DeploymentIntentGroup",
+			s: state.StateInfo{
+				Actions: []state.ActionEntry{
+					created,
+					{State: state.StateEnum.Approved, TimeStamp: t0.Add(2 * time.Hour)},
+				},
+				Approvals: []state.ApprovalEntry{approve("alice", time.Hour), approve("bob", 2*time.Hour)},
+			},
+			expected: 2,
+		},
+		{
+			label: "Expired approvals",
+			s: state.StateInfo{
+				Actions:   []state.ActionEntry{created},
+				Approvals: []state.ApprovalEntry{approve("alice", time.Hour), approve("bob", 30*time.Hour)},
+			},
+			policy:   &ApprovalPolicy{Spec: ApprovalPolicySpec{RequiredApprovals: 2, Expiry: "24h"}},
+			expected: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			got, err := countApprovals(testCase.s, testCase.policy, t0.Add(31*time.Hour))
+			if err != nil {
+				t.Fatalf("countApprovals returned an unexpected error %s", err)
+			}
+			if got != testCase.expected {
+				t.Errorf("countApprovals returned %d, expected %d", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestCheckApprover(t *testing.T) {
+	policy := &ApprovalPolicy{Spec: ApprovalPolicySpec{RequiredApprovals: 2, Roles: []string{"release-manager"}}}
+
+	testCases := []struct {
+		label  string
+		policy *ApprovalPolicy
+		a      Approval
+		allow  bool
+	}{
+		{label: "No policy", a: Approval{Approver: "anonymous"}, allow: true},
+		{label: "Anonymous approver", policy: policy, a: Approval{Approver: "anonymous", Roles: []string{"release-manager"}}},
+		{label: "Approver with a role", policy: policy, a: Approval{Approver: "alice", Roles: []string{"ops", "release-manager"}}, allow: true},
+		{label: "Approver without a role", policy: policy, a: Approval{Approver: "bob", Roles: []string{"ops"}}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			err := checkApprover(testCase.policy, testCase.a)
+			if testCase.allow && err != nil {
+				t.Fatalf("checkApprover returned an unexpected error %s", err)
+			}
+			if !testCase.allow {
+				if _, ok := err.(*ApproverNotAllowedError); !ok {
+					t.Fatalf("checkApprover expected ApproverNotAllowedError, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+// approvalItems returns the records of a valid DeploymentIntentGroup with the
+// given stateInfo, in a project with a policy requiring two release managers
+func approvalItems(t *testing.T, s state.StateInfo) map[string]map[string][]byte {
+	items := validationItems()
+	digKey := DeploymentIntentGroupKey{Name: gdDig, Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion}.String()
+	si, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items[digKey]["stateInfo"] = si
+	items[ApprovalPolicyKey{ApprovalPolicy: approvalPolicyKeyName, Project: gdProject}.String()] = map[string][]byte{
+		"approvalpolicymetadata": []byte(`{"metadata":{"name":"policy"},"spec":{"required-approvals":2,"roles":["release-manager"]}}`),
+	}
+	return items
+}
+
+// racingDB modifies the stateInfo of the DeploymentIntentGroup after it was
+// read, like a concurrent operation would
+type racingDB struct {
+	*db.MockDB
+}
+
+func (m *racingDB) Find(table string, key db.Key, tag string) ([][]byte, error) {
+	values, err := m.MockDB.Find(table, key, tag)
+	if tag == "stateInfo" {
+		m.Versions[fmt.Sprintf("%v", key)]++
+	}
+	return values, err
+}
+
+func TestApproveAndReject(t *testing.T) {
+	created := state.ActionEntry{State: state.StateEnum.Created, TimeStamp: time.Now().Add(-time.Hour)}
+	alice := Approval{Approver: "alice", Roles: []string{"release-manager"}, Comment: "looks good"}
+	bob := Approval{Approver: "bob", Roles: []string{"release-manager"}}
+
+	t.Run("First approval", func(t *testing.T) {
+		mockdb := &insertRecordingDB{MockDB: &db.MockDB{Items: approvalItems(t, state.StateInfo{Actions: []state.ActionEntry{created}})}}
+		db.DBconn = mockdb
+
+		status, err := NewInstantiationClient().Approve(gdProject, gdCompositeApp, gdVersion, gdDig, alice)
+		if err != nil {
+			t.Fatalf("Approve returned an unexpected error %s", err)
+		}
+		if status.State != state.StateEnum.Created || status.Approvals != 1 || status.RequiredApprovals != 2 {
+			t.Errorf("Approve returned unexpected status %v", status)
+		}
+		s := mockdb.inserted.(state.StateInfo)
+		if len(s.Actions) != 1 || len(s.Approvals) != 1 || s.Approvals[0].Comment != "looks good" {
+			t.Errorf("Approve recorded unexpected stateInfo %v", s)
+		}
+	})
+
+	t.Run("Approval satisfying the policy", func(t *testing.T) {
+		s := state.StateInfo{
+			Actions: []state.ActionEntry{created},
+			Approvals: []state.ApprovalEntry{
+				{Approver: "alice", Decision: state.DecisionEnum.Approve, TimeStamp: time.Now().Add(-time.Minute)},
+			},
+		}
+		mockdb := &insertRecordingDB{MockDB: &db.MockDB{Items: approvalItems(t, s)}}
+		db.DBconn = mockdb
+
+		status, err := NewInstantiationClient().Approve(gdProject, gdCompositeApp, gdVersion, gdDig, bob)
+		if err != nil {
+			t.Fatalf("Approve returned an unexpected error %s", err)
+		}
+		if status.State != state.StateEnum.Approved || status.Approvals != 2 {
+			t.Errorf("Approve returned unexpected status %v", status)
+		}
+	})
+
+	t.Run("Approver without a role", func(t *testing.T) {
+		db.DBconn = &db.MockDB{Items: approvalItems(t, state.StateInfo{Actions: []state.ActionEntry{created}})}
+
+		_, err := NewInstantiationClient().Approve(gdProject, gdCompositeApp, gdVersion, gdDig, Approval{Approver: "carol", Roles: []string{"ops"}})
+		if _, ok := pkgerrors.Cause(err).(*ApproverNotAllowedError); !ok {
+			t.Fatalf("Approve expected ApproverNotAllowedError, got %v", err)
+		}
+	})
+
+	t.Run("Concurrent approval", func(t *testing.T) {
+		digKey := DeploymentIntentGroupKey{Name: gdDig, Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion}.String()
+		db.DBconn = &racingDB{MockDB: &db.MockDB{
+			Items:    approvalItems(t, state.StateInfo{Actions: []state.ActionEntry{created}}),
+			Versions: map[string]int64{digKey: 3},
+		}}
+
+		_, err := NewInstantiationClient().Approve(gdProject, gdCompositeApp, gdVersion, gdDig, alice)
+		if _, ok := pkgerrors.Cause(err).(*db.VersionConflictError); !ok {
+			t.Fatalf("Approve expected VersionConflictError, got %v", err)
+		}
+	})
+
+	t.Run("Rejection of an Approved DeploymentIntentGroup", func(t *testing.T) {
+		s := state.StateInfo{
+			Actions: []state.ActionEntry{created, {State: state.StateEnum.Approved, TimeStamp: time.Now().Add(-time.Minute)}},
+		}
+		mockdb := &insertRecordingDB{MockDB: &db.MockDB{Items: approvalItems(t, s)}}
+		db.DBconn = mockdb
+
+		status, err := NewInstantiationClient().Reject(gdProject, gdCompositeApp, gdVersion, gdDig, bob)
+		if err != nil {
+			t.Fatalf("Reject returned an unexpected error %s", err)
+		}
+		if status.State != state.StateEnum.Created || status.Approvals != 0 {
+			t.Errorf("Reject returned unexpected status %v", status)
+		}
+		got := mockdb.inserted.(state.StateInfo)
+		if len(got.Approvals) != 1 || got.Approvals[0].Decision != state.DecisionEnum.Reject {
+			t.Errorf("Reject recorded unexpected stateInfo %v", got)
+		}
+	})
+}
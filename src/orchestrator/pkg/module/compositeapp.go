@@ -65,6 +65,7 @@ type CompositeAppManager interface {
 	CreateCompositeApp(c CompositeApp, p string) (CompositeApp, error)
 	GetCompositeApp(name string, version string, p string) (CompositeApp, error)
 	GetAllCompositeApps(p string) ([]CompositeApp, error)
+	ListCompositeApps(p string, o db.ListOptions) ([]CompositeApp, string, error)
 	DeleteCompositeApp(name string, version string, p string) error
 }
 
@@ -143,10 +144,17 @@ func (v *CompositeAppClient) GetCompositeApp(name string, version string, p stri
 
 // GetAllCompositeApps returns all the compositeApp for a given project
 func (v *CompositeAppClient) GetAllCompositeApps(p string) ([]CompositeApp, error) {
+	caList, _, err := v.ListCompositeApps(p, db.ListOptions{})
+	return caList, err
+}
+
+// ListCompositeApps returns a page of the compositeApps of a given project,
+// and the continue token of the next page
+func (v *CompositeAppClient) ListCompositeApps(p string, o db.ListOptions) ([]CompositeApp, string, error) {
 
 	_, err := NewProjectClient().GetProject(p)
 	if err != nil {
-		return []CompositeApp{}, pkgerrors.New("Unable to find the project")
+		return []CompositeApp{}, "", pkgerrors.New("Unable to find the project")
 	}
 
 	key := CompositeAppKey{
@@ -156,21 +164,24 @@ func (v *CompositeAppClient) GetAllCompositeApps(p string) ([]CompositeApp, erro
 	}
 
 	var caList []CompositeApp
-	values, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	values, next, err := findPage(v.storeName, key, v.tagMeta, o, map[string]string{
+		"name":    "compositeapp",
+		"version": "compositeappversion",
+	})
 	if err != nil {
-		return []CompositeApp{}, pkgerrors.Wrap(err, "Getting CompositeApps")
+		return []CompositeApp{}, "", pkgerrors.Wrap(err, "Getting CompositeApps")
 	}
 
 	for _, value := range values {
 		ca := CompositeApp{}
 		err = db.DBconn.Unmarshal(value, &ca)
 		if err != nil {
-			return []CompositeApp{}, pkgerrors.Wrap(err, "Unmarshaling CompositeApp")
+			return []CompositeApp{}, "", pkgerrors.Wrap(err, "Unmarshaling CompositeApp")
 		}
 		caList = append(caList, ca)
 	}
 
-	return caList, nil
+	return caList, next, nil
 }
 
 // DeleteCompositeApp deletes the  CompositeApp from database
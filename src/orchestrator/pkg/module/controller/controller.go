@@ -18,6 +18,7 @@ package controller
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
@@ -29,15 +30,68 @@ import (
 // Controller contains the parameters needed for Controllers
 // It implements the interface for managing the Controllers
 type Controller struct {
-	Metadata mtypes.Metadata `json:"metadata"`
-	Spec     ControllerSpec  `json:"spec"`
+	Metadata mtypes.Metadata   `json:"metadata"`
+	Spec     ControllerSpec    `json:"spec"`
+	Status   *ControllerStatus `json:"status,omitempty"`
 }
 
 type ControllerSpec struct {
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	Type     string `json:"type"`
-	Priority int    `json:"priority"`
+	Host         string         `json:"host"`
+	Port         int            `json:"port"`
+	Type         string         `json:"type"`
+	Priority     int            `json:"priority"`
+	Timeout      string         `json:"timeout,omitempty"`
+	Retries      int            `json:"retries,omitempty"`
+	RetryBackoff string         `json:"retryBackoff,omitempty"`
+	TLS          *ControllerTLS `json:"tls,omitempty"`
+}
+
+// ControllerTLS holds the mutual TLS settings used to reach a controller.
+// The files are read from the orchestrator's file system.
+type ControllerTLS struct {
+	CAFile     string `json:"caFile,omitempty"`
+	CertFile   string `json:"certFile,omitempty"`
+	KeyFile    string `json:"keyFile,omitempty"`
+	ServerName string `json:"serverName,omitempty"`
+}
+
+const MaxControllerRetries = 10
+
+// ConnOptions converts the call and transport settings of the spec
+// into the options of the RPC connection
+func (cs ControllerSpec) ConnOptions() (rpc.ConnOptions, error) {
+	var err error
+	opts := rpc.ConnOptions{
+		Retries: cs.Retries,
+	}
+
+	if cs.Timeout != "" {
+		opts.Timeout, err = time.ParseDuration(cs.Timeout)
+		if err != nil || opts.Timeout <= 0 {
+			return rpc.ConnOptions{}, pkgerrors.Errorf("Invalid controller timeout: %v", cs.Timeout)
+		}
+	}
+	if cs.Retries < 0 || cs.Retries > MaxControllerRetries {
+		return rpc.ConnOptions{}, pkgerrors.Errorf("Invalid controller retries: %v", cs.Retries)
+	}
+	if cs.RetryBackoff != "" {
+		opts.RetryBackoff, err = time.ParseDuration(cs.RetryBackoff)
+		if err != nil || opts.RetryBackoff <= 0 {
+			return rpc.ConnOptions{}, pkgerrors.Errorf("Invalid controller retry backoff: %v", cs.RetryBackoff)
+		}
+	}
+	if cs.TLS != nil {
+		if (cs.TLS.CertFile == "") != (cs.TLS.KeyFile == "") {
+			return rpc.ConnOptions{}, pkgerrors.New("Controller TLS client certificate and key must be set together")
+		}
+		opts.TLS = &rpc.TLSOptions{
+			CAFile:     cs.TLS.CAFile,
+			CertFile:   cs.TLS.CertFile,
+			KeyFile:    cs.TLS.KeyFile,
+			ServerName: cs.TLS.ServerName,
+		}
+	}
+	return opts, nil
 }
 
 const MinControllerPriority = 1
@@ -96,19 +150,27 @@ func (mc *ControllerClient) CreateController(m Controller, mayExist bool) (Contr
 		ControllerName: m.Metadata.Name,
 	}
 
+	opts, err := m.Spec.ConnOptions()
+	if err != nil {
+		return Controller{}, err
+	}
+
 	//Check if this Controller already exists
-	_, err := mc.GetController(m.Metadata.Name)
+	_, err = mc.GetController(m.Metadata.Name)
 	if err == nil && !mayExist {
 		return Controller{}, pkgerrors.New("Controller already exists")
 	}
 
+	// status is runtime information and is not stored
+	m.Status = nil
 	err = db.DBconn.Insert(mc.collectionName, key, nil, mc.tagMeta, m)
 	if err != nil {
 		return Controller{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
 
 	// send message to create/update the  rpc connection
-	rpc.UpdateRpcConn(m.Metadata.Name, m.Spec.Host, m.Spec.Port)
+	rpc.UpdateRpcConnWithOptions(m.Metadata.Name, m.Spec.Host, m.Spec.Port, opts)
+	setControllerStatus(m.Metadata.Name, ControllerStatus{Health: ControllerHealthUnknown})
 
 	return m, nil
 }
@@ -131,6 +193,8 @@ func (mc *ControllerClient) GetController(name string) (Controller, error) {
 		if err != nil {
 			return Controller{}, pkgerrors.Wrap(err, "Unmarshaling Value")
 		}
+		s := getControllerStatus(microserv.Metadata.Name)
+		microserv.Status = &s
 		return microserv, nil
 	}
 
@@ -157,6 +221,8 @@ func (mc *ControllerClient) GetControllers() ([]Controller, error) {
 		if err != nil {
 			return []Controller{}, pkgerrors.Wrap(err, "Unmarshaling Value")
 		}
+		s := getControllerStatus(microserv.Metadata.Name)
+		microserv.Status = &s
 
 		resp = append(resp, microserv)
 	}
@@ -178,6 +244,7 @@ func (mc *ControllerClient) DeleteController(name string) error {
 
 	// send message to close rpc connection
 	rpc.RemoveRpcConn(name)
+	removeControllerStatus(name)
 
 	return nil
 }
@@ -189,6 +256,13 @@ func (mc *ControllerClient) InitControllers() {
 		log.Info("Initializing RPC connection for controller", log.Fields{
 			"Controller": v.Metadata.Name,
 		})
-		rpc.UpdateRpcConn(v.Metadata.Name, v.Spec.Host, v.Spec.Port)
+		opts, err := v.Spec.ConnOptions()
+		if err != nil {
+			log.Warn("Invalid connection settings for controller, using defaults", log.Fields{
+				"Controller": v.Metadata.Name,
+				"Error":      err,
+			})
+		}
+		rpc.UpdateRpcConnWithOptions(v.Metadata.Name, v.Spec.Host, v.Spec.Port, opts)
 	}
 }
@@ -110,6 +110,9 @@ func TestGetController(t *testing.T) {
 					Host: "132.156.0.10",
 					Port: 8080,
 				},
+				Status: &ControllerStatus{
+					Health: ControllerHealthUnknown,
+				},
 			},
 			expectedError: "",
 			mockdb: &db.MockDB{
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	rpc "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/rpc"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Health values reported for a registered controller
+const (
+	ControllerHealthUnknown     = "Unknown"
+	ControllerHealthServing     = "Serving"
+	ControllerHealthNotServing  = "NotServing"
+	ControllerHealthUnreachable = "Unreachable"
+)
+
+// healthCheckTimeout bounds a single gRPC health check
+const healthCheckTimeout = 5 * time.Second
+
+// ControllerStatus is the result of the most recent health check of a controller
+type ControllerStatus struct {
+	Health        string    `json:"health"`
+	LastProbeTime time.Time `json:"lastProbeTime,omitempty"`
+	Message       string    `json:"message,omitempty"`
+}
+
+var healthMutex = &sync.Mutex{}
+var controllerHealth = make(map[string]ControllerStatus)
+
+// probeController runs the gRPC health protocol against a controller.
+// It is a variable so that unit tests can replace it.
+var probeController = func(name string) ControllerStatus {
+	s := ControllerStatus{
+		Health:        ControllerHealthUnknown,
+		LastProbeTime: time.Now(),
+	}
+
+	conn := rpc.GetRpcConn(name)
+	if conn == nil {
+		s.Health = ControllerHealthUnreachable
+		s.Message = "No RPC connection to controller"
+		return s
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		// A controller which does not implement the health service
+		// answered the call, so it is up and taking requests.
+		if status.Code(err) == codes.Unimplemented {
+			s.Health = ControllerHealthServing
+			s.Message = "Health service not implemented by controller"
+			return s
+		}
+		s.Health = ControllerHealthUnreachable
+		s.Message = err.Error()
+		return s
+	}
+
+	switch resp.Status {
+	case healthpb.HealthCheckResponse_SERVING:
+		s.Health = ControllerHealthServing
+	case healthpb.HealthCheckResponse_NOT_SERVING:
+		s.Health = ControllerHealthNotServing
+	default:
+		s.Health = ControllerHealthUnknown
+	}
+	return s
+}
+
+func setControllerStatus(name string, s ControllerStatus) {
+	healthMutex.Lock()
+	defer healthMutex.Unlock()
+	controllerHealth[name] = s
+}
+
+func removeControllerStatus(name string) {
+	healthMutex.Lock()
+	defer healthMutex.Unlock()
+	delete(controllerHealth, name)
+}
+
+// getControllerStatus returns the last known status of a controller
+func getControllerStatus(name string) ControllerStatus {
+	healthMutex.Lock()
+	defer healthMutex.Unlock()
+	if s, ok := controllerHealth[name]; ok {
+		return s
+	}
+	return ControllerStatus{Health: ControllerHealthUnknown}
+}
+
+// ProbeController runs a health check against the named controller
+// and records the result
+func ProbeController(name string) ControllerStatus {
+	s := probeController(name)
+	setControllerStatus(name, s)
+	if s.Health != ControllerHealthServing {
+		log.Warn("Controller health check failed", log.Fields{
+			"Controller": name,
+			"Health":     s.Health,
+			"Message":    s.Message,
+		})
+	}
+	return s
+}
+
+// CheckControllersHealth probes each of the named controllers and returns
+// an error naming every controller which is not serving
+func (mc *ControllerClient) CheckControllersHealth(names []string) error {
+	var down []string
+	for _, name := range names {
+		s := ProbeController(name)
+		if s.Health != ControllerHealthServing {
+			down = append(down, name+" ("+s.Health+")")
+		}
+	}
+	if len(down) > 0 {
+		return pkgerrors.Errorf("Controllers are not available: %v", down)
+	}
+	return nil
+}
+
+// getHealthCheckInterval returns the configured health check period
+func getHealthCheckInterval() time.Duration {
+	i := config.GetConfiguration().ControllerHealthInterval
+	d, err := time.ParseDuration(i)
+	if err != nil || d <= 0 {
+		log.Warn("Invalid controller health check interval, using default", log.Fields{
+			"Interval": i,
+		})
+		return 30 * time.Second
+	}
+	return d
+}
+
+// StartHealthChecks periodically probes every registered controller
+func (mc *ControllerClient) StartHealthChecks() {
+	interval := getHealthCheckInterval()
+	go func() {
+		for {
+			vals, err := mc.GetControllers()
+			if err != nil {
+				log.Error("Error getting controllers for health check", log.Fields{
+					"Error": err,
+				})
+			}
+			for _, v := range vals {
+				ProbeController(v.Metadata.Name)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
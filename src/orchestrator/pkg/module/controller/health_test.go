@@ -0,0 +1,112 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+)
+
+func TestCheckControllersHealth(t *testing.T) {
+	health := map[string]string{
+		"ovnaction": ControllerHealthServing,
+		"gpic":      ControllerHealthServing,
+		"dtc":       ControllerHealthNotServing,
+		"rsync":     ControllerHealthUnreachable,
+	}
+	saved := probeController
+	probeController = func(name string) ControllerStatus {
+		return ControllerStatus{Health: health[name]}
+	}
+	defer func() { probeController = saved }()
+
+	testCases := []struct {
+		label         string
+		names         []string
+		expectedError string
+		notExpected   string
+	}{
+		{
+			label: "All Controllers Serving",
+			names: []string{"ovnaction", "gpic"},
+		},
+		{
+			label:         "Some Controllers Not Serving",
+			names:         []string{"ovnaction", "dtc", "rsync"},
+			expectedError: "dtc (NotServing) rsync (Unreachable)",
+			notExpected:   "ovnaction",
+		},
+		{
+			label: "No Controllers",
+			names: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			impl := NewControllerClient()
+			err := impl.CheckControllersHealth(testCase.names)
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("CheckControllersHealth returned an unexpected error %s", err)
+				}
+				if strings.Contains(err.Error(), testCase.expectedError) == false {
+					t.Fatalf("CheckControllersHealth returned an unexpected error %s", err)
+				}
+				if strings.Contains(err.Error(), testCase.notExpected) {
+					t.Fatalf("CheckControllersHealth reported a serving controller %s", err)
+				}
+			} else if testCase.expectedError != "" {
+				t.Fatalf("CheckControllersHealth expected error %s", testCase.expectedError)
+			}
+		})
+	}
+}
+
+func TestGetControllerStatus(t *testing.T) {
+	db.DBconn = &db.MockDB{
+		Items: map[string]map[string][]byte{
+			ControllerKey{ControllerName: "dtc"}.String(): {
+				"controllermetadata": []byte(
+					"{\"metadata\":{\"name\":\"dtc\"}," +
+						"\"spec\":{\"host\":\"132.156.0.10\",\"port\": 8080 }}"),
+			},
+		},
+	}
+	saved := probeController
+	probeController = func(name string) ControllerStatus {
+		return ControllerStatus{Health: ControllerHealthNotServing, Message: "draining"}
+	}
+	defer func() { probeController = saved }()
+
+	impl := NewControllerClient()
+	ProbeController("dtc")
+	defer removeControllerStatus("dtc")
+
+	got, err := impl.GetController("dtc")
+	if err != nil {
+		t.Fatalf("GetController returned an unexpected error %s", err)
+	}
+	if got.Status == nil {
+		t.Fatalf("GetController returned no status")
+	}
+	if got.Status.Health != ControllerHealthNotServing || got.Status.Message != "draining" {
+		t.Errorf("GetController returned unexpected status %v", *got.Status)
+	}
+}
@@ -0,0 +1,267 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+/*
+This file deals with the referential validation of a DeploymentIntentGroup
+before it is approved
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/module/controller"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// The kinds of the objects a ValidationIssue is about
+const (
+	IssueKindApp              = "app"
+	IssueKindAppProfile       = "app-profile"
+	IssueKindCompositeProfile = "composite-profile"
+	IssueKindPlacement        = "placement"
+	IssueKindClusterProvider  = "cluster-provider"
+	IssueKindCluster          = "cluster"
+	IssueKindClusterLabel     = "cluster-label"
+	IssueKindLogicalCloud     = "logical-cloud"
+	IssueKindController       = "controller"
+	IssueKindValues           = "values"
+)
+
+// ValidationIssue is an object referenced by the DeploymentIntentGroup that
+// is missing or invalid
+type ValidationIssue struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	App     string `json:"app,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationReport is the result of the validation of a DeploymentIntentGroup.
+// It is valid when there are no errors.
+type ValidationReport struct {
+	Valid  bool              `json:"valid"`
+	Errors []ValidationIssue `json:"errors"`
+}
+
+// ValidationReportError is returned when a DeploymentIntentGroup is not valid
+type ValidationReportError struct {
+	Report ValidationReport
+}
+
+func (e *ValidationReportError) Error() string {
+	return fmt.Sprintf("DeploymentIntentGroup is not valid: %d errors", len(e.Report.Errors))
+}
+
+// logicalCloudKey is the key of the logical clouds that dcm stores
+type logicalCloudKey struct {
+	Project          string `json:"project"`
+	LogicalCloudName string `json:"logical-cloud-name"`
+}
+
+// We will use json marshalling to convert to string to
+// preserve the underlying structure.
+func (lk logicalCloudKey) String() string {
+	out, err := json.Marshal(lk)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// validationReportBuilder collects the issues of a report, once each
+type validationReportBuilder struct {
+	report ValidationReport
+	seen   map[ValidationIssue]bool
+}
+
+func (b *validationReportBuilder) add(kind, name, app, message string) {
+	issue := ValidationIssue{Kind: kind, Name: name, App: app, Message: message}
+	if b.seen == nil {
+		b.seen = map[ValidationIssue]bool{}
+	}
+	if b.seen[issue] {
+		return
+	}
+	b.seen[issue] = true
+	b.report.Errors = append(b.report.Errors, issue)
+}
+
+func (b *validationReportBuilder) done() ValidationReport {
+	b.report.Valid = len(b.report.Errors) == 0
+	if b.report.Errors == nil {
+		b.report.Errors = []ValidationIssue{}
+	}
+	return b.report
+}
+
+/*
+Validate takes in projectName, compositeAppName, compositeAppVersion,
+DeploymentIntentName. It checks that every object the DeploymentIntentGroup references exists:
+the profiles and placement of its apps, the cluster providers, clusters and cluster labels of
+the placement, the logical cloud and the controllers of its intents. The override values are
+checked against the schemas of the charts once the apps and their profiles are found.
+*/
+func (c InstantiationClient) Validate(p string, ca string, v string, di string) (ValidationReport, error) {
+	dIGrp, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroup(di, p, ca, v)
+	if err != nil {
+		return ValidationReport{}, pkgerrors.Wrap(err, "Not finding the deploymentIntentGroup")
+	}
+
+	allApps, err := NewAppClient().GetApps(p, ca, v)
+	if err != nil {
+		return ValidationReport{}, pkgerrors.Wrap(err, "Not finding the apps")
+	}
+
+	b := &validationReportBuilder{}
+	validateAppProfiles(b, p, ca, v, dIGrp.Spec.Profile, allApps)
+
+	iList, err := NewIntentClient().GetAllIntents(p, ca, v, di)
+	if err != nil {
+		return ValidationReport{}, pkgerrors.Wrap(err, "Not finding the intents")
+	}
+	gIntent := ""
+	controllers := make([]string, 0)
+	for _, eachMap := range iList.ListOfIntents {
+		for cn, intent := range eachMap {
+			if cn == GenericPlacementIntentName {
+				gIntent = intent
+				continue
+			}
+			controllers = append(controllers, cn)
+		}
+	}
+	sort.Strings(controllers)
+	for _, cn := range controllers {
+		_, err := controller.NewControllerClient().GetController(cn)
+		if err != nil {
+			b.add(IssueKindController, cn, "", "Controller is not registered")
+		}
+	}
+
+	if gIntent == "" {
+		b.add(IssueKindPlacement, GenericPlacementIntentName, "", "DeploymentIntentGroup has no generic placement intent")
+	} else {
+		err = validatePlacement(b, p, ca, v, di, gIntent, allApps)
+		if err != nil {
+			return ValidationReport{}, err
+		}
+	}
+
+	if lc := dIGrp.Spec.LogicalCloud; lc != "" {
+		value, err := db.DBconn.Find("orchestrator", logicalCloudKey{Project: p, LogicalCloudName: lc}, "logicalcloud")
+		if err != nil || len(value) == 0 || value[0] == nil {
+			b.add(IssueKindLogicalCloud, lc, "", "Logical cloud does not exist")
+		}
+	}
+
+	// The values can only be checked when the apps and their profiles are found
+	if len(b.report.Errors) == 0 {
+		err = validateOverrideValues(p, ca, v, dIGrp)
+		if vErr, ok := err.(*ValuesValidationError); ok {
+			for _, vv := range vErr.Violations {
+				b.add(IssueKindValues, vv.Pointer, vv.App, vv.Message)
+			}
+		} else if err != nil {
+			return ValidationReport{}, err
+		}
+	}
+
+	return b.done(), nil
+}
+
+// validateAppProfiles checks that the composite profile has a profile for every app
+func validateAppProfiles(b *validationReportBuilder, p, ca, v, cp string, allApps []App) {
+	_, err := NewCompositeProfileClient().GetCompositeProfile(cp, p, ca, v)
+	if err != nil {
+		b.add(IssueKindCompositeProfile, cp, "", "Composite profile does not exist")
+		return
+	}
+	for _, a := range allApps {
+		_, err := NewAppProfileClient().GetAppProfileByApp(p, ca, v, cp, a.Metadata.Name)
+		if err != nil {
+			b.add(IssueKindAppProfile, cp, a.Metadata.Name, "App has no profile in the composite profile")
+		}
+	}
+}
+
+// validatePlacement checks that every app has an app intent in the generic placement
+// intent, and that the cluster providers, clusters and cluster labels of the app
+// intents exist
+func validatePlacement(b *validationReportBuilder, p, ca, v, di, gIntent string, allApps []App) error {
+	apps := map[string]bool{}
+	for _, a := range allApps {
+		apps[a.Metadata.Name] = true
+	}
+
+	appIntents, err := NewAppIntentClient().GetAllAppIntents(p, ca, v, gIntent, di)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Not finding the app intents")
+	}
+
+	placed := map[string]bool{}
+	for _, ai := range appIntents.ArrayOfAppClusterInfo {
+		if !apps[ai.Name] {
+			b.add(IssueKindApp, ai.Name, ai.Name, "App intent references an app that does not exist")
+			continue
+		}
+		placed[ai.Name] = true
+
+		for _, allOf := range ai.AllOfArray {
+			validatePlacementTarget(b, ai.Name, allOf.ProviderName, allOf.ClusterName, allOf.ClusterLabelName)
+			for _, anyOf := range allOf.AnyOfArray {
+				validatePlacementTarget(b, ai.Name, anyOf.ProviderName, anyOf.ClusterName, anyOf.ClusterLabelName)
+			}
+		}
+		for _, anyOf := range ai.AnyOfArray {
+			validatePlacementTarget(b, ai.Name, anyOf.ProviderName, anyOf.ClusterName, anyOf.ClusterLabelName)
+		}
+	}
+
+	for _, a := range allApps {
+		if !placed[a.Metadata.Name] {
+			b.add(IssueKindPlacement, gIntent, a.Metadata.Name, "App has no app intent in the generic placement intent")
+		}
+	}
+	return nil
+}
+
+// validatePlacementTarget checks the cluster provider of an entry of an app intent,
+// and its cluster or cluster label
+func validatePlacementTarget(b *validationReportBuilder, app, pn, cn, cln string) {
+	cc := cluster.NewClusterClient()
+	if _, err := cc.GetClusterProvider(pn); err != nil {
+		b.add(IssueKindClusterProvider, pn, app, "Cluster provider does not exist")
+		return
+	}
+	switch {
+	case cn != "":
+		if _, err := cc.GetCluster(pn, cn); err != nil {
+			b.add(IssueKindCluster, pn+SEPARATOR+cn, app, "Cluster does not exist")
+		}
+	case cln != "":
+		names, err := cc.GetClustersWithLabel(pn, cln)
+		if err != nil || len(names) == 0 {
+			b.add(IssueKindClusterLabel, pn+SEPARATOR+cln, app, "No cluster has the cluster label")
+		}
+	}
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/module/controller"
+)
+
+// validationItems returns the records of a valid DeploymentIntentGroup
+func validationItems() map[string]map[string][]byte {
+	items := map[string]map[string][]byte{}
+	items[DeploymentIntentGroupKey{Name: gdDig, Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion}.String()] = map[string][]byte{
+		"deploymentintentgroupmetadata": []byte(`{"metadata":{"name":"testDig"},"spec":{"profile":"cp1","version":"r1","logical-cloud":"lc1"}}`),
+	}
+	items[AppKey{App: "", Project: gdProject, CompositeApp: gdCompositeApp, CompositeAppVersion: gdVersion}.String()] = map[string][]byte{
+		"appmetadata": []byte(`{"metadata":{"name":"app1"},"spec":{"format":"manifests"}}`),
+	}
+	items[CompositeProfileKey{Name: "cp1", Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion}.String()] = map[string][]byte{
+		"compositeprofilemetadata": []byte(`{"metadata":{"name":"cp1"}}`),
+	}
+	items[fmt.Sprintf("%v", AppProfileFindByAppKey{Project: gdProject, CompositeApp: gdCompositeApp, CompositeAppVersion: gdVersion, CompositeProfile: "cp1", AppName: "app1"})] = map[string][]byte{
+		"profilemetadata": []byte(`{"metadata":{"name":"profile1"},"spec":{"app-name":"app1"}}`),
+	}
+	items[IntentKey{Name: "", Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion, DeploymentIntentGroup: gdDig}.String()] = map[string][]byte{
+		"addintent": []byte(`{"metadata":{"name":"intents"},"spec":{"intent":{"genericPlacementIntent":"gpi1","ovnaction":"ovn1"}}}`),
+	}
+	items[AppIntentKey{Name: "", Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion, Intent: "gpi1", DeploymentIntentGroupName: gdDig}.String()] = map[string][]byte{
+		"appintentmetadata": []byte(`{"metadata":{"name":"ai1"},"spec":{"app-name":"app1","intent":{"allOf":[{"provider-name":"p1","cluster-name":"c1"}],"anyOf":[{"provider-name":"p1","cluster-label-name":"edge"}]}}}`),
+	}
+	items[controller.ControllerKey{ControllerName: "ovnaction"}.String()] = map[string][]byte{
+		"controllermetadata": []byte(`{"metadata":{"name":"ovnaction"},"spec":{"host":"ovnaction","port":9053}}`),
+	}
+	items[fmt.Sprintf("%v", cluster.ClusterProviderKey{ClusterProviderName: "p1"})] = map[string][]byte{
+		"clustermetadata": []byte(`{"metadata":{"name":"p1"}}`),
+	}
+	items[fmt.Sprintf("%v", cluster.ClusterKey{ClusterProviderName: "p1", ClusterName: "c1"})] = map[string][]byte{
+		"clustermetadata": []byte(`{"metadata":{"name":"c1"}}`),
+	}
+	items[fmt.Sprintf("%v", cluster.LabelKey{ClusterProviderName: "p1", ClusterLabelName: "edge"})] = map[string][]byte{
+		"cluster": []byte("c1"),
+	}
+	items[fmt.Sprintf("%v", cluster.ClusterLabelKey{ClusterProviderName: "p1", ClusterName: "c1"})] = map[string][]byte{
+		"clustermetadata": []byte(`{"label-name":"edge"}`),
+	}
+	items[logicalCloudKey{Project: gdProject, LogicalCloudName: "lc1"}.String()] = map[string][]byte{
+		"logicalcloud": []byte(`{"metadata":{"name":"lc1"}}`),
+	}
+	return items
+}
+
+func TestValidateDeploymentIntentGroup(t *testing.T) {
+	testCases := []struct {
+		label    string
+		modify   func(items map[string]map[string][]byte)
+		expected []ValidationIssue
+	}{
+		{
+			label:    "Valid DeploymentIntentGroup",
+			modify:   func(items map[string]map[string][]byte) {},
+			expected: []ValidationIssue{},
+		},
+		{
+			label: "Missing referenced objects",
+			modify: func(items map[string]map[string][]byte) {
+				delete(items, fmt.Sprintf("%v", AppProfileFindByAppKey{Project: gdProject, CompositeApp: gdCompositeApp, CompositeAppVersion: gdVersion, CompositeProfile: "cp1", AppName: "app1"}))
+				delete(items, controller.ControllerKey{ControllerName: "ovnaction"}.String())
+				delete(items, fmt.Sprintf("%v", cluster.ClusterKey{ClusterProviderName: "p1", ClusterName: "c1"}))
+				delete(items, fmt.Sprintf("%v", cluster.LabelKey{ClusterProviderName: "p1", ClusterLabelName: "edge"}))
+				delete(items, logicalCloudKey{Project: gdProject, LogicalCloudName: "lc1"}.String())
+			},
+			expected: []ValidationIssue{
+				{Kind: IssueKindAppProfile, Name: "cp1", App: "app1", Message: "App has no profile in the composite profile"},
+				{Kind: IssueKindController, Name: "ovnaction", Message: "Controller is not registered"},
+				{Kind: IssueKindCluster, Name: "p1+c1", App: "app1", Message: "Cluster does not exist"},
+				{Kind: IssueKindClusterLabel, Name: "p1+edge", App: "app1", Message: "No cluster has the cluster label"},
+				{Kind: IssueKindLogicalCloud, Name: "lc1", Message: "Logical cloud does not exist"},
+			},
+		},
+		{
+			label: "Missing cluster provider",
+			modify: func(items map[string]map[string][]byte) {
+				delete(items, fmt.Sprintf("%v", cluster.ClusterProviderKey{ClusterProviderName: "p1"}))
+			},
+			expected: []ValidationIssue{
+				{Kind: IssueKindClusterProvider, Name: "p1", App: "app1", Message: "Cluster provider does not exist"},
+			},
+		},
+		{
+			label: "App intent of an unknown app",
+			modify: func(items map[string]map[string][]byte) {
+				items[AppIntentKey{Name: "", Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion, Intent: "gpi1", DeploymentIntentGroupName: gdDig}.String()] = map[string][]byte{
+					"appintentmetadata": []byte(`{"metadata":{"name":"ai1"},"spec":{"app-name":"ghost","intent":{"allOf":[{"provider-name":"p1","cluster-name":"c1"}]}}}`),
+				}
+			},
+			expected: []ValidationIssue{
+				{Kind: IssueKindApp, Name: "ghost", App: "ghost", Message: "App intent references an app that does not exist"},
+				{Kind: IssueKindPlacement, Name: "gpi1", App: "app1", Message: "App has no app intent in the generic placement intent"},
+			},
+		},
+		{
+			label: "Missing generic placement intent and composite profile",
+			modify: func(items map[string]map[string][]byte) {
+				items[IntentKey{Name: "", Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion, DeploymentIntentGroup: gdDig}.String()] = map[string][]byte{
+					"addintent": []byte(`{"metadata":{"name":"intents"},"spec":{"intent":{"ovnaction":"ovn1"}}}`),
+				}
+				delete(items, CompositeProfileKey{Name: "cp1", Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion}.String())
+			},
+			expected: []ValidationIssue{
+				{Kind: IssueKindCompositeProfile, Name: "cp1", Message: "Composite profile does not exist"},
+				{Kind: IssueKindPlacement, Name: GenericPlacementIntentName, Message: "DeploymentIntentGroup has no generic placement intent"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			items := validationItems()
+			testCase.modify(items)
+			db.DBconn = &db.MockDB{Items: items}
+
+			report, err := NewInstantiationClient().Validate(gdProject, gdCompositeApp, gdVersion, gdDig)
+			if err != nil {
+				t.Fatalf("Validate returned an unexpected error %s", err)
+			}
+			if report.Valid != (len(testCase.expected) == 0) {
+				t.Errorf("Validate returned valid %v with errors %v", report.Valid, report.Errors)
+			}
+			if !reflect.DeepEqual(report.Errors, testCase.expected) {
+				t.Errorf("Validate returned unexpected errors:\n got %v\nwant %v", report.Errors, testCase.expected)
+			}
+		})
+	}
+}
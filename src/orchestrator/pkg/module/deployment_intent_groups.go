@@ -50,10 +50,16 @@ type DepSpecData struct {
 	LogicalCloud string `json:"logical-cloud"`
 }
 
-// OverrideValues has appName and ValuesObj
+// OverrideValues has appName and ValuesObj. The values apply to all the
+// clusters of the app unless they are scoped to a cluster provider, or to a
+// cluster or a cluster label of that provider. String values are set like
+// with helm --set, other JSON values are set as they are at their dotted key.
 type OverrideValues struct {
-	AppName   string            `json:"app-name"`
-	ValuesObj map[string]string `json:"values"`
+	AppName         string                 `json:"app-name"`
+	ClusterProvider string                 `json:"cluster-provider,omitempty"`
+	Cluster         string                 `json:"cluster,omitempty"`
+	ClusterLabel    string                 `json:"cluster-label,omitempty"`
+	ValuesObj       map[string]interface{} `json:"values"`
 }
 
 // Values has ImageRepository
@@ -66,8 +72,10 @@ type DeploymentIntentGroupManager interface {
 	CreateDeploymentIntentGroup(d DeploymentIntentGroup, p string, ca string, v string) (DeploymentIntentGroup, error)
 	GetDeploymentIntentGroup(di string, p string, ca string, v string) (DeploymentIntentGroup, error)
 	GetDeploymentIntentGroupState(di string, p string, ca string, v string) (state.StateInfo, error)
+	GetDeploymentIntentGroupVersion(di string, p string, ca string, v string) (int64, error)
 	DeleteDeploymentIntentGroup(di string, p string, ca string, v string) error
 	GetAllDeploymentIntentGroups(p string, ca string, v string) ([]DeploymentIntentGroup, error)
+	ListDeploymentIntentGroups(p string, ca string, v string, o db.ListOptions) ([]DeploymentIntentGroup, string, error)
 }
 
 // DeploymentIntentGroupKey consists of Name of the deployment group, project name, CompositeApp name, CompositeApp version
@@ -125,6 +133,12 @@ func (c *DeploymentIntentGroupClient) CreateDeploymentIntentGroup(d DeploymentIn
 		return DeploymentIntentGroup{}, pkgerrors.New("Unable to find the composite-app")
 	}
 
+	// Validate the values of the apps against the schemas of their charts
+	err = validateOverrideValues(p, ca, v, d)
+	if err != nil {
+		return DeploymentIntentGroup{}, err
+	}
+
 	gkey := DeploymentIntentGroupKey{
 		Name:         d.MetaData.Name,
 		Project:      p,
@@ -146,7 +160,7 @@ func (c *DeploymentIntentGroupClient) CreateDeploymentIntentGroup(d DeploymentIn
 	}
 	s.Actions = append(s.Actions, a)
 
-	err = db.DBconn.Insert(c.storeName, gkey, nil, c.tagState, s)
+	err = db.DBconn.Insert(c.storeName, gkey, stateQuery(s), c.tagState, s)
 	if err != nil {
 		return DeploymentIntentGroup{}, pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+d.MetaData.Name)
 	}
@@ -154,6 +168,72 @@ func (c *DeploymentIntentGroupClient) CreateDeploymentIntentGroup(d DeploymentIn
 	return d, nil
 }
 
+// stateQuery returns the query fields the DeploymentIntentGroups are stored
+// with, so that they can be listed by their current state
+func stateQuery(s state.StateInfo) map[string]string {
+	current, _ := state.GetCurrentStateFromStateInfo(s)
+	return map[string]string{"state": current}
+}
+
+// BackfillStates stores the DeploymentIntentGroups created before they were
+// stored with their current state again, so that they can be listed by it. It
+// returns the number of DeploymentIntentGroups stored again. The
+// DeploymentIntentGroups which already have a state, or which are updated
+// meanwhile, are left as they are.
+func (c *DeploymentIntentGroupClient) BackfillStates() (int, error) {
+	n := 0
+	projects, err := NewProjectClient().GetAllProjects()
+	if err != nil {
+		return n, pkgerrors.Wrap(err, "Get Projects")
+	}
+	for _, p := range projects {
+		cas, err := NewCompositeAppClient().GetAllCompositeApps(p.MetaData.Name)
+		if err != nil {
+			return n, pkgerrors.Wrap(err, "Get CompositeApps")
+		}
+		for _, ca := range cas {
+			digs, err := c.GetAllDeploymentIntentGroups(p.MetaData.Name, ca.Metadata.Name, ca.Spec.Version)
+			if err != nil {
+				return n, err
+			}
+			for _, d := range digs {
+				key := DeploymentIntentGroupKey{
+					Name:         d.MetaData.Name,
+					Project:      p.MetaData.Name,
+					CompositeApp: ca.Metadata.Name,
+					Version:      ca.Spec.Version,
+				}
+				version, err := db.DBconn.GetVersion(c.storeName, key)
+				if err != nil {
+					return n, pkgerrors.Wrap(err, "Get DeploymentIntentGroup version error")
+				}
+				values, err := db.DBconn.FindWithOptions(c.storeName, key, c.tagState, db.FindOptions{Missing: []string{"state"}})
+				if err != nil {
+					return n, pkgerrors.Wrap(err, "Get DeploymentIntentGroup StateInfo error")
+				}
+				if len(values) == 0 {
+					continue
+				}
+				s := state.StateInfo{}
+				err = db.DBconn.Unmarshal(values[0], &s)
+				if err != nil {
+					return n, pkgerrors.Wrap(err, "Unmarshalling DeploymentIntentGroup StateInfo")
+				}
+				_, err = db.DBconn.InsertIfVersion(c.storeName, key, stateQuery(s), c.tagState, s, version)
+				if _, ok := err.(*db.VersionConflictError); ok {
+					// Updated meanwhile, and stored with its state then
+					continue
+				}
+				if err != nil {
+					return n, pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+key.Name)
+				}
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
 // GetDeploymentIntentGroup returns the DeploymentIntentGroup with a given name, project, compositeApp and version of compositeApp
 func (c *DeploymentIntentGroupClient) GetDeploymentIntentGroup(di string, p string, ca string, v string) (DeploymentIntentGroup, error) {
 
@@ -184,6 +264,14 @@ func (c *DeploymentIntentGroupClient) GetDeploymentIntentGroup(di string, p stri
 
 // GetAllDeploymentIntentGroups returns all the deploymentIntentGroups under a specific project, compositeApp and version
 func (c *DeploymentIntentGroupClient) GetAllDeploymentIntentGroups(p string, ca string, v string) ([]DeploymentIntentGroup, error) {
+	diList, _, err := c.ListDeploymentIntentGroups(p, ca, v, db.ListOptions{})
+	return diList, err
+}
+
+// ListDeploymentIntentGroups returns a page of the deploymentIntentGroups under a specific
+// project, compositeApp and version, and the continue token of the next page. The
+// deploymentIntentGroups can be filtered and sorted by their current state.
+func (c *DeploymentIntentGroupClient) ListDeploymentIntentGroups(p string, ca string, v string, o db.ListOptions) ([]DeploymentIntentGroup, string, error) {
 
 	key := DeploymentIntentGroupKey{
 		Name:         "",
@@ -195,31 +283,33 @@ func (c *DeploymentIntentGroupClient) GetAllDeploymentIntentGroups(p string, ca
 	//Check if project exists
 	_, err := NewProjectClient().GetProject(p)
 	if err != nil {
-		return []DeploymentIntentGroup{}, pkgerrors.Wrap(err, "Unable to find the project")
+		return []DeploymentIntentGroup{}, "", pkgerrors.Wrap(err, "Unable to find the project")
 	}
 
 	//check if compositeApp exists
 	_, err = NewCompositeAppClient().GetCompositeApp(ca, v, p)
 	if err != nil {
-		return []DeploymentIntentGroup{}, pkgerrors.Wrap(err, "Unable to find the composite-app, check CompositeAppName and Version")
+		return []DeploymentIntentGroup{}, "", pkgerrors.Wrap(err, "Unable to find the composite-app, check CompositeAppName and Version")
 	}
 	var diList []DeploymentIntentGroup
-	result, err := db.DBconn.Find(c.storeName, key, c.tagMetaData)
+	result, next, err := findPage(c.storeName, key, c.tagMetaData, o, map[string]string{
+		"name":  "deploymentintentgroup",
+		"state": "state",
+	})
 	if err != nil {
-		return []DeploymentIntentGroup{}, pkgerrors.Wrap(err, "Get DeploymentIntentGroup error")
+		return []DeploymentIntentGroup{}, "", pkgerrors.Wrap(err, "Get DeploymentIntentGroup error")
 	}
 
 	for _, value := range result {
 		di := DeploymentIntentGroup{}
 		err = db.DBconn.Unmarshal(value, &di)
 		if err != nil {
-			return []DeploymentIntentGroup{}, pkgerrors.Wrap(err, "Unmarshaling DeploymentIntentGroup")
+			return []DeploymentIntentGroup{}, "", pkgerrors.Wrap(err, "Unmarshaling DeploymentIntentGroup")
 		}
 		diList = append(diList, di)
 	}
 
-	return diList, nil
-
+	return diList, next, nil
 }
 
 // GetDeploymentIntentGroupState returns the AppContent with a given DeploymentIntentname, project, compositeAppName and version of compositeApp
@@ -249,6 +339,24 @@ func (c *DeploymentIntentGroupClient) GetDeploymentIntentGroupState(di string, p
 	return state.StateInfo{}, pkgerrors.New("Error getting DeploymentIntentGroup StateInfo")
 }
 
+// GetDeploymentIntentGroupVersion returns the version of the DeploymentIntentGroup,
+// which changes with its metadata and with its stateInfo
+func (c *DeploymentIntentGroupClient) GetDeploymentIntentGroupVersion(di string, p string, ca string, v string) (int64, error) {
+
+	key := DeploymentIntentGroupKey{
+		Name:         di,
+		Project:      p,
+		CompositeApp: ca,
+		Version:      v,
+	}
+
+	version, err := db.DBconn.GetVersion(c.storeName, key)
+	if err != nil {
+		return 0, pkgerrors.Wrap(err, "Get DeploymentIntentGroup version error")
+	}
+	return version, nil
+}
+
 // DeleteDeploymentIntentGroup deletes a DeploymentIntentGroup
 func (c *DeploymentIntentGroupClient) DeleteDeploymentIntentGroup(di string, p string, ca string, v string) error {
 	k := DeploymentIntentGroupKey{
@@ -283,6 +391,10 @@ func (c *DeploymentIntentGroupClient) DeleteDeploymentIntentGroup(di string, p s
 
 		for _, id := range state.GetContextIdsFromStateInfo(s) {
 			context, err := state.GetAppContextFromId(id)
+			if err != nil && id != ctxid {
+				// The AppContexts retired by an update are deleted once terminated
+				continue
+			}
 			if err != nil {
 				return pkgerrors.Wrap(err, "Error getting appcontext from Deployment Intent Group StateInfo")
 			}
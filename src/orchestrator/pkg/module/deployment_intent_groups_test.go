@@ -49,11 +49,11 @@ func TestCreateDeploymentIntentGroup(t *testing.T) {
 					Version: "version of deployment",
 					OverrideValuesObj: []OverrideValues{
 						{AppName: "TestAppName",
-							ValuesObj: map[string]string{
+							ValuesObj: map[string]interface{}{
 								"imageRepository": "registry.hub.docker.com",
 							}},
 						{AppName: "TestAppName",
-							ValuesObj: map[string]string{
+							ValuesObj: map[string]interface{}{
 								"imageRepository": "registry.hub.docker.com",
 							}},
 					},
@@ -75,11 +75,11 @@ func TestCreateDeploymentIntentGroup(t *testing.T) {
 					Version: "version of deployment",
 					OverrideValuesObj: []OverrideValues{
 						{AppName: "TestAppName",
-							ValuesObj: map[string]string{
+							ValuesObj: map[string]interface{}{
 								"imageRepository": "registry.hub.docker.com",
 							}},
 						{AppName: "TestAppName",
-							ValuesObj: map[string]string{
+							ValuesObj: map[string]interface{}{
 								"imageRepository": "registry.hub.docker.com",
 							}},
 					},
@@ -161,11 +161,11 @@ func TestGetDeploymentIntentGroup(t *testing.T) {
 					Version: "version of deployment",
 					OverrideValuesObj: []OverrideValues{
 						{AppName: "TestAppName",
-							ValuesObj: map[string]string{
+							ValuesObj: map[string]interface{}{
 								"imageRepository": "registry.hub.docker.com",
 							}},
 						{AppName: "TestAppName",
-							ValuesObj: map[string]string{
+							ValuesObj: map[string]interface{}{
 								"imageRepository": "registry.hub.docker.com",
 							}},
 					},
@@ -0,0 +1,456 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/gpic"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// drainPollInterval and drainTimeout bound the wait for rsync to complete
+// the operations of a drain on each DeploymentIntentGroup
+var drainPollInterval = 5 * time.Second
+var drainTimeout = 30 * time.Minute
+
+/*
+ClusterDrain is the progress report of the drain of a cluster. The cluster is
+cordoned, and the apps which an anyOf group of an instantiated
+DeploymentIntentGroup placed on it are moved to another cluster of their
+group, one DeploymentIntentGroup after the other.
+*/
+type ClusterDrain struct {
+	ClusterProvider        string                         `json:"cluster-provider"`
+	Cluster                string                         `json:"cluster"`
+	State                  string                         `json:"state"`
+	StartTime              time.Time                      `json:"start-time"`
+	EndTime                time.Time                      `json:"end-time,omitempty"`
+	DeploymentIntentGroups []DrainedDeploymentIntentGroup `json:"deployment-intent-groups"`
+}
+
+// DrainedDeploymentIntentGroup is the progress of the drain of a
+// DeploymentIntentGroup, with the apps moved away from the cluster
+type DrainedDeploymentIntentGroup struct {
+	Project               string   `json:"project"`
+	CompositeApp          string   `json:"composite-app"`
+	CompositeAppVersion   string   `json:"composite-app-version"`
+	DeploymentIntentGroup string   `json:"deployment-intent-group"`
+	Apps                  []string `json:"apps"`
+	State                 string   `json:"state"`
+	Message               string   `json:"message,omitempty"`
+}
+
+type DrainState = string
+
+type drainStates struct {
+	Draining    DrainState
+	Pending     DrainState
+	Moving      DrainState
+	Terminating DrainState
+	Drained     DrainState
+	Failed      DrainState
+}
+
+// DrainStateEnum lists the states of a ClusterDrain and of its
+// DeploymentIntentGroups. A ClusterDrain is Draining, Drained or Failed.
+var DrainStateEnum = &drainStates{
+	Draining:    "Draining",
+	Pending:     "Pending",
+	Moving:      "Moving",
+	Terminating: "Terminating",
+	Drained:     "Drained",
+	Failed:      "Failed",
+}
+
+// ClusterDrainKey is the key structure that is used in the database
+type ClusterDrainKey struct {
+	ClusterProvider string `json:"clusterprovider"`
+	Cluster         string `json:"clusterdrain"`
+}
+
+// We will use json marshalling to convert to string to
+// preserve the underlying structure.
+func (dk ClusterDrainKey) String() string {
+	out, err := json.Marshal(dk)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// ClusterDrainManager is an interface which exposes the ClusterDrain functionality
+type ClusterDrainManager interface {
+	Drain(provider, cluster string) (ClusterDrain, error)
+	GetDrain(provider, cluster string) (ClusterDrain, error)
+}
+
+// ClusterDrainClient implements the ClusterDrainManager interface
+type ClusterDrainClient struct {
+	storeName     string
+	tagMeta       string
+	instantiation InstantiationManager
+}
+
+// NewClusterDrainClient returns an instance of the ClusterDrainClient
+func NewClusterDrainClient() *ClusterDrainClient {
+	return &ClusterDrainClient{
+		storeName:     "orchestrator",
+		tagMeta:       "clusterdrain",
+		instantiation: NewInstantiationClient(),
+	}
+}
+
+/*
+Drain cordons the cluster and starts moving the apps away from it. It returns
+the initial report, listing the DeploymentIntentGroups to drain. The progress
+is followed with GetDrain.
+*/
+func (c *ClusterDrainClient) Drain(provider, cn string) (ClusterDrain, error) {
+	d, err := c.GetDrain(provider, cn)
+	if err == nil && d.State == DrainStateEnum.Draining {
+		return ClusterDrain{}, pkgerrors.New("Cluster is already being drained")
+	}
+
+	_, err = cluster.NewClusterClient().CordonCluster(provider, cn, true, "")
+	if err != nil {
+		return ClusterDrain{}, pkgerrors.Wrap(err, "Cordoning the cluster")
+	}
+
+	digs, err := findDrainedDeploymentIntentGroups(provider + SEPARATOR + cn)
+	if err != nil {
+		return ClusterDrain{}, err
+	}
+
+	d = ClusterDrain{
+		ClusterProvider:        provider,
+		Cluster:                cn,
+		State:                  DrainStateEnum.Draining,
+		StartTime:              time.Now(),
+		DeploymentIntentGroups: digs,
+	}
+	if len(digs) == 0 {
+		d.State = DrainStateEnum.Drained
+		d.EndTime = d.StartTime
+	}
+	err = c.saveDrain(d)
+	if err != nil {
+		return ClusterDrain{}, err
+	}
+
+	if d.State == DrainStateEnum.Draining {
+		go c.drain(d)
+	}
+	return d, nil
+}
+
+// GetDrain returns the report of the last drain of the cluster
+func (c *ClusterDrainClient) GetDrain(provider, cn string) (ClusterDrain, error) {
+	key := ClusterDrainKey{
+		ClusterProvider: provider,
+		Cluster:         cn,
+	}
+	value, err := db.DBconn.Find(c.storeName, key, c.tagMeta)
+	if err != nil {
+		return ClusterDrain{}, pkgerrors.Wrap(err, "Get ClusterDrain")
+	}
+
+	//value is a byte array
+	if value != nil {
+		d := ClusterDrain{}
+		err = db.DBconn.Unmarshal(value[0], &d)
+		if err != nil {
+			return ClusterDrain{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		return d, nil
+	}
+	return ClusterDrain{}, pkgerrors.New("Error getting ClusterDrain")
+}
+
+/*
+RecoverDrains fails the drains which a restart of the orchestrator
+interrupted. Their cluster stays cordoned, and draining it again moves the
+apps which are still on it.
+*/
+func (c *ClusterDrainClient) RecoverDrains() error {
+	values, err := db.DBconn.Find(c.storeName, ClusterDrainKey{}, c.tagMeta)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Get ClusterDrains")
+	}
+	for _, value := range values {
+		d := ClusterDrain{}
+		err = db.DBconn.Unmarshal(value, &d)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		if d.State != DrainStateEnum.Draining {
+			continue
+		}
+		failInterruptedDrain(&d, time.Now())
+		err = c.saveDrain(d)
+		if err != nil {
+			return err
+		}
+		log.Warn("Failed the drain interrupted by a restart", log.Fields{"Cluster": d.ClusterProvider + SEPARATOR + d.Cluster})
+	}
+	return nil
+}
+
+// failInterruptedDrain fails the drain and the DeploymentIntentGroups whose
+// drain had not completed
+func failInterruptedDrain(d *ClusterDrain, now time.Time) {
+	for i := range d.DeploymentIntentGroups {
+		dig := &d.DeploymentIntentGroups[i]
+		if dig.State != DrainStateEnum.Drained && dig.State != DrainStateEnum.Failed {
+			dig.State = DrainStateEnum.Failed
+			dig.Message = "Drain interrupted by a restart of the orchestrator"
+		}
+	}
+	d.State = DrainStateEnum.Failed
+	d.EndTime = now
+}
+
+func (c *ClusterDrainClient) saveDrain(d ClusterDrain) error {
+	key := ClusterDrainKey{
+		ClusterProvider: d.ClusterProvider,
+		Cluster:         d.Cluster,
+	}
+	err := db.DBconn.Insert(c.storeName, key, nil, c.tagMeta, d)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Updating ClusterDrain")
+	}
+	return nil
+}
+
+// findDrainedDeploymentIntentGroups returns the instantiated
+// DeploymentIntentGroups with apps an anyOf group placed on the cluster,
+// named <provider>+<cluster>
+func findDrainedDeploymentIntentGroups(pc string) ([]DrainedDeploymentIntentGroup, error) {
+	digs := []DrainedDeploymentIntentGroup{}
+	projects, err := NewProjectClient().GetAllProjects()
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Getting projects")
+	}
+	for _, project := range projects {
+		p := project.MetaData.Name
+		cas, err := NewCompositeAppClient().GetAllCompositeApps(p)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Getting composite apps")
+		}
+		for _, compositeApp := range cas {
+			ca, v := compositeApp.Metadata.Name, compositeApp.Spec.Version
+			digList, err := NewDeploymentIntentGroupClient().GetAllDeploymentIntentGroups(p, ca, v)
+			if err != nil {
+				return nil, pkgerrors.Wrap(err, "Getting DeploymentIntentGroups")
+			}
+			for _, dig := range digList {
+				di := dig.MetaData.Name
+				s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+				if err != nil {
+					return nil, pkgerrors.Wrap(err, "Getting DeploymentIntentGroup state")
+				}
+				stateVal, err := state.GetCurrentStateFromStateInfo(s)
+				if err != nil || stateVal != state.StateEnum.Instantiated {
+					continue
+				}
+				ac, err := state.GetAppContextFromId(state.GetLastContextIdFromStateInfo(s))
+				if err != nil {
+					return nil, pkgerrors.Wrap(err, "Getting AppContext of DeploymentIntentGroup "+di)
+				}
+				apps, err := appsInAnyOfGroupOn(ac, pc)
+				if err != nil {
+					return nil, pkgerrors.Wrap(err, "Getting apps of DeploymentIntentGroup "+di)
+				}
+				if len(apps) == 0 {
+					continue
+				}
+				digs = append(digs, DrainedDeploymentIntentGroup{
+					Project:               p,
+					CompositeApp:          ca,
+					CompositeAppVersion:   v,
+					DeploymentIntentGroup: di,
+					Apps:                  apps,
+					State:                 DrainStateEnum.Pending,
+				})
+			}
+		}
+	}
+	return digs, nil
+}
+
+// appsInAnyOfGroupOn returns the apps of the AppContext which an anyOf group
+// placed on the cluster
+func appsInAnyOfGroupOn(ac appcontext.AppContext, pc string) ([]string, error) {
+	order, err := appOrder(ac)
+	if err != nil {
+		return nil, err
+	}
+	apps := []string{}
+	for _, app := range order {
+		gmap, err := ac.GetClusterGroupMap(app)
+		if err != nil {
+			return nil, err
+		}
+	groups:
+		for _, clusters := range gmap {
+			for _, cn := range clusters {
+				if cn == pc {
+					apps = append(apps, app)
+					break groups
+				}
+			}
+		}
+	}
+	return apps, nil
+}
+
+// drain drains the DeploymentIntentGroups of the report one after the other
+// and records the progress
+func (c *ClusterDrainClient) drain(d ClusterDrain) {
+	pc := d.ClusterProvider + SEPARATOR + d.Cluster
+	d.State = DrainStateEnum.Drained
+	for i := range d.DeploymentIntentGroups {
+		dig := &d.DeploymentIntentGroups[i]
+		err := c.drainDeploymentIntentGroup(d, dig)
+		if err != nil {
+			log.Error("Error draining DeploymentIntentGroup", log.Fields{"Cluster": pc, "DeploymentIntentGroup": dig.DeploymentIntentGroup, "Error": err})
+			dig.State = DrainStateEnum.Failed
+			dig.Message = err.Error()
+			d.State = DrainStateEnum.Failed
+		} else {
+			dig.State = DrainStateEnum.Drained
+		}
+		c.saveProgress(d)
+	}
+	d.EndTime = time.Now()
+	c.saveProgress(d)
+	log.Info("Drained cluster", log.Fields{"Cluster": pc, "State": d.State})
+}
+
+func (c *ClusterDrainClient) saveProgress(d ClusterDrain) {
+	err := c.saveDrain(d)
+	if err != nil {
+		log.Warn("Error saving the progress of the drain", log.Fields{"Cluster": d.Cluster, "Error": err})
+	}
+}
+
+/*
+drainDeploymentIntentGroup moves the apps of the DeploymentIntentGroup away
+from the cluster. The DeploymentIntentGroup is updated, and the cordoned
+cluster is left out of its anyOf groups. The update terminates the resources
+the former AppContext deployed to the cluster, and the drain of the
+DeploymentIntentGroup is done once rsync has instantiated the new AppContext
+and terminated the former one.
+*/
+func (c *ClusterDrainClient) drainDeploymentIntentGroup(d ClusterDrain, dig *DrainedDeploymentIntentGroup) error {
+	p, ca, v, di := dig.Project, dig.CompositeApp, dig.CompositeAppVersion, dig.DeploymentIntentGroup
+
+	dig.State = DrainStateEnum.Moving
+	c.saveProgress(d)
+
+	s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Getting DeploymentIntentGroup state")
+	}
+	prevCtxID := state.GetLastContextIdFromStateInfo(s)
+
+	// An app whose anyOf group only had the cordoned cluster would be placed
+	// nowhere by the update, and removed from the cluster
+	err = checkEligibleClusters(p, ca, v, di, dig.Apps)
+	if err != nil {
+		return err
+	}
+
+	err = c.instantiation.Update(p, ca, v, di)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Updating DeploymentIntentGroup")
+	}
+	s, err = NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Getting DeploymentIntentGroup state")
+	}
+	ctxID := state.GetLastContextIdFromStateInfo(s)
+	err = waitForAppContextStatus(ctxID, appcontext.AppContextStatusEnum.Instantiated, appcontext.AppContextStatusEnum.InstantiateFailed)
+	if err != nil {
+		return err
+	}
+
+	dig.State = DrainStateEnum.Terminating
+	c.saveProgress(d)
+
+	// The update handed the former AppContext to rsync, to terminate the
+	// resources it deployed to the cluster
+	err = waitForAppContextStatus(prevCtxID, appcontext.AppContextStatusEnum.Terminated, appcontext.AppContextStatusEnum.TerminateFailed)
+	if err != nil {
+		return err
+	}
+	deleteRetiredAppContexts(s)
+	return nil
+}
+
+// checkEligibleClusters resolves the placement intents of the apps, which
+// leave out the cordoned clusters, and fails when an anyOf group of an app
+// has no eligible cluster left
+func checkEligibleClusters(p, ca, v, di string, apps []string) error {
+	gIntent, err := findGenericPlacementIntent(p, ca, v, di)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		specData, err := NewAppIntentClient().GetAllIntentsByApp(app, p, ca, v, gIntent, di)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Unable to get the intents for app "+app)
+		}
+		l, err := gpic.IntentResolver(specData.Intent)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Unable to resolve the intents for app "+app)
+		}
+		for _, g := range l.ClusterGroups {
+			if len(g.OptionalClusters) == 0 {
+				return pkgerrors.New("No other eligible cluster for app " + app)
+			}
+		}
+	}
+	return nil
+}
+
+// waitForAppContextStatus waits for rsync to bring the AppContext to the
+// done status, and fails on the failed status or after drainTimeout
+func waitForAppContextStatus(ctxID string, done, failed appcontext.StatusValue) error {
+	deadline := time.Now().Add(drainTimeout)
+	for time.Now().Before(deadline) {
+		// The status is not set until rsync has started on the AppContext
+		s, err := state.GetAppContextStatus(ctxID)
+		if err == nil {
+			switch s.Status {
+			case done:
+				return nil
+			case failed:
+				return pkgerrors.Errorf("AppContext %s is %s", ctxID, s.Status)
+			}
+		}
+		time.Sleep(drainPollInterval)
+	}
+	return pkgerrors.Errorf("Timed out waiting for AppContext %s to be %s", ctxID, done)
+}
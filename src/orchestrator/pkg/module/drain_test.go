@@ -0,0 +1,81 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+)
+
+func TestDrain(t *testing.T) {
+	clusterKey := fmt.Sprintf("%v", cluster.ClusterKey{ClusterProviderName: "p1", ClusterName: "c1"})
+	drainKey := ClusterDrainKey{ClusterProvider: "p1", Cluster: "c2"}.String()
+	db.DBconn = &db.MockDB{
+		Items: map[string]map[string][]byte{
+			clusterKey: {"clustermetadata": []byte(`{"metadata":{"name":"c1"}}`)},
+			drainKey:   {"clusterdrain": []byte(`{"cluster-provider":"p1","cluster":"c2","state":"Draining"}`)},
+		},
+	}
+	c := &ClusterDrainClient{
+		storeName:     "orchestrator",
+		tagMeta:       "clusterdrain",
+		instantiation: &mockInstantiation{},
+	}
+
+	// Without DeploymentIntentGroups on the cluster, the drain is done
+	// once the cluster is cordoned
+	d, err := c.Drain("p1", "c1")
+	if err != nil {
+		t.Fatalf("Drain returned an error: %s", err)
+	}
+	if d.State != DrainStateEnum.Drained || len(d.DeploymentIntentGroups) != 0 {
+		t.Errorf("Expected the cluster to be drained, got %+v", d)
+	}
+
+	if _, err := c.Drain("p1", "c2"); err == nil {
+		t.Errorf("Expected an error draining a cluster which is being drained")
+	}
+	if _, err := c.Drain("p1", "c3"); err == nil {
+		t.Errorf("Expected an error draining a missing cluster")
+	}
+}
+
+func TestFailInterruptedDrain(t *testing.T) {
+	d := ClusterDrain{
+		State: DrainStateEnum.Draining,
+		DeploymentIntentGroups: []DrainedDeploymentIntentGroup{
+			{DeploymentIntentGroup: "dig1", State: DrainStateEnum.Drained},
+			{DeploymentIntentGroup: "dig2", State: DrainStateEnum.Moving},
+			{DeploymentIntentGroup: "dig3", State: DrainStateEnum.Pending},
+		},
+	}
+	now := time.Now()
+	failInterruptedDrain(&d, now)
+	if d.State != DrainStateEnum.Failed || !d.EndTime.Equal(now) {
+		t.Errorf("Expected the drain to be failed, got %+v", d)
+	}
+	expected := []DrainState{DrainStateEnum.Drained, DrainStateEnum.Failed, DrainStateEnum.Failed}
+	for i, dig := range d.DeploymentIntentGroups {
+		if dig.State != expected[i] {
+			t.Errorf("Expected %s to be %s, got %s", dig.DeploymentIntentGroup, expected[i], dig.State)
+		}
+	}
+}
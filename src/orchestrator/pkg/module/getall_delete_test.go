@@ -77,6 +77,34 @@ func TestGetApps(t *testing.T) {
 	})
 }
 
+func TestListApps(t *testing.T) {
+	items := map[string]map[string][]byte{}
+	items[fmt.Sprintf("%v", AppKey{App: "", Project: gdProject, CompositeApp: gdCompositeApp, CompositeAppVersion: gdVersion})] = map[string][]byte{
+		"appmetadata": []byte("{\"metadata\":{\"name\":\"app1\"}}"),
+	}
+
+	t.Run("List Last Page Of Apps", func(t *testing.T) {
+		db.DBconn = &db.MockDB{Items: items}
+		impl := NewAppClient()
+		got, next, err := impl.ListApps(gdProject, gdCompositeApp, gdVersion, db.ListOptions{Limit: 1, Sort: "-name"})
+		if err != nil {
+			t.Fatalf("ListApps returned an unexpected error %s", err)
+		}
+		if len(got) != 1 || next != "" {
+			t.Fatalf("ListApps returned unexpected page: %v, %q", got, next)
+		}
+	})
+
+	t.Run("List Apps Unsupported Filter", func(t *testing.T) {
+		db.DBconn = &db.MockDB{Items: items}
+		impl := NewAppClient()
+		_, _, err := impl.ListApps(gdProject, gdCompositeApp, gdVersion, db.ListOptions{Filters: map[string][]string{"state": {"Instantiated"}}})
+		if _, ok := pkgerrors.Cause(err).(*db.ListOptionsError); !ok {
+			t.Fatalf("ListApps expected ListOptionsError, got %v", err)
+		}
+	})
+}
+
 func TestGetAllCompositeApps(t *testing.T) {
 	t.Run("Get All Composite Apps", func(t *testing.T) {
 		items := map[string]map[string][]byte{}
@@ -182,6 +210,77 @@ func TestGetAllDeploymentIntentGroups(t *testing.T) {
 	})
 }
 
+func TestBackfillStates(t *testing.T) {
+	items := map[string]map[string][]byte{}
+	gdProjectItem(items)
+	gdCompositeAppItem(items)
+	items[ProjectKey{ProjectName: ""}.String()] = map[string][]byte{
+		"projectmetadata": []byte("{\"metadata\":{\"Name\":\"testProject\"}}"),
+	}
+	items[CompositeAppKey{CompositeAppName: "", Version: "", Project: gdProject}.String()] = map[string][]byte{
+		"compositeappmetadata": []byte("{\"metadata\":{\"Name\":\"testCompositeApp\"},\"spec\":{\"Version\":\"v1\"}}"),
+	}
+	items[DeploymentIntentGroupKey{Name: "", Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion}.String()] = map[string][]byte{
+		"deploymentintentgroupmetadata": []byte("{\"metadata\":{\"name\":\"testDig\"}}"),
+	}
+	items[DeploymentIntentGroupKey{Name: gdDig, Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion}.String()] = map[string][]byte{
+		"stateInfo": []byte("{\"actions\":[{\"state\":\"Created\",\"instance\":\"\",\"time\":\"2020-01-01T00:00:00Z\"}]}"),
+	}
+	digKey := DeploymentIntentGroupKey{Name: gdDig, Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion}.String()
+
+	testCases := []struct {
+		label    string
+		state    bool
+		versions map[string]int64
+		expected int
+	}{
+		{
+			label:    "Store the state of a DeploymentIntentGroup without one",
+			versions: map[string]int64{digKey: 3},
+			expected: 1,
+		},
+		{
+			label:    "Leave a DeploymentIntentGroup with a state",
+			state:    true,
+			versions: map[string]int64{digKey: 3},
+			expected: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			if testCase.state {
+				items[digKey]["state"] = []byte("Created")
+			} else {
+				delete(items[digKey], "state")
+			}
+			mdb := &db.MockDB{Items: items, Versions: testCase.versions}
+			db.DBconn = mdb
+
+			n, err := NewDeploymentIntentGroupClient().BackfillStates()
+			if err != nil {
+				t.Fatalf("BackfillStates returned an unexpected error %s", err)
+			}
+			if n != testCase.expected {
+				t.Fatalf("BackfillStates stored %d DeploymentIntentGroups again, expected %d", n, testCase.expected)
+			}
+			if testCase.expected > 0 && mdb.Versions[digKey] != 4 {
+				t.Errorf("BackfillStates did not store the DeploymentIntentGroup at its version, got version %d", mdb.Versions[digKey])
+			}
+		})
+	}
+}
+
+func TestStateQuery(t *testing.T) {
+	s := state.StateInfo{Actions: []state.ActionEntry{
+		{State: state.StateEnum.Created},
+		{State: state.StateEnum.Approved},
+	}}
+	if got := stateQuery(s)["state"]; got != state.StateEnum.Approved {
+		t.Fatalf("stateQuery returned state %q, expected %q", got, state.StateEnum.Approved)
+	}
+}
+
 func TestGetDeploymentIntentGroupState(t *testing.T) {
 	t.Run("Get Deployment Intent Group State", func(t *testing.T) {
 		items := map[string]map[string][]byte{}
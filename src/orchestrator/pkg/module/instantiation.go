@@ -27,6 +27,8 @@ import (
 	gpic "github.com/onap/multicloud-k8s/src/orchestrator/pkg/gpic"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
 	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/metrics"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/module/controller"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/status"
 	"github.com/onap/multicloud-k8s/src/orchestrator/utils/helm"
@@ -45,6 +47,9 @@ const SEPARATOR = "+"
 // InstantiationClient implements the InstantiationManager
 type InstantiationClient struct {
 	db InstantiationClientDbInfo
+	// ifMatch is the If-Match header the version of the DeploymentIntentGroup
+	// has to match for the operations to change it
+	ifMatch string
 }
 
 type DeploymentStatus struct {
@@ -73,10 +78,14 @@ type InstantiationKey struct {
 // InstantiationManager is an interface which exposes the
 // InstantiationManager functionalities
 type InstantiationManager interface {
-	Approve(p string, ca string, v string, di string) error
+	Approve(p string, ca string, v string, di string, a Approval) (ApprovalStatus, error)
+	Reject(p string, ca string, v string, di string, a Approval) (ApprovalStatus, error)
+	Validate(p string, ca string, v string, di string) (ValidationReport, error)
 	Instantiate(p string, ca string, v string, di string) error
 	Status(p, ca, v, di, qInstance, qType, qOutput string, qApps, qClusters, qResources []string) (DeploymentStatus, error)
 	Terminate(p string, ca string, v string, di string) error
+	Update(p string, ca string, v string, di string) error
+	IfMatch(header string) InstantiationManager
 }
 
 // InstantiationClientDbInfo consists of storeName and tagState
@@ -95,61 +104,223 @@ func NewInstantiationClient() *InstantiationClient {
 	}
 }
 
-//Approve approves an instantiation
-func (c InstantiationClient) Approve(p string, ca string, v string, di string) error {
-	s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+// IfMatch returns the InstantiationManager whose operations only change the
+// DeploymentIntentGroup at a version the If-Match header matches. They return
+// a VersionConflictError otherwise.
+func (c InstantiationClient) IfMatch(header string) InstantiationManager {
+	c.ifMatch = header
+	return &c
+}
+
+/*
+Approve records the approval of the DeploymentIntentGroup by the approver. The
+DeploymentIntentGroup is Approved once the approvals satisfy the approval policy
+of the project, or on the first approval when the project has no policy.
+*/
+func (c InstantiationClient) Approve(p string, ca string, v string, di string, a Approval) (ApprovalStatus, error) {
+	s, version, err := c.getStateInfo(p, ca, v, di)
 	if err != nil {
 		log.Info("DeploymentIntentGroup has no state info ", log.Fields{"DeploymentIntentGroup: ": di})
-		return pkgerrors.Wrap(err, "DeploymentIntentGroup has no state info: "+di)
+		return ApprovalStatus{}, pkgerrors.Wrap(err, "DeploymentIntentGroup has no state info: "+di)
 	}
 	stateVal, err := state.GetCurrentStateFromStateInfo(s)
 	if err != nil {
 		log.Info("Error getting current state from DeploymentIntentGroup stateInfo", log.Fields{"DeploymentIntentGroup ": di})
-		return pkgerrors.Errorf("Error getting current state from DeploymentIntentGroup stateInfo: " + di)
+		return ApprovalStatus{}, pkgerrors.Errorf("Error getting current state from DeploymentIntentGroup stateInfo: " + di)
+	}
+	ap, err := getApprovalPolicy(p)
+	if err != nil {
+		return ApprovalStatus{}, err
 	}
 	switch stateVal {
 	case state.StateEnum.Approved:
-		return nil
+		return approvalStatus(s, ap)
 	case state.StateEnum.Terminated:
 		break
 	case state.StateEnum.Created:
 		break
 	case state.StateEnum.Applied:
-		return pkgerrors.Errorf("DeploymentIntentGroup is in an invalid state" + stateVal)
+		return ApprovalStatus{}, pkgerrors.Errorf("DeploymentIntentGroup is in an invalid state" + stateVal)
 	case state.StateEnum.Instantiated:
-		return pkgerrors.Errorf("DeploymentIntentGroup has already been instantiated" + di)
+		return ApprovalStatus{}, pkgerrors.Errorf("DeploymentIntentGroup has already been instantiated" + di)
 	default:
-		return pkgerrors.Errorf("DeploymentIntentGroup is in an unknown state" + stateVal)
+		return ApprovalStatus{}, pkgerrors.Errorf("DeploymentIntentGroup is in an unknown state" + stateVal)
+	}
+
+	err = checkApprover(ap, a)
+	if err != nil {
+		return ApprovalStatus{}, err
+	}
+
+	// The objects the intents reference may have changed since the creation
+	report, err := c.Validate(p, ca, v, di)
+	if err != nil {
+		return ApprovalStatus{}, err
+	}
+	if !report.Valid {
+		return ApprovalStatus{}, &ValidationReportError{Report: report}
 	}
 
+	now := time.Now()
+	s.Approvals = append(s.Approvals, state.ApprovalEntry{
+		Approver:  a.Approver,
+		Roles:     a.Roles,
+		Decision:  state.DecisionEnum.Approve,
+		Comment:   a.Comment,
+		TimeStamp: now,
+	})
+	count, err := countApprovals(s, ap, now)
+	if err != nil {
+		return ApprovalStatus{}, err
+	}
+	if count >= requiredApprovals(ap) {
+		s.Actions = append(s.Actions, state.ActionEntry{
+			State:     state.StateEnum.Approved,
+			ContextId: "",
+			TimeStamp: now,
+		})
+	}
+
+	_, err = c.saveStateInfo(p, ca, v, di, s, version)
+	if err != nil {
+		return ApprovalStatus{}, err
+	}
+
+	return approvalStatus(s, ap)
+}
+
+/*
+Reject records the rejection of the DeploymentIntentGroup by the approver. The
+approvals given so far no longer count, and an Approved DeploymentIntentGroup
+goes back to Created.
+*/
+func (c InstantiationClient) Reject(p string, ca string, v string, di string, a Approval) (ApprovalStatus, error) {
+	s, version, err := c.getStateInfo(p, ca, v, di)
+	if err != nil {
+		return ApprovalStatus{}, pkgerrors.Wrap(err, "DeploymentIntentGroup has no state info: "+di)
+	}
+	stateVal, err := state.GetCurrentStateFromStateInfo(s)
+	if err != nil {
+		return ApprovalStatus{}, pkgerrors.Errorf("Error getting current state from DeploymentIntentGroup stateInfo: " + di)
+	}
+	switch stateVal {
+	case state.StateEnum.Approved:
+		break
+	case state.StateEnum.Terminated:
+		break
+	case state.StateEnum.Created:
+		break
+	case state.StateEnum.Instantiated:
+		return ApprovalStatus{}, pkgerrors.Errorf("DeploymentIntentGroup has already been instantiated" + di)
+	default:
+		return ApprovalStatus{}, pkgerrors.Errorf("DeploymentIntentGroup is in an invalid state" + stateVal)
+	}
+
+	ap, err := getApprovalPolicy(p)
+	if err != nil {
+		return ApprovalStatus{}, err
+	}
+	err = checkApprover(ap, a)
+	if err != nil {
+		return ApprovalStatus{}, err
+	}
+
+	now := time.Now()
+	s.Approvals = append(s.Approvals, state.ApprovalEntry{
+		Approver:  a.Approver,
+		Roles:     a.Roles,
+		Decision:  state.DecisionEnum.Reject,
+		Comment:   a.Comment,
+		TimeStamp: now,
+	})
+	if stateVal == state.StateEnum.Approved {
+		s.Actions = append(s.Actions, state.ActionEntry{
+			State:     state.StateEnum.Created,
+			ContextId: "",
+			TimeStamp: now,
+		})
+	}
+
+	_, err = c.saveStateInfo(p, ca, v, di, s, version)
+	if err != nil {
+		return ApprovalStatus{}, err
+	}
+
+	return approvalStatus(s, ap)
+}
+
+// approvalStatus returns the progress of the approval of the DeploymentIntentGroup
+func approvalStatus(s state.StateInfo, ap *ApprovalPolicy) (ApprovalStatus, error) {
+	stateVal, err := state.GetCurrentStateFromStateInfo(s)
+	if err != nil {
+		return ApprovalStatus{}, err
+	}
+	count, err := countApprovals(s, ap, time.Now())
+	if err != nil {
+		return ApprovalStatus{}, err
+	}
+	entries := s.Approvals
+	if entries == nil {
+		entries = []state.ApprovalEntry{}
+	}
+	return ApprovalStatus{
+		State:             stateVal,
+		Approvals:         count,
+		RequiredApprovals: requiredApprovals(ap),
+		Entries:           entries,
+	}, nil
+}
+
+// getStateInfo returns the stateInfo of the DeploymentIntentGroup and the
+// version of the DeploymentIntentGroup it was read at
+func (c InstantiationClient) getStateInfo(p, ca, v, di string) (state.StateInfo, int64, error) {
+	digClient := NewDeploymentIntentGroupClient()
+	// The version is read first, so that a stateInfo saved in between is
+	// detected by saveStateInfo
+	version, err := digClient.GetDeploymentIntentGroupVersion(di, p, ca, v)
+	if err != nil {
+		return state.StateInfo{}, 0, err
+	}
+	// The stateInfo is saved at this version, so the If-Match header holds
+	// until then
+	if !db.IfMatch(c.ifMatch, version) {
+		return state.StateInfo{}, 0, pkgerrors.Wrap(&db.VersionConflictError{Version: version},
+			"The DeploymentIntentGroup does not match If-Match "+c.ifMatch)
+	}
+	s, err := digClient.GetDeploymentIntentGroupState(di, p, ca, v)
+	if err != nil {
+		return state.StateInfo{}, 0, err
+	}
+	return s, version, nil
+}
+
+// saveStateInfo stores the stateInfo of the DeploymentIntentGroup if the
+// DeploymentIntentGroup is still at the version the stateInfo was read at,
+// and returns the new version. A VersionConflictError is returned otherwise.
+func (c InstantiationClient) saveStateInfo(p, ca, v, di string, s state.StateInfo, version int64) (int64, error) {
 	key := DeploymentIntentGroupKey{
 		Name:         di,
 		Project:      p,
 		CompositeApp: ca,
 		Version:      v,
 	}
-	a := state.ActionEntry{
-		State:     state.StateEnum.Approved,
-		ContextId: "",
-		TimeStamp: time.Now(),
-	}
-	s.Actions = append(s.Actions, a)
-
-	err = db.DBconn.Insert(c.db.storeName, key, nil, c.db.tagState, s)
+	version, err := db.DBconn.InsertIfVersion(c.db.storeName, key, stateQuery(s), c.db.tagState, s, version)
 	if err != nil {
-		return pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+di)
+		return 0, pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+di)
 	}
-
-	return nil
+	return version, nil
 }
 
-func getOverrideValuesByAppName(ov []OverrideValues, a string) map[string]string {
+func getOverrideValuesByAppName(ov []OverrideValues, a string) map[string]interface{} {
+	values := map[string]interface{}{}
 	for _, eachOverrideVal := range ov {
-		if eachOverrideVal.AppName == a {
-			return eachOverrideVal.ValuesObj
+		if eachOverrideVal.AppName == a && eachOverrideVal.ClusterProvider == "" {
+			for k, v := range eachOverrideVal.ValuesObj {
+				values[k] = v
+			}
 		}
 	}
-	return map[string]string{}
+	return values
 }
 
 /*
@@ -175,18 +346,24 @@ func findGenericPlacementIntent(p, ca, v, di string) (string, error) {
 // GetSortedTemplateForApp returns the sorted templates.
 //It takes in arguments - appName, project, compositeAppName, releaseName, compositeProfileName, array of override values
 func GetSortedTemplateForApp(appName, p, ca, v, rName, cp string, overrideValues []OverrideValues) ([]helm.KubernetesResourceTemplate, error) {
+	return getSortedTemplateForAppWithValues(appName, p, ca, v, rName, cp, getOverrideValuesByAppName(overrideValues, appName))
+}
+
+// getSortedTemplateForAppWithValues returns the sorted templates of the app
+// rendered with the given override values
+func getSortedTemplateForAppWithValues(appName, p, ca, v, rName, cp string, overrideValuesOfApp map[string]interface{}) ([]helm.KubernetesResourceTemplate, error) {
 
 	log.Info(":: Processing App ::", log.Fields{"appName": appName})
 
 	var sortedTemplates []helm.KubernetesResourceTemplate
 
-	aC, err := NewAppClient().GetAppContent(appName, p, ca, v)
+	app, err := NewAppClient().GetApp(appName, p, ca, v)
 	if err != nil {
-		return sortedTemplates, pkgerrors.Wrap(err, fmt.Sprint("Not finding the content of app:: ", appName))
+		return sortedTemplates, pkgerrors.Wrap(err, fmt.Sprint("Not finding the app:: ", appName))
 	}
-	appContent, err := base64.StdEncoding.DecodeString(aC.FileContent)
+	appContent, err := getAppContent(app, p, ca, v)
 	if err != nil {
-		return sortedTemplates, pkgerrors.Wrap(err, "Fail to convert to byte array")
+		return sortedTemplates, err
 	}
 
 	log.Info(":: Got the app content.. ::", log.Fields{"appName": appName})
@@ -202,19 +379,13 @@ func GetSortedTemplateForApp(appName, p, ca, v, rName, cp string, overrideValues
 
 	log.Info(":: Got the app Profile content .. ::", log.Fields{"appName": appName})
 
-	overrideValuesOfApp := getOverrideValuesByAppName(overrideValues, appName)
-	//Convert override values from map to array of strings of the following format
-	//foo=bar
-	overrideValuesOfAppStr := []string{}
-	if overrideValuesOfApp != nil {
-		for k, v := range overrideValuesOfApp {
-			overrideValuesOfAppStr = append(overrideValuesOfAppStr, k+"="+v)
-		}
+	resolver, err := getResolverForApp(app, rName)
+	if err != nil {
+		return sortedTemplates, err
 	}
 
-	sortedTemplates, err = helm.NewTemplateClient("", "default", rName,
-		ManifestFileName).Resolve(appContent,
-		appProfileContent, overrideValuesOfAppStr,
+	sortedTemplates, err = resolver.Resolve(appContent,
+		appProfileContent, overrideValuesOfApp,
 		appName)
 
 	log.Info(":: Total no. of sorted templates ::", log.Fields{"len(sortedTemplates):": len(sortedTemplates)})
@@ -222,12 +393,28 @@ func GetSortedTemplateForApp(appName, p, ca, v, rName, cp string, overrideValues
 	return sortedTemplates, err
 }
 
+// getResolverForApp returns the resolver for the format of the app content
+func getResolverForApp(app App, rName string) (helm.Resolver, error) {
+	switch app.Spec.Format {
+	case "", AppFormatHelm:
+		return helm.NewTemplateClient("", "default", rName, ManifestFileName), nil
+	case AppFormatManifests:
+		return helm.NewManifestClient(ManifestFileName), nil
+	case AppFormatKustomize:
+		return helm.NewKustomizeClient(ManifestFileName), nil
+	}
+	return nil, pkgerrors.Errorf("Unsupported format %s of app %s", app.Spec.Format, app.Metadata.Name)
+}
+
 func calculateDirPath(fp string) string {
 	sa := strings.Split(fp, "/")
 	return "/" + sa[1] + "/" + sa[2] + "/"
 }
 
 func cleanTmpfiles(sortedTemplates []helm.KubernetesResourceTemplate) error {
+	if len(sortedTemplates) == 0 {
+		return nil
+	}
 	dp := calculateDirPath(sortedTemplates[0].FilePath)
 	for _, st := range sortedTemplates {
 		log.Info("Clean up ::", log.Fields{"file: ": st.FilePath})
@@ -251,14 +438,15 @@ Instantiate methods takes in projectName, compositeAppName, compositeAppVersion,
 DeploymentIntentName. This method is responsible for template resolution, intent
 resolution, creation and saving of context for saving into etcd.
 */
-func (c InstantiationClient) Instantiate(p string, ca string, v string, di string) error {
+func (c InstantiationClient) Instantiate(p string, ca string, v string, di string) (err error) {
+	defer func(start time.Time) { metrics.ObserveLifecycleOperation("instantiate", start, err) }(time.Now())
 
 	dIGrp, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroup(di, p, ca, v)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Not finding the deploymentIntentGroup")
 	}
 
-	s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+	s, version, err := c.getStateInfo(p, ca, v, di)
 	if err != nil {
 		return pkgerrors.Errorf("Error retrieving DeploymentIntentGroup stateInfo: " + di)
 	}
@@ -281,6 +469,57 @@ func (c InstantiationClient) Instantiate(p string, ca string, v string, di strin
 		return pkgerrors.Errorf("DeploymentIntentGroup is in an unknown state" + stateVal)
 	}
 
+	return c.instantiate(p, ca, v, di, dIGrp, s, version, "")
+}
+
+/*
+Update takes in projectName, compositeAppName, compositeAppVersion,
+DeploymentIntentName. It builds a new AppContext for an instantiated
+DeploymentIntentGroup from its current intents and deploys it in place
+of the AppContext it was instantiated with. The resources the former
+AppContext deployed and the new one does not are terminated.
+*/
+func (c InstantiationClient) Update(p string, ca string, v string, di string) (err error) {
+	defer func(start time.Time) { metrics.ObserveLifecycleOperation("update", start, err) }(time.Now())
+
+	dIGrp, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroup(di, p, ca, v)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Not finding the deploymentIntentGroup")
+	}
+
+	s, version, err := c.getStateInfo(p, ca, v, di)
+	if err != nil {
+		return pkgerrors.Errorf("Error retrieving DeploymentIntentGroup stateInfo: " + di)
+	}
+	stateVal, err := state.GetCurrentStateFromStateInfo(s)
+	if err != nil {
+		return pkgerrors.Errorf("Error getting current state from DeploymentIntentGroup stateInfo: " + di)
+	}
+	if stateVal != state.StateEnum.Instantiated {
+		return pkgerrors.Errorf("DeploymentIntentGroup is not instantiated :" + di)
+	}
+
+	return c.instantiate(p, ca, v, di, dIGrp, s, version, state.GetLastContextIdFromStateInfo(s))
+}
+
+/*
+instantiate builds the AppContext of the DeploymentIntentGroup, has it updated
+by the placement and action controllers and hands it to rsync. When updateFrom
+is set, the new AppContext replaces the AppContext with that id and the action
+controllers are called with both, in priority order. Once the new
+AppContext is handed to rsync, the former one is handed to rsync to terminate
+the resources the new one no longer deploys. The stateInfo is saved before
+rsync is called, so that only one of concurrent operations on the
+DeploymentIntentGroup, read at the version, reaches rsync.
+*/
+func (c InstantiationClient) instantiate(p, ca, v, di string, dIGrp DeploymentIntentGroup, s state.StateInfo, version int64, updateFrom string) error {
+	deleteRetiredAppContexts(s)
+
+	err := checkControllersAvailable(p, ca, v, di)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Unable to instantiate DeploymentIntentGroup "+di)
+	}
+
 	rName := dIGrp.Spec.Version //rName is releaseName
 	overrideValues := dIGrp.Spec.OverrideValuesObj
 	cp := dIGrp.Spec.Profile
@@ -320,24 +559,6 @@ func (c InstantiationClient) Instantiate(p string, ca string, v string, di strin
 		appOrderInstr.Apporder = append(appOrderInstr.Apporder, eachApp.Metadata.Name)
 		appdep[eachApp.Metadata.Name] = "go"
 
-		sortedTemplates, err := GetSortedTemplateForApp(eachApp.Metadata.Name, p, ca, v, rName, cp, overrideValues)
-
-		if err != nil {
-			deleteAppContext(context)
-			log.Error("Unable to get the sorted templates for app", log.Fields{})
-			return pkgerrors.Wrap(err, "Unable to get the sorted templates for app")
-		}
-
-		log.Info(":: Resolved all the templates ::", log.Fields{"appName": eachApp.Metadata.Name, "SortedTemplate": sortedTemplates})
-
-		resources, err := getResources(sortedTemplates)
-		if err != nil {
-			deleteAppContext(context)
-			return pkgerrors.Wrapf(err, "Unable to get the resources for app :: %s", eachApp.Metadata.Name)
-		}
-
-		defer cleanTmpfiles(sortedTemplates)
-
 		specData, err := NewAppIntentClient().GetAllIntentsByApp(eachApp.Metadata.Name, p, ca, v, gIntent, di)
 		if err != nil {
 			deleteAppContext(context)
@@ -352,6 +573,14 @@ func (c InstantiationClient) Instantiate(p string, ca string, v string, di strin
 
 		log.Info(":: listOfClusters ::", log.Fields{"listOfClusters": listOfClusters})
 
+		// The app is rendered once, or once per distinct set of override
+		// values when some of its override values are scoped to clusters
+		resources, err := getClusterResources(eachApp.Metadata.Name, p, ca, v, rName, cp, overrideValues, listOfClusters)
+		if err != nil {
+			deleteAppContext(context)
+			return pkgerrors.Wrapf(err, "Unable to get the resources for app :: %s", eachApp.Metadata.Name)
+		}
+
 		//BEGIN: storing into etcd
 		// Add an app to the app context
 		apphandle, err := context.AddApp(compositeHandle, eachApp.Metadata.Name)
@@ -415,41 +644,53 @@ func (c InstantiationClient) Instantiate(p string, ca string, v string, di strin
 		return pkgerrors.Wrap(err, "Error deleting extra clusters")
 	}
 
-	err = callGrpcForControllerList(pl.pActCont, mapOfControllers, ctxval)
+	if updateFrom == "" {
+		err = callGrpcForControllerList(pl.pActCont, mapOfControllers, ctxval)
+	} else {
+		err = callGrpcUpdateForControllerList(pl.pActCont, mapOfControllers, updateFrom, ctxval)
+	}
 	if err != nil {
 		deleteAppContext(context)
 		return pkgerrors.Wrap(err, "Error calling gRPC for action controller list")
 	}
 	// END: Scheduler code
 
-	// BEGIN : Rsync code
-	err = callRsyncInstall(ctxval)
-	if err != nil {
-		deleteAppContext(context)
-		return pkgerrors.Wrap(err, "Error calling rsync")
-	}
-	// END : Rsyc code
-
 	// BEGIN:: save the context in the orchestrator db record
-	key := DeploymentIntentGroupKey{
-		Name:         di,
-		Project:      p,
-		CompositeApp: ca,
-		Version:      v,
-	}
 	a := state.ActionEntry{
 		State:     state.StateEnum.Instantiated,
 		ContextId: ctxval.(string),
 		TimeStamp: time.Now(),
 	}
-	s.Actions = append(s.Actions, a)
-	err = db.DBconn.Insert(c.db.storeName, key, nil, c.db.tagState, s)
+	prev := s
+	s.Actions = append(s.Actions[:len(s.Actions):len(s.Actions)], a)
+	version, err = c.saveStateInfo(p, ca, v, di, s, version)
 	if err != nil {
 		log.Warn(":: Error updating DeploymentIntentGroup state in DB ::", log.Fields{"Error": err.Error(), "GPIntent": gIntent, "DeploymentIntentGroup": di, "CompositeApp": ca, "CompositeAppVersion": v, "Project": p, "AppContext": ctxval.(string)})
-		return pkgerrors.Wrap(err, "Error adding DeploymentIntentGroup state to DB")
+		deleteAppContext(context)
+		return err
 	}
 	// END:: save the context in the orchestrator db record
 
+	// BEGIN : Rsync code
+	err = callRsyncInstall(ctxval)
+	if err != nil {
+		deleteAppContext(context)
+		// Nothing was handed to rsync, the DeploymentIntentGroup keeps its previous state
+		if _, serr := c.saveStateInfo(p, ca, v, di, prev, version); serr != nil {
+			log.Warn(":: Error restoring DeploymentIntentGroup state in DB ::", log.Fields{"Error": serr.Error(), "DeploymentIntentGroup": di})
+		}
+		return pkgerrors.Wrap(err, "Error calling rsync")
+	}
+	// END : Rsyc code
+
+	if updateFrom != "" {
+		err = retireAppContext(updateFrom, context)
+		if err != nil {
+			log.Error(":: Error terminating the resources removed by the update ::", log.Fields{"DeploymentIntentGroup": di, "AppContext": updateFrom, "Error": err})
+			return pkgerrors.Wrap(err, "Error terminating the resources removed by the update")
+		}
+	}
+
 	log.Info(":: Done with instantiation call to rsync... ::", log.Fields{"CompositeAppName": ca})
 	return err
 }
@@ -501,9 +742,10 @@ func (c InstantiationClient) Status(p, ca, v, di, qInstance, qType, qOutput stri
 Terminate takes in projectName, compositeAppName, compositeAppVersion,
 DeploymentIntentName and calls rsync to terminate.
 */
-func (c InstantiationClient) Terminate(p string, ca string, v string, di string) error {
+func (c InstantiationClient) Terminate(p string, ca string, v string, di string) (err error) {
+	defer func(start time.Time) { metrics.ObserveLifecycleOperation("terminate", start, err) }(time.Now())
 
-	s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+	s, version, err := c.getStateInfo(p, ca, v, di)
 	if err != nil {
 		return pkgerrors.Wrap(err, "DeploymentIntentGroup has no state info: "+di)
 	}
@@ -518,27 +760,41 @@ func (c InstantiationClient) Terminate(p string, ca string, v string, di string)
 	}
 
 	currentCtxId := state.GetLastContextIdFromStateInfo(s)
-	err = callRsyncUninstall(currentCtxId)
-	if err != nil {
-		return err
-	}
+	deleteRetiredAppContexts(s)
 
-	key := DeploymentIntentGroupKey{
-		Name:         di,
-		Project:      p,
-		CompositeApp: ca,
-		Version:      v,
-	}
+	// The stateInfo is saved before rsync is called, so that only one of
+	// concurrent operations on the DeploymentIntentGroup reaches rsync
 	a := state.ActionEntry{
 		State:     state.StateEnum.Terminated,
 		ContextId: currentCtxId,
 		TimeStamp: time.Now(),
 	}
-	s.Actions = append(s.Actions, a)
+	prev := s
+	s.Actions = append(s.Actions[:len(s.Actions):len(s.Actions)], a)
+	version, err = c.saveStateInfo(p, ca, v, di, s, version)
+	if err != nil {
+		return err
+	}
 
-	err = db.DBconn.Insert(c.db.storeName, key, nil, c.db.tagState, s)
+	err = callRsyncUninstall(currentCtxId)
 	if err != nil {
-		return pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+di)
+		if _, serr := c.saveStateInfo(p, ca, v, di, prev, version); serr != nil {
+			log.Warn("Error restoring DeploymentIntentGroup state in DB", log.Fields{"Error": serr.Error(), "DeploymentIntentGroup": di})
+		}
+		return err
+	}
+
+	// Let the controllers release what they hold for the DeploymentIntentGroup,
+	// unwinding the order in which they were called on instantiation.
+	pl, mapOfControllers, err := getPrioritizedControllerList(p, ca, v, di)
+	if err != nil {
+		log.Warn("Unable to notify controllers of termination", log.Fields{"DeploymentIntentGroup": di, "Error": err})
+	} else {
+		cl := append(append([]controller.Controller{}, pl.pPlaCont...), pl.pActCont...)
+		err = callGrpcTerminateForControllerList(cl, mapOfControllers, currentCtxId)
+		if err != nil {
+			log.Warn("Controllers failed to process termination", log.Fields{"DeploymentIntentGroup": di, "Error": err})
+		}
 	}
 
 	return nil
@@ -28,6 +28,7 @@ import (
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
 	gpic "github.com/onap/multicloud-k8s/src/orchestrator/pkg/gpic"
 	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
 	"github.com/onap/multicloud-k8s/src/orchestrator/utils"
 	"github.com/onap/multicloud-k8s/src/orchestrator/utils/helm"
 	pkgerrors "github.com/pkg/errors"
@@ -144,7 +145,8 @@ func addResourcesToCluster(ct appcontext.AppContext, ch interface{}, resources [
 }
 
 //addClustersToAppContext method shall add cluster details save into etcd
-func addClustersToAppContext(l gpic.ClusterList, ct appcontext.AppContext, appHandle interface{}, resources []resource) error {
+//along with the resources of each cluster, keyed by <provider>+<cluster>
+func addClustersToAppContext(l gpic.ClusterList, ct appcontext.AppContext, appHandle interface{}, clusterResources map[string][]resource) error {
 	mc := l.MandatoryClusters
 	gc := l.ClusterGroups
 
@@ -160,7 +162,7 @@ func addClustersToAppContext(l gpic.ClusterList, ct appcontext.AppContext, appHa
 			return pkgerrors.Wrapf(err, "Error adding Cluster(provider::%s and name::%s) to AppContext", p, n)
 		}
 
-		err = addResourcesToCluster(ct, clusterhandle, resources)
+		err = addResourcesToCluster(ct, clusterhandle, clusterResources[p+SEPARATOR+n])
 		if err != nil {
 			return pkgerrors.Wrapf(err, "Error adding Resources to Cluster(provider::%s and name::%s) to AppContext", p, n)
 		}
@@ -193,7 +195,7 @@ func addClustersToAppContext(l gpic.ClusterList, ct appcontext.AppContext, appHa
 				return pkgerrors.Wrapf(err, "Error adding Cluster(provider::%s and name::%s) to AppContext", p, n)
 			}
 
-			err = addResourcesToCluster(ct, clusterhandle, resources)
+			err = addResourcesToCluster(ct, clusterhandle, clusterResources[p+SEPARATOR+n])
 			if err != nil {
 				return pkgerrors.Wrapf(err, "Error adding Resources to Cluster(provider::%s, name::%s and groupName:: %s) to AppContext", p, n, gn)
 			}
@@ -205,7 +207,7 @@ func addClustersToAppContext(l gpic.ClusterList, ct appcontext.AppContext, appHa
 /*
 verifyResources method is just to check if the resource handles are correctly saved.
 */
-func verifyResources(l gpic.ClusterList, ct appcontext.AppContext, resources []resource, appName string) error {
+func verifyResources(l gpic.ClusterList, ct appcontext.AppContext, clusterResources map[string][]resource, appName string) error {
 
 	for _, cg := range l.ClusterGroups {
 		gn := cg.GroupNumber
@@ -215,7 +217,7 @@ func verifyResources(l gpic.ClusterList, ct appcontext.AppContext, resources []r
 			n := eachCluster.ClusterName
 			cn := p + SEPARATOR + n
 
-			for _, res := range resources {
+			for _, res := range clusterResources[cn] {
 				rh, err := ct.GetResourceHandle(appName, cn, res.name)
 				if err != nil {
 					return pkgerrors.Wrapf(err, "Error getting resource handle for resource :: %s, app:: %s, cluster :: %s, groupName :: %s", appName, res.name, cn, gn)
@@ -234,7 +236,7 @@ func verifyResources(l gpic.ClusterList, ct appcontext.AppContext, resources []r
 		p := mc.ProviderName
 		n := mc.ClusterName
 		cn := p + SEPARATOR + n
-		for _, res := range resources {
+		for _, res := range clusterResources[cn] {
 			rh, err := ct.GetResourceHandle(appName, cn, res.name)
 			if err != nil {
 				return pkgerrors.Wrapf(err, "Error getting resoure handle for resource :: %s, app:: %s, cluster :: %s", appName, res.name, cn)
@@ -244,3 +246,156 @@ func verifyResources(l gpic.ClusterList, ct appcontext.AppContext, resources []r
 	}
 	return nil
 }
+
+// appOrder returns the apps of the AppContext in their order
+func appOrder(ac appcontext.AppContext) ([]string, error) {
+	order, err := ac.GetAppInstruction("order")
+	if err != nil {
+		return nil, err
+	}
+	var appOrderInstr struct {
+		Apporder []string `json:"apporder"`
+	}
+	err = json.Unmarshal([]byte(order.(string)), &appOrderInstr)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Unmarshalling app order instruction")
+	}
+	return appOrderInstr.Apporder, nil
+}
+
+// resourceOrder returns the resources of the app on the cluster in their order
+func resourceOrder(ac appcontext.AppContext, app, cluster string) ([]string, error) {
+	order, err := ac.GetResourceInstruction(app, cluster, "order")
+	if err != nil {
+		return nil, err
+	}
+	var resOrderInstr struct {
+		Resorder []string `json:"resorder"`
+	}
+	err = json.Unmarshal([]byte(order.(string)), &resOrderInstr)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Unmarshalling resource order instruction")
+	}
+	return resOrderInstr.Resorder, nil
+}
+
+// deployedResources returns the resources the AppContext deploys, keyed by
+// <app>/<cluster>/<resource>
+func deployedResources(ac appcontext.AppContext) (map[string]bool, error) {
+	deployed := make(map[string]bool)
+	apps, err := appOrder(ac)
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range apps {
+		clusters, err := ac.GetClusterNames(app)
+		if err != nil {
+			return nil, err
+		}
+		for _, cn := range clusters {
+			resources, err := resourceOrder(ac, app, cn)
+			if err != nil {
+				return nil, err
+			}
+			for _, res := range resources {
+				deployed[app+"/"+cn+"/"+res] = true
+			}
+		}
+	}
+	return deployed, nil
+}
+
+/*
+pruneRetiredAppContext leaves the resources which the AppContext next deploys
+out of the resource order of the AppContext prev, so that terminating prev only
+removes the resources next no longer deploys. The apps and clusters of prev
+are kept, for rsync to remove what it tracks for them.
+*/
+func pruneRetiredAppContext(prev, next appcontext.AppContext) error {
+	deployed, err := deployedResources(next)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error getting the resources of the new AppContext")
+	}
+	apps, err := appOrder(prev)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error getting the apps of the former AppContext")
+	}
+	for _, app := range apps {
+		clusters, err := prev.GetClusterNames(app)
+		if err != nil {
+			return pkgerrors.Wrapf(err, "Error getting the clusters of app %s", app)
+		}
+		for _, cn := range clusters {
+			resources, err := resourceOrder(prev, app, cn)
+			if err != nil {
+				return pkgerrors.Wrapf(err, "Error getting the resources of app %s on cluster %s", app, cn)
+			}
+			removed := []string{}
+			for _, res := range resources {
+				if !deployed[app+"/"+cn+"/"+res] {
+					removed = append(removed, res)
+				}
+			}
+			if len(removed) == len(resources) {
+				continue
+			}
+			ch, err := prev.GetClusterHandle(app, cn)
+			if err != nil {
+				return pkgerrors.Wrapf(err, "Error getting the handle of cluster %s of app %s", cn, app)
+			}
+			var resOrderInstr struct {
+				Resorder []string `json:"resorder"`
+			}
+			resOrderInstr.Resorder = removed
+			jresOrderInstr, _ := json.Marshal(resOrderInstr)
+			// Adding the instruction again replaces it
+			_, err = prev.AddInstruction(ch, "resource", "order", string(jresOrderInstr))
+			if err != nil {
+				return pkgerrors.Wrapf(err, "Error updating the resource order of app %s on cluster %s", app, cn)
+			}
+		}
+	}
+	return nil
+}
+
+/*
+retireAppContext hands rsync the AppContext with id prevID, which a
+DeploymentIntentGroup was updated from, to terminate the resources that the
+new AppContext next no longer deploys. The AppContext is deleted by
+deleteRetiredAppContexts once rsync has terminated it.
+*/
+func retireAppContext(prevID string, next appcontext.AppContext) error {
+	prev, err := state.GetAppContextFromId(prevID)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error getting the former AppContext")
+	}
+	err = pruneRetiredAppContext(prev, next)
+	if err != nil {
+		return err
+	}
+	return callRsyncUninstall(prevID)
+}
+
+/*
+deleteRetiredAppContexts deletes the AppContexts of the DeploymentIntentGroup
+other than its current one, once rsync has terminated them. The AppContexts
+which failed to terminate are kept until the DeploymentIntentGroup is deleted.
+*/
+func deleteRetiredAppContexts(s state.StateInfo) {
+	current := state.GetLastContextIdFromStateInfo(s)
+	for _, id := range state.GetContextIdsFromStateInfo(s) {
+		if id == current {
+			continue
+		}
+		// The status is missing once the AppContext is deleted
+		acStatus, err := state.GetAppContextStatus(id)
+		if err != nil || acStatus.Status != appcontext.AppContextStatusEnum.Terminated {
+			continue
+		}
+		ac, err := state.GetAppContextFromId(id)
+		if err != nil {
+			continue
+		}
+		deleteAppContext(ac)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/contextdb"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
+)
+
+// makeTestAppContext makes an AppContext deploying the resources, given by
+// app and cluster, and sets its status
+func makeTestAppContext(t *testing.T, apps map[string]map[string][]string, status appcontext.StatusValue) contextForCompositeApp {
+	cca, err := makeAppContextForCompositeApp("p", "ca", "v1", "r", "di")
+	if err != nil {
+		t.Fatalf("Error making AppContext: %s", err)
+	}
+	var order []string
+	for app, clusters := range apps {
+		order = append(order, app)
+		ah, err := cca.context.AddApp(cca.compositeAppHandle, app)
+		if err != nil {
+			t.Fatalf("Error adding app: %s", err)
+		}
+		for cn, names := range clusters {
+			ch, err := cca.context.AddCluster(ah, cn)
+			if err != nil {
+				t.Fatalf("Error adding cluster: %s", err)
+			}
+			var resources []resource
+			for _, n := range names {
+				resources = append(resources, resource{name: n, filecontent: n})
+			}
+			err = addResourcesToCluster(cca.context, ch, resources)
+			if err != nil {
+				t.Fatalf("Error adding resources: %s", err)
+			}
+		}
+	}
+	jorder, _ := json.Marshal(map[string][]string{"apporder": order})
+	_, err = cca.context.AddInstruction(cca.compositeAppHandle, "app", "order", string(jorder))
+	if err != nil {
+		t.Fatalf("Error adding app order: %s", err)
+	}
+	_, err = cca.context.AddLevelValue(cca.compositeAppHandle, "status", appcontext.AppContextStatus{Status: status})
+	if err != nil {
+		t.Fatalf("Error adding status: %s", err)
+	}
+	return cca
+}
+
+func TestPruneRetiredAppContext(t *testing.T) {
+	contextdb.Db = &contextdb.MockEtcd{}
+
+	prev := makeTestAppContext(t, map[string]map[string][]string{
+		"a1": {
+			"p+c1": {"r1+Deployment", "r2+Service"},
+			"p+c2": {"r1+Deployment"},
+		},
+		"a2": {
+			"p+c1": {"r3+ConfigMap"},
+		},
+	}, appcontext.AppContextStatusEnum.Instantiated)
+	next := makeTestAppContext(t, map[string]map[string][]string{
+		"a1": {
+			"p+c1": {"r1+Deployment"},
+			"p+c3": {"r1+Deployment"},
+		},
+	}, appcontext.AppContextStatusEnum.Instantiating)
+
+	err := pruneRetiredAppContext(prev.context, next.context)
+	if err != nil {
+		t.Fatalf("pruneRetiredAppContext returned an error: %s", err)
+	}
+
+	// Only the resources which the new AppContext no longer deploys are
+	// left to terminate
+	expected := map[string][]string{
+		"a1/p+c1": {"r2+Service"},
+		"a1/p+c2": {"r1+Deployment"},
+		"a2/p+c1": {"r3+ConfigMap"},
+	}
+	for k, want := range expected {
+		app, cn := k[:2], k[3:]
+		got, err := resourceOrder(prev.context, app, cn)
+		if err != nil {
+			t.Fatalf("Error getting the resource order of %s: %s", k, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Resource order of %s: expected %v, got %v", k, want, got)
+		}
+	}
+	got, err := resourceOrder(next.context, "a1", "p+c1")
+	if err != nil || !reflect.DeepEqual(got, []string{"r1+Deployment"}) {
+		t.Errorf("Expected the new AppContext to be left as it is, got %v, %v", got, err)
+	}
+}
+
+func TestDeleteRetiredAppContexts(t *testing.T) {
+	contextdb.Db = &contextdb.MockEtcd{}
+
+	apps := map[string]map[string][]string{"a1": {"p+c1": {"r1+Deployment"}}}
+	terminated := makeTestAppContext(t, apps, appcontext.AppContextStatusEnum.Terminated)
+	failed := makeTestAppContext(t, apps, appcontext.AppContextStatusEnum.TerminateFailed)
+	terminating := makeTestAppContext(t, apps, appcontext.AppContextStatusEnum.Terminating)
+	current := makeTestAppContext(t, apps, appcontext.AppContextStatusEnum.Terminated)
+
+	s := state.StateInfo{}
+	for _, cca := range []contextForCompositeApp{terminated, failed, terminating, current} {
+		s.Actions = append(s.Actions, state.ActionEntry{
+			State:     state.StateEnum.Instantiated,
+			ContextId: cca.ctxval.(string),
+		})
+	}
+	deleteRetiredAppContexts(s)
+
+	for _, tc := range []struct {
+		name    string
+		cca     contextForCompositeApp
+		deleted bool
+	}{
+		{"terminated", terminated, true},
+		{"failed to terminate", failed, false},
+		{"terminating", terminating, false},
+		{"current", current, false},
+	} {
+		_, err := state.GetAppContextFromId(tc.cca.ctxval.(string))
+		if deleted := err != nil; deleted != tc.deleted {
+			t.Errorf("AppContext %s: expected deleted to be %v, got %v", tc.name, tc.deleted, deleted)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+/*
+This file deals with the override values of the deployment intent group
+which are scoped to clusters, the rendering of apps per cluster, and the
+validation of the values against the values.schema.json of the charts.
+*/
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	gpic "github.com/onap/multicloud-k8s/src/orchestrator/pkg/gpic"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/utils/helm"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// hasClusterScopedOverrides returns true if some override values of the app
+// are scoped to clusters
+func hasClusterScopedOverrides(ov []OverrideValues, a string) bool {
+	for _, eachOverrideVal := range ov {
+		if eachOverrideVal.AppName == a && eachOverrideVal.ClusterProvider != "" {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+getOverrideValuesForCluster returns the override values of the app for a cluster.
+Values scoped to the cluster provider override the unscoped values, values scoped
+to a label selector matching the labels of the cluster override those, and
+values scoped to the cluster itself override all others.
+*/
+func getOverrideValuesForCluster(ov []OverrideValues, a, provider, clusterName string) (map[string]interface{}, error) {
+	var providerVals, labelVals, clusterVals []OverrideValues
+	for _, eachOverrideVal := range ov {
+		if eachOverrideVal.AppName != a || eachOverrideVal.ClusterProvider != provider {
+			continue
+		}
+		switch {
+		case eachOverrideVal.Cluster != "":
+			if eachOverrideVal.Cluster == clusterName {
+				clusterVals = append(clusterVals, eachOverrideVal)
+			}
+		case eachOverrideVal.ClusterLabel != "":
+			labelVals = append(labelVals, eachOverrideVal)
+		default:
+			providerVals = append(providerVals, eachOverrideVal)
+		}
+	}
+
+	if len(labelVals) > 0 {
+		cc := cluster.NewClusterClient()
+		labels, err := cc.GetClusterLabels(provider, clusterName)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "Error getting the labels of cluster %s%s%s", provider, SEPARATOR, clusterName)
+		}
+		set := cluster.LabelSet(labels)
+		matched := labelVals[:0]
+		for _, eachOverrideVal := range labelVals {
+			selector, err := cc.GetLabelSelector(provider, eachOverrideVal.ClusterLabel)
+			if err != nil {
+				return nil, pkgerrors.Wrapf(err, "Error parsing the cluster label of the override values of app %s", a)
+			}
+			if selector.Matches(set) {
+				matched = append(matched, eachOverrideVal)
+			}
+		}
+		labelVals = matched
+	}
+
+	values := getOverrideValuesByAppName(ov, a)
+	for _, scoped := range [][]OverrideValues{providerVals, labelVals, clusterVals} {
+		for _, eachOverrideVal := range scoped {
+			for k, v := range eachOverrideVal.ValuesObj {
+				values[k] = v
+			}
+		}
+	}
+	return values, nil
+}
+
+// overrideValuesKey returns a key identifying a set of override values
+func overrideValuesKey(values map[string]interface{}) string {
+	kv := make([]string, 0, len(values))
+	for k, v := range values {
+		// Typed values are told apart from the strings by their JSON
+		jv, _ := json.Marshal(v)
+		kv = append(kv, k+"="+string(jv))
+	}
+	sort.Strings(kv)
+	return strings.Join(kv, "\n")
+}
+
+// clustersOfClusterList returns all the clusters, mandatory and optional, of the cluster list
+func clustersOfClusterList(l gpic.ClusterList) []gpic.ClusterWithName {
+	clusters := append([]gpic.ClusterWithName{}, l.MandatoryClusters...)
+	for _, eachGrp := range l.ClusterGroups {
+		clusters = append(clusters, eachGrp.OptionalClusters...)
+	}
+	return clusters
+}
+
+/*
+getClusterResources renders the app for the clusters of the cluster list and returns
+its resources by cluster, <provider>+<cluster>. The app is rendered once per distinct
+set of override values, so only once unless some of its override values are scoped
+to clusters.
+*/
+func getClusterResources(appName, p, ca, v, rName, cp string, ov []OverrideValues, l gpic.ClusterList) (map[string][]resource, error) {
+	scoped := hasClusterScopedOverrides(ov, appName)
+	rendered := make(map[string][]resource)
+	clusterResources := make(map[string][]resource)
+
+	for _, c := range clustersOfClusterList(l) {
+		cn := c.ProviderName + SEPARATOR + c.ClusterName
+		values := getOverrideValuesByAppName(ov, appName)
+		if scoped {
+			var err error
+			values, err = getOverrideValuesForCluster(ov, appName, c.ProviderName, c.ClusterName)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		key := overrideValuesKey(values)
+		resources, found := rendered[key]
+		if !found {
+			sortedTemplates, err := getSortedTemplateForAppWithValues(appName, p, ca, v, rName, cp, values)
+			if err != nil {
+				cleanTmpfiles(sortedTemplates)
+				log.Error("Unable to get the sorted templates for app", log.Fields{"appName": appName, "cluster": cn})
+				return nil, pkgerrors.Wrap(err, "Unable to get the sorted templates for app")
+			}
+
+			log.Info(":: Resolved all the templates ::", log.Fields{"appName": appName, "cluster": cn, "SortedTemplate": sortedTemplates})
+
+			resources, err = getResources(sortedTemplates)
+			cleanTmpfiles(sortedTemplates)
+			if err != nil {
+				return nil, err
+			}
+			rendered[key] = resources
+		}
+		clusterResources[cn] = resources
+	}
+
+	log.Info(":: Rendered app for clusters ::", log.Fields{"appName": appName, "renderings": len(rendered), "clusters": len(clusterResources)})
+	return clusterResources, nil
+}
+
+// ValuesViolation is a value of an app that does not satisfy the
+// values.schema.json of its chart. Pointer is the JSON pointer of the value in
+// the values the chart is rendered with
+type ValuesViolation struct {
+	App     string `json:"app"`
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// ValuesValidationError is returned when the values of the apps of a
+// deployment intent group do not satisfy the schemas of their charts
+type ValuesValidationError struct {
+	Violations []ValuesViolation `json:"violations"`
+}
+
+func (e *ValuesValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, fmt.Sprintf("%s: %s: %s", v.App, v.Pointer, v.Message))
+	}
+	return "Values do not satisfy the chart values schema: " + strings.Join(msgs, "; ")
+}
+
+/*
+overrideValueSets returns the sets of override values the app can be rendered
+with: the values which are not scoped to clusters, and those merged with the
+values of each scope. Scopes are checked one at a time, the clusters they
+apply to are only known at instantiation.
+*/
+func overrideValueSets(ov []OverrideValues, a string) []map[string]interface{} {
+	sets := []map[string]interface{}{getOverrideValuesByAppName(ov, a)}
+	for _, eachOverrideVal := range ov {
+		if eachOverrideVal.AppName != a || eachOverrideVal.ClusterProvider == "" {
+			continue
+		}
+		values := getOverrideValuesByAppName(ov, a)
+		for k, v := range eachOverrideVal.ValuesObj {
+			values[k] = v
+		}
+		sets = append(sets, values)
+	}
+	return sets
+}
+
+/*
+validateOverrideValues validates the values the Helm apps of the deployment
+intent group are rendered with, the chart defaults merged with the values of
+the app profile and the override values, against the values.schema.json of
+their charts and subcharts. It returns a ValuesValidationError listing the
+violations. It does not pin the digests of the charts it fetches.
+*/
+func validateOverrideValues(p, ca, v string, d DeploymentIntentGroup) error {
+	apps, err := NewAppClient().GetApps(p, ca, v)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Not finding the apps")
+	}
+
+	var violations []ValuesViolation
+	for _, app := range apps {
+		if app.Spec.Format != "" && app.Spec.Format != AppFormatHelm {
+			continue
+		}
+		appName := app.Metadata.Name
+
+		appContent, _, err := fetchAppContent(app, p, ca, v)
+		if err != nil {
+			return err
+		}
+		appPC, err := NewAppProfileClient().GetAppProfileContentByApp(p, ca, v, d.Spec.Profile, appName)
+		if err != nil {
+			return pkgerrors.Wrap(err, fmt.Sprintf("Not finding the appProfileContent for:: %s", appName))
+		}
+		appProfileContent, err := base64.StdEncoding.DecodeString(appPC.Profile)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Fail to convert to byte array")
+		}
+
+		tc := helm.NewTemplateClient("", "default", d.Spec.Version, ManifestFileName)
+		found := make(map[ValuesViolation]bool)
+		validated := make(map[string]bool)
+		for _, values := range overrideValueSets(d.Spec.OverrideValuesObj, appName) {
+			key := overrideValuesKey(values)
+			if validated[key] {
+				continue
+			}
+			validated[key] = true
+
+			vs, err := tc.ValidateValues(appContent, appProfileContent, values, appName)
+			if err != nil {
+				return pkgerrors.Wrapf(err, "Unable to validate the values of app %s", appName)
+			}
+			for _, sv := range vs {
+				vv := ValuesViolation{App: appName, Pointer: sv.Pointer, Message: sv.Message}
+				if !found[vv] {
+					found[vv] = true
+					violations = append(violations, vv)
+				}
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		log.Error(":: Values do not satisfy the chart values schema ::", log.Fields{"violations": violations})
+		return &ValuesValidationError{Violations: violations}
+	}
+	return nil
+}
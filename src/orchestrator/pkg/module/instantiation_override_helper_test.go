@@ -0,0 +1,151 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+)
+
+func TestGetOverrideValuesForCluster(t *testing.T) {
+	ov := []OverrideValues{
+		{AppName: "app1", ValuesObj: map[string]interface{}{"replicaCount": "1", "image.registry": "default"}},
+		{AppName: "app2", ValuesObj: map[string]interface{}{"replicaCount": "9"}},
+		{AppName: "app1", ClusterProvider: "provider1", ValuesObj: map[string]interface{}{"image.registry": "provider1"}},
+		{AppName: "app1", ClusterProvider: "provider1", ClusterLabel: "edge", ValuesObj: map[string]interface{}{"replicaCount": "2"}},
+		{AppName: "app1", ClusterProvider: "provider1", ClusterLabel: "region=eu,!edge", ValuesObj: map[string]interface{}{"image.registry": "eu"}},
+		{AppName: "app1", ClusterProvider: "provider1", ClusterLabel: "zone.a1", ValuesObj: map[string]interface{}{"image.registry": "a1"}},
+		{AppName: "app1", ClusterProvider: "provider1", Cluster: "cluster1", ValuesObj: map[string]interface{}{"image.registry": "site1"}},
+		{AppName: "app1", ClusterProvider: "provider2", Cluster: "cluster1", ValuesObj: map[string]interface{}{"image.registry": "other"}},
+	}
+
+	label, _ := json.Marshal(cluster.ClusterLabel{LabelName: "edge"})
+	region, _ := json.Marshal(cluster.ClusterLabel{LabelName: "region", Value: "eu"})
+	zone, _ := json.Marshal(cluster.ClusterLabel{LabelName: "zone.a1"})
+	db.DBconn = &db.MockDB{
+		Items: map[string]map[string][]byte{
+			fmt.Sprintf("%v", cluster.ClusterLabelKey{ClusterProviderName: "provider1", ClusterName: "cluster2"}): {
+				"clustermetadata": label,
+			},
+			fmt.Sprintf("%v", cluster.ClusterLabelKey{ClusterProviderName: "provider1", ClusterName: "cluster3"}): {
+				"clustermetadata": region,
+			},
+			fmt.Sprintf("%v", cluster.ClusterLabelKey{ClusterProviderName: "provider1", ClusterName: "cluster4"}): {
+				"clustermetadata": zone,
+			},
+			// The label names from before the label values select by the exact name
+			fmt.Sprintf("%v", cluster.LabelKey{ClusterProviderName: "provider1", ClusterLabelName: "zone.a1"}): {
+				"cluster": []byte("cluster4"),
+			},
+		},
+	}
+
+	if hasClusterScopedOverrides(ov, "app1") == false || hasClusterScopedOverrides(ov, "app2") {
+		t.Errorf("hasClusterScopedOverrides returned unexpected results")
+	}
+	if got := getOverrideValuesByAppName(ov, "app1"); reflect.DeepEqual(got, ov[0].ValuesObj) == false {
+		t.Errorf("getOverrideValuesByAppName returned unexpected values: got %v; expected %v", got, ov[0].ValuesObj)
+	}
+
+	testCases := []struct {
+		label    string
+		provider string
+		cluster  string
+		expected map[string]interface{}
+	}{
+		{
+			label:    "Cluster scoped values override provider values",
+			provider: "provider1",
+			cluster:  "cluster1",
+			expected: map[string]interface{}{"replicaCount": "1", "image.registry": "site1"},
+		},
+		{
+			label:    "Label scoped values apply to labeled clusters",
+			provider: "provider1",
+			cluster:  "cluster2",
+			expected: map[string]interface{}{"replicaCount": "2", "image.registry": "provider1"},
+		},
+		{
+			label:    "Label selector scoped values apply to selected clusters",
+			provider: "provider1",
+			cluster:  "cluster3",
+			expected: map[string]interface{}{"replicaCount": "1", "image.registry": "eu"},
+		},
+		{
+			label:    "Label names from before the label values select by the exact name",
+			provider: "provider1",
+			cluster:  "cluster4",
+			expected: map[string]interface{}{"replicaCount": "1", "image.registry": "a1"},
+		},
+		{
+			label:    "Unscoped values apply to other providers",
+			provider: "provider3",
+			cluster:  "cluster1",
+			expected: map[string]interface{}{"replicaCount": "1", "image.registry": "default"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			got, err := getOverrideValuesForCluster(ov, "app1", testCase.provider, testCase.cluster)
+			if err != nil {
+				t.Fatalf("getOverrideValuesForCluster returned an unexpected error %s", err)
+			}
+			if reflect.DeepEqual(got, testCase.expected) == false {
+				t.Errorf("getOverrideValuesForCluster returned unexpected values: got %v; expected %v", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestOverrideValuesKey(t *testing.T) {
+	a := overrideValuesKey(map[string]interface{}{"a": "1", "b": "2"})
+	b := overrideValuesKey(map[string]interface{}{"b": "2", "a": "1"})
+	c := overrideValuesKey(map[string]interface{}{"a": "1", "b": "3"})
+	if a != b {
+		t.Errorf("overrideValuesKey is not independent of map order: %q != %q", a, b)
+	}
+	if a == c {
+		t.Errorf("overrideValuesKey returned the same key for different values: %q", a)
+	}
+	d := overrideValuesKey(map[string]interface{}{"a": float64(1), "b": "2"})
+	if a == d {
+		t.Errorf("overrideValuesKey returned the same key for a string and a number: %q", a)
+	}
+}
+
+func TestOverrideValueSets(t *testing.T) {
+	ov := []OverrideValues{
+		{AppName: "app1", ValuesObj: map[string]interface{}{"replicaCount": float64(1), "image.registry": "default"}},
+		{AppName: "app1", ClusterProvider: "provider1", ValuesObj: map[string]interface{}{"image.registry": "provider1"}},
+		{AppName: "app1", ClusterProvider: "provider1", Cluster: "cluster1", ValuesObj: map[string]interface{}{"replicaCount": float64(3)}},
+		{AppName: "app2", ClusterProvider: "provider1", ValuesObj: map[string]interface{}{"replicaCount": float64(9)}},
+	}
+	expected := []map[string]interface{}{
+		{"replicaCount": float64(1), "image.registry": "default"},
+		{"replicaCount": float64(1), "image.registry": "provider1"},
+		{"replicaCount": float64(3), "image.registry": "default"},
+	}
+	if got := overrideValueSets(ov, "app1"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("overrideValueSets returned unexpected sets: got %v; expected %v", got, expected)
+	}
+}
@@ -178,6 +178,34 @@ func getPrioritizedControllerList(p, ca, v, di string) (PrioritizedControlList,
 
 }
 
+/*
+checkControllersAvailable verifies that every controller referenced by the intents of
+the deployment intent group, and the resource synchronizer, is serving. It lets
+instantiation fail before any AppContext is built for it.
+*/
+func checkControllersAvailable(p, ca, v, di string) error {
+	iList, err := NewIntentClient().GetAllIntents(p, ca, v, di)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0)
+	for _, eachmap := range iList.ListOfIntents {
+		for cn := range eachmap {
+			if cn != GenericPlacementIntentName {
+				names = append(names, cn)
+			}
+		}
+	}
+
+	cc := controller.NewControllerClient()
+	if _, err := cc.GetController(rsyncName); err == nil {
+		names = append(names, rsyncName)
+	}
+
+	return cc.CheckControllersHealth(names)
+}
+
 /*
 callGrpcForControllerList method shall take in a list of controllers, a map of contollers to controllerIntentNames and contextID. It invokes the context
 updation through the grpc client for the given list of controllers.
@@ -195,6 +223,62 @@ func callGrpcForControllerList(cl []controller.Controller, mc map[string]string,
 	return nil
 }
 
+/*
+reverseControllerList returns the controllers of the list in reverse priority order.
+*/
+func reverseControllerList(cl []controller.Controller) []controller.Controller {
+	rl := make([]controller.Controller, 0, len(cl))
+	for i := len(cl) - 1; i >= 0; i-- {
+		rl = append(rl, cl[i])
+	}
+	return rl
+}
+
+/*
+callGrpcUpdateForControllerList method shall take in a list of controllers, a map of controllers to controllerIntentNames,
+the contextID being replaced and the new contextID. It invokes the context update, with the previous context, through the
+grpc client for the given list of controllers in priority order, like the instantiation.
+*/
+func callGrpcUpdateForControllerList(cl []controller.Controller, mc map[string]string, oldcontextid, contextid interface{}) error {
+	for _, c := range cl {
+		controller := c.Metadata.Name
+		controllerIntentName := mc[controller]
+		err := client.InvokeContextUpdateFrom(controller, controllerIntentName, fmt.Sprintf("%v", oldcontextid), fmt.Sprintf("%v", contextid))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+callGrpcTerminateForControllerList method shall take in a list of controllers, a map of controllers to controllerIntentNames
+and contextID. It notifies the given list of controllers of the termination, in reverse priority order. A failing controller
+does not stop the others from being notified, the errors are returned together.
+*/
+func callGrpcTerminateForControllerList(cl []controller.Controller, mc map[string]string, contextid interface{}) error {
+	var failed []string
+	for _, c := range reverseControllerList(cl) {
+		controller := c.Metadata.Name
+		controllerIntentName := mc[controller]
+		appContextID := fmt.Sprintf("%v", contextid)
+		err := client.InvokeContextTerminate(controller, controllerIntentName, appContextID)
+		if err != nil {
+			log.Error("Error notifying controller of termination", log.Fields{
+				"Controller": controller,
+				"Intent":     controllerIntentName,
+				"AppContext": appContextID,
+				"Error":      err,
+			})
+			failed = append(failed, controller)
+		}
+	}
+	if len(failed) > 0 {
+		return pkgerrors.Errorf("Terminate failed for controllers: %v", failed)
+	}
+	return nil
+}
+
 /*
 queryDBAndSetRsyncInfo queries the MCO db to find the record the sync controller
 and then sets the RsyncInfo global variable.
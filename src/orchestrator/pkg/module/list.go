@@ -0,0 +1,37 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+)
+
+// findPage returns a page of the tag values of the documents with the key,
+// and the continue token of the next page. fields maps the filters and the
+// sort orders the list supports to the fields of the documents.
+func findPage(storeName string, key db.Key, tag string, o db.ListOptions, fields map[string]string) ([][]byte, string, error) {
+	fo, err := o.FindOptions(fields)
+	if err != nil {
+		return nil, "", err
+	}
+	values, err := db.DBconn.FindWithOptions(storeName, key, tag, fo)
+	if err != nil {
+		return nil, "", err
+	}
+	values, next := o.Page(values)
+	return values, next, nil
+}
@@ -32,6 +32,10 @@ type Client struct {
 	Intent                 *IntentClient
 	CompositeProfile       *CompositeProfileClient
 	AppProfile             *AppProfileClient
+	RepoCredential         *RepoCredentialClient
+	ApprovalPolicy         *ApprovalPolicyClient
+	Schedule               *ScheduleClient
+	ClusterDrain           *ClusterDrainClient
 	// Add Clients for API's here
 	Instantiation *InstantiationClient
 }
@@ -49,6 +53,10 @@ func NewClient() *Client {
 	c.Intent = NewIntentClient()
 	c.CompositeProfile = NewCompositeProfileClient()
 	c.AppProfile = NewAppProfileClient()
+	c.RepoCredential = NewRepoCredentialClient()
+	c.ApprovalPolicy = NewApprovalPolicyClient()
+	c.Schedule = NewScheduleClient()
+	c.ClusterDrain = NewClusterDrainClient()
 	// Add Client API handlers here
 	c.Instantiation = NewInstantiationClient()
 	return c
@@ -56,9 +56,12 @@ func (pk ProjectKey) String() string {
 // ProjectManager is an interface exposes the Project functionality
 type ProjectManager interface {
 	CreateProject(pr Project, exists bool) (Project, error)
+	UpdateProject(pr Project, ifMatch string) (Project, int64, error)
 	GetProject(name string) (Project, error)
+	GetProjectVersion(name string) (int64, error)
 	DeleteProject(name string) error
 	GetAllProjects() ([]Project, error)
+	ListProjects(o db.ListOptions) ([]Project, string, error)
 }
 
 // ProjectClient implements the ProjectManager
@@ -99,6 +102,59 @@ func (v *ProjectClient) CreateProject(p Project, exists bool) (Project, error) {
 	return p, nil
 }
 
+// UpdateProject creates or updates the Project. An existing Project is only
+// updated at the version the If-Match header matches, and a VersionConflictError
+// is returned otherwise. It returns the version the Project was stored at.
+func (v *ProjectClient) UpdateProject(p Project, ifMatch string) (Project, int64, error) {
+
+	//Construct the composite key to select the entry
+	key := ProjectKey{
+		ProjectName: p.MetaData.Name,
+	}
+
+	version, err := db.DBconn.GetVersion(v.storeName, key)
+	if err != nil {
+		return Project{}, 0, pkgerrors.Wrap(err, "Get Project version")
+	}
+	if !db.IfMatch(ifMatch, version) {
+		return Project{}, 0, pkgerrors.Wrap(&db.VersionConflictError{Version: version},
+			"The Project does not match If-Match "+ifMatch)
+	}
+
+	_, err = v.GetProject(p.MetaData.Name)
+	if err != nil {
+		if ifMatch != "" {
+			return Project{}, 0, pkgerrors.Wrap(&db.VersionConflictError{Version: version},
+				"The Project does not exist")
+		}
+		err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, p)
+		if err != nil {
+			return Project{}, 0, pkgerrors.Wrap(err, "Creating DB Entry")
+		}
+		// A new document is stored at the first version
+		return p, 1, nil
+	}
+
+	version, err = db.DBconn.InsertIfVersion(v.storeName, key, nil, v.tagMeta, p, version)
+	if err != nil {
+		return Project{}, 0, pkgerrors.Wrap(err, "Updating DB Entry")
+	}
+
+	return p, version, nil
+}
+
+// GetProjectVersion returns the version of the Project for corresponding name
+func (v *ProjectClient) GetProjectVersion(name string) (int64, error) {
+	key := ProjectKey{
+		ProjectName: name,
+	}
+	version, err := db.DBconn.GetVersion(v.storeName, key)
+	if err != nil {
+		return 0, pkgerrors.Wrap(err, "Get Project version")
+	}
+	return version, nil
+}
+
 // GetProject returns the Project for corresponding name
 func (v *ProjectClient) GetProject(name string) (Project, error) {
 
@@ -126,25 +182,31 @@ func (v *ProjectClient) GetProject(name string) (Project, error) {
 
 // GetAllProjects returns all the projects
 func (v *ProjectClient) GetAllProjects() ([]Project, error) {
+	res, _, err := v.ListProjects(db.ListOptions{})
+	return res, err
+}
+
+// ListProjects returns a page of the projects, and the continue token of the next page
+func (v *ProjectClient) ListProjects(o db.ListOptions) ([]Project, string, error) {
 	key := ProjectKey{
 		ProjectName: "",
 	}
 
 	var res []Project
-	values, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	values, next, err := findPage(v.storeName, key, v.tagMeta, o, map[string]string{"name": "project"})
 	if err != nil {
-
+		return []Project{}, "", pkgerrors.Wrap(err, "Get Projects")
 	}
 
 	for _, value := range values {
 		p := Project{}
 		err = db.DBconn.Unmarshal(value, &p)
 		if err != nil {
-			return []Project{}, pkgerrors.Wrap(err, "Unmarshaling Project")
+			return []Project{}, "", pkgerrors.Wrap(err, "Unmarshaling Project")
 		}
 		res = append(res, p)
 	}
-	return res, nil
+	return res, next, nil
 }
 
 // DeleteProject the  Project from database
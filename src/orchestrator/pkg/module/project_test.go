@@ -114,11 +114,13 @@ func TestCreateProject(t *testing.T) {
 
 func TestUpdateProject(t *testing.T) {
 	testCases := []struct {
-		label         string
-		inp           Project
-		expectedError string
-		mockdb        *db.MockDB
-		expected      Project
+		label           string
+		inp             Project
+		ifMatch         string
+		expectedError   string
+		expectedVersion int64
+		mockdb          *db.MockDB
+		expected        Project
 	}{
 		{
 			label: "Update Project",
@@ -138,7 +140,36 @@ func TestUpdateProject(t *testing.T) {
 					UserData2:   "update userData2",
 				},
 			},
-			expectedError: "",
+			ifMatch:         `"3"`,
+			expectedError:   "",
+			expectedVersion: 4,
+			mockdb: &db.MockDB{
+				Items: map[string]map[string][]byte{
+					ProjectKey{ProjectName: "testProject"}.String(): {
+						"projectmetadata": []byte(
+							"{" +
+								"\"metadata\" : {" +
+								"\"Name\":\"testProject\"," +
+								"\"Description\":\"Test project for unit testing\"," +
+								"\"UserData1\":\"userData1\"," +
+								"\"UserData2\":\"userData2\"}" +
+								"}"),
+					},
+				},
+				Versions: map[string]int64{
+					ProjectKey{ProjectName: "testProject"}.String(): 3,
+				},
+			},
+		},
+		{
+			label: "Update Project at a stale version",
+			inp: Project{
+				MetaData: ProjectMetaData{
+					Name: "testProject",
+				},
+			},
+			ifMatch:       `"2"`,
+			expectedError: "does not match If-Match",
 			mockdb: &db.MockDB{
 				Items: map[string]map[string][]byte{
 					ProjectKey{ProjectName: "testProject"}.String(): {
@@ -152,7 +183,25 @@ func TestUpdateProject(t *testing.T) {
 								"}"),
 					},
 				},
+				Versions: map[string]int64{
+					ProjectKey{ProjectName: "testProject"}.String(): 3,
+				},
+			},
+		},
+		{
+			label: "Create Project",
+			inp: Project{
+				MetaData: ProjectMetaData{
+					Name: "newProject",
+				},
 			},
+			expected: Project{
+				MetaData: ProjectMetaData{
+					Name: "newProject",
+				},
+			},
+			expectedVersion: 1,
+			mockdb:          &db.MockDB{},
 		},
 		{
 			label: "Failed Update Project",
@@ -162,7 +211,7 @@ func TestUpdateProject(t *testing.T) {
 					Description: "Unknown project for unit testing",
 				},
 			},
-			expectedError: "Creating DB Entry",
+			expectedError: "Get Project version",
 			mockdb: &db.MockDB{
 				Err: pkgerrors.New("Error Updating Project"),
 			},
@@ -173,7 +222,7 @@ func TestUpdateProject(t *testing.T) {
 		t.Run(testCase.label, func(t *testing.T) {
 			db.DBconn = testCase.mockdb
 			impl := NewProjectClient()
-			got, err := impl.CreateProject(testCase.inp, true)
+			got, version, err := impl.UpdateProject(testCase.inp, testCase.ifMatch)
 			if err != nil {
 				if testCase.expectedError == "" {
 					t.Fatalf("Update returned an unexpected error %s", err)
@@ -186,6 +235,9 @@ func TestUpdateProject(t *testing.T) {
 					t.Errorf("Update returned unexpected body: got %v;"+
 						" expected %v", got, testCase.expected)
 				}
+				if version != testCase.expectedVersion {
+					t.Errorf("Update returned version %d, expected %d", version, testCase.expectedVersion)
+				}
 			}
 		})
 	}
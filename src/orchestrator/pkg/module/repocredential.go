@@ -0,0 +1,264 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"encoding/json"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// RepoCredential contains the credentials of a Helm repository or an OCI
+// registry that the apps of a project reference
+type RepoCredential struct {
+	Metadata RepoCredentialMetaData `json:"metadata"`
+	Spec     RepoCredentialSpec     `json:"spec"`
+}
+
+// RepoCredentialMetaData contains the parameters for creating a repo credential
+type RepoCredentialMetaData struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	UserData1   string `json:"userData1"`
+	UserData2   string `json:"userData2"`
+}
+
+// RepoCredentialSpec contains either a username and password, or a token
+type RepoCredentialSpec struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// transform applies f, which encrypts or decrypts, to the password and the
+// token
+func (s RepoCredentialSpec) transform(f func(string) (string, error)) (RepoCredentialSpec, error) {
+	var err error
+	s.Password, err = f(s.Password)
+	if err != nil {
+		return RepoCredentialSpec{}, pkgerrors.Wrap(err, "Password")
+	}
+	s.Token, err = f(s.Token)
+	if err != nil {
+		return RepoCredentialSpec{}, pkgerrors.Wrap(err, "Token")
+	}
+	return s, nil
+}
+
+// RepoCredentialKey is the key structure that is used in the database
+type RepoCredentialKey struct {
+	RepoCredential string `json:"repocredential"`
+	Project        string `json:"project"`
+}
+
+// We will use json marshalling to convert to string to
+// preserve the underlying structure.
+func (rk RepoCredentialKey) String() string {
+	out, err := json.Marshal(rk)
+	if err != nil {
+		return ""
+	}
+
+	return string(out)
+}
+
+// RepoCredentialManager is an interface exposes the RepoCredential functionality
+type RepoCredentialManager interface {
+	CreateRepoCredential(rc RepoCredential, p string, exists bool) (RepoCredential, error)
+	GetRepoCredential(name string, p string) (RepoCredential, error)
+	GetAllRepoCredentials(p string) ([]RepoCredential, error)
+	DeleteRepoCredential(name string, p string) error
+}
+
+// RepoCredentialClient implements the RepoCredentialManager
+// It will also be used to maintain some localized state
+type RepoCredentialClient struct {
+	storeName string
+	tagMeta   string
+}
+
+// NewRepoCredentialClient returns an instance of the RepoCredentialClient
+// which implements the RepoCredentialManager
+func NewRepoCredentialClient() *RepoCredentialClient {
+	return &RepoCredentialClient{
+		storeName: "orchestrator",
+		tagMeta:   "repocredentialmetadata",
+	}
+}
+
+// CreateRepoCredential creates or updates the credentials of a repository
+func (v *RepoCredentialClient) CreateRepoCredential(rc RepoCredential, p string, exists bool) (RepoCredential, error) {
+
+	//Construct the composite key to select the entry
+	key := RepoCredentialKey{
+		RepoCredential: rc.Metadata.Name,
+		Project:        p,
+	}
+
+	//Check if the Project exists
+	_, err := NewProjectClient().GetProject(p)
+	if err != nil {
+		return RepoCredential{}, pkgerrors.New("Unable to find the project")
+	}
+
+	//Check if this RepoCredential already exists
+	_, err = v.GetRepoCredential(rc.Metadata.Name, p)
+	if err == nil && !exists {
+		return RepoCredential{}, pkgerrors.New("RepoCredential already exists")
+	}
+
+	// The password and the token are encrypted at rest
+	stored := rc
+	stored.Spec, err = rc.Spec.transform(secret.Encrypt)
+	if err != nil {
+		return RepoCredential{}, pkgerrors.Wrap(err, "Encrypting RepoCredential")
+	}
+	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, stored)
+	if err != nil {
+		return RepoCredential{}, pkgerrors.Wrap(err, "Creating DB Entry")
+	}
+
+	return rc, nil
+}
+
+// GetRepoCredential returns the RepoCredential for corresponding name
+func (v *RepoCredentialClient) GetRepoCredential(name string, p string) (RepoCredential, error) {
+
+	//Construct the composite key to select the entry
+	key := RepoCredentialKey{
+		RepoCredential: name,
+		Project:        p,
+	}
+	value, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	if err != nil {
+		return RepoCredential{}, pkgerrors.Wrap(err, "Get RepoCredential")
+	}
+
+	//value is a byte array
+	if value != nil {
+		rc := RepoCredential{}
+		err = db.DBconn.Unmarshal(value[0], &rc)
+		if err != nil {
+			return RepoCredential{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		rc.Spec, err = rc.Spec.transform(secret.Decrypt)
+		if err != nil {
+			return RepoCredential{}, pkgerrors.Wrap(err, "Decrypting RepoCredential")
+		}
+		return rc, nil
+	}
+
+	return RepoCredential{}, pkgerrors.New("Error getting RepoCredential")
+}
+
+// GetAllRepoCredentials returns all the RepoCredentials of the project
+func (v *RepoCredentialClient) GetAllRepoCredentials(p string) ([]RepoCredential, error) {
+
+	key := RepoCredentialKey{
+		RepoCredential: "",
+		Project:        p,
+	}
+
+	var resp []RepoCredential
+	values, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	if err != nil {
+		return []RepoCredential{}, pkgerrors.Wrap(err, "Get RepoCredentials")
+	}
+
+	for _, value := range values {
+		rc := RepoCredential{}
+		err = db.DBconn.Unmarshal(value, &rc)
+		if err != nil {
+			return []RepoCredential{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		rc.Spec, err = rc.Spec.transform(secret.Decrypt)
+		if err != nil {
+			return []RepoCredential{}, pkgerrors.Wrap(err, "Decrypting RepoCredential")
+		}
+		resp = append(resp, rc)
+	}
+
+	return resp, nil
+}
+
+// ReencryptSecrets re-encrypts the passwords and the tokens of the
+// RepoCredentials with the primary secret key, and returns the number of
+// RepoCredentials updated. It is run by the secret migration command of
+// the orchestrator.
+func (v *RepoCredentialClient) ReencryptSecrets() (int, error) {
+	n := 0
+	projects, err := NewProjectClient().GetAllProjects()
+	if err != nil {
+		return n, pkgerrors.Wrap(err, "Get Projects")
+	}
+	for _, p := range projects {
+		key := RepoCredentialKey{Project: p.MetaData.Name}
+		values, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+		if err != nil {
+			return n, pkgerrors.Wrap(err, "Get RepoCredentials")
+		}
+		for _, value := range values {
+			rc := RepoCredential{}
+			err = db.DBconn.Unmarshal(value, &rc)
+			if err != nil {
+				return n, pkgerrors.Wrap(err, "Unmarshaling Value")
+			}
+			changed := false
+			reencrypt := func(value string) (string, error) {
+				out, c, err := secret.Reencrypt(value)
+				changed = changed || c
+				return out, err
+			}
+			rc.Spec, err = rc.Spec.transform(reencrypt)
+			if err != nil {
+				return n, pkgerrors.Wrapf(err, "Re-encrypting RepoCredential %s", rc.Metadata.Name)
+			}
+			if !changed {
+				continue
+			}
+			key := RepoCredentialKey{
+				RepoCredential: rc.Metadata.Name,
+				Project:        p.MetaData.Name,
+			}
+			err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, rc)
+			if err != nil {
+				return n, pkgerrors.Wrap(err, "Updating RepoCredential")
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// DeleteRepoCredential deletes the RepoCredential from database
+func (v *RepoCredentialClient) DeleteRepoCredential(name string, p string) error {
+
+	//Construct the composite key to select the entry
+	key := RepoCredentialKey{
+		RepoCredential: name,
+		Project:        p,
+	}
+	err := db.DBconn.Remove(v.storeName, key)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Delete RepoCredential Entry;")
+	}
+
+	return nil
+}
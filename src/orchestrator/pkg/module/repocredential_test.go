@@ -0,0 +1,56 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
+)
+
+func TestRepoCredentialSpecTransform(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repocredential")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	k, err := secret.NewLocalKMS(filepath.Join(dir, "keys.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret.SetKeyProvider(k)
+	defer secret.SetKeyProvider(nil)
+
+	spec := RepoCredentialSpec{Username: "user", Password: "pass", Token: "token"}
+	encrypted, err := spec.transform(secret.Encrypt)
+	if err != nil {
+		t.Fatalf("Encrypting returned an error (%s)", err)
+	}
+	if encrypted.Username != "user" || !secret.IsEncrypted(encrypted.Password) || !secret.IsEncrypted(encrypted.Token) {
+		t.Fatalf("Expected the password and the token to be encrypted, got %+v", encrypted)
+	}
+	decrypted, err := encrypted.transform(secret.Decrypt)
+	if err != nil {
+		t.Fatalf("Decrypting returned an error (%s)", err)
+	}
+	if decrypted != spec {
+		t.Fatalf("Expected %+v, got %+v", spec, decrypted)
+	}
+}
@@ -0,0 +1,347 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/cron"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Schedule runs an operation of the InstantiationManager on a
+// DeploymentIntentGroup at a given time, or repeatedly following a cron expression
+type Schedule struct {
+	MetaData ScheduleMetaData `json:"metadata"`
+	Spec     ScheduleSpec     `json:"spec"`
+	Status   *ScheduleStatus  `json:"status,omitempty"`
+}
+
+// ScheduleMetaData has name, description, userdata1, userdata2
+type ScheduleMetaData struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	UserData1   string `json:"userData1"`
+	UserData2   string `json:"userData2"`
+}
+
+/*
+ScheduleSpec has the operation to run and either the time to run it at, or
+the cron expression to run it by. The cron expression and the maintenance
+windows are evaluated in the time zone of the schedule, UTC by default.
+*/
+type ScheduleSpec struct {
+	Operation          string              `json:"operation"`
+	At                 string              `json:"at,omitempty"`
+	Cron               string              `json:"cron,omitempty"`
+	TimeZone           string              `json:"time-zone,omitempty"`
+	MaintenanceWindows []MaintenanceWindow `json:"maintenance-windows,omitempty"`
+}
+
+// MaintenanceWindow restricts the changes of the clusters with the cluster
+// label to the duration following each start of the window. A due operation
+// is held until the windows of all the cluster labels of the schedule which
+// select a cluster of the DeploymentIntentGroup are open.
+type MaintenanceWindow struct {
+	ClusterProvider string `json:"cluster-provider"`
+	ClusterLabel    string `json:"cluster-label"`
+	Start           string `json:"start"`
+	Duration        string `json:"duration"`
+}
+
+// ScheduleStatus has the time the schedule was created, the outcome of its
+// last run and the time it is next due. A zero NextRun means it is done.
+type ScheduleStatus struct {
+	Created    time.Time `json:"created"`
+	LastRun    time.Time `json:"last-run"`
+	LastResult string    `json:"last-result,omitempty"`
+	NextRun    time.Time `json:"next-run"`
+}
+
+type ScheduleOperation = string
+
+type scheduleOperations struct {
+	Instantiate ScheduleOperation
+	Update      ScheduleOperation
+	Terminate   ScheduleOperation
+}
+
+// ScheduleOperationEnum lists the operations a schedule can run
+var ScheduleOperationEnum = &scheduleOperations{
+	Instantiate: "instantiate",
+	Update:      "update",
+	Terminate:   "terminate",
+}
+
+// InvalidScheduleError is returned when the spec of a schedule can not be run
+type InvalidScheduleError struct {
+	Reason string
+}
+
+func (e *InvalidScheduleError) Error() string {
+	return "Invalid schedule: " + e.Reason
+}
+
+// ScheduleKey is the key structure that is used in the database
+type ScheduleKey struct {
+	Schedule              string `json:"schedule"`
+	Project               string `json:"project"`
+	CompositeApp          string `json:"compositeapp"`
+	Version               string `json:"compositeappversion"`
+	DeploymentIntentGroup string `json:"deploymentintentgroup"`
+}
+
+// We will use json marshalling to convert to string to
+// preserve the underlying structure.
+func (sk ScheduleKey) String() string {
+	out, err := json.Marshal(sk)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// ScheduleManager is an interface which exposes the Schedule functionality
+type ScheduleManager interface {
+	CreateSchedule(s Schedule, p string, ca string, v string, di string) (Schedule, error)
+	GetSchedule(name string, p string, ca string, v string, di string) (Schedule, error)
+	GetAllSchedules(p string, ca string, v string, di string) ([]Schedule, error)
+	DeleteSchedule(name string, p string, ca string, v string, di string) error
+}
+
+// ScheduleClient implements the ScheduleManager interface
+type ScheduleClient struct {
+	storeName     string
+	tagMeta       string
+	tagStatus     string
+	instantiation InstantiationManager
+	clusters      cluster.ClusterManager
+	// placement returns the clusters the DeploymentIntentGroup is placed on
+	placement func(p, ca, v, di string) (map[string]bool, error)
+}
+
+// NewScheduleClient returns an instance of the ScheduleClient
+func NewScheduleClient() *ScheduleClient {
+	return &ScheduleClient{
+		storeName:     "orchestrator",
+		tagMeta:       "schedulemetadata",
+		tagStatus:     "schedulestatus",
+		instantiation: NewInstantiationClient(),
+		clusters:      cluster.NewClusterClient(),
+		placement:     placementClusters,
+	}
+}
+
+// location returns the time zone of the schedule
+func (s Schedule) location() (*time.Location, error) {
+	if s.Spec.TimeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(s.Spec.TimeZone)
+	if err != nil {
+		return nil, &InvalidScheduleError{Reason: "unknown time zone " + s.Spec.TimeZone}
+	}
+	return loc, nil
+}
+
+// check verifies that the schedule can be run
+func (s Schedule) check() error {
+	switch s.Spec.Operation {
+	case ScheduleOperationEnum.Instantiate, ScheduleOperationEnum.Update, ScheduleOperationEnum.Terminate:
+	default:
+		return &InvalidScheduleError{Reason: "unknown operation " + s.Spec.Operation}
+	}
+	if (s.Spec.At == "") == (s.Spec.Cron == "") {
+		return &InvalidScheduleError{Reason: "exactly one of at and cron has to be set"}
+	}
+	if s.Spec.At != "" {
+		if _, err := time.Parse(time.RFC3339, s.Spec.At); err != nil {
+			return &InvalidScheduleError{Reason: "at is not an RFC 3339 time: " + err.Error()}
+		}
+	}
+	if s.Spec.Cron != "" {
+		if _, err := cron.Parse(s.Spec.Cron); err != nil {
+			return &InvalidScheduleError{Reason: err.Error()}
+		}
+	}
+	if _, err := s.location(); err != nil {
+		return err
+	}
+	for _, w := range s.Spec.MaintenanceWindows {
+		if _, err := cron.Parse(w.Start); err != nil {
+			return &InvalidScheduleError{Reason: "maintenance window of " + w.ClusterLabel + ": " + err.Error()}
+		}
+		d, err := time.ParseDuration(w.Duration)
+		if err != nil || d <= 0 {
+			return &InvalidScheduleError{Reason: "maintenance window of " + w.ClusterLabel + " has an invalid duration " + w.Duration}
+		}
+	}
+	return nil
+}
+
+// CreateSchedule creates a schedule of the DeploymentIntentGroup
+func (c *ScheduleClient) CreateSchedule(s Schedule, p string, ca string, v string, di string) (Schedule, error) {
+
+	//Construct the composite key to select the entry
+	key := ScheduleKey{
+		Schedule:              s.MetaData.Name,
+		Project:               p,
+		CompositeApp:          ca,
+		Version:               v,
+		DeploymentIntentGroup: di,
+	}
+
+	_, err := c.GetSchedule(s.MetaData.Name, p, ca, v, di)
+	if err == nil {
+		return Schedule{}, pkgerrors.New("Schedule already exists")
+	}
+
+	// check if the deploymentIntentGrpName exists
+	_, err = NewDeploymentIntentGroupClient().GetDeploymentIntentGroup(di, p, ca, v)
+	if err != nil {
+		return Schedule{}, pkgerrors.New("Unable to find the deployment-intent-group-name")
+	}
+
+	err = s.check()
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	s.Status = nil
+	err = db.DBconn.Insert(c.storeName, key, nil, c.tagMeta, s)
+	if err != nil {
+		return Schedule{}, pkgerrors.Wrap(err, "Creating DB Entry")
+	}
+	status := ScheduleStatus{Created: time.Now()}
+	err = db.DBconn.Insert(c.storeName, key, nil, c.tagStatus, status)
+	if err != nil {
+		return Schedule{}, pkgerrors.Wrap(err, "Creating DB Entry")
+	}
+
+	s.Status = &status
+	s.Status.NextRun = s.nextRun(status)
+	return s, nil
+}
+
+// GetSchedule returns the schedule of the DeploymentIntentGroup with its status
+func (c *ScheduleClient) GetSchedule(name string, p string, ca string, v string, di string) (Schedule, error) {
+
+	//Construct the composite key to select the entry
+	key := ScheduleKey{
+		Schedule:              name,
+		Project:               p,
+		CompositeApp:          ca,
+		Version:               v,
+		DeploymentIntentGroup: di,
+	}
+	value, err := db.DBconn.Find(c.storeName, key, c.tagMeta)
+	if err != nil {
+		return Schedule{}, pkgerrors.Wrap(err, "Get Schedule")
+	}
+
+	//value is a byte array
+	if value != nil {
+		s := Schedule{}
+		err = db.DBconn.Unmarshal(value[0], &s)
+		if err != nil {
+			return Schedule{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		status, err := c.getScheduleStatus(key)
+		if err != nil {
+			return Schedule{}, err
+		}
+		status.NextRun = s.nextRun(status)
+		s.Status = &status
+		return s, nil
+	}
+
+	return Schedule{}, pkgerrors.New("Error getting Schedule")
+}
+
+// GetAllSchedules returns the schedules of the DeploymentIntentGroup
+func (c *ScheduleClient) GetAllSchedules(p string, ca string, v string, di string) ([]Schedule, error) {
+
+	//Construct the composite key to select the entry
+	key := ScheduleKey{
+		Schedule:              "",
+		Project:               p,
+		CompositeApp:          ca,
+		Version:               v,
+		DeploymentIntentGroup: di,
+	}
+	values, err := db.DBconn.Find(c.storeName, key, c.tagMeta)
+	if err != nil {
+		return []Schedule{}, pkgerrors.Wrap(err, "Get Schedules")
+	}
+
+	var list []Schedule
+	for _, value := range values {
+		s := Schedule{}
+		err = db.DBconn.Unmarshal(value, &s)
+		if err != nil {
+			return []Schedule{}, pkgerrors.Wrap(err, "Unmarshaling Schedule")
+		}
+		// The status is kept in its own tag
+		key.Schedule = s.MetaData.Name
+		status, err := c.getScheduleStatus(key)
+		if err != nil {
+			return []Schedule{}, err
+		}
+		status.NextRun = s.nextRun(status)
+		s.Status = &status
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// DeleteSchedule deletes the schedule of the DeploymentIntentGroup
+func (c *ScheduleClient) DeleteSchedule(name string, p string, ca string, v string, di string) error {
+
+	//Construct the composite key to select the entry
+	key := ScheduleKey{
+		Schedule:              name,
+		Project:               p,
+		CompositeApp:          ca,
+		Version:               v,
+		DeploymentIntentGroup: di,
+	}
+	err := db.DBconn.Remove(c.storeName, key)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Delete Schedule Entry;")
+	}
+	return nil
+}
+
+func (c *ScheduleClient) getScheduleStatus(key ScheduleKey) (ScheduleStatus, error) {
+	value, err := db.DBconn.Find(c.storeName, key, c.tagStatus)
+	if err != nil {
+		return ScheduleStatus{}, pkgerrors.Wrap(err, "Get Schedule status")
+	}
+	status := ScheduleStatus{}
+	if len(value) == 0 || value[0] == nil {
+		return status, nil
+	}
+	err = db.DBconn.Unmarshal(value[0], &status)
+	if err != nil {
+		return ScheduleStatus{}, pkgerrors.Wrap(err, "Unmarshaling Schedule status")
+	}
+	return status, nil
+}
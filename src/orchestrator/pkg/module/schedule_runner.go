@@ -0,0 +1,338 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+/*
+This file deals with running the operations of the schedules once they are
+due and the maintenance windows of the cluster labels of their placement are
+open
+*/
+
+import (
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/gpic"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/cron"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// nextRun returns the time the schedule is next due, the zero time when a
+// one-shot schedule has already run
+func (s Schedule) nextRun(status ScheduleStatus) time.Time {
+	if s.Spec.At != "" {
+		if !status.LastRun.IsZero() {
+			return time.Time{}
+		}
+		at, err := time.Parse(time.RFC3339, s.Spec.At)
+		if err != nil {
+			return time.Time{}
+		}
+		return at
+	}
+
+	e, err := cron.Parse(s.Spec.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	loc, err := s.location()
+	if err != nil {
+		return time.Time{}
+	}
+	from := status.Created
+	if status.LastRun.After(from) {
+		from = status.LastRun
+	}
+	return e.Next(from.In(loc))
+}
+
+// openAt reports whether the maintenance window is open at t
+func (w MaintenanceWindow) openAt(t time.Time, loc *time.Location) (bool, error) {
+	e, err := cron.Parse(w.Start)
+	if err != nil {
+		return false, err
+	}
+	d, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return false, pkgerrors.Wrap(err, "Invalid duration of the maintenance window")
+	}
+	start := e.Prev(t.In(loc), d)
+	return !start.IsZero() && t.Before(start.Add(d)), nil
+}
+
+// windowsOpen reports whether the maintenance windows of the schedule are
+// open at t. Only the windows of the cluster labels of the clusters the
+// DeploymentIntentGroup is placed on apply.
+func (c *ScheduleClient) windowsOpen(s Schedule, p, ca, v, di string, t time.Time) (bool, error) {
+	if len(s.Spec.MaintenanceWindows) == 0 {
+		return true, nil
+	}
+	loc, err := s.location()
+	if err != nil {
+		return false, err
+	}
+	placed, err := c.placement(p, ca, v, di)
+	if err != nil {
+		return false, pkgerrors.Wrap(err, "Error getting the clusters of the DeploymentIntentGroup")
+	}
+	for _, w := range s.Spec.MaintenanceWindows {
+		names, err := c.clusters.GetClustersWithLabel(w.ClusterProvider, w.ClusterLabel)
+		if err != nil {
+			return false, pkgerrors.Wrap(err, "Error getting the clusters of the maintenance window")
+		}
+		applies := false
+		for _, n := range names {
+			if placed[w.ClusterProvider+SEPARATOR+n] {
+				applies = true
+				break
+			}
+		}
+		if !applies {
+			continue
+		}
+		open, err := w.openAt(t, loc)
+		if err != nil || !open {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// placementClusters returns the clusters, <provider>+<cluster>, the
+// DeploymentIntentGroup is placed on: the clusters its generic placement
+// intents select and the clusters it is deployed to
+func placementClusters(p, ca, v, di string) (map[string]bool, error) {
+	placed := map[string]bool{}
+	gIntent, err := findGenericPlacementIntent(p, ca, v, di)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := NewAppClient().GetApps(p, ca, v)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Unable to get the apps")
+	}
+	for _, app := range apps {
+		specData, err := NewAppIntentClient().GetAllIntentsByApp(app.Metadata.Name, p, ca, v, gIntent, di)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Unable to get the intents for app "+app.Metadata.Name)
+		}
+		l, err := gpic.IntentResolver(specData.Intent)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Unable to resolve the intents for app "+app.Metadata.Name)
+		}
+		for _, c := range clustersOfClusterList(l) {
+			placed[c.ProviderName+SEPARATOR+c.ClusterName] = true
+		}
+	}
+
+	s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Unable to get the state of the DeploymentIntentGroup")
+	}
+	ctxID := state.GetLastContextIdFromStateInfo(s)
+	if ctxID == "" {
+		return placed, nil
+	}
+	ac, err := state.GetAppContextFromId(ctxID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Unable to get the AppContext of the DeploymentIntentGroup")
+	}
+	order, err := appOrder(ac)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Unable to get the apps of the AppContext")
+	}
+	for _, app := range order {
+		clusters, err := ac.GetClusterNames(app)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Unable to get the clusters of app "+app)
+		}
+		for _, c := range clusters {
+			placed[c] = true
+		}
+	}
+	return placed, nil
+}
+
+// StartScheduler periodically runs the operations of the schedules that are due
+func StartScheduler(interval time.Duration) {
+	c := NewScheduleClient()
+	go func() {
+		for {
+			c.RunDueSchedules(time.Now())
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// RunDueSchedules runs the operations of the schedules of all the
+// DeploymentIntentGroups that are due at now
+func (c *ScheduleClient) RunDueSchedules(now time.Time) {
+	projects, err := NewProjectClient().GetAllProjects()
+	if err != nil {
+		log.Error("Error getting projects for the schedules", log.Fields{"Error": err})
+		return
+	}
+	for _, project := range projects {
+		p := project.MetaData.Name
+		cas, err := NewCompositeAppClient().GetAllCompositeApps(p)
+		if err != nil {
+			log.Error("Error getting composite apps for the schedules", log.Fields{"Project": p, "Error": err})
+			continue
+		}
+		for _, compositeApp := range cas {
+			ca, v := compositeApp.Metadata.Name, compositeApp.Spec.Version
+			digs, err := NewDeploymentIntentGroupClient().GetAllDeploymentIntentGroups(p, ca, v)
+			if err != nil {
+				log.Error("Error getting DeploymentIntentGroups for the schedules", log.Fields{"Project": p, "CompositeApp": ca, "Error": err})
+				continue
+			}
+			for _, dig := range digs {
+				c.runDueSchedulesOfDig(p, ca, v, dig.MetaData.Name, now)
+			}
+		}
+	}
+}
+
+func (c *ScheduleClient) runDueSchedulesOfDig(p, ca, v, di string, now time.Time) {
+	schedules, err := c.GetAllSchedules(p, ca, v, di)
+	if err != nil {
+		log.Error("Error getting schedules", log.Fields{"DeploymentIntentGroup": di, "Error": err})
+		return
+	}
+	for _, s := range schedules {
+		next := s.Status.NextRun
+		if next.IsZero() || next.After(now) {
+			continue
+		}
+		open, err := c.windowsOpen(s, p, ca, v, di, now)
+		if err != nil {
+			log.Error("Error evaluating the maintenance windows", log.Fields{"Schedule": s.MetaData.Name, "Error": err})
+			continue
+		}
+		if !open {
+			// Held until the windows open
+			continue
+		}
+		err = c.runSchedule(s, p, ca, v, di, now)
+		if err != nil {
+			log.Error("Error recording the run of the schedule", log.Fields{"Schedule": s.MetaData.Name, "Error": err})
+		}
+	}
+}
+
+// runSchedule runs the operation of the schedule and records its outcome in
+// the status of the schedule and the stateInfo of the DeploymentIntentGroup
+func (c *ScheduleClient) runSchedule(s Schedule, p, ca, v, di string, now time.Time) error {
+	key := ScheduleKey{
+		Schedule:              s.MetaData.Name,
+		Project:               p,
+		CompositeApp:          ca,
+		Version:               v,
+		DeploymentIntentGroup: di,
+	}
+	status, version, claimed, err := c.claimRun(key, s, now)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		log.Info("The schedule was run by another instance", log.Fields{
+			"DeploymentIntentGroup": di,
+			"Schedule":              s.MetaData.Name,
+		})
+		return nil
+	}
+
+	switch s.Spec.Operation {
+	case ScheduleOperationEnum.Instantiate:
+		err = c.instantiation.Instantiate(p, ca, v, di)
+	case ScheduleOperationEnum.Update:
+		err = c.instantiation.Update(p, ca, v, di)
+	case ScheduleOperationEnum.Terminate:
+		err = c.instantiation.Terminate(p, ca, v, di)
+	default:
+		err = &InvalidScheduleError{Reason: "unknown operation " + s.Spec.Operation}
+	}
+
+	entry := state.RunEntry{
+		Schedule:  s.MetaData.Name,
+		Operation: s.Spec.Operation,
+		Result:    state.RunResultEnum.Succeeded,
+		TimeStamp: now,
+	}
+	if err != nil {
+		entry.Result = state.RunResultEnum.Failed
+		entry.Message = err.Error()
+	}
+	log.Info("Ran the operation of the schedule", log.Fields{
+		"DeploymentIntentGroup": di,
+		"Schedule":              s.MetaData.Name,
+		"Operation":             s.Spec.Operation,
+		"Result":                entry.Result,
+	})
+
+	status.LastResult = entry.Result
+	_, err = db.DBconn.InsertIfVersion(c.storeName, key, nil, c.tagStatus, status, version)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error updating the status of the schedule")
+	}
+
+	// The operation has updated the stateInfo, which is read again
+	ic := NewInstantiationClient()
+	si, version, err := ic.getStateInfo(p, ca, v, di)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error getting the stateInfo of the DeploymentIntentGroup")
+	}
+	si.Runs = append(si.Runs, entry)
+	_, err = ic.saveStateInfo(p, ca, v, di, si, version)
+	return err
+}
+
+// claimRun records the run of the schedule at now in its status before the
+// operation is run, so that the operation is run once even when several
+// instances run the schedules, and is not run again when its outcome fails
+// to be recorded. It reports false when another instance has claimed the run.
+// It returns the claimed status and its version.
+func (c *ScheduleClient) claimRun(key ScheduleKey, s Schedule, now time.Time) (ScheduleStatus, int64, bool, error) {
+	// The version is read before the status, so that a claim made since the
+	// status was read fails to be stored
+	version, err := db.DBconn.GetVersion(c.storeName, key)
+	if err != nil {
+		return ScheduleStatus{}, 0, false, pkgerrors.Wrap(err, "Error getting the version of the schedule")
+	}
+	status, err := c.getScheduleStatus(key)
+	if err != nil {
+		return ScheduleStatus{}, 0, false, err
+	}
+	if !status.LastRun.Equal(s.Status.LastRun) {
+		return ScheduleStatus{}, 0, false, nil
+	}
+
+	status.LastRun = now
+	status.LastResult = ""
+	status.NextRun = time.Time{}
+	version, err = db.DBconn.InsertIfVersion(c.storeName, key, nil, c.tagStatus, status, version)
+	if _, ok := err.(*db.VersionConflictError); ok {
+		return ScheduleStatus{}, 0, false, nil
+	}
+	if err != nil {
+		return ScheduleStatus{}, 0, false, pkgerrors.Wrap(err, "Error claiming the run of the schedule")
+	}
+	return status, version, true, nil
+}
@@ -0,0 +1,248 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/onap/multicloud-k8s/src/clm/pkg/cluster"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/state"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+type mockInstantiation struct {
+	// Only the operations a schedule runs are implemented
+	InstantiationManager
+	ran []string
+	err error
+}
+
+func (m *mockInstantiation) Instantiate(p string, ca string, v string, di string) error {
+	m.ran = append(m.ran, ScheduleOperationEnum.Instantiate)
+	return m.err
+}
+
+func (m *mockInstantiation) Update(p string, ca string, v string, di string) error {
+	m.ran = append(m.ran, ScheduleOperationEnum.Update)
+	return m.err
+}
+
+func (m *mockInstantiation) Terminate(p string, ca string, v string, di string) error {
+	m.ran = append(m.ran, ScheduleOperationEnum.Terminate)
+	return m.err
+}
+
+type mockLabelClusters struct {
+	// Only the lookup of the clusters by label is implemented
+	cluster.ClusterManager
+	labels map[string][]string
+}
+
+func (m *mockLabelClusters) GetClustersWithLabel(provider, label string) ([]string, error) {
+	return m.labels[provider+SEPARATOR+label], nil
+}
+
+// claimedDB fails the conditional writes of the schedule status, like
+// another instance claiming the run meanwhile
+type claimedDB struct {
+	*insertRecordingDB
+}
+
+func (m *claimedDB) InsertIfVersion(table string, key db.Key, query interface{}, tag string, data interface{}, version int64) (int64, error) {
+	if tag == "schedulestatus" {
+		return 0, &db.VersionConflictError{Version: version}
+	}
+	return m.insertRecordingDB.InsertIfVersion(table, key, query, tag, data, version)
+}
+
+func TestCheckSchedule(t *testing.T) {
+	testCases := []struct {
+		label string
+		spec  ScheduleSpec
+		valid bool
+	}{
+		{label: "One-shot", spec: ScheduleSpec{Operation: "instantiate", At: "2020-06-02T02:00:00+09:00"}, valid: true},
+		{label: "Cron", spec: ScheduleSpec{Operation: "update", Cron: "0 2 * * *", TimeZone: "UTC"}, valid: true},
+		{label: "Unknown operation", spec: ScheduleSpec{Operation: "restart", Cron: "0 2 * * *"}},
+		{label: "Both at and cron", spec: ScheduleSpec{Operation: "terminate", At: "2020-06-02T02:00:00Z", Cron: "0 2 * * *"}},
+		{label: "Neither at nor cron", spec: ScheduleSpec{Operation: "terminate"}},
+		{label: "Invalid cron", spec: ScheduleSpec{Operation: "terminate", Cron: "0 25 * * *"}},
+		{label: "Invalid window", spec: ScheduleSpec{Operation: "terminate", Cron: "0 2 * * *",
+			MaintenanceWindows: []MaintenanceWindow{{ClusterProvider: "p1", ClusterLabel: "edge", Start: "0 1 * * *", Duration: "forever"}}}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			err := Schedule{Spec: testCase.spec}.check()
+			if testCase.valid && err != nil {
+				t.Fatalf("check returned an unexpected error %s", err)
+			}
+			if !testCase.valid {
+				if _, ok := err.(*InvalidScheduleError); !ok {
+					t.Fatalf("check expected InvalidScheduleError, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRunDueSchedules(t *testing.T) {
+	created := time.Date(2020, 6, 1, 1, 0, 0, 0, time.UTC)
+	now := time.Date(2020, 6, 2, 2, 0, 30, 0, time.UTC)
+	nightly := ScheduleSpec{Operation: ScheduleOperationEnum.Update, Cron: "0 2 * * *"}
+	// Opens at 01:00 for two hours
+	open := []MaintenanceWindow{{ClusterProvider: "p1", ClusterLabel: "edge", Start: "0 1 * * *", Duration: "2h"}}
+	// Opens at 22:00 for two hours
+	closed := []MaintenanceWindow{{ClusterProvider: "p1", ClusterLabel: "edge", Start: "0 22 * * *", Duration: "2h"}}
+	// The clusters labelled core are not placed
+	otherClosed := []MaintenanceWindow{{ClusterProvider: "p1", ClusterLabel: "core", Start: "0 22 * * *", Duration: "2h"}}
+
+	testCases := []struct {
+		label    string
+		spec     ScheduleSpec
+		status   ScheduleStatus
+		err      error
+		claimed  bool
+		expected []string
+		result   state.RunResult
+	}{
+		{
+			label:    "Due cron schedule",
+			spec:     nightly,
+			status:   ScheduleStatus{Created: created},
+			expected: []string{ScheduleOperationEnum.Update},
+			result:   state.RunResultEnum.Succeeded,
+		},
+		{
+			label:  "Cron schedule that already ran",
+			spec:   nightly,
+			status: ScheduleStatus{Created: created, LastRun: now.Add(-10 * time.Second)},
+		},
+		{
+			label: "Cron schedule in another time zone",
+			spec:  ScheduleSpec{Operation: ScheduleOperationEnum.Update, Cron: "0 2 * * *", TimeZone: "Etc/GMT-9"},
+			// 02:00 in UTC+9 is 17:00 UTC
+			status: ScheduleStatus{Created: now.Add(-time.Hour)},
+		},
+		{
+			label:    "Due one-shot schedule",
+			spec:     ScheduleSpec{Operation: ScheduleOperationEnum.Terminate, At: "2020-06-02T01:30:00Z"},
+			status:   ScheduleStatus{Created: created},
+			expected: []string{ScheduleOperationEnum.Terminate},
+			result:   state.RunResultEnum.Succeeded,
+		},
+		{
+			label:  "One-shot schedule in the future",
+			spec:   ScheduleSpec{Operation: ScheduleOperationEnum.Terminate, At: "2020-06-02T03:00:00Z"},
+			status: ScheduleStatus{Created: created},
+		},
+		{
+			label:  "One-shot schedule that already ran",
+			spec:   ScheduleSpec{Operation: ScheduleOperationEnum.Terminate, At: "2020-06-02T01:30:00Z"},
+			status: ScheduleStatus{Created: created, LastRun: now.Add(-time.Minute)},
+		},
+		{
+			label:    "Open maintenance window",
+			spec:     ScheduleSpec{Operation: ScheduleOperationEnum.Update, Cron: "0 2 * * *", MaintenanceWindows: open},
+			status:   ScheduleStatus{Created: created},
+			expected: []string{ScheduleOperationEnum.Update},
+			result:   state.RunResultEnum.Succeeded,
+		},
+		{
+			label:  "Closed maintenance window",
+			spec:   ScheduleSpec{Operation: ScheduleOperationEnum.Update, Cron: "0 2 * * *", MaintenanceWindows: closed},
+			status: ScheduleStatus{Created: created},
+		},
+		{
+			label:    "Closed maintenance window of other clusters",
+			spec:     ScheduleSpec{Operation: ScheduleOperationEnum.Update, Cron: "0 2 * * *", MaintenanceWindows: append(open, otherClosed...)},
+			status:   ScheduleStatus{Created: created},
+			expected: []string{ScheduleOperationEnum.Update},
+			result:   state.RunResultEnum.Succeeded,
+		},
+		{
+			label:   "Run claimed by another instance",
+			spec:    nightly,
+			status:  ScheduleStatus{Created: created},
+			claimed: true,
+		},
+		{
+			label:    "Failed operation",
+			spec:     ScheduleSpec{Operation: ScheduleOperationEnum.Instantiate, At: "2020-06-02T01:30:00Z"},
+			status:   ScheduleStatus{Created: created},
+			err:      pkgerrors.New("DeploymentIntentGroup must be Approved before instantiating"),
+			expected: []string{ScheduleOperationEnum.Instantiate},
+			result:   state.RunResultEnum.Failed,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			s, _ := json.Marshal(Schedule{MetaData: ScheduleMetaData{Name: "nightly"}, Spec: testCase.spec})
+			status, _ := json.Marshal(testCase.status)
+			si, _ := json.Marshal(state.StateInfo{Actions: []state.ActionEntry{{State: state.StateEnum.Approved, TimeStamp: created}}})
+			mockdb := &insertRecordingDB{MockDB: &db.MockDB{Items: map[string]map[string][]byte{
+				ScheduleKey{Schedule: "", Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion, DeploymentIntentGroup: gdDig}.String(): {
+					"schedulemetadata": s,
+				},
+				ScheduleKey{Schedule: "nightly", Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion, DeploymentIntentGroup: gdDig}.String(): {
+					"schedulestatus": status,
+				},
+				DeploymentIntentGroupKey{Name: gdDig, Project: gdProject, CompositeApp: gdCompositeApp, Version: gdVersion}.String(): {
+					"stateInfo": si,
+				},
+			}}}
+			db.DBconn = mockdb
+			if testCase.claimed {
+				db.DBconn = &claimedDB{mockdb}
+			}
+
+			mock := &mockInstantiation{err: testCase.err}
+			c := NewScheduleClient()
+			c.instantiation = mock
+			c.clusters = &mockLabelClusters{labels: map[string][]string{
+				"p1+edge": {"c1", "c2"},
+				"p1+core": {"c3"},
+			}}
+			c.placement = func(p, ca, v, di string) (map[string]bool, error) {
+				return map[string]bool{"p1+c1": true}, nil
+			}
+			c.runDueSchedulesOfDig(gdProject, gdCompositeApp, gdVersion, gdDig, now)
+
+			if len(mock.ran) != len(testCase.expected) || (len(mock.ran) > 0 && mock.ran[0] != testCase.expected[0]) {
+				t.Fatalf("Scheduler ran %v, expected %v", mock.ran, testCase.expected)
+			}
+			if len(testCase.expected) == 0 {
+				if mockdb.inserted != nil {
+					t.Errorf("Scheduler recorded %v without running", mockdb.inserted)
+				}
+				return
+			}
+			got := mockdb.inserted.(state.StateInfo)
+			if len(got.Runs) != 1 || got.Runs[0].Result != testCase.result || got.Runs[0].Schedule != "nightly" {
+				t.Errorf("Scheduler recorded unexpected runs %v", got.Runs)
+			}
+			if testCase.err != nil && got.Runs[0].Message != testCase.err.Error() {
+				t.Errorf("Scheduler recorded message %q, expected %q", got.Runs[0].Message, testCase.err.Error())
+			}
+		})
+	}
+}
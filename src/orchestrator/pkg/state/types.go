@@ -22,7 +22,9 @@ import "time"
 // information about resources which can be instantiated via rsync.
 // The last Actions entry holds the current state of the container object.
 type StateInfo struct {
-	Actions []ActionEntry `json:"actions"`
+	Actions   []ActionEntry   `json:"actions"`
+	Approvals []ApprovalEntry `json:"approvals,omitempty"`
+	Runs      []RunEntry      `json:"scheduled-runs,omitempty"`
 }
 
 // ActionEntry is used to keep track of the time an action (e.g. Created, Instantiate, Terminate) was invoked
@@ -33,6 +35,49 @@ type ActionEntry struct {
 	TimeStamp time.Time  `json:"time"`
 }
 
+// ApprovalEntry records the decision of an approver on the object, with the roles
+// the approver held and the comment the approver gave
+type ApprovalEntry struct {
+	Approver  string           `json:"approver"`
+	Roles     []string         `json:"roles,omitempty"`
+	Decision  ApprovalDecision `json:"decision"`
+	Comment   string           `json:"comment,omitempty"`
+	TimeStamp time.Time        `json:"time"`
+}
+
+// RunEntry records the outcome of an operation a schedule ran on the object
+type RunEntry struct {
+	Schedule  string    `json:"schedule"`
+	Operation string    `json:"operation"`
+	Result    RunResult `json:"result"`
+	Message   string    `json:"message,omitempty"`
+	TimeStamp time.Time `json:"time"`
+}
+
+type RunResult = string
+
+type runResults struct {
+	Succeeded RunResult
+	Failed    RunResult
+}
+
+var RunResultEnum = &runResults{
+	Succeeded: "Succeeded",
+	Failed:    "Failed",
+}
+
+type ApprovalDecision = string
+
+type decisions struct {
+	Approve ApprovalDecision
+	Reject  ApprovalDecision
+}
+
+var DecisionEnum = &decisions{
+	Approve: "Approve",
+	Reject:  "Reject",
+}
+
 type StateValue = string
 
 type states struct {
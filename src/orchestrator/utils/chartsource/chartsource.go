@@ -0,0 +1,545 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package chartsource fetches the charts that apps reference in a Helm
+// repository or an OCI registry, and caches them by digest.
+package chartsource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Supported source types
+const (
+	TypeHelmRepo = "helm-repo"
+	TypeOCI      = "oci"
+)
+
+// Media types of the OCI manifests and of the chart layers
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	helmChartMediaType   = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+)
+
+// maxChartSize limits the size of the charts that are downloaded, and
+// maxMetadataSize the size of the indexes, manifests and tokens
+const (
+	maxChartSize    = 100 << 20
+	maxMetadataSize = 16 << 20
+)
+
+// DefaultTimeout bounds the requests of the Fetchers created without a
+// client
+const DefaultTimeout = 5 * time.Minute
+
+var digestRegex = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// Source references a chart in a Helm repository or an OCI registry.
+// Digest pins the content of the chart once it has been fetched.
+type Source struct {
+	Type      string
+	URL       string
+	Chart     string
+	Version   string
+	Reference string
+	Digest    string
+}
+
+// Credentials authenticate against the repository or the registry
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// Fetcher fetches the charts and caches them by digest in cacheDir.
+// The credentials are only sent to the repository or the registry, and to
+// the authorization services of the registries on tokenHosts.
+type Fetcher struct {
+	client     *http.Client
+	cacheDir   string
+	tokenHosts map[string]bool
+}
+
+// NewFetcher returns a Fetcher using the given http client, or a client
+// with DefaultTimeout
+func NewFetcher(client *http.Client, cacheDir string) *Fetcher {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &Fetcher{
+		client:     client,
+		cacheDir:   cacheDir,
+		tokenHosts: map[string]bool{},
+	}
+}
+
+// TrustTokenHosts adds the hosts of a comma separated list to the hosts of
+// the authorization services the credentials of a registry are sent to,
+// besides the registry itself
+func (f *Fetcher) TrustTokenHosts(hosts string) *Fetcher {
+	for _, h := range strings.Split(hosts, ",") {
+		h = strings.TrimSpace(h)
+		if h != "" {
+			f.tokenHosts[h] = true
+		}
+	}
+	return f
+}
+
+// sameOrigin tells whether the urls have the same scheme and host
+func sameOrigin(a, b *url.URL) bool {
+	return a.Scheme == b.Scheme && a.Host == b.Host
+}
+
+// Validate checks that the source has the fields its type requires
+func Validate(src Source) error {
+	switch src.Type {
+	case TypeHelmRepo:
+		if src.URL == "" || src.Chart == "" || src.Version == "" {
+			return pkgerrors.New("A helm-repo source requires url, chart and version")
+		}
+		u, err := url.Parse(src.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return pkgerrors.Errorf("Invalid repository url %s", src.URL)
+		}
+	case TypeOCI:
+		if src.Reference == "" {
+			return pkgerrors.New("An oci source requires reference")
+		}
+		if _, err := parseReference(src.Reference); err != nil {
+			return err
+		}
+	default:
+		return pkgerrors.Errorf("Unsupported source type %s", src.Type)
+	}
+	if src.Digest != "" && !digestRegex.MatchString(src.Digest) {
+		return pkgerrors.Errorf("Invalid digest %s", src.Digest)
+	}
+	return nil
+}
+
+// ChartName returns the name of the chart of a valid source: the chart of a
+// Helm repository, or the last element of the repository of an artifact
+func ChartName(src Source) string {
+	if src.Type == TypeHelmRepo {
+		return src.Chart
+	}
+	ref, _ := parseReference(src.Reference)
+	return path.Base(ref.repository)
+}
+
+// Digest returns the digest of the content
+func Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Fetch returns the chart tarball of the source along with its digest.
+// A chart pinned by digest is served from the cache when it is there, and
+// it is an error if the chart fetched does not match the pinned digest.
+// The chart is streamed to a file of the cache while its digest is
+// computed, and is read back once it is complete and verified.
+func (f *Fetcher) Fetch(src Source, creds *Credentials) ([]byte, string, error) {
+	err := Validate(src)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if src.Digest != "" {
+		content, err := f.readCache(src.Digest)
+		if err == nil {
+			return content, src.Digest, nil
+		}
+	}
+
+	tmpDir := ""
+	if f.cacheDir != "" && os.MkdirAll(f.cacheDir, 0700) == nil {
+		tmpDir = f.cacheDir
+	}
+	tmp, err := ioutil.TempFile(tmpDir, "chart-")
+	if err != nil {
+		return nil, "", pkgerrors.Wrap(err, "Creating chart file")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := sha256.New()
+	w := io.MultiWriter(tmp, h)
+	var expected string
+	switch src.Type {
+	case TypeHelmRepo:
+		expected, err = f.fetchFromRepo(src, creds, w)
+	case TypeOCI:
+		expected, err = f.fetchFromRegistry(src, creds, w)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	digest := "sha256:" + hex.EncodeToString(h.Sum(nil))
+	if expected != "" && expected != digest {
+		return nil, "", pkgerrors.Errorf("Chart digest %s does not match the digest %s of the source", digest, expected)
+	}
+	if src.Digest != "" && src.Digest != digest {
+		return nil, "", pkgerrors.Errorf("Chart digest %s does not match the pinned digest %s", digest, src.Digest)
+	}
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, "", pkgerrors.Wrap(err, "Reading chart file")
+	}
+	content, err := ioutil.ReadAll(tmp)
+	if err != nil {
+		return nil, "", pkgerrors.Wrap(err, "Reading chart file")
+	}
+	if tmpDir != "" {
+		tmp.Close()
+		err = os.Rename(tmp.Name(), f.cachePath(digest))
+		if err != nil {
+			log.Warn("Unable to cache chart", log.Fields{"digest": digest, "error": err.Error()})
+		}
+	}
+	return content, digest, nil
+}
+
+func (f *Fetcher) cachePath(digest string) string {
+	return filepath.Join(f.cacheDir, strings.Replace(digest, ":", "-", 1)+".tgz")
+}
+
+func (f *Fetcher) readCache(digest string) ([]byte, error) {
+	if f.cacheDir == "" {
+		return nil, pkgerrors.New("No chart cache")
+	}
+	content, err := ioutil.ReadFile(f.cachePath(digest))
+	if err != nil {
+		return nil, err
+	}
+	// Discard a corrupted entry
+	if Digest(content) != digest {
+		os.Remove(f.cachePath(digest))
+		return nil, pkgerrors.New("Corrupted chart cache entry")
+	}
+	return content, nil
+}
+
+// copyBounded copies the reader to the writer, and fails when the reader
+// has more than limit bytes
+func copyBounded(w io.Writer, r io.Reader, limit int64) error {
+	n, err := io.Copy(w, io.LimitReader(r, limit+1))
+	if err != nil {
+		return err
+	}
+	if n > limit {
+		return pkgerrors.Errorf("Larger than the limit of %d bytes", limit)
+	}
+	return nil
+}
+
+// open sends a GET request with the credentials and returns a successful
+// response, whose body the caller closes
+func (f *Fetcher) open(u string, creds *Credentials, accept string) (*http.Response, error) {
+	resp, err := f.do(u, creds, accept, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Registries ask for a token from their authorization service
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			token, err := f.getToken(u, challenge, creds)
+			if err != nil {
+				return nil, err
+			}
+			resp.Body.Close()
+			resp, err = f.do(u, nil, accept, token)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, pkgerrors.Errorf("GET %s: %s", u, resp.Status)
+	}
+	return resp, nil
+}
+
+// get returns the body of a successful GET request, up to maxMetadataSize
+func (f *Fetcher) get(u string, creds *Credentials, accept string) ([]byte, error) {
+	resp, err := f.open(u, creds, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body bytes.Buffer
+	err = copyBounded(&body, resp.Body, maxMetadataSize)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "GET %s", u)
+	}
+	return body.Bytes(), nil
+}
+
+// download streams the body of a successful GET request, up to
+// maxChartSize, to the writer
+func (f *Fetcher) download(u string, creds *Credentials, w io.Writer) error {
+	resp, err := f.open(u, creds, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = copyBounded(w, resp.Body, maxChartSize)
+	if err != nil {
+		return pkgerrors.Wrapf(err, "GET %s", u)
+	}
+	return nil
+}
+
+func (f *Fetcher) do(u string, creds *Credentials, accept, token string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	switch {
+	case token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	case creds != nil && creds.Token != "":
+		req.Header.Set("Authorization", "Bearer "+creds.Token)
+	case creds != nil && creds.Username != "":
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "GET %s", u)
+	}
+	return resp, nil
+}
+
+// getToken gets a token from the authorization service of the challenge to
+// the request of the url. The credentials are only sent to an authorization
+// service on the host of the registry or on a trusted host, otherwise an
+// anonymous token is requested.
+func (f *Fetcher) getToken(requestURL, challenge string, creds *Credentials) (string, error) {
+	params := map[string]string{}
+	for _, m := range regexp.MustCompile(`(\w+)="([^"]*)"`).FindAllStringSubmatch(challenge, -1) {
+		params[m[1]] = m[2]
+	}
+	if params["realm"] == "" {
+		return "", pkgerrors.Errorf("Invalid authentication challenge %s", challenge)
+	}
+	u, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Parsing authentication realm")
+	}
+	r, err := url.Parse(requestURL)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Parsing registry url")
+	}
+	if creds != nil && !(u.Scheme == "https" && (u.Host == r.Host || f.tokenHosts[u.Host])) {
+		log.Warn("Not sending the credentials to an untrusted authentication realm", log.Fields{
+			"Realm": u.Scheme + "://" + u.Host,
+		})
+		creds = nil
+	}
+	q := u.Query()
+	for _, k := range []string{"service", "scope"} {
+		if params[k] != "" {
+			q.Set(k, params[k])
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	resp, err := f.do(u.String(), creds, "", "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", pkgerrors.Errorf("Getting registry token: %s", resp.Status)
+	}
+	var tr struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxMetadataSize)).Decode(&tr)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Decoding registry token")
+	}
+	if tr.Token != "" {
+		return tr.Token, nil
+	}
+	return tr.AccessToken, nil
+}
+
+// repoIndex is the part of the index.yaml of a Helm repository in use
+type repoIndex struct {
+	Entries map[string][]struct {
+		Version string   `json:"version"`
+		URLs    []string `json:"urls"`
+		Digest  string   `json:"digest"`
+	} `json:"entries"`
+}
+
+// fetchFromRepo downloads the chart version from the index of the repository
+// to the writer, and returns the digest of the index for the chart
+func (f *Fetcher) fetchFromRepo(src Source, creds *Credentials, w io.Writer) (string, error) {
+	base, err := url.Parse(strings.TrimSuffix(src.URL, "/") + "/")
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Parsing repository url")
+	}
+	indexURL, _ := base.Parse("index.yaml")
+	data, err := f.get(indexURL.String(), creds, "")
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Getting repository index")
+	}
+	var index repoIndex
+	err = yaml.Unmarshal(data, &index)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Parsing repository index")
+	}
+
+	for _, cv := range index.Entries[src.Chart] {
+		if cv.Version != src.Version {
+			continue
+		}
+		if len(cv.URLs) == 0 {
+			return "", pkgerrors.Errorf("No url for chart %s version %s", src.Chart, src.Version)
+		}
+		chartURL, err := base.Parse(cv.URLs[0])
+		if err != nil {
+			return "", pkgerrors.Wrap(err, "Parsing chart url")
+		}
+		// As Helm does, the credentials of the repository are not sent to
+		// the charts the index lists on other hosts
+		chartCreds := creds
+		if !sameOrigin(chartURL, base) {
+			chartCreds = nil
+		}
+		err = f.download(chartURL.String(), chartCreds, w)
+		if err != nil {
+			return "", pkgerrors.Wrap(err, "Downloading chart")
+		}
+		if cv.Digest == "" {
+			return "", nil
+		}
+		return "sha256:" + cv.Digest, nil
+	}
+	return "", pkgerrors.Errorf("Chart %s version %s not found in %s", src.Chart, src.Version, src.URL)
+}
+
+// ociReference is a parsed reference to an artifact in an OCI registry
+type ociReference struct {
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+// parseReference parses references of the form
+// [oci://]registry/repository[:tag][@digest]
+func parseReference(ref string) (ociReference, error) {
+	var r ociReference
+	s := strings.TrimPrefix(ref, "oci://")
+	i := strings.Index(s, "/")
+	if i <= 0 || i == len(s)-1 {
+		return r, pkgerrors.Errorf("Invalid oci reference %s", ref)
+	}
+	r.registry, s = s[:i], s[i+1:]
+	if i := strings.Index(s, "@"); i >= 0 {
+		r.digest, s = s[i+1:], s[:i]
+		if !digestRegex.MatchString(r.digest) {
+			return r, pkgerrors.Errorf("Invalid digest in oci reference %s", ref)
+		}
+	}
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		r.tag, s = s[i+1:], s[:i]
+	}
+	r.repository = s
+	if r.repository == "" || (r.tag == "" && r.digest == "") {
+		return r, pkgerrors.Errorf("Invalid oci reference %s, a tag or digest is required", ref)
+	}
+	return r, nil
+}
+
+// fetchFromRegistry downloads the chart layer of the artifact to the writer,
+// and returns the digest of the layer
+func (f *Fetcher) fetchFromRegistry(src Source, creds *Credentials, w io.Writer) (string, error) {
+	ref, _ := parseReference(src.Reference)
+	base := fmt.Sprintf("https://%s/v2/%s", ref.registry, ref.repository)
+
+	// The chart layer of a pinned chart is downloaded directly
+	layer := src.Digest
+	if layer == "" {
+		manifestRef := ref.digest
+		if manifestRef == "" {
+			manifestRef = ref.tag
+		}
+		data, err := f.get(base+"/manifests/"+manifestRef, creds, ociManifestMediaType)
+		if err != nil {
+			return "", pkgerrors.Wrap(err, "Getting chart manifest")
+		}
+		var manifest struct {
+			Layers []struct {
+				MediaType string `json:"mediaType"`
+				Digest    string `json:"digest"`
+			} `json:"layers"`
+		}
+		err = json.Unmarshal(data, &manifest)
+		if err != nil {
+			return "", pkgerrors.Wrap(err, "Parsing chart manifest")
+		}
+		for _, l := range manifest.Layers {
+			if l.MediaType == helmChartMediaType {
+				layer = l.Digest
+				break
+			}
+		}
+		if layer == "" {
+			return "", pkgerrors.Errorf("No chart layer in %s", src.Reference)
+		}
+	}
+
+	err := f.download(base+"/blobs/"+layer, creds, w)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Downloading chart layer")
+	}
+	return layer, nil
+}
@@ -0,0 +1,259 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chartsource
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+var testChart = []byte("chart content")
+
+// newRepoServer serves a Helm repository with the test chart
+func newRepoServer(t *testing.T, requests *int) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/charts/index.yaml":
+			fmt.Fprintf(w, `apiVersion: v1
+entries:
+  mychart:
+  - version: 1.0.0
+    urls:
+    - mychart-1.0.0.tgz
+    digest: %s
+`, strings.TrimPrefix(Digest(testChart), "sha256:"))
+		case "/charts/mychart-1.0.0.tgz":
+			w.Write(testChart)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// newRegistryServer serves an OCI registry that hands out bearer tokens
+func newRegistryServer(t *testing.T) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:charts/mychart:pull" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"token": "secret"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:charts/mychart:pull"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/charts/mychart/manifests/1.0.0":
+			fmt.Fprintf(w, `{"schemaVersion": 2, "layers": [{"mediaType": "%s", "digest": "%s"}]}`,
+				helmChartMediaType, Digest(testChart))
+		case "/v2/charts/mychart/blobs/" + Digest(testChart):
+			w.Write(testChart)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return srv
+}
+
+func TestFetchFromRepo(t *testing.T) {
+	requests := 0
+	srv := newRepoServer(t, &requests)
+	defer srv.Close()
+
+	cacheDir, err := ioutil.TempDir("", "chart-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	f := NewFetcher(srv.Client(), cacheDir)
+	src := Source{Type: TypeHelmRepo, URL: srv.URL + "/charts", Chart: "mychart", Version: "1.0.0"}
+
+	_, _, err = f.Fetch(src, nil)
+	if err == nil {
+		t.Fatalf("Expected an error without credentials")
+	}
+
+	creds := &Credentials{Username: "user", Password: "pass"}
+	content, digest, err := f.Fetch(src, creds)
+	if err != nil {
+		t.Fatalf("Fetch returned an error (%s)", err)
+	}
+	if string(content) != string(testChart) || digest != Digest(testChart) {
+		t.Fatalf("Unexpected chart %q with digest %s", content, digest)
+	}
+
+	// The pinned chart comes from the cache
+	requests = 0
+	src.Digest = digest
+	content, _, err = f.Fetch(src, creds)
+	if err != nil {
+		t.Fatalf("Fetch of the pinned chart returned an error (%s)", err)
+	}
+	if requests != 0 || string(content) != string(testChart) {
+		t.Fatalf("Expected the pinned chart from the cache, got %d requests", requests)
+	}
+
+	// A chart that does not match the pinned digest is rejected
+	src.Digest = Digest([]byte("other content"))
+	_, _, err = f.Fetch(src, creds)
+	if err == nil || !strings.Contains(err.Error(), "does not match the pinned digest") {
+		t.Fatalf("Expected a digest mismatch error, got %v", err)
+	}
+
+	src.Digest = ""
+	src.Version = "2.0.0"
+	_, _, err = f.Fetch(src, creds)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestFetchFromRegistry(t *testing.T) {
+	srv := newRegistryServer(t)
+	defer srv.Close()
+
+	f := NewFetcher(srv.Client(), "")
+	host := strings.TrimPrefix(srv.URL, "https://")
+	src := Source{Type: TypeOCI, Reference: "oci://" + host + "/charts/mychart:1.0.0"}
+
+	content, digest, err := f.Fetch(src, nil)
+	if err != nil {
+		t.Fatalf("Fetch returned an error (%s)", err)
+	}
+	if string(content) != string(testChart) || digest != Digest(testChart) {
+		t.Fatalf("Unexpected chart %q with digest %s", content, digest)
+	}
+
+	// The layer of a pinned chart is downloaded directly
+	src.Reference = host + "/charts/mychart:2.0.0"
+	src.Digest = digest
+	_, _, err = f.Fetch(src, nil)
+	if err != nil {
+		t.Fatalf("Fetch of the pinned chart returned an error (%s)", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		label string
+		src   Source
+		valid bool
+	}{
+		{"Helm repository", Source{Type: TypeHelmRepo, URL: "https://charts.example.com", Chart: "a", Version: "1.0.0"}, true},
+		{"Missing version", Source{Type: TypeHelmRepo, URL: "https://charts.example.com", Chart: "a"}, false},
+		{"Invalid url", Source{Type: TypeHelmRepo, URL: "ftp://charts.example.com", Chart: "a", Version: "1.0.0"}, false},
+		{"OCI reference", Source{Type: TypeOCI, Reference: "oci://registry.example.com/charts/a:1.0.0"}, true},
+		{"OCI reference without tag", Source{Type: TypeOCI, Reference: "registry.example.com/charts/a"}, false},
+		{"Invalid digest", Source{Type: TypeOCI, Reference: "registry.example.com/charts/a:1.0.0", Digest: "sha256:abc"}, false},
+		{"Unknown type", Source{Type: "git"}, false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			err := Validate(testCase.src)
+			if (err == nil) != testCase.valid {
+				t.Fatalf("Unexpected validation result %v", err)
+			}
+			if testCase.valid && ChartName(testCase.src) != "a" {
+				t.Fatalf("Unexpected chart name %s", ChartName(testCase.src))
+			}
+		})
+	}
+}
+
+func TestCopyBounded(t *testing.T) {
+	var buf bytes.Buffer
+	err := copyBounded(&buf, strings.NewReader("0123456789"), 10)
+	if err != nil || buf.String() != "0123456789" {
+		t.Fatalf("Unexpected copy %q (%v)", buf.String(), err)
+	}
+	err = copyBounded(ioutil.Discard, strings.NewReader("0123456789a"), 10)
+	if err == nil {
+		t.Fatalf("Expected an error copying more than the limit")
+	}
+}
+
+func TestCredentialsStayOnOrigin(t *testing.T) {
+	var leaked []string
+	other := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			leaked = append(leaked, r.URL.Path)
+		}
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, `{"token": "anonymous"}`)
+			return
+		}
+		w.Write(testChart)
+	}))
+	defer other.Close()
+
+	repo := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/charts/index.yaml":
+			fmt.Fprintf(w, "entries:\n  mychart:\n  - version: 1.0.0\n    urls:\n    - %s/mychart-1.0.0.tgz\n", other.URL)
+		case r.Header.Get("Authorization") == "Bearer anonymous":
+			w.Write(testChart)
+		default:
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token"`, other.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer repo.Close()
+
+	f := NewFetcher(repo.Client(), "")
+	creds := &Credentials{Username: "user", Password: "pass"}
+
+	_, _, err := f.Fetch(Source{Type: TypeHelmRepo, URL: repo.URL + "/charts", Chart: "mychart", Version: "1.0.0"}, creds)
+	if err != nil {
+		t.Fatalf("Fetch returned an error (%s)", err)
+	}
+	host := strings.TrimPrefix(repo.URL, "https://")
+	_, _, err = f.Fetch(Source{Type: TypeOCI, Reference: host + "/charts/mychart:1.0.0", Digest: Digest(testChart)}, creds)
+	if err != nil {
+		t.Fatalf("Fetch returned an error (%s)", err)
+	}
+	if len(leaked) > 0 {
+		t.Fatalf("Expected no credentials on another host, got them on %v", leaked)
+	}
+
+	// The credentials are sent to a trusted authorization service
+	f.TrustTokenHosts(strings.TrimPrefix(other.URL, "https://"))
+	_, _, err = f.Fetch(Source{Type: TypeOCI, Reference: host + "/charts/mychart:1.0.0", Digest: Digest(testChart)}, creds)
+	if err != nil {
+		t.Fatalf("Fetch returned an error (%s)", err)
+	}
+	if len(leaked) != 1 || leaked[0] != "/token" {
+		t.Fatalf("Expected the credentials on the trusted token host, got them on %v", leaked)
+	}
+}
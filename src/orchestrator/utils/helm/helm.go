@@ -154,7 +154,8 @@ func (h *TemplateClient) mergeValues(dest map[string]interface{}, src map[string
 	return dest
 }
 
-// GenerateKubernetesArtifacts a mapping of type to fully evaluated helm template
+// GenerateKubernetesArtifacts a mapping of type to fully evaluated helm template.
+// Charts with apiVersion v2 are rendered with the Helm v3 libraries
 func (h *TemplateClient) GenerateKubernetesArtifacts(inputPath string, valueFiles []string,
 	values []string) ([]KubernetesResourceTemplate, error) {
 
@@ -196,6 +197,15 @@ func (h *TemplateClient) GenerateKubernetesArtifacts(inputPath string, valueFile
 		return retData, fmt.Errorf("release name %s is not a valid DNS label: %s", releaseName, strings.Join(msgs, ";"))
 	}
 
+	// Charts with apiVersion v2 are rendered with the Helm v3 libraries
+	v3, err := isHelmV3Chart(chartPath)
+	if err != nil {
+		return retData, err
+	}
+	if v3 {
+		return h.generateKubernetesArtifactsV3(chartPath, rawVals, namespace, releaseName, outputDir)
+	}
+
 	// Check chart requirements to make sure all dependencies are present in /charts
 	c, err := chartutil.Load(chartPath)
 	if err != nil {
@@ -294,7 +304,7 @@ func getGroupVersionKind(data string) (schema.GroupVersionKind, error) {
 
 // Resolver is an interface exposes the helm related functionalities
 type Resolver interface {
-	Resolve(appContent, appProfileContent []byte, overrideValuesOfAppStr []string, appName string) ([]KubernetesResourceTemplate, error)
+	Resolve(appContent, appProfileContent []byte, overrideValues map[string]interface{}, appName string) ([]KubernetesResourceTemplate, error)
 }
 
 
@@ -312,7 +322,7 @@ func cleanupTempFiles(fp string)  error {
 
 
 // Resolve function
-func (h *TemplateClient) Resolve(appContent []byte, appProfileContent []byte, overrideValuesOfAppStr []string, appName string) ([]KubernetesResourceTemplate, error) {
+func (h *TemplateClient) Resolve(appContent []byte, appProfileContent []byte, overrideValues map[string]interface{}, appName string) ([]KubernetesResourceTemplate, error) {
 
 	var sortedTemplates []KubernetesResourceTemplate
 
@@ -347,8 +357,19 @@ func (h *TemplateClient) Resolve(appContent []byte, appProfileContent []byte, ov
 		return sortedTemplates, pkgerrors.Wrap(err, "Error while copying configresources to chart")
 	}
 
-	chartPath := filepath.Join(chartBasePath, appName)
-	sortedTemplates, err = h.GenerateKubernetesArtifacts(chartPath, []string{prYamlClient.GetValues()}, overrideValuesOfAppStr)
+	//String override values are set like with --set, typed ones from a values file
+	set, typedFile, err := h.processOverrideValues(overrideValues)
+	if err != nil {
+		return sortedTemplates, pkgerrors.Wrap(err, "Error while processing override values")
+	}
+	valueFiles := []string{prYamlClient.GetValues()}
+	if typedFile != "" {
+		defer os.Remove(typedFile)
+		valueFiles = append(valueFiles, typedFile)
+	}
+
+	chartPath := findAppDir(chartBasePath, appName)
+	sortedTemplates, err = h.GenerateKubernetesArtifacts(chartPath, valueFiles, set)
 	if err != nil {
 		logger.Error("Error while generating final k8s yaml", logger.Fields{})
 		return sortedTemplates, pkgerrors.Wrap(err, "Error while generating final k8s yaml")
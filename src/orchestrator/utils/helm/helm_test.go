@@ -201,3 +201,80 @@ func TestGenerateKubernetesArtifacts(t *testing.T) {
 	}
 
 }
+
+func TestGenerateKubernetesArtifactsV3(t *testing.T) {
+
+	chartDir := "../../mock_files/mock_charts/testchartv3"
+
+	testCases := []struct {
+		label         string
+		values        []string
+		expectedKinds []string
+		expectedFiles []string
+		expectedError string
+	}{
+		{
+			label: "Generate artifacts of a v3 chart in install order",
+			//CRDs first, then pre-install hooks by weight, the manifests
+			//by kind and the post-install hooks. Test hooks are left out
+			expectedKinds: []string{
+				"CustomResourceDefinition",
+				"ConfigMap",
+				"Job",
+				"Service",
+				"Deployment",
+				"Job",
+			},
+			expectedFiles: []string{
+				"testchartv3/crds/crontab.yaml-0",
+				"testchartv3/templates/pre-install-hooks.yaml-1-testreleasename-pre-install-config",
+				"testchartv3/templates/pre-install-hooks.yaml-0-testreleasename-pre-install-job",
+				"testchartv3/templates/service.yaml-0",
+				"testchartv3/templates/deployment.yaml-0",
+				"testchartv3/templates/post-install-job.yaml-0-testreleasename-post-install-job",
+			},
+		},
+		{
+			label:         "Generate artifacts of a v3 chart with values violating the schema",
+			values:        []string{"service.port=0"},
+			expectedError: "schema",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			tc := NewTemplateClient("1.19.4", "testnamespace", "testreleasename", "manifest.yaml")
+			out, err := tc.GenerateKubernetesArtifacts(chartDir, []string{}, testCase.values)
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("Got an error %s", err)
+				}
+				if strings.Contains(err.Error(), testCase.expectedError) == false {
+					t.Fatalf("Got unexpected error message %s", err)
+				}
+				return
+			}
+			if testCase.expectedError != "" {
+				t.Fatalf("Expected error %s, got none", testCase.expectedError)
+			}
+			if len(out) != len(testCase.expectedKinds) {
+				t.Fatalf("Got %d templates, expected %d: %v", len(out), len(testCase.expectedKinds), out)
+			}
+			for i, v := range out {
+				if v.GVK.Kind != testCase.expectedKinds[i] {
+					t.Errorf("Got kind %s at %d, expected %s", v.GVK.Kind, i, testCase.expectedKinds[i])
+				}
+				if strings.HasSuffix(v.FilePath, testCase.expectedFiles[i]) == false {
+					t.Errorf("Got file %s at %d, expected %s", v.FilePath, i, testCase.expectedFiles[i])
+				}
+				data, err := ioutil.ReadFile(v.FilePath)
+				if err != nil {
+					t.Fatalf("Unable to read file %s", v.FilePath)
+				}
+				if strings.Contains(string(data), "{{") {
+					t.Errorf("Template %s was not rendered", v.FilePath)
+				}
+			}
+		})
+	}
+}
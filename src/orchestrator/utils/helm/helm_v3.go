@@ -0,0 +1,254 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	logger "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	utils "github.com/onap/multicloud-k8s/src/orchestrator/utils"
+	pkgerrors "github.com/pkg/errors"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// isHelmV3Chart checks whether the chart in chartPath has apiVersion v2 and is
+// to be rendered with the Helm v3 libraries
+func isHelmV3Chart(chartPath string) (bool, error) {
+	data, err := ioutil.ReadFile(filepath.Join(chartPath, "Chart.yaml"))
+	if err != nil {
+		return false, pkgerrors.Wrap(err, "Reading Chart.yaml")
+	}
+	var metadata struct {
+		APIVersion string `json:"apiVersion"`
+	}
+	err = yaml.Unmarshal(data, &metadata)
+	if err != nil {
+		return false, pkgerrors.Wrap(err, "Parsing Chart.yaml")
+	}
+	return metadata.APIVersion == chart.APIVersionV2, nil
+}
+
+// capabilities returns the capabilities the chart is rendered with, for the
+// Kubernetes version of the client if it is set
+func (h *TemplateClient) capabilities() (*chartutil.Capabilities, error) {
+	caps := *chartutil.DefaultCapabilities
+	if h.kubeVersion == "" {
+		return &caps, nil
+	}
+	v, err := version.ParseGeneric(h.kubeVersion)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Invalid kube version %s", h.kubeVersion)
+	}
+	caps.KubeVersion = chartutil.KubeVersion{
+		Version: "v" + v.String(),
+		Major:   fmt.Sprint(v.Major()),
+		Minor:   fmt.Sprint(v.Minor()),
+	}
+	return &caps, nil
+}
+
+// splitManifests splits a manifest into its documents, in the order they
+// appear in the manifest. SplitManifests names the documents manifest-0,
+// manifest-1 and so on.
+func splitManifests(data string) []string {
+	docs := releaseutil.SplitManifests(data)
+	ordered := make([]string, 0, len(docs))
+	for i := 0; i < len(docs); i++ {
+		ordered = append(ordered, docs[fmt.Sprintf("manifest-%d", i)])
+	}
+	return ordered
+}
+
+// crdFiles returns the files in the crds directory of the chart and of its
+// dependencies, named with the full path of their chart
+func crdFiles(ch *chart.Chart) []*chart.File {
+	var files []*chart.File
+	for _, f := range ch.Files {
+		if strings.HasPrefix(f.Name, "crds/") {
+			files = append(files, &chart.File{Name: path.Join(ch.ChartFullPath(), f.Name), Data: f.Data})
+		}
+	}
+	for _, dep := range ch.Dependencies() {
+		files = append(files, crdFiles(dep)...)
+	}
+	return files
+}
+
+// writeTemplate writes the rendered template to the output directory
+func writeTemplate(outputDir, name, data string) (KubernetesResourceTemplate, error) {
+	filePath := filepath.Join(outputDir, name)
+	utils.EnsureDirectory(filePath)
+	err := ioutil.WriteFile(filePath, []byte(data), 0666)
+	if err != nil {
+		return KubernetesResourceTemplate{}, err
+	}
+	gvk, err := getGroupVersionKind(data)
+	if err != nil {
+		return KubernetesResourceTemplate{}, err
+	}
+	return KubernetesResourceTemplate{
+		GVK:      gvk,
+		FilePath: filePath,
+	}, nil
+}
+
+// hooksForEvent returns the hooks run on the event, sorted by weight
+func hooksForEvent(hooks []*release.Hook, event release.HookEvent) []*release.Hook {
+	var selected []*release.Hook
+	for _, hook := range hooks {
+		for _, e := range hook.Events {
+			if e == event {
+				selected = append(selected, hook)
+				break
+			}
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Weight < selected[j].Weight
+	})
+	return selected
+}
+
+/*
+generateKubernetesArtifactsV3 renders a chart with apiVersion v2 with the Helm v3
+libraries, the same way k8splugin installs it. The templates are returned in the
+order they are to be installed: the CRDs of the crds directory, the pre-install
+hooks by weight, the manifests in Helm install order and the post-install hooks
+by weight. Hooks for other events, such as tests, are not part of an install and
+are left out.
+*/
+func (h *TemplateClient) generateKubernetesArtifactsV3(chartPath string, rawVals []byte,
+	namespace, releaseName, outputDir string) ([]KubernetesResourceTemplate, error) {
+	var retData []KubernetesResourceTemplate
+
+	chartRequested, err := loader.Load(chartPath)
+	if err != nil {
+		return retData, pkgerrors.Wrap(err, "Loading chart")
+	}
+	if chartRequested.Metadata.Type != "" && chartRequested.Metadata.Type != "application" {
+		return retData, fmt.Errorf("chart %q has an unsupported type and is not installable: %q",
+			chartRequested.Metadata.Name, chartRequested.Metadata.Type)
+	}
+
+	vals, err := chartutil.ReadValues(rawVals)
+	if err != nil {
+		return retData, pkgerrors.Wrap(err, "Reading values")
+	}
+	// Enable or disable the dependencies of the chart according to their
+	// conditions and tags, and import the values they export
+	err = chartutil.ProcessDependencies(chartRequested, vals)
+	if err != nil {
+		return retData, pkgerrors.Wrap(err, "Processing chart dependencies")
+	}
+
+	caps, err := h.capabilities()
+	if err != nil {
+		return retData, err
+	}
+	options := chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	// ToRenderValues also validates the values against the values.schema.json
+	// files of the chart and its dependencies
+	renderVals, err := chartutil.ToRenderValues(chartRequested, vals, options, caps)
+	if err != nil {
+		return retData, err
+	}
+
+	for _, crd := range crdFiles(chartRequested) {
+		data := string(crd.Data)
+		if strings.HasPrefix(filepath.Base(crd.Name), "_") || h.whitespaceRegex.MatchString(data) {
+			continue
+		}
+		for i, doc := range splitManifests(data) {
+			if h.whitespaceRegex.MatchString(doc) {
+				continue
+			}
+			kres, err := writeTemplate(outputDir, fmt.Sprintf("%s-%d", crd.Name, i), doc)
+			if err != nil {
+				return retData, err
+			}
+			retData = append(retData, kres)
+		}
+	}
+
+	renderedTemplates, err := engine.Render(chartRequested, renderVals)
+	if err != nil {
+		return retData, err
+	}
+	// Split the templates with several documents so that every document is
+	// written to a file of its own, as for the charts with apiVersion v1
+	newRenderedTemplates := make(map[string]string)
+	for k, v := range renderedTemplates {
+		if filepath.Base(k) == "NOTES.txt" {
+			continue
+		}
+		for i, doc := range splitManifests(v) {
+			newRenderedTemplates[fmt.Sprintf("%s-%d", k, i)] = doc
+		}
+	}
+
+	hooks, manifests, err := releaseutil.SortManifests(newRenderedTemplates, caps.APIVersions, releaseutil.InstallOrder)
+	if err != nil {
+		return retData, err
+	}
+
+	for _, hook := range hooksForEvent(hooks, release.HookPreInstall) {
+		kres, err := writeTemplate(outputDir, hook.Path+"-"+hook.Name, hook.Manifest)
+		if err != nil {
+			return retData, err
+		}
+		retData = append(retData, kres)
+	}
+	for _, m := range manifests {
+		b := filepath.Base(m.Name)
+		if strings.HasPrefix(b, "_") || h.whitespaceRegex.MatchString(m.Content) {
+			continue
+		}
+		kres, err := writeTemplate(outputDir, m.Name, m.Content)
+		if err != nil {
+			return retData, err
+		}
+		retData = append(retData, kres)
+	}
+	for _, hook := range hooksForEvent(hooks, release.HookPostInstall) {
+		kres, err := writeTemplate(outputDir, hook.Path+"-"+hook.Name, hook.Manifest)
+		if err != nil {
+			return retData, err
+		}
+		retData = append(retData, kres)
+	}
+
+	logger.Info(":: Rendered chart with Helm v3 ::", logger.Fields{"chart": chartRequested.Metadata.Name, "hooks": len(hooks), "templates": len(retData)})
+	return retData, nil
+}
@@ -0,0 +1,307 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	logger "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	pkgerrors "github.com/pkg/errors"
+
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// KustomizeClient resolves the apps that are packaged as a kustomize base
+type KustomizeClient struct {
+	manifestName string
+}
+
+// NewKustomizeClient returns a new instance of KustomizeClient
+func NewKustomizeClient(manifestFileName string) *KustomizeClient {
+	return &KustomizeClient{
+		manifestName: manifestFileName,
+	}
+}
+
+// kustomization is the kustomization.yaml generated for the patches of a profile
+type kustomization struct {
+	APIVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Resources  []string         `json:"resources"`
+	Patches    []kustomizePatch `json:"patches,omitempty"`
+}
+
+type kustomizePatch struct {
+	Path   string       `json:"path"`
+	Target *patchTarget `json:"target,omitempty"`
+}
+
+// kustomizationFileNames are the names kustomize reads a kustomization from
+var kustomizationFileNames = map[string]bool{
+	"kustomization.yaml": true,
+	"kustomization.yml":  true,
+	"Kustomization":      true,
+}
+
+// kustomizationReferences are the fields of a kustomization which reference
+// the files and the directories it loads
+type kustomizationReferences struct {
+	Resources             []string             `json:"resources"`
+	Bases                 []string             `json:"bases"`
+	Components            []string             `json:"components"`
+	Crds                  []string             `json:"crds"`
+	Configurations        []string             `json:"configurations"`
+	Generators            []string             `json:"generators"`
+	Transformers          []string             `json:"transformers"`
+	Validators            []string             `json:"validators"`
+	PatchesStrategicMerge []string             `json:"patchesStrategicMerge"`
+	Patches               []kustomizePatch     `json:"patches"`
+	PatchesJSON6902       []kustomizePatch     `json:"patchesJson6902"`
+	OpenAPI               map[string]string    `json:"openapi"`
+	ConfigMapGenerator    []generatorReference `json:"configMapGenerator"`
+	SecretGenerator       []generatorReference `json:"secretGenerator"`
+}
+
+type generatorReference struct {
+	Files []string `json:"files"`
+	Envs  []string `json:"envs"`
+	Env   string   `json:"env"`
+}
+
+// paths returns the paths referenced by the kustomization. The inline
+// patches and plugin configs are left out.
+func (r kustomizationReferences) paths() []string {
+	var paths []string
+	for _, l := range [][]string{r.Resources, r.Bases, r.Components, r.Crds, r.Configurations,
+		r.Generators, r.Transformers, r.Validators, r.PatchesStrategicMerge} {
+		for _, p := range l {
+			if !strings.Contains(p, "\n") {
+				paths = append(paths, p)
+			}
+		}
+	}
+	for _, p := range append(r.Patches, r.PatchesJSON6902...) {
+		paths = append(paths, p.Path)
+	}
+	paths = append(paths, r.OpenAPI["path"])
+	for _, g := range append(r.ConfigMapGenerator, r.SecretGenerator...) {
+		for _, f := range g.Files {
+			// The files may be given a key, as key=path
+			if i := strings.Index(f, "="); i >= 0 {
+				f = f[i+1:]
+			}
+			paths = append(paths, f)
+		}
+		paths = append(paths, g.Envs...)
+		paths = append(paths, g.Env)
+	}
+	return paths
+}
+
+// remoteReference tells whether kustomize fetches a reference from a git
+// repository or a URL instead of the file system
+func remoteReference(p string) bool {
+	l := strings.ToLower(p)
+	if strings.Contains(l, "://") || strings.Contains(l, "_git/") {
+		return true
+	}
+	for _, prefix := range []string{"git::", "gh:", "git@", "github.com"} {
+		if strings.HasPrefix(l, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkKustomizeReferences checks the kustomizations found in root, so that
+// the build only loads the files of root. The remote bases and resources,
+// and the paths outside of root, are rejected.
+func checkKustomizeReferences(root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !kustomizationFileNames[info.Name()] {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Reading kustomization")
+		}
+		var refs kustomizationReferences
+		err = yaml.Unmarshal(data, &refs)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Unmarshaling kustomization")
+		}
+		dir := filepath.Dir(path)
+		for _, p := range refs.paths() {
+			if p == "" {
+				continue
+			}
+			if remoteReference(p) {
+				return pkgerrors.Errorf("Remote reference %s of the kustomization is not supported", p)
+			}
+			rel, err := filepath.Rel(root, filepath.Join(dir, p))
+			if filepath.IsAbs(p) || err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return pkgerrors.Errorf("Reference %s of the kustomization is outside of the app", p)
+			}
+		}
+		return nil
+	})
+}
+
+// profileFile returns the path of a file of the profile, which must not be
+// outside of the profile
+func profileFile(profilePath, name string) (string, error) {
+	p := filepath.Join(profilePath, name)
+	rel, err := filepath.Rel(profilePath, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", pkgerrors.Errorf("Path %s is outside of the profile", name)
+	}
+	return p, nil
+}
+
+// prepareBuild lays out the app, the overlay and the patches of the profile
+// in workDir, and returns the directory of the kustomization to build
+func (k *KustomizeClient) prepareBuild(pkg appPackage, workDir string) (string, error) {
+	basePath := filepath.Join(workDir, "base")
+	err := os.Rename(pkg.appPath, basePath)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Preparing kustomize base")
+	}
+	buildPath := basePath
+
+	if pkg.profile.GetOverlay() != "" {
+		overlay, err := profileFile(pkg.profilePath, pkg.profile.override.Type.Overlay)
+		if err != nil {
+			return "", err
+		}
+		buildPath = filepath.Join(workDir, "overlay")
+		err = os.Rename(overlay, buildPath)
+		if err != nil {
+			return "", pkgerrors.Wrap(err, "Preparing kustomize overlay")
+		}
+	}
+
+	if len(pkg.profile.override.Type.Patches) == 0 {
+		return buildPath, nil
+	}
+
+	patchPath := filepath.Join(workDir, "patches")
+	err = os.MkdirAll(patchPath, 0755)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Preparing kustomize patches")
+	}
+	kust := kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Resources:  []string{filepath.Join("..", filepath.Base(buildPath))},
+	}
+	for i, p := range pkg.profile.override.Type.Patches {
+		src, err := profileFile(pkg.profilePath, p.FilePath)
+		if err != nil {
+			return "", err
+		}
+		data, err := ioutil.ReadFile(src)
+		if err != nil {
+			return "", pkgerrors.Wrap(err, "Reading patch file")
+		}
+		name := fmt.Sprintf("patch-%d%s", i, filepath.Ext(p.FilePath))
+		err = ioutil.WriteFile(filepath.Join(patchPath, name), data, 0644)
+		if err != nil {
+			return "", pkgerrors.Wrap(err, "Writing patch file")
+		}
+		kust.Patches = append(kust.Patches, kustomizePatch{Path: name, Target: p.Target})
+	}
+	data, err := yaml.Marshal(kust)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Marshaling kustomization")
+	}
+	err = ioutil.WriteFile(filepath.Join(patchPath, "kustomization.yaml"), data, 0644)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Writing kustomization")
+	}
+	return patchPath, nil
+}
+
+// Resolve builds the kustomize base of the app, with the overlay and the
+// patches of the profile applied to it, and returns the resources sorted by
+// kind. Override values only apply to Helm charts.
+func (k *KustomizeClient) Resolve(appContent []byte, appProfileContent []byte, overrideValues map[string]interface{}, appName string) ([]KubernetesResourceTemplate, error) {
+	var sortedTemplates []KubernetesResourceTemplate
+
+	if len(overrideValues) > 0 {
+		return sortedTemplates, pkgerrors.Errorf("Override values are not supported for the kustomization of app %s", appName)
+	}
+
+	pkg, err := extractAppPackage(appContent, appProfileContent, k.manifestName, appName)
+	if err != nil {
+		logger.Error("Error while extracting app", logger.Fields{"app": appName, "error": err.Error()})
+		return sortedTemplates, err
+	}
+	defer pkg.cleanup()
+
+	workDir, err := ioutil.TempDir("", "kustomize-")
+	if err != nil {
+		return sortedTemplates, pkgerrors.Wrap(err, "Got error creating temp dir")
+	}
+	defer os.RemoveAll(workDir)
+
+	buildPath, err := k.prepareBuild(pkg, workDir)
+	if err != nil {
+		return sortedTemplates, err
+	}
+
+	// krusty clones the remote bases and reads the files it is pointed to
+	// next to the build, so the kustomizations are checked beforehand
+	err = checkKustomizeReferences(workDir)
+	if err != nil {
+		return sortedTemplates, pkgerrors.Wrapf(err, "Checking kustomization of app %s", appName)
+	}
+	opts := krusty.MakeDefaultOptions()
+	opts.LoadRestrictions = types.LoadRestrictionsRootOnly
+	kz := krusty.MakeKustomizer(opts)
+	resMap, err := kz.Run(filesys.MakeFsOnDisk(), buildPath)
+	if err != nil {
+		return sortedTemplates, pkgerrors.Wrapf(err, "Building kustomization of app %s", appName)
+	}
+
+	var manifests []releaseutil.Manifest
+	for i, res := range resMap.Resources() {
+		data, err := res.AsYAML()
+		if err != nil {
+			return sortedTemplates, pkgerrors.Wrap(err, "Marshaling kustomize resource")
+		}
+		manifests = append(manifests, releaseutil.Manifest{
+			Name:    fmt.Sprintf("%s/kustomize/%d-%s-%s.yaml", appName, i, strings.ToLower(res.GetKind()), res.GetName()),
+			Content: string(data),
+		})
+	}
+	if len(manifests) == 0 {
+		return sortedTemplates, pkgerrors.Errorf("No resources in the kustomization of app %s", appName)
+	}
+
+	return writeSortedManifests(manifests, "kustomize-tmpl-")
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKustomizeResolve(t *testing.T) {
+
+	appContent := map[string]string{
+		"testapp/kustomization.yaml": "resources:\n  - deployment.yaml\n  - service.yaml\n",
+		"testapp/deployment.yaml":    testDeployment,
+		"testapp/service.yaml":       testService,
+	}
+
+	testCases := []struct {
+		label          string
+		profileContent map[string]string
+		expectedKinds  []string
+		expectedText   []string
+		expectedError  string
+	}{
+		{
+			label: "Build the base",
+			profileContent: map[string]string{
+				"manifest.yaml": "version: v1\ntype:\n",
+			},
+			expectedKinds: []string{"Service", "Deployment"},
+			expectedText:  []string{"replicas: 1"},
+		},
+		{
+			label: "Build the base with patches",
+			profileContent: map[string]string{
+				"manifest.yaml":         "version: v1\ntype:\n  patches:\n    - filepath: patches/replicas.yaml\n    - filepath: patches/image.json\n      target:\n        kind: Deployment\n        name: web\n",
+				"patches/replicas.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 3\n",
+				"patches/image.json":    `[{"op": "replace", "path": "/spec/template/spec/containers/0/image", "value": "nginx:1.20"}]`,
+			},
+			expectedKinds: []string{"Service", "Deployment"},
+			expectedText:  []string{"replicas: 3", "nginx:1.20"},
+		},
+		{
+			label: "Build an overlay with patches",
+			profileContent: map[string]string{
+				"manifest.yaml":           "version: v1\ntype:\n  overlay: edge\n  patches:\n    - filepath: replicas.yaml\n",
+				"edge/kustomization.yaml": "resources:\n  - ../base\nnamespace: edge\n",
+				"replicas.yaml":           "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 2\n",
+			},
+			expectedKinds: []string{"Service", "Deployment"},
+			expectedText:  []string{"replicas: 2", "namespace: edge"},
+		},
+		{
+			label: "Build an overlay with a remote base",
+			profileContent: map[string]string{
+				"manifest.yaml":           "version: v1\ntype:\n  overlay: edge\n",
+				"edge/kustomization.yaml": "resources:\n  - ../base\n  - github.com/example/app//deploy?ref=v1\n",
+			},
+			expectedError: "Remote reference",
+		},
+		{
+			label: "Build an overlay with a base outside of the app",
+			profileContent: map[string]string{
+				"manifest.yaml":           "version: v1\ntype:\n  overlay: edge\n",
+				"edge/kustomization.yaml": "resources:\n  - ../../../etc/app\n",
+			},
+			expectedError: "outside of the app",
+		},
+		{
+			label: "Build with a patch outside of the profile",
+			profileContent: map[string]string{
+				"manifest.yaml": "version: v1\ntype:\n  patches:\n    - filepath: ../replicas.yaml\n",
+			},
+			expectedError: "outside of the profile",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			kc := NewKustomizeClient("manifest.yaml")
+			out, err := kc.Resolve(makeTarGz(t, appContent), makeTarGz(t, testCase.profileContent),
+				nil, "testapp")
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("Got an error %s", err)
+				}
+				if strings.Contains(err.Error(), testCase.expectedError) == false {
+					t.Fatalf("Got unexpected error message %s", err)
+				}
+				return
+			}
+			if testCase.expectedError != "" {
+				t.Fatalf("Expected error %s, got none", testCase.expectedError)
+			}
+			contents := checkTemplates(t, out, testCase.expectedKinds)
+			for _, text := range testCase.expectedText {
+				if strings.Contains(contents[1], text) == false {
+					t.Errorf("Expected %s in %s", text, contents[1])
+				}
+			}
+		})
+	}
+}
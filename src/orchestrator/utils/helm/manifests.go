@@ -0,0 +1,246 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	logger "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	utils "github.com/onap/multicloud-k8s/src/orchestrator/utils"
+	pkgerrors "github.com/pkg/errors"
+
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+// ManifestClient resolves the apps that are packaged as a bundle of plain
+// Kubernetes manifests
+type ManifestClient struct {
+	whitespaceRegex *regexp.Regexp
+	manifestName    string
+}
+
+// NewManifestClient returns a new instance of ManifestClient
+func NewManifestClient(manifestFileName string) *ManifestClient {
+	return &ManifestClient{
+		whitespaceRegex: regexp.MustCompile(`^\s*$`),
+		manifestName:    manifestFileName,
+	}
+}
+
+// appPackage is an app package extracted along with its profile
+type appPackage struct {
+	basePath    string
+	appPath     string
+	profilePath string
+	profile     ProfileYamlClient
+}
+
+// findAppDir returns the directory of the app in an extracted package: the
+// directory named after the app, or else the only directory of the package,
+// as for charts fetched from a repository, or else the package itself
+func findAppDir(basePath, appName string) string {
+	if fi, err := os.Stat(filepath.Join(basePath, appName)); err == nil && fi.IsDir() {
+		return filepath.Join(basePath, appName)
+	}
+	entries, err := ioutil.ReadDir(basePath)
+	if err == nil && len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(basePath, entries[0].Name())
+	}
+	return basePath
+}
+
+// extractAppPackage extracts the app and its profile, and copies the
+// configuration overrides of the profile into the app
+func extractAppPackage(appContent, appProfileContent []byte, manifestName, appName string) (appPackage, error) {
+	var pkg appPackage
+
+	basePath, err := utils.ExtractTarBall(bytes.NewBuffer(appContent))
+	if err != nil {
+		return pkg, pkgerrors.Wrap(err, "Error while extracting appContent")
+	}
+	pkg.basePath = basePath
+	pkg.appPath = findAppDir(basePath, appName)
+
+	prPath, err := utils.ExtractTarBall(bytes.NewBuffer(appProfileContent))
+	if err != nil {
+		pkg.cleanup()
+		return appPackage{}, pkgerrors.Wrap(err, "Error while extracting Profile Content")
+	}
+	pkg.profilePath = prPath
+
+	pkg.profile, err = ProcessProfileYaml(prPath, manifestName)
+	if err != nil {
+		pkg.cleanup()
+		return appPackage{}, pkgerrors.Wrap(err, "Error while processing Profile Manifest")
+	}
+
+	err = pkg.profile.CopyConfigurationOverrides(basePath)
+	if err != nil {
+		pkg.cleanup()
+		return appPackage{}, pkgerrors.Wrap(err, "Error while copying configresources to app")
+	}
+	return pkg, nil
+}
+
+// cleanup removes the extracted app and profile
+func (p appPackage) cleanup() {
+	if p.basePath != "" {
+		os.RemoveAll(p.basePath)
+	}
+	if p.profilePath != "" {
+		os.RemoveAll(p.profilePath)
+	}
+}
+
+// isManifestFile checks whether the file is a Kubernetes manifest of a bundle
+func isManifestFile(path string) bool {
+	b := filepath.Base(path)
+	if strings.HasPrefix(b, "_") || strings.HasPrefix(b, ".") {
+		return false
+	}
+	switch strings.ToLower(filepath.Ext(b)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// sortManifestsByKind sorts the manifests by kind in the given order. The
+// manifests of kinds that are not in the order come last, sorted by kind, and
+// the manifests of the same kind are sorted by name.
+func sortManifestsByKind(manifests []releaseutil.Manifest, ordering releaseutil.KindSortOrder) []releaseutil.Manifest {
+	rank := make(map[string]int, len(ordering))
+	for i, kind := range ordering {
+		rank[kind] = i
+	}
+	sort.SliceStable(manifests, func(i, j int) bool {
+		a, b := manifests[i], manifests[j]
+		ra, aok := rank[a.Head.Kind]
+		rb, bok := rank[b.Head.Kind]
+		switch {
+		case aok && bok && ra != rb:
+			return ra < rb
+		case aok != bok:
+			return aok
+		case a.Head.Kind != b.Head.Kind:
+			return a.Head.Kind < b.Head.Kind
+		}
+		return a.Name < b.Name
+	})
+	return manifests
+}
+
+// writeSortedManifests sorts the manifests by kind, in the order Helm installs
+// them, and writes them to a new temporary directory
+func writeSortedManifests(manifests []releaseutil.Manifest, prefix string) ([]KubernetesResourceTemplate, error) {
+	var retData []KubernetesResourceTemplate
+
+	for i, m := range manifests {
+		var head releaseutil.SimpleHead
+		err := yaml.Unmarshal([]byte(m.Content), &head)
+		if err != nil {
+			return retData, pkgerrors.Wrapf(err, "Parsing manifest %s", m.Name)
+		}
+		if head.Kind == "" || head.Version == "" {
+			return retData, pkgerrors.Errorf("Manifest %s has no apiVersion or kind", m.Name)
+		}
+		manifests[i].Head = &head
+	}
+
+	outputDir, err := ioutil.TempDir("", prefix)
+	if err != nil {
+		return retData, pkgerrors.Wrap(err, "Got error creating temp dir")
+	}
+	logger.Info(":: The o/p dir:: ", logger.Fields{"OutPutDirectory ": outputDir})
+
+	for _, m := range sortManifestsByKind(manifests, releaseutil.InstallOrder) {
+		kres, err := writeTemplate(outputDir, m.Name, m.Content)
+		if err != nil {
+			return retData, err
+		}
+		retData = append(retData, kres)
+	}
+	return retData, nil
+}
+
+// Resolve splits the manifests of the bundle into one resource per file,
+// sorted by kind. The values of the profile and the override values only
+// apply to Helm charts, the configuration overrides of the profile can be
+// used to replace manifests of the bundle.
+func (m *ManifestClient) Resolve(appContent []byte, appProfileContent []byte, overrideValues map[string]interface{}, appName string) ([]KubernetesResourceTemplate, error) {
+	var sortedTemplates []KubernetesResourceTemplate
+
+	if len(overrideValues) > 0 {
+		return sortedTemplates, pkgerrors.Errorf("Override values are not supported for the manifests of app %s", appName)
+	}
+
+	pkg, err := extractAppPackage(appContent, appProfileContent, m.manifestName, appName)
+	if err != nil {
+		logger.Error("Error while extracting app", logger.Fields{"app": appName, "error": err.Error()})
+		return sortedTemplates, err
+	}
+	defer pkg.cleanup()
+
+	var files []string
+	err = filepath.Walk(pkg.appPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && isManifestFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return sortedTemplates, pkgerrors.Wrap(err, "Reading manifests")
+	}
+	sort.Strings(files)
+
+	var manifests []releaseutil.Manifest
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return sortedTemplates, pkgerrors.Wrap(err, "Reading manifest")
+		}
+		rel, err := filepath.Rel(pkg.appPath, f)
+		if err != nil {
+			return sortedTemplates, err
+		}
+		for i, doc := range splitManifests(string(data)) {
+			if m.whitespaceRegex.MatchString(doc) {
+				continue
+			}
+			manifests = append(manifests, releaseutil.Manifest{
+				Name:    fmt.Sprintf("%s/%s-%d", appName, rel, i),
+				Content: doc,
+			})
+		}
+	}
+	if len(manifests) == 0 {
+		return sortedTemplates, pkgerrors.Errorf("No manifests found in app %s", appName)
+	}
+
+	return writeSortedManifests(manifests, "manifest-tmpl-")
+}
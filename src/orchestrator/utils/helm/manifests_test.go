@@ -0,0 +1,189 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// makeTarGz packages the files, keyed by their path, as a tar.gz
+func makeTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hdr := &tar.Header{Name: name, Mode: 0600, Size: int64(len(files[name])), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("Failed writing tar header: %s", err)
+		}
+		if _, err := tw.Write([]byte(files[name])); err != nil {
+			t.Fatalf("Failed writing tar content: %s", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Failed closing tar writer: %s", err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatalf("Failed closing gzip writer: %s", err)
+	}
+	return buf.Bytes()
+}
+
+// checkTemplates checks the kinds of the templates and returns their content
+func checkTemplates(t *testing.T, out []KubernetesResourceTemplate, expectedKinds []string) []string {
+	t.Helper()
+	if len(out) != len(expectedKinds) {
+		t.Fatalf("Got %d templates, expected %d: %v", len(out), len(expectedKinds), out)
+	}
+	var contents []string
+	for i, v := range out {
+		if v.GVK.Kind != expectedKinds[i] {
+			t.Errorf("Got kind %s at %d, expected %s", v.GVK.Kind, i, expectedKinds[i])
+		}
+		data, err := ioutil.ReadFile(v.FilePath)
+		if err != nil {
+			t.Fatalf("Unable to read file %s", v.FilePath)
+		}
+		contents = append(contents, string(data))
+	}
+	os.RemoveAll(calculateTestDirPath(out[0].FilePath))
+	return contents
+}
+
+func calculateTestDirPath(fp string) string {
+	sa := strings.Split(fp, "/")
+	return "/" + sa[1] + "/" + sa[2] + "/"
+}
+
+const testDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+        - name: web
+          image: nginx:1.19
+`
+
+const testService = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+    - port: 80
+`
+
+func TestManifestResolve(t *testing.T) {
+
+	appContent := map[string]string{
+		"testapp/web.yaml":       testDeployment + "---\n" + testService,
+		"testapp/config/cm.yml":  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web-config\ndata:\n  mode: default\n",
+		"testapp/namespace.json": `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "web"}}`,
+		"testapp/README.md":      "Not a manifest",
+		"testapp/_skipped.yaml":  "apiVersion: v1\nkind: Secret\n",
+	}
+
+	testCases := []struct {
+		label          string
+		appContent     map[string]string
+		profileContent map[string]string
+		overrideValues map[string]interface{}
+		expectedKinds  []string
+		expectedText   string
+		expectedError  string
+	}{
+		{
+			label:      "Resolve manifests sorted by kind",
+			appContent: appContent,
+			profileContent: map[string]string{
+				"manifest.yaml": "version: v1\ntype:\n",
+			},
+			expectedKinds: []string{"Namespace", "ConfigMap", "Service", "Deployment"},
+			expectedText:  "mode: default",
+		},
+		{
+			label:      "Resolve manifests with configuration overrides",
+			appContent: appContent,
+			profileContent: map[string]string{
+				"manifest.yaml": "version: v1\ntype:\n  configresource:\n    - filepath: cm.yaml\n      chartpath: testapp/config/cm.yml\n",
+				"cm.yaml":       "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web-config\ndata:\n  mode: edge\n",
+			},
+			expectedKinds: []string{"Namespace", "ConfigMap", "Service", "Deployment"},
+			expectedText:  "mode: edge",
+		},
+		{
+			label:      "Resolve manifests with override values",
+			appContent: appContent,
+			profileContent: map[string]string{
+				"manifest.yaml": "version: v1\ntype:\n",
+			},
+			overrideValues: map[string]interface{}{"replicas": "2"},
+			expectedError:  "Override values are not supported",
+		},
+		{
+			label: "Resolve manifests without apiVersion",
+			appContent: map[string]string{
+				"testapp/web.yaml": "kind: Deployment\nmetadata:\n  name: web\n",
+			},
+			profileContent: map[string]string{
+				"manifest.yaml": "version: v1\ntype:\n",
+			},
+			expectedError: "has no apiVersion or kind",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			mc := NewManifestClient("manifest.yaml")
+			out, err := mc.Resolve(makeTarGz(t, testCase.appContent), makeTarGz(t, testCase.profileContent),
+				testCase.overrideValues, "testapp")
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("Got an error %s", err)
+				}
+				if strings.Contains(err.Error(), testCase.expectedError) == false {
+					t.Fatalf("Got unexpected error message %s", err)
+				}
+				return
+			}
+			if testCase.expectedError != "" {
+				t.Fatalf("Expected error %s, got none", testCase.expectedError)
+			}
+			contents := checkTemplates(t, out, testCase.expectedKinds)
+			if strings.Contains(contents[1], testCase.expectedText) == false {
+				t.Errorf("Expected %s in %s", testCase.expectedText, contents[1])
+			}
+		})
+	}
+}
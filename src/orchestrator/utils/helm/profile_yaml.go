@@ -36,6 +36,22 @@ type:
       chartpath: chart/config/resources/config.yaml
     - filepath: config2.yaml
       chartpath: chart/config/resources/config2.yaml
+
+#Apps in the kustomize format take patches or an overlay instead of values
+---
+version: v1
+type:
+  overlay: overlay
+  patches:
+    - filepath: replicas.yaml
+    - filepath: image.json
+      target:
+        kind: Deployment
+        name: web
+
+#The overlay is a directory of the profile with a kustomization.yaml, which
+#refers to the app as ../base. The patches are applied on top of the overlay,
+#or of the app if there is no overlay.
 */
 
 type overrideFiles struct {
@@ -43,9 +59,28 @@ type overrideFiles struct {
 	ChartPath string `yaml:"chartpath"`
 }
 
+// patchTarget selects the resources a patch applies to. It is written as is
+// into the generated kustomization, hence the json tags
+type patchTarget struct {
+	Group              string `yaml:"group" json:"group,omitempty"`
+	Version            string `yaml:"version" json:"version,omitempty"`
+	Kind               string `yaml:"kind" json:"kind,omitempty"`
+	Name               string `yaml:"name" json:"name,omitempty"`
+	Namespace          string `yaml:"namespace" json:"namespace,omitempty"`
+	LabelSelector      string `yaml:"labelSelector" json:"labelSelector,omitempty"`
+	AnnotationSelector string `yaml:"annotationSelector" json:"annotationSelector,omitempty"`
+}
+
+type patchFiles struct {
+	FilePath string       `yaml:"filepath"`
+	Target   *patchTarget `yaml:"target"`
+}
+
 type supportedOverrides struct {
 	ConfigResource []overrideFiles `yaml:"configresource"`
 	Values         string          `yaml:"values"`
+	Overlay        string          `yaml:"overlay"`
+	Patches        []patchFiles    `yaml:"patches"`
 }
 
 type profileOverride struct {
@@ -68,6 +103,15 @@ func (p ProfileYamlClient) GetValues() string {
 	return filepath.Join(p.path, p.override.Type.Values)
 }
 
+//GetOverlay returns a path to the kustomize overlay that was part
+//of the profile, or an empty string if there is none
+func (p ProfileYamlClient) GetOverlay() string {
+	if p.override.Type.Overlay == "" {
+		return ""
+	}
+	return filepath.Join(p.path, p.override.Type.Overlay)
+}
+
 //CopyConfigurationOverrides copies the various files that are
 //provided as overrides to their corresponding locations within
 //the destination chart.
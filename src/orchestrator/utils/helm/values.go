@@ -0,0 +1,237 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helm
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/xeipuuv/gojsonschema"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// valuesSchemaFile is the JSON schema of the values of a chart
+const valuesSchemaFile = "values.schema.json"
+
+// SchemaViolation is a value that does not satisfy the values.schema.json of
+// a chart. Pointer is the JSON pointer of the value in the merged values
+type SchemaViolation struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// processOverrideValues returns the override values that are strings, set
+// like with helm --set, and a values file with the other override values,
+// which are typed JSON values set as they are at their dotted key. The
+// values file is to be removed once used, there is none without typed values
+func (h *TemplateClient) processOverrideValues(values map[string]interface{}) ([]string, string, error) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var set []string
+	typed := map[string]interface{}{}
+	for _, k := range keys {
+		if s, ok := values[k].(string); ok {
+			set = append(set, k+"="+s)
+			continue
+		}
+		path := strings.Split(k, ".")
+		v := values[k]
+		for i := len(path) - 1; i >= 0; i-- {
+			if path[i] == "" {
+				return nil, "", pkgerrors.Errorf("Invalid override value key %q", k)
+			}
+			v = map[string]interface{}{path[i]: v}
+		}
+		typed = h.mergeValues(typed, v.(map[string]interface{}))
+	}
+	if len(typed) == 0 {
+		return set, "", nil
+	}
+
+	data, err := yaml.Marshal(typed)
+	if err != nil {
+		return nil, "", pkgerrors.Wrap(err, "Marshaling typed override values")
+	}
+	f, err := ioutil.TempFile("", "override-values-")
+	if err != nil {
+		return nil, "", pkgerrors.Wrap(err, "Creating typed override values file")
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, "", pkgerrors.Wrap(err, "Writing typed override values file")
+	}
+	return set, f.Name(), nil
+}
+
+// ValidateValues validates the values the chart of the app is rendered with,
+// its default values merged with the values of the profile and the override
+// values, against the values.schema.json of the chart and of its enabled
+// subcharts. There are no violations when the charts have no schema
+func (h *TemplateClient) ValidateValues(appContent, appProfileContent []byte, overrideValues map[string]interface{}, appName string) ([]SchemaViolation, error) {
+	pkg, err := extractAppPackage(appContent, appProfileContent, h.manifestName, appName)
+	if err != nil {
+		return nil, err
+	}
+	defer pkg.cleanup()
+
+	chrt, err := loader.Load(pkg.appPath)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Loading the chart")
+	}
+
+	var valueFiles []string
+	chartValues := filepath.Join(pkg.appPath, "values.yaml")
+	if _, err := os.Stat(chartValues); err == nil {
+		valueFiles = append(valueFiles, chartValues)
+	}
+	valueFiles = append(valueFiles, pkg.profile.GetValues())
+
+	set, typedFile, err := h.processOverrideValues(overrideValues)
+	if err != nil {
+		return nil, err
+	}
+	if typedFile != "" {
+		defer os.Remove(typedFile)
+		valueFiles = append(valueFiles, typedFile)
+	}
+
+	rawVals, err := h.processValues(valueFiles, set)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := chartutil.ReadValues(rawVals)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Parsing values")
+	}
+	// The subcharts disabled by the values are not validated, and the
+	// values of the others are merged with their defaults
+	err = chartutil.ProcessDependencies(chrt, vals)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Processing the subcharts")
+	}
+	coalesced, err := chartutil.CoalesceValues(chrt, vals)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Merging the values of the subcharts")
+	}
+
+	violations, err := validateChartValues(chrt, coalesced, "")
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Pointer < violations[j].Pointer
+	})
+	return violations, nil
+}
+
+// validateChartValues validates the values against the schema of the chart,
+// and the values under the name of each subchart against its schema. The
+// pointers of the violations are prefixed with the pointer of the values
+func validateChartValues(chrt *chart.Chart, values map[string]interface{}, pointer string) ([]SchemaViolation, error) {
+	var violations []SchemaViolation
+	if chrt.Schema != nil {
+		rawVals, err := json.Marshal(values)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Marshaling values")
+		}
+		vs, err := validateValuesSchema(chrt.Schema, rawVals)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "Chart %s", chrt.Name())
+		}
+		for _, v := range vs {
+			v.Pointer = pointer + v.Pointer
+			violations = append(violations, v)
+		}
+	}
+	for _, sub := range chrt.Dependencies() {
+		subValues := map[string]interface{}{}
+		switch v := values[sub.Name()].(type) {
+		case map[string]interface{}:
+			subValues = v
+		case chartutil.Values:
+			subValues = v
+		}
+		vs, err := validateChartValues(sub, subValues, pointer+"/"+escapePointerToken(sub.Name()))
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, vs...)
+	}
+	return violations, nil
+}
+
+// validateValuesSchema validates the values against the schema
+func validateValuesSchema(schema, rawVals []byte) ([]SchemaViolation, error) {
+	vals, err := yaml.YAMLToJSON(rawVals)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Converting values to JSON")
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewBytesLoader(vals))
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Validating values against "+valuesSchemaFile)
+	}
+
+	var violations []SchemaViolation
+	for _, re := range result.Errors() {
+		pointer := jsonPointer(re.Context())
+		// Missing properties are reported on the object which requires them
+		if re.Type() == "required" {
+			if p, ok := re.Details()["property"].(string); ok {
+				pointer += "/" + escapePointerToken(p)
+			}
+		}
+		violations = append(violations, SchemaViolation{
+			Pointer: pointer,
+			Message: re.Description(),
+		})
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Pointer < violations[j].Pointer
+	})
+	return violations, nil
+}
+
+// jsonPointer returns the JSON pointer of a value of the document validated
+func jsonPointer(c *gojsonschema.JsonContext) string {
+	tokens := strings.Split(c.String("\x00"), "\x00")
+	var b strings.Builder
+	// The first token is the root of the document
+	for _, t := range tokens[1:] {
+		b.WriteString("/" + escapePointerToken(t))
+	}
+	return b.String()
+}
+
+// escapePointerToken escapes a reference token of a JSON pointer
+func escapePointerToken(t string) string {
+	return strings.Replace(strings.Replace(t, "~", "~0", -1), "/", "~1", -1)
+}
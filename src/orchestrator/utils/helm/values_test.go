@@ -0,0 +1,188 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+)
+
+func TestProcessOverrideValues(t *testing.T) {
+	tc := NewTemplateClient("1.12.3", "testnamespace", "testreleasename", "manifest.yaml")
+
+	set, typedFile, err := tc.processOverrideValues(map[string]interface{}{
+		"service.type":    "NodePort",
+		"service.port":    float64(8080),
+		"image":           map[string]interface{}{"pullPolicy": "Always"},
+		"ingress.enabled": true,
+		"ingress.hosts":   []interface{}{"a.example.com", "b.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("processOverrideValues returned an error (%s)", err)
+	}
+	defer os.Remove(typedFile)
+
+	expectedSet := []string{"service.type=NodePort"}
+	if !reflect.DeepEqual(set, expectedSet) {
+		t.Fatalf("Unexpected --set values %v", set)
+	}
+
+	data, err := ioutil.ReadFile(typedFile)
+	if err != nil {
+		t.Fatalf("Reading the typed values file returned an error (%s)", err)
+	}
+	var typed map[string]interface{}
+	yaml.Unmarshal(data, &typed)
+	expected := map[string]interface{}{
+		"service": map[string]interface{}{"port": float64(8080)},
+		"image":   map[string]interface{}{"pullPolicy": "Always"},
+		"ingress": map[string]interface{}{
+			"enabled": true,
+			"hosts":   []interface{}{"a.example.com", "b.example.com"},
+		},
+	}
+	if !reflect.DeepEqual(typed, expected) {
+		t.Fatalf("Unexpected typed values %v", typed)
+	}
+
+	_, _, err = tc.processOverrideValues(map[string]interface{}{"service..port": float64(1)})
+	if err == nil {
+		t.Fatalf("Expected an error for an invalid key")
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	appContent := map[string]string{
+		"testapp/Chart.yaml":  "apiVersion: v2\nname: testapp\nversion: 0.1.0\n",
+		"testapp/values.yaml": "service:\n  type: ClusterIP\n  port: 80\n",
+		"testapp/values.schema.json": `{
+  "type": "object",
+  "required": ["service"],
+  "properties": {
+    "service": {
+      "type": "object",
+      "required": ["port", "type"],
+      "properties": {
+        "type": {"enum": ["ClusterIP", "NodePort"]},
+        "port": {"type": "integer", "minimum": 1, "maximum": 65535},
+        "annotations": {"type": "object", "additionalProperties": {"type": "string"}}
+      }
+    }
+  }
+}`,
+	}
+	profileContent := map[string]string{
+		"manifest.yaml":        "version: v1\ntype:\n  values: override_values.yaml\n",
+		"override_values.yaml": "service:\n  type: NodePort\n",
+	}
+
+	testCases := []struct {
+		label          string
+		appContent     map[string]string
+		profileValues  string
+		overrideValues map[string]interface{}
+		expected       []SchemaViolation
+	}{
+		{
+			label:          "Valid string and typed values",
+			appContent:     appContent,
+			overrideValues: map[string]interface{}{"service.port": "8080", "service.annotations": map[string]interface{}{"a/b": "c"}},
+		},
+		{
+			label:          "String value out of range",
+			appContent:     appContent,
+			overrideValues: map[string]interface{}{"service.port": "70000"},
+			expected: []SchemaViolation{
+				{Pointer: "/service/port", Message: "Must be less than or equal to 65535"},
+			},
+		},
+		{
+			label:          "Typed values of the wrong type",
+			appContent:     appContent,
+			overrideValues: map[string]interface{}{"service.port": "80x", "service.annotations": map[string]interface{}{"a/b": float64(1)}},
+			expected: []SchemaViolation{
+				{Pointer: "/service/annotations/a~1b", Message: "Invalid type. Expected: string, given: integer"},
+				{Pointer: "/service/port", Message: "Invalid type. Expected: integer, given: string"},
+			},
+		},
+		{
+			label:          "Invalid profile values",
+			appContent:     appContent,
+			profileValues:  "service:\n  type: LoadBalancer\n",
+			overrideValues: map[string]interface{}{"service": map[string]interface{}{"port": float64(1)}},
+			expected: []SchemaViolation{
+				{Pointer: "/service/type", Message: "service.type must be one of the following: \"ClusterIP\", \"NodePort\""},
+			},
+		},
+		{
+			label: "Subchart schemas",
+			appContent: map[string]string{
+				"testapp/Chart.yaml":                      "apiVersion: v2\nname: testapp\nversion: 0.1.0\ndependencies:\n- name: db\n  version: 0.1.0\n- name: cache\n  version: 0.1.0\n  condition: cache.enabled\n",
+				"testapp/values.yaml":                     "cache:\n  enabled: false\n",
+				"testapp/charts/db/Chart.yaml":            "apiVersion: v2\nname: db\nversion: 0.1.0\n",
+				"testapp/charts/db/values.yaml":           "replicas: 1\n",
+				"testapp/charts/db/values.schema.json":    `{"type": "object", "properties": {"replicas": {"type": "integer", "minimum": 1}}}`,
+				"testapp/charts/cache/Chart.yaml":         "apiVersion: v2\nname: cache\nversion: 0.1.0\n",
+				"testapp/charts/cache/values.schema.json": `{"type": "object", "required": ["size"]}`,
+			},
+			overrideValues: map[string]interface{}{"db.replicas": "0"},
+			expected: []SchemaViolation{
+				{Pointer: "/db/replicas", Message: "Must be greater than or equal to 1"},
+			},
+		},
+		{
+			label: "Chart without schema",
+			appContent: map[string]string{
+				"testapp/Chart.yaml":  "apiVersion: v2\nname: testapp\nversion: 0.1.0\n",
+				"testapp/values.yaml": "service:\n  port: 80\n",
+			},
+			overrideValues: map[string]interface{}{"service.port": "x"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			pc := map[string]string{}
+			for k, v := range profileContent {
+				pc[k] = v
+			}
+			if testCase.profileValues != "" {
+				pc["override_values.yaml"] = testCase.profileValues
+			}
+			tc := NewTemplateClient("1.12.3", "testnamespace", "testreleasename", "manifest.yaml")
+			got, err := tc.ValidateValues(makeTarGz(t, testCase.appContent), makeTarGz(t, pc),
+				testCase.overrideValues, "testapp")
+			if err != nil {
+				t.Fatalf("ValidateValues returned an error (%s)", err)
+			}
+			if len(got) != len(testCase.expected) {
+				t.Fatalf("Unexpected violations %v", got)
+			}
+			for i := range got {
+				if got[i].Pointer != testCase.expected[i].Pointer ||
+					!strings.Contains(got[i].Message, testCase.expected[i].Message) {
+					t.Fatalf("Unexpected violation %v; expected %v", got[i], testCase.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/openapi"
+	moduleLib "github.com/onap/multicloud-k8s/src/ovnaction/pkg/module"
+)
+
+// openAPISpec documents the routes of NewRouter. The operations of the
+// collections and of their items are generated from the resources, the
+// other routes have an operation. Every route must have one, see
+// TestOpenAPISpec.
+func openAPISpec() openapi.Spec {
+	return openapi.Spec{
+		Title:   "EMCO OVN Action Controller",
+		Version: "v2",
+		Resources: map[string]openapi.Resource{
+			"network-controller-intent": {Name: "network controller intent", Schema: netCntIntJSONFile, Model: moduleLib.NetControlIntent{}, UpdateStatus: http.StatusCreated},
+			"workload-intents":          {Name: "workload intent", Schema: workloadIntJSONFile, Model: moduleLib.WorkloadIntent{}, UpdateStatus: http.StatusCreated},
+			"interfaces":                {Name: "workload interface intent", Schema: netIfJSONFile, Model: moduleLib.WorkloadIfIntent{}, UpdateStatus: http.StatusCreated},
+			"network-chains":            {Name: "network chain", Model: moduleLib.Chain{}, UpdateStatus: http.StatusCreated},
+		},
+		Operations: map[string]openapi.Operation{
+			"GET /v2/openapi.json": {Summary: "Get the OpenAPI specification of the service", Response: map[string]interface{}{}},
+		},
+	}
+}
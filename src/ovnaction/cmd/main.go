@@ -15,66 +15,31 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"math/rand"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"time"
 
 	"github.com/gorilla/handlers"
-	updatepb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/grpc/contextupdate"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/controllersdk"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/audit"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/auth"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/contextdb"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/metrics"
 	"github.com/onap/multicloud-k8s/src/ovnaction/api"
 	register "github.com/onap/multicloud-k8s/src/ovnaction/pkg/grpc"
 	"github.com/onap/multicloud-k8s/src/ovnaction/pkg/grpc/contextupdateserver"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/testdata"
 )
 
 func startGrpcServer() error {
-	var tls bool
-
-	if strings.Contains(config.GetConfiguration().GrpcEnableTLS, "enable") {
-		tls = true
-	} else {
-		tls = false
-	}
-	certFile := config.GetConfiguration().GrpcServerCert
-	keyFile := config.GetConfiguration().GrpcServerKey
-
 	_, port := register.GetServerHostPort()
 
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
-	if err != nil {
-		log.Fatalf("Could not listen to port: %v", err)
-	}
-	var opts []grpc.ServerOption
-	if tls {
-		if certFile == "" {
-			certFile = testdata.Path("server.pem")
-		}
-		if keyFile == "" {
-			keyFile = testdata.Path("server.key")
-		}
-		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
-		if err != nil {
-			log.Fatalf("Could not generate credentials %v", err)
-		}
-		opts = []grpc.ServerOption{grpc.Creds(creds)}
-	}
-	grpcServer := grpc.NewServer(opts...)
-	updatepb.RegisterContextupdateServer(grpcServer, contextupdateserver.NewContextupdateServer())
-
 	log.Println("Starting OVN Network Action Controller gRPC Server")
-	err = grpcServer.Serve(lis)
+	err := controllersdk.StartGrpcServer("ovnaction", port, contextupdateserver.NewController())
 	if err != nil {
 		log.Fatalf("ovnaction grpc server is not serving %v", err)
 	}
@@ -96,9 +61,12 @@ func main() {
 		log.Println(err)
 		log.Fatalln("Exiting...")
 	}
+	metrics.InstrumentDatabases()
 
 	httpRouter := api.NewRouter(nil)
-	loggedRouter := handlers.LoggingHandler(os.Stdout, httpRouter)
+	httpRouter.Use(metrics.Middleware("ovnaction"))
+	httpRouter.Use(audit.Middleware("ovnaction"))
+	loggedRouter := handlers.LoggingHandler(os.Stdout, metrics.Handler(httpRouter))
 	log.Println("Starting Network Customization Manager")
 
 	httpServer := &http.Server{
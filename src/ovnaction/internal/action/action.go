@@ -160,3 +160,39 @@ func UpdateAppContext(intentName, appContextId string) error {
 
 	return nil
 }
+
+// TerminateAppContext releases what the supplied intent holds for the given
+// AppContext ID once its deployment intent group has been terminated.
+// The network annotations are only carried by the resources in the AppContext,
+// which rsync has removed, so there is nothing left to clean up beyond
+// confirming which workloads were affected.
+func TerminateAppContext(intentName, appContextId string) error {
+	var ac appcontext.AppContext
+	_, err := ac.LoadAppContext(appContextId)
+	if err != nil {
+		return pkgerrors.Wrapf(err, "Error getting AppContext with Id: %v", appContextId)
+	}
+	caMeta, err := ac.GetCompositeAppMeta()
+	if err != nil {
+		return pkgerrors.Wrapf(err, "Error getting metadata for AppContext with Id: %v", appContextId)
+	}
+
+	wis, err := module.NewWorkloadIntentClient().GetWorkloadIntents(caMeta.Project, caMeta.CompositeApp, caMeta.Version, caMeta.DeploymentIntentGroup, intentName)
+	if err != nil {
+		return pkgerrors.Wrapf(err, "Error getting Workload Intents for Network Control Intent %v for %v/%v%v/%v not found", intentName, caMeta.Project, caMeta.CompositeApp, caMeta.DeploymentIntentGroup, caMeta.Version)
+	}
+
+	for _, wi := range wis {
+		log.Info("Released workload intent", log.Fields{
+			"project":                 caMeta.Project,
+			"composite app":           caMeta.CompositeApp,
+			"composite app version":   caMeta.Version,
+			"deployment intent group": caMeta.DeploymentIntentGroup,
+			"network control intent":  intentName,
+			"workload intent":         wi.Metadata.Name,
+			"app":                     wi.Spec.AppName,
+		})
+	}
+
+	return nil
+}
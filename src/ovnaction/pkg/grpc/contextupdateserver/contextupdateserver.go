@@ -14,37 +14,34 @@ limitations under the License.
 package contextupdateserver
 
 import (
-	"context"
-	"fmt"
-
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/controllersdk"
 	contextpb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/grpc/contextupdate"
-	log "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
 	"github.com/onap/multicloud-k8s/src/ovnaction/internal/action"
-	//"google.golang.org/grpc/codes"
-	//"google.golang.org/grpc/status"
 )
 
-type contextupdateServer struct {
-	contextpb.UnimplementedContextupdateServer
-}
+const controllerName = "ovnaction"
 
-func (cs *contextupdateServer) UpdateAppContext(ctx context.Context, req *contextpb.ContextUpdateRequest) (*contextpb.ContextUpdateResponse, error) {
-	log.Info("Received Update App Context request", log.Fields{
-		"AppContextId": req.AppContext,
-		"IntentName":   req.IntentName,
-	})
+// ovnactionController applies and releases network control intents
+type ovnactionController struct{}
 
-	err := action.UpdateAppContext(req.IntentName, req.AppContext)
+// UpdateAppContext applies the network control intent to the AppContext.
+// The network annotations are derived from the intents alone, so on an
+// update they are applied to the new AppContext just as on instantiation.
+func (c ovnactionController) UpdateAppContext(intentName, appContextId string) error {
+	return action.UpdateAppContext(intentName, appContextId)
+}
 
-	if err != nil {
-		return &contextpb.ContextUpdateResponse{AppContextUpdated: false, AppContextUpdateMessage: err.Error()}, nil
-	}
+// TerminateAppContext releases the network control intent
+func (c ovnactionController) TerminateAppContext(intentName, appContextId string) error {
+	return action.TerminateAppContext(intentName, appContextId)
+}
 
-	return &contextpb.ContextUpdateResponse{AppContextUpdated: true, AppContextUpdateMessage: fmt.Sprintf("Successful application of intent %v to %v", req.IntentName, req.AppContext)}, nil
+// NewController returns the ovnaction controller
+func NewController() controllersdk.Controller {
+	return ovnactionController{}
 }
 
 // NewContextUpdateServer exported
-func NewContextupdateServer() *contextupdateServer {
-	s := &contextupdateServer{}
-	return s
+func NewContextupdateServer() contextpb.ContextupdateServer {
+	return controllersdk.NewContextupdateServer(controllerName, NewController())
 }
@@ -48,6 +48,25 @@ func TestUpdateAppContext_UnknownContextReportsFailure(t *testing.T) {
 	}
 }
 
+func TestTerminateAppContext_UnknownContextReportsFailure(t *testing.T) {
+	contextdb.Db = &contextdb.MockEtcd{}
+
+	srv := NewContextupdateServer()
+	resp, err := srv.TerminateAppContext(context.Background(), &contextpb.TerminateRequest{
+		AppContext: "nonexistent-appcontext",
+		IntentName: "intent-1",
+	})
+	if err != nil {
+		t.Fatalf("TerminateAppContext returned transport error: %v (expected in-band failure)", err)
+	}
+	if resp.AppContextTerminated {
+		t.Error("AppContextTerminated = true, want false for an unknown app context")
+	}
+	if resp.AppContextTerminateMessage == "" {
+		t.Error("expected a non-empty failure message")
+	}
+}
+
 func TestNewContextupdateServer(t *testing.T) {
 	if NewContextupdateServer() == nil {
 		t.Error("NewContextupdateServer() = nil, want non-nil server")
@@ -28,8 +28,12 @@ import (
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/contextdb"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/db"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/metrics"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/secret"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/testdata"
 )
 
@@ -66,6 +70,7 @@ func startGrpcServer() error {
 	}
 	grpcServer := grpc.NewServer(opts...)
 	installpb.RegisterInstallappServer(grpcServer, installappserver.NewInstallAppServer())
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
 
 	log.Println("Starting rsync gRPC Server")
 	err = grpcServer.Serve(lis)
@@ -79,6 +84,10 @@ func main() {
 
 	rand.Seed(time.Now().UnixNano())
 
+	// The kubeconfigs of the clusters are encrypted by clm
+	conf := config.GetConfiguration()
+	secret.Configure(secret.Config{KeyProvider: conf.SecretKeyProvider, KeyFile: conf.SecretKeyFile})
+
 	// Initialize the mongodb
 	err := db.InitializeDatabaseConnection("mco")
 	if err != nil {
@@ -95,6 +104,11 @@ func main() {
 		log.Fatalln("Exiting...")
 	}
 
+	metrics.InstrumentDatabases()
+
+	// rsync has no HTTP API, the metrics are served on their own port
+	metrics.Serve(config.GetConfiguration().MetricsPort)
+
 	// Start grpc
 	log.Println("starting rsync GRPC server..")
 	err = startGrpcServer()
@@ -26,6 +26,7 @@ import (
 
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/appcontext"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/logutils"
+	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/infra/metrics"
 	"github.com/onap/multicloud-k8s/src/orchestrator/pkg/resourcestatus"
 	kubeclient "github.com/onap/multicloud-k8s/src/rsync/pkg/client"
 	connector "github.com/onap/multicloud-k8s/src/rsync/pkg/connector"
@@ -90,23 +91,41 @@ func getSubResApprove(ac appcontext.AppContext, name string, app string, cluster
 	return byteRes, sh, nil
 }
 
+// setResourceStatus updates the status of a resource and counts it in the
+// metrics
+func setResourceStatus(ac appcontext.AppContext, sh interface{}, s string) error {
+	countResourceStatus(sh, s)
+	return ac.UpdateStatusValue(sh, resourcestatus.ResourceStatus{Status: s})
+}
+
+// countResourceStatus counts the resource with the status handle sh in the
+// metrics of the resources by status. A deleted resource is no longer
+// counted.
+func countResourceStatus(sh interface{}, s string) {
+	if s == resourcestatus.RsyncStatusEnum.Deleted {
+		metrics.ForgetResourceStatus(fmt.Sprint(sh))
+		return
+	}
+	metrics.SetResourceStatus(fmt.Sprint(sh), s)
+}
+
 func terminateResource(ac appcontext.AppContext, c *kubeclient.Client, name string, app string, cluster string, label string) error {
 	res, sh, err := getRes(ac, name, app, cluster)
 	if err != nil {
 		if sh != nil {
-			ac.UpdateStatusValue(sh, resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Failed})
+			setResourceStatus(ac, sh, resourcestatus.RsyncStatusEnum.Failed)
 		}
 		return err
 	}
 	if err := c.Delete(res); err != nil {
-		ac.UpdateStatusValue(sh, resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Failed})
+		setResourceStatus(ac, sh, resourcestatus.RsyncStatusEnum.Failed)
 		logutils.Error("Failed to delete res", logutils.Fields{
 			"error":    err,
 			"resource": name,
 		})
 		return err
 	}
-	ac.UpdateStatusValue(sh, resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Deleted})
+	setResourceStatus(ac, sh, resourcestatus.RsyncStatusEnum.Deleted)
 	logutils.Info("Deleted::", logutils.Fields{
 		"cluster":  cluster,
 		"resource": name,
@@ -118,7 +137,7 @@ func instantiateResource(ac appcontext.AppContext, c *kubeclient.Client, name st
 	res, sh, err := getRes(ac, name, app, cluster)
 	if err != nil {
 		if sh != nil {
-			ac.UpdateStatusValue(sh, resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Failed})
+			setResourceStatus(ac, sh, resourcestatus.RsyncStatusEnum.Failed)
 		}
 		return err
 	}
@@ -154,14 +173,14 @@ func instantiateResource(ac appcontext.AppContext, c *kubeclient.Client, name st
 		return err
 	}
 	if err := c.Apply(b); err != nil {
-		ac.UpdateStatusValue(sh, resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Failed})
+		setResourceStatus(ac, sh, resourcestatus.RsyncStatusEnum.Failed)
 		logutils.Error("Failed to apply res", logutils.Fields{
 			"error":    err,
 			"resource": name,
 		})
 		return err
 	}
-	ac.UpdateStatusValue(sh, resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Applied})
+	setResourceStatus(ac, sh, resourcestatus.RsyncStatusEnum.Applied)
 	logutils.Info("Installed::", logutils.Fields{
 		"cluster":  cluster,
 		"resource": name,
@@ -218,6 +237,7 @@ func updateResourceStatus(ac appcontext.AppContext, resState resourcestatus.Reso
 			continue
 		}
 
+		countResourceStatus(sh, resState.Status)
 		err = ac.UpdateStatusValue(sh, resState)
 		if err != nil {
 			return err
@@ -286,6 +306,7 @@ Loop:
 	for {
 		select {
 		case rerr := <-rch:
+			metrics.SetClusterReachable(cluster, rerr == nil)
 			if rerr == nil {
 				break Loop
 			} else {
@@ -586,7 +607,6 @@ func waitForDone(ac appcontext.AppContext) {
 			return
 		}
 	}
-	return
 }
 
 func kickoffRetryWatcher(instca *CompositeAppContext, ac appcontext.AppContext, acStatus appcontext.AppContextStatus, wg *errgroup.Group) {
@@ -751,7 +771,10 @@ func applyFnComApp(instca *CompositeAppContext, acStatus appcontext.AppContextSt
 						"cluster": cluster,
 					})
 				}
-				g.Go(func() error {
+				g.Go(func() (err error) {
+					defer func(start time.Time) {
+						metrics.ObserveClusterApply(cluster, string(acStatus.Status), start, err)
+					}(time.Now())
 					c, err := con.GetClient(cluster)
 					if err != nil {
 						logutils.Error("Error in creating kubeconfig client", logutils.Fields{
@@ -793,6 +816,7 @@ func applyFnComApp(instca *CompositeAppContext, acStatus appcontext.AppContextSt
 								})
 								// If failure is due to reachability issues start retrying
 								if err = c.IsReachable(); err != nil {
+									metrics.SetClusterReachable(cluster, false)
 									reachable = false
 									break
 								}